@@ -0,0 +1,30 @@
+package application
+
+import "context"
+
+type actorKey struct{}
+
+// Actor is who a request is made by: a signed-in user or a device using its
+// API key. Devices carry the station or table their key is scoped to.
+type Actor struct {
+	UserID    string
+	DeviceID  string
+	Role      string
+	StationID string
+	TableID   string
+}
+
+func (a Actor) IsDevice() bool {
+	return a.DeviceID != ""
+}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the zero Actor for requests made by background
+// jobs and tests that never went through authentication.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
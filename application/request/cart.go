@@ -0,0 +1,40 @@
+package request
+
+import "time"
+
+type (
+	// OpenCart returns the open cart of the table whose QR code holds
+	// TableToken, or of a kiosk's table, and the customer's own cart
+	// otherwise.
+	OpenCart struct {
+		TableToken string `json:"table_token" form:"table_token" binding:"omitempty,uuid"`
+		OrderType  string `json:"order_type" form:"order_type"`
+	}
+
+	UpdateCart struct {
+		OrderType string `json:"order_type" form:"order_type" binding:"required"`
+	}
+
+	AddCartLine struct {
+		MenuID   string `json:"menu_id" form:"menu_id" binding:"required,uuid"`
+		Quantity int    `json:"quantity" form:"quantity" binding:"required"`
+	}
+
+	UpdateCartLine struct {
+		Quantity int `json:"quantity" form:"quantity" binding:"required"`
+	}
+
+	ApplyCartVoucher struct {
+		VoucherCode string `json:"voucher_code" form:"voucher_code" binding:"required"`
+	}
+
+	// CheckoutCart carries what CreateTransaction needs beyond the cart.
+	// TableID is only used by a customer's own cart ordered for dine-in.
+	CheckoutCart struct {
+		TableID         string     `json:"table_id" form:"table_id" binding:"omitempty,uuid"`
+		PickupAt        *time.Time `json:"pickup_at" form:"pickup_at"`
+		PaymentProvider string     `json:"payment_provider" form:"payment_provider"`
+		GuestName       string     `json:"guest_name" form:"guest_name" binding:"max=100"`
+		GuestPhone      string     `json:"guest_phone" form:"guest_phone" binding:"max=20"`
+	}
+)
@@ -0,0 +1,16 @@
+package request
+
+type (
+	OpenShift struct {
+		OpeningFloat string `json:"opening_float" form:"opening_float" binding:"omitempty,numeric"`
+	}
+
+	CloseShift struct {
+		CountedCash string `json:"counted_cash" form:"counted_cash" binding:"required,numeric"`
+	}
+
+	ConfirmPayment struct {
+		Method         string `json:"method" form:"method" binding:"required,oneof=cash card"`
+		AmountTendered string `json:"amount_tendered" form:"amount_tendered" binding:"omitempty,numeric"`
+	}
+)
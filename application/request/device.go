@@ -0,0 +1,12 @@
+package request
+
+type (
+	// CreateDevice scopes kitchen devices to a station and kiosks to a table;
+	// both are optional.
+	CreateDevice struct {
+		Name      string `json:"name" form:"name" binding:"required"`
+		Role      string `json:"role" form:"role" binding:"required"`
+		StationID string `json:"station_id" form:"station_id" binding:"omitempty,uuid"`
+		TableID   string `json:"table_id" form:"table_id" binding:"omitempty,uuid"`
+	}
+)
@@ -0,0 +1,34 @@
+package request
+
+import (
+	"fp-kpl/platform/pagination"
+	"time"
+)
+
+type (
+	// SubmitFeedback rates a served transaction from 1 to 5 stars; menu
+	// ratings are optional and only for menus in that transaction.
+	SubmitFeedback struct {
+		TransactionID string       `json:"transaction_id" form:"transaction_id" binding:"required,uuid"`
+		Rating        int          `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+		MenuRatings   []MenuRating `json:"menu_ratings" form:"menu_ratings" binding:"dive"`
+		Comment       string       `json:"comment" form:"comment" binding:"max=1000"`
+	}
+
+	MenuRating struct {
+		MenuID string `json:"menu_id" form:"menu_id" binding:"required,uuid"`
+		Rating int    `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+	}
+
+	// GetAllFeedback filters the feedback listing. From and To bound when
+	// the feedback was submitted, To being exclusive.
+	GetAllFeedback struct {
+		pagination.Request
+		MenuID     string     `form:"menu_id" binding:"omitempty,uuid"`
+		MinRating  int        `form:"min_rating" binding:"omitempty,min=1,max=5"`
+		MaxRating  int        `form:"max_rating" binding:"omitempty,min=1,max=5"`
+		From       *time.Time `form:"from"`
+		To         *time.Time `form:"to"`
+		HasComment *bool      `form:"has_comment"`
+	}
+)
@@ -5,3 +5,28 @@ type (
 		IsAvailable *bool `json:"is_available" form:"is_available" binding:"required"`
 	}
 )
+
+type (
+	// TimeWindow uses weekdays from 0 (Sunday) to 6, all days when empty,
+	// and HH:MM times, all day when both are empty.
+	TimeWindow struct {
+		Days      []int  `json:"days" form:"days" binding:"dive,min=0,max=6"`
+		StartTime string `json:"start_time" form:"start_time"`
+		EndTime   string `json:"end_time" form:"end_time"`
+	}
+
+	// UpdateSchedule replaces a schedule; no windows means always available.
+	UpdateSchedule struct {
+		Windows []TimeWindow `json:"windows" form:"windows" binding:"dive"`
+	}
+
+	PriceOverride struct {
+		Name  string `json:"name" form:"name" binding:"required"`
+		Price string `json:"price" form:"price" binding:"required"`
+		TimeWindow
+	}
+
+	UpdatePriceOverrides struct {
+		Overrides []PriceOverride `json:"overrides" form:"overrides" binding:"dive"`
+	}
+)
@@ -1,7 +1,13 @@
 package request
 
+import "time"
+
 type (
 	CalculateTotalPrice struct {
-		Orders []Order `json:"orders" form:"orders" binding:"required"`
+		Orders      []Order `json:"orders" form:"orders" binding:"required"`
+		VoucherCode string  `json:"voucher_code" form:"voucher_code"`
+		OrderType   string  `json:"order_type" form:"order_type"`
+		// PickupAt checks the menus against the pickup time instead of now.
+		PickupAt *time.Time `json:"pickup_at" form:"pickup_at"`
 	}
 )
@@ -0,0 +1,31 @@
+package request
+
+import "time"
+
+type (
+	// CreatePromotion leaves the voucher code empty for promotions that
+	// apply on their own. Days are weekdays from 0 (Sunday) to 6 and the
+	// daily window uses HH:MM.
+	CreatePromotion struct {
+		Name         string     `json:"name" form:"name" binding:"required"`
+		Type         string     `json:"type" form:"type" binding:"required"`
+		Value        string     `json:"value" form:"value"`
+		BuyQuantity  int        `json:"buy_quantity" form:"buy_quantity" binding:"min=0"`
+		FreeQuantity int        `json:"free_quantity" form:"free_quantity" binding:"min=0"`
+		CategoryID   string     `json:"category_id" form:"category_id" binding:"omitempty,uuid"`
+		MenuID       string     `json:"menu_id" form:"menu_id" binding:"omitempty,uuid"`
+		MinimumSpend string     `json:"minimum_spend" form:"minimum_spend"`
+		StartsAt     *time.Time `json:"starts_at" form:"starts_at"`
+		EndsAt       *time.Time `json:"ends_at" form:"ends_at"`
+		Days         []int      `json:"days" form:"days" binding:"dive,min=0,max=6"`
+		StartTime    string     `json:"start_time" form:"start_time"`
+		EndTime      string     `json:"end_time" form:"end_time"`
+		VoucherCode  string     `json:"voucher_code" form:"voucher_code"`
+		UsageLimit   int        `json:"usage_limit" form:"usage_limit" binding:"min=0"`
+		PerUserLimit int        `json:"per_user_limit" form:"per_user_limit" binding:"min=0"`
+	}
+
+	UpdatePromotionStatus struct {
+		IsActive *bool `json:"is_active" form:"is_active" binding:"required"`
+	}
+)
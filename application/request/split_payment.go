@@ -0,0 +1,22 @@
+package request
+
+type (
+	// SplitBill shares a bill out. Either every share names an amount or
+	// every share names the order lines it pays for.
+	SplitBill struct {
+		Splits []SplitShare `json:"splits" form:"splits" binding:"required,min=1,dive"`
+	}
+
+	SplitShare struct {
+		Amount   string   `json:"amount" form:"amount" binding:"omitempty,numeric"`
+		OrderIDs []string `json:"order_ids" form:"order_ids" binding:"omitempty,dive,uuid"`
+		// PaymentProvider is optional; the transaction's provider is used
+		// when empty.
+		PaymentProvider string `json:"payment_provider" form:"payment_provider"`
+	}
+
+	RefundPayment struct {
+		Amount string `json:"amount" form:"amount" binding:"required,numeric"`
+		Reason string `json:"reason" form:"reason"`
+	}
+)
@@ -0,0 +1,7 @@
+package request
+
+type (
+	AssignStation struct {
+		StationID string `json:"station_id" form:"station_id" binding:"omitempty,uuid"`
+	}
+)
@@ -1,9 +1,26 @@
 package request
 
+import "time"
+
 type (
 	TransactionCreate struct {
-		TableID string  `json:"table_id" form:"table_id" binding:"required"`
-		Orders  []Order `json:"orders" form:"orders" binding:"required"`
+		// OrderType defaults to dine-in. TableID is only needed for dine-in
+		// and may be left out by a kiosk bound to a table; PickupAt is
+		// required for pickup orders.
+		OrderType string     `json:"order_type" form:"order_type"`
+		TableID   string     `json:"table_id" form:"table_id" binding:"omitempty,uuid"`
+		PickupAt  *time.Time `json:"pickup_at" form:"pickup_at"`
+		Orders    []Order    `json:"orders" form:"orders" binding:"required"`
+		// PaymentProvider is optional; the default provider is used when empty.
+		PaymentProvider string `json:"payment_provider" form:"payment_provider"`
+		VoucherCode     string `json:"voucher_code" form:"voucher_code"`
+		// RedeemPoints spends the customer's loyalty points on the order.
+		// Points beyond what the order can absorb are left in the balance.
+		RedeemPoints int64 `json:"redeem_points" form:"redeem_points" binding:"min=0"`
+		// GuestName and GuestPhone are the optional pickup contact of an
+		// order placed without an account.
+		GuestName  string `json:"guest_name" form:"guest_name" binding:"max=100"`
+		GuestPhone string `json:"guest_phone" form:"guest_phone" binding:"max=20"`
 	}
 
 	Order struct {
@@ -11,19 +28,38 @@ type (
 		Quantity int    `json:"quantity" form:"quantity" binding:"required"`
 	}
 
+	// The kitchen and waiter actions take either the queue code called out
+	// in the restaurant or the transaction ID, which stays unambiguous when
+	// a code from the previous business day is still in flight.
 	StartCooking struct {
-		QueueCode string `json:"queue_code" form:"queue_code" binding:"required"`
+		QueueCode     string `json:"queue_code" form:"queue_code" binding:"required_without=TransactionID"`
+		TransactionID string `json:"transaction_id" form:"transaction_id" binding:"omitempty,uuid"`
+		StationID     string `json:"station_id" form:"station_id" binding:"omitempty,uuid"`
 	}
 
 	FinishCooking struct {
-		QueueCode string `json:"queue_code" form:"queue_code" binding:"required"`
+		QueueCode     string `json:"queue_code" form:"queue_code" binding:"required_without=TransactionID"`
+		TransactionID string `json:"transaction_id" form:"transaction_id" binding:"omitempty,uuid"`
+		StationID     string `json:"station_id" form:"station_id" binding:"omitempty,uuid"`
 	}
 
 	StartDelivering struct {
-		QueueCode string `json:"queue_code" form:"queue_code" binding:"required"`
+		QueueCode     string `json:"queue_code" form:"queue_code" binding:"required_without=TransactionID"`
+		TransactionID string `json:"transaction_id" form:"transaction_id" binding:"omitempty,uuid"`
 	}
 
 	FinishDelivering struct {
-		QueueCode string `json:"queue_code" form:"queue_code" binding:"required"`
+		QueueCode     string `json:"queue_code" form:"queue_code" binding:"required_without=TransactionID"`
+		TransactionID string `json:"transaction_id" form:"transaction_id" binding:"omitempty,uuid"`
+	}
+
+	// CollectOrder hands a takeaway or pickup order over at the counter.
+	CollectOrder struct {
+		QueueCode     string `json:"queue_code" form:"queue_code" binding:"required_without=TransactionID"`
+		TransactionID string `json:"transaction_id" form:"transaction_id" binding:"omitempty,uuid"`
+	}
+
+	UpdatePriority struct {
+		Priority *int `json:"priority" form:"priority" binding:"required,min=0"`
 	}
 )
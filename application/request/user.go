@@ -12,4 +12,17 @@ type (
 		Email    string `json:"email" form:"email" binding:"required,email"`
 		Password string `json:"password" form:"password" binding:"required,min=8"`
 	}
+
+	// UserUpdateProfile changes only the fields that are given. Changing the
+	// email takes the current password.
+	UserUpdateProfile struct {
+		Name            string `json:"name" form:"name" binding:"omitempty,min=2,max=100"`
+		PhoneNumber     string `json:"phone_number" form:"phone_number" binding:"omitempty,min=8,max=20"`
+		Email           string `json:"email" form:"email" binding:"omitempty,email"`
+		CurrentPassword string `json:"current_password" form:"current_password"`
+	}
+
+	UserDelete struct {
+		Password string `json:"password" form:"password" binding:"required"`
+	}
 )
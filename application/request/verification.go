@@ -0,0 +1,18 @@
+package request
+
+type (
+	VerificationSend struct {
+		Channel string `json:"channel" form:"channel" binding:"required,oneof=email phone"`
+	}
+
+	VerificationVerify struct {
+		Channel string `json:"channel" form:"channel" binding:"required,oneof=email phone"`
+		Code    string `json:"code" form:"code" binding:"required,numeric,len=6"`
+	}
+
+	// VerificationLink is the query string of the link sent by email.
+	VerificationLink struct {
+		Challenge string `form:"challenge" binding:"required,uuid"`
+		Code      string `form:"code" binding:"required,numeric,len=6"`
+	}
+)
@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+type (
+	// Cart is revalidated whenever it is read or changed: lines that cannot
+	// be ordered any more are flagged and left out of Pricing, and a voucher
+	// that no longer applies is reported in VoucherError.
+	Cart struct {
+		ID           string          `json:"id"`
+		TableID      string          `json:"table_id,omitempty"`
+		IsShared     bool            `json:"is_shared"`
+		OrderType    string          `json:"order_type"`
+		Lines        []CartLine      `json:"lines"`
+		VoucherCode  string          `json:"voucher_code,omitempty"`
+		VoucherError string          `json:"voucher_error,omitempty"`
+		Pricing      *PriceBreakdown `json:"pricing,omitempty"`
+		CanCheckout  bool            `json:"can_checkout"`
+		ExpiresAt    time.Time       `json:"expires_at"`
+	}
+
+	CartLine struct {
+		ID          string             `json:"id"`
+		Menu        MenuForTransaction `json:"menu"`
+		Quantity    int                `json:"quantity"`
+		IsAvailable bool               `json:"is_available"`
+		Reason      string             `json:"reason,omitempty"`
+	}
+)
@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+type (
+	Shift struct {
+		ID           string     `json:"id"`
+		CashierID    string     `json:"cashier_id"`
+		OpeningFloat string     `json:"opening_float"`
+		CashTotal    string     `json:"cash_total"`
+		CardTotal    string     `json:"card_total"`
+		Transactions int        `json:"transactions"`
+		ExpectedCash string     `json:"expected_cash"`
+		CountedCash  string     `json:"counted_cash,omitempty"`
+		Variance     string     `json:"variance,omitempty"`
+		OpenedAt     time.Time  `json:"opened_at"`
+		ClosedAt     *time.Time `json:"closed_at"`
+	}
+
+	ConfirmPayment struct {
+		TransactionID  string `json:"transaction_id"`
+		QueueCode      string `json:"queue_code"`
+		ShiftID        string `json:"shift_id"`
+		Method         string `json:"method"`
+		TotalPrice     string `json:"total_price"`
+		AmountTendered string `json:"amount_tendered"`
+		Change         string `json:"change"`
+	}
+)
@@ -2,7 +2,8 @@ package response
 
 type (
 	Category struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
+		ID       string       `json:"id"`
+		Name     string       `json:"name"`
+		Schedule []TimeWindow `json:"schedule,omitempty"`
 	}
 )
@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+type (
+	Device struct {
+		ID         string     `json:"id"`
+		Name       string     `json:"name"`
+		Role       string     `json:"role"`
+		StationID  string     `json:"station_id,omitempty"`
+		TableID    string     `json:"table_id,omitempty"`
+		KeyPrefix  string     `json:"key_prefix"`
+		LastSeenAt *time.Time `json:"last_seen_at"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+
+	// CreatedDevice is the only response that carries the API key.
+	CreatedDevice struct {
+		Device
+		Key string `json:"key"`
+	}
+)
@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type (
+	DisplayBoard struct {
+		Preparing []DisplayEntry `json:"preparing"`
+		Ready     []DisplayEntry `json:"ready"`
+		UpdatedAt time.Time      `json:"updated_at"`
+	}
+
+	DisplayEntry struct {
+		QueueCode   string `json:"queue_code"`
+		Highlighted bool   `json:"highlighted"`
+	}
+)
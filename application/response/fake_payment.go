@@ -0,0 +1,8 @@
+package response
+
+type FakePayment struct {
+	Token       string `json:"token"`
+	OrderID     string `json:"order_id"`
+	Status      string `json:"status"`
+	GrossAmount string `json:"gross_amount"`
+}
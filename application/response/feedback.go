@@ -0,0 +1,33 @@
+package response
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type (
+	Feedback struct {
+		ID            string               `json:"id"`
+		TransactionID string               `json:"transaction_id"`
+		QueueCode     string               `json:"queue_code,omitempty"`
+		UserID        string               `json:"user_id"`
+		UserName      string               `json:"user_name,omitempty"`
+		Rating        int                  `json:"rating"`
+		MenuRatings   []FeedbackMenuRating `json:"menu_ratings"`
+		Comment       string               `json:"comment"`
+		CreatedAt     time.Time            `json:"created_at"`
+	}
+
+	FeedbackMenuRating struct {
+		MenuID   string `json:"menu_id"`
+		MenuName string `json:"menu_name,omitempty"`
+		Rating   int    `json:"rating"`
+	}
+
+	// MenuRating aggregates the ratings a menu received in feedback.
+	MenuRating struct {
+		Average decimal.Decimal `json:"average"`
+		Count   int             `json:"count"`
+	}
+)
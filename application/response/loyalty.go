@@ -0,0 +1,31 @@
+package response
+
+import "time"
+
+type (
+	// LoyaltyAccount is a customer's points balance with their latest ledger
+	// entries, newest first.
+	LoyaltyAccount struct {
+		Balance        int64          `json:"balance"`
+		PointValue     string         `json:"point_value"`
+		ExpiringPoints int64          `json:"expiring_points"`
+		ExpiringAt     *time.Time     `json:"expiring_at,omitempty"`
+		History        []LoyaltyEntry `json:"history"`
+	}
+
+	LoyaltyEntry struct {
+		ID            string     `json:"id"`
+		TransactionID string     `json:"transaction_id,omitempty"`
+		Type          string     `json:"type"`
+		Points        int64      `json:"points"`
+		ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+		CreatedAt     time.Time  `json:"created_at"`
+	}
+
+	// LoyaltySync reports one run of the ledger sync.
+	LoyaltySync struct {
+		Accounts int `json:"accounts"`
+		Entries  int `json:"entries"`
+		Failed   int `json:"failed"`
+	}
+)
@@ -3,13 +3,32 @@ package response
 import "github.com/shopspring/decimal"
 
 type (
+	// Menu reports its availability and price at the time of the request;
+	// RegularPrice is the price without any override. Rating is left out
+	// until the menu has been rated.
 	Menu struct {
-		ID          string          `json:"id"`
-		Name        string          `json:"name"`
-		Description string          `json:"description"`
-		ImageUrl    string          `json:"image_url"`
-		IsAvailable bool            `json:"is_available"`
-		Price       decimal.Decimal `json:"price"`
-		Category    Category        `json:"category"`
+		ID             string          `json:"id"`
+		Name           string          `json:"name"`
+		Description    string          `json:"description"`
+		ImageUrl       string          `json:"image_url"`
+		IsAvailable    bool            `json:"is_available"`
+		Price          decimal.Decimal `json:"price"`
+		RegularPrice   decimal.Decimal `json:"regular_price"`
+		Category       Category        `json:"category"`
+		Schedule       []TimeWindow    `json:"schedule,omitempty"`
+		PriceOverrides []PriceOverride `json:"price_overrides,omitempty"`
+		Rating         *MenuRating     `json:"rating,omitempty"`
+	}
+
+	TimeWindow struct {
+		Days      []int  `json:"days"`
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+	}
+
+	PriceOverride struct {
+		Name  string          `json:"name"`
+		Price decimal.Decimal `json:"price"`
+		TimeWindow
 	}
 )
@@ -2,6 +2,29 @@ package response
 
 type (
 	CalculateTotalPrice struct {
-		TotalPrice string `json:"total_price"`
+		TotalPrice string         `json:"total_price"`
+		Pricing    PriceBreakdown `json:"pricing"`
+	}
+
+	PriceBreakdown struct {
+		Subtotal          string     `json:"subtotal"`
+		Discounts         []Discount `json:"discounts"`
+		Discount          string     `json:"discount"`
+		LoyaltyPoints     int64      `json:"loyalty_points"`
+		LoyaltyDiscount   string     `json:"loyalty_discount"`
+		PackagingFee      string     `json:"packaging_fee"`
+		ServiceChargeRate string     `json:"service_charge_rate"`
+		ServiceCharge     string     `json:"service_charge"`
+		TaxRate           string     `json:"tax_rate"`
+		Tax               string     `json:"tax"`
+		Rounding          string     `json:"rounding"`
+		Total             string     `json:"total"`
+	}
+
+	Discount struct {
+		PromotionID string `json:"promotion_id"`
+		Name        string `json:"name"`
+		VoucherCode string `json:"voucher_code,omitempty"`
+		Amount      string `json:"amount"`
 	}
 )
@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type (
+	ExpiredTransaction struct {
+		TransactionID string    `json:"transaction_id"`
+		TotalPrice    string    `json:"total_price"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+)
@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type (
+	Promotion struct {
+		ID           string     `json:"id"`
+		Name         string     `json:"name"`
+		Type         string     `json:"type"`
+		Value        string     `json:"value"`
+		BuyQuantity  int        `json:"buy_quantity,omitempty"`
+		FreeQuantity int        `json:"free_quantity,omitempty"`
+		CategoryID   string     `json:"category_id,omitempty"`
+		MenuID       string     `json:"menu_id,omitempty"`
+		MinimumSpend string     `json:"minimum_spend"`
+		StartsAt     *time.Time `json:"starts_at"`
+		EndsAt       *time.Time `json:"ends_at"`
+		Days         []int      `json:"days"`
+		StartTime    string     `json:"start_time"`
+		EndTime      string     `json:"end_time"`
+		VoucherCode  string     `json:"voucher_code,omitempty"`
+		UsageLimit   int        `json:"usage_limit"`
+		PerUserLimit int        `json:"per_user_limit"`
+		IsActive     bool       `json:"is_active"`
+	}
+)
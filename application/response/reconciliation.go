@@ -0,0 +1,20 @@
+package response
+
+type (
+	Reconciliation struct {
+		Checked       int                  `json:"checked"`
+		Updated       int                  `json:"updated"`
+		Failed        int                  `json:"failed"`
+		Discrepancies []PaymentDiscrepancy `json:"discrepancies"`
+	}
+
+	PaymentDiscrepancy struct {
+		TransactionID string `json:"transaction_id"`
+		Type          string `json:"type"`
+		LocalStatus   string `json:"local_status"`
+		GatewayStatus string `json:"gateway_status"`
+		LocalAmount   string `json:"local_amount"`
+		GatewayAmount string `json:"gateway_amount"`
+		Resolved      bool   `json:"resolved"`
+	}
+)
@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+type (
+	SLABreach struct {
+		TransactionID string    `json:"transaction_id"`
+		QueueCode     string    `json:"queue_code"`
+		Type          string    `json:"type"`
+		Since         time.Time `json:"since"`
+		Deadline      time.Time `json:"deadline"`
+		OverdueBy     string    `json:"overdue_by"`
+		DetectedAt    time.Time `json:"detected_at"`
+	}
+)
@@ -0,0 +1,37 @@
+package response
+
+import "time"
+
+type (
+	SplitBill struct {
+		TransactionID   string         `json:"transaction_id"`
+		TotalPrice      string         `json:"total_price"`
+		PaidAmount      string         `json:"paid_amount"`
+		RemainingAmount string         `json:"remaining_amount"`
+		PaymentStatus   string         `json:"payment_status"`
+		Payments        []SplitPayment `json:"payments"`
+	}
+
+	SplitPayment struct {
+		ID             string     `json:"id"`
+		Provider       string     `json:"payment_provider"`
+		Amount         string     `json:"amount"`
+		Status         string     `json:"status"`
+		OrderIDs       []string   `json:"order_ids,omitempty"`
+		RefundedAmount string     `json:"refunded_amount"`
+		Token          string     `json:"token,omitempty"`
+		PaymentLink    string     `json:"payment_link,omitempty"`
+		PaidAt         *time.Time `json:"paid_at"`
+	}
+
+	Refund struct {
+		ID             string    `json:"id"`
+		PaymentID      string    `json:"payment_id"`
+		TransactionID  string    `json:"transaction_id"`
+		Amount         string    `json:"amount"`
+		Reason         string    `json:"reason"`
+		RefundedAmount string    `json:"refunded_amount"`
+		Refundable     string    `json:"refundable"`
+		CreatedAt      time.Time `json:"created_at"`
+	}
+)
@@ -0,0 +1,13 @@
+package response
+
+type (
+	Station struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	StationAssignment struct {
+		ID        string `json:"id"`
+		StationID string `json:"station_id"`
+	}
+)
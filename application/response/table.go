@@ -5,4 +5,11 @@ type (
 		ID          string `json:"id"`
 		TableNumber string `json:"table_number"`
 	}
+
+	// TableToken is what goes in the table's QR code.
+	TableToken struct {
+		ID          string `json:"id"`
+		TableNumber string `json:"table_number"`
+		Token       string `json:"token"`
+	}
 )
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -8,9 +10,15 @@ type (
 	TransactionCreate struct {
 		TransactionID string                      `json:"transaction_id"`
 		TotalPrice    string                      `json:"total_price"`
+		Provider      string                      `json:"payment_provider"`
 		Token         string                      `json:"token"`
 		PaymentLink   string                      `json:"payment_link"`
+		Pricing       PriceBreakdown              `json:"pricing"`
 		Orders        []OrderForTransactionCreate `json:"orders"`
+		OrderType     string                      `json:"order_type"`
+		PickupAt      *time.Time                  `json:"pickup_at,omitempty"`
+		// GuestToken is only set for guest orders and is shown once.
+		GuestToken string `json:"guest_token,omitempty"`
 	}
 
 	OrderForTransactionCreate struct {
@@ -27,12 +35,38 @@ type (
 	Transaction struct {
 		ID           string                `json:"id"`
 		QueueCode    string                `json:"queue_code"`
+		BusinessDay  string                `json:"business_day,omitempty"`
 		EstimateTime string                `json:"estimate_time"`
 		Orders       []OrderForTransaction `json:"orders"`
 		TotalPrice   decimal.Decimal       `json:"total_price"`
-		Table        Table                 `json:"table"`
-		OrderStatus  string                `json:"order_status"`
-		IsDelayed    bool                  `json:"is_delayed"`
+		Pricing      PriceBreakdown        `json:"pricing"`
+		// Table is left out for takeaway and pickup orders placed without one.
+		Table       *Table       `json:"table,omitempty"`
+		OrderType   string       `json:"order_type"`
+		PickupAt    *time.Time   `json:"pickup_at,omitempty"`
+		OrderStatus string       `json:"order_status"`
+		GuestName   string       `json:"guest_name,omitempty"`
+		IsDelayed   bool         `json:"is_delayed"`
+		Estimate    WaitEstimate `json:"estimate"`
+	}
+
+	// GuestTransaction is what a guest holding the guest token sees of their
+	// order: its place in the queue and when it should be ready, nothing that
+	// identifies them, their table or what they paid.
+	GuestTransaction struct {
+		QueueCode    string       `json:"queue_code"`
+		OrderStatus  string       `json:"order_status"`
+		OrderType    string       `json:"order_type"`
+		PickupAt     *time.Time   `json:"pickup_at,omitempty"`
+		EstimateTime string       `json:"estimate_time"`
+		Estimate     WaitEstimate `json:"estimate"`
+	}
+
+	WaitEstimate struct {
+		QueuePosition int       `json:"queue_position,omitempty"`
+		ETA           time.Time `json:"eta"`
+		EarliestETA   time.Time `json:"earliest_eta"`
+		LatestETA     time.Time `json:"latest_eta"`
 	}
 
 	OrderForTransaction struct {
@@ -40,6 +74,22 @@ type (
 		Quantity int                `json:"quantity"`
 	}
 
+	// Reorder is a cart built from a past transaction for the customer to
+	// check out again. Pricing is left out when nothing can be reordered.
+	Reorder struct {
+		SourceTransactionID string                      `json:"source_transaction_id"`
+		OrderType           string                      `json:"order_type"`
+		Orders              []OrderForTransactionCreate `json:"orders"`
+		Unavailable         []UnavailableOrder          `json:"unavailable"`
+		Pricing             *PriceBreakdown             `json:"pricing,omitempty"`
+	}
+
+	UnavailableOrder struct {
+		Menu     MenuForWaiter `json:"menu"`
+		Quantity int           `json:"quantity"`
+		Reason   string        `json:"reason"`
+	}
+
 	TransactionForWaiter struct {
 		QueueCode string           `json:"queue_code"`
 		Orders    []OrderForWaiter `json:"orders"`
@@ -62,9 +112,23 @@ type (
 
 	NextOrder struct {
 		QueueCode string                `json:"queue_code"`
+		Station   string                `json:"station,omitempty"`
 		Orders    []OrderForTransaction `json:"orders"`
 	}
 
+	UpdatePriority struct {
+		ID        string `json:"id"`
+		QueueCode string `json:"queue_code"`
+		Priority  int    `json:"priority"`
+	}
+
+	StatusChange struct {
+		Status    string    `json:"status"`
+		UserID    string    `json:"user_id,omitempty"`
+		DeviceID  string    `json:"device_id,omitempty"`
+		ChangedAt time.Time `json:"changed_at"`
+	}
+
 	StartCooking struct {
 		QueueCode string                `json:"queue_code"`
 		Orders    []OrderForTransaction `json:"orders"`
@@ -81,4 +145,9 @@ type (
 	}
 
 	FinishDelivering struct{}
+
+	CollectOrder struct {
+		QueueCode string                `json:"queue_code"`
+		Orders    []OrderForTransaction `json:"orders"`
+	}
 )
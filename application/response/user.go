@@ -2,11 +2,15 @@ package response
 
 type (
 	User struct {
-		ID          string `json:"id"`
-		Email       string `json:"email"`
-		Name        string `json:"name"`
-		PhoneNumber string `json:"phone_number,omitempty"`
-		Role        string `json:"role"`
+		ID            string `json:"id"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		PhoneNumber   string `json:"phone_number,omitempty"`
+		PhoneVerified bool   `json:"phone_verified"`
+		Role          string `json:"role"`
+		// Loyalty is only filled in for the customer's own profile.
+		Loyalty *LoyaltyAccount `json:"loyalty,omitempty"`
 	}
 
 	UserRegister struct {
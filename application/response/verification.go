@@ -0,0 +1,22 @@
+package response
+
+import "time"
+
+type (
+	// VerificationSent tells where a code went, masked, and when the next one
+	// may be requested.
+	VerificationSent struct {
+		Channel     string    `json:"channel"`
+		Destination string    `json:"destination"`
+		ExpiresAt   time.Time `json:"expires_at"`
+		ResendAfter time.Time `json:"resend_after"`
+	}
+
+	Verification struct {
+		EmailVerified bool `json:"email_verified"`
+		PhoneVerified bool `json:"phone_verified"`
+		// CanOrder is false when orders require a verified account and this
+		// one is not.
+		CanOrder bool `json:"can_order"`
+	}
+)
@@ -0,0 +1,495 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/cart"
+	"fp-kpl/domain/identity"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/table"
+	"fp-kpl/domain/transaction"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	CartService interface {
+		OpenCart(ctx context.Context, userID string, req request.OpenCart) (response.Cart, error)
+		GetCart(ctx context.Context, userID string, cartID string) (response.Cart, error)
+		UpdateCart(ctx context.Context, userID string, cartID string, req request.UpdateCart) (response.Cart, error)
+		AddCartLine(ctx context.Context, userID string, cartID string, req request.AddCartLine) (response.Cart, error)
+		UpdateCartLine(ctx context.Context, userID string, cartID string, lineID string, req request.UpdateCartLine) (response.Cart, error)
+		RemoveCartLine(ctx context.Context, userID string, cartID string, lineID string) (response.Cart, error)
+		ApplyCartVoucher(ctx context.Context, userID string, cartID string, req request.ApplyCartVoucher) (response.Cart, error)
+		RemoveCartVoucher(ctx context.Context, userID string, cartID string) (response.Cart, error)
+		CheckoutCart(ctx context.Context, userID string, cartID string, req request.CheckoutCart) (response.TransactionCreate, error)
+	}
+
+	cartService struct {
+		cartRepository     cart.Repository
+		menuRepository     menu.Repository
+		tableRepository    table.Repository
+		orderService       OrderService
+		transactionService TransactionService
+		clock              shared.Clock
+		idleTimeout        time.Duration
+	}
+
+	// cartContents is a cart checked against the menus as they are now.
+	cartContents struct {
+		lines  []response.CartLine
+		orders []request.Order
+	}
+)
+
+func NewCartService(
+	cartRepository cart.Repository,
+	menuRepository menu.Repository,
+	tableRepository table.Repository,
+	orderService OrderService,
+	transactionService TransactionService,
+	clock shared.Clock,
+	idleTimeout time.Duration,
+) CartService {
+	if idleTimeout <= 0 {
+		idleTimeout = cart.DefaultIdleTimeout
+	}
+	return &cartService{
+		cartRepository:     cartRepository,
+		menuRepository:     menuRepository,
+		tableRepository:    tableRepository,
+		orderService:       orderService,
+		transactionService: transactionService,
+		clock:              clock,
+		idleTimeout:        idleTimeout,
+	}
+}
+
+// OpenCart returns the cart the user should be adding to, starting a new one
+// when there is none or the last one expired. Guests who scanned a table's QR
+// code share the table's cart; a kiosk always uses the cart of the table it
+// is bound to.
+func (s *cartService) OpenCart(ctx context.Context, userID string, req request.OpenCart) (response.Cart, error) {
+	actorTableID := application.ActorFromContext(ctx).TableID
+	if userID == "" && actorTableID == "" {
+		return response.Cart{}, table.ErrorTableRequired
+	}
+
+	orderType, err := transaction.NewOrderType(req.OrderType)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	var retrievedTable table.Table
+	switch {
+	case actorTableID != "":
+		retrievedTable, err = s.tableRepository.GetTableByID(ctx, nil, actorTableID)
+	case req.TableToken != "":
+		retrievedTable, err = s.tableRepository.GetTableByToken(ctx, nil, req.TableToken)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Cart{}, table.ErrorTableNotFound
+		}
+		return response.Cart{}, err
+	}
+
+	tableIdentity := retrievedTable.ID
+	var tableID string
+	if !tableIdentity.IsEmpty() {
+		tableID = tableIdentity.String()
+	}
+
+	now := s.clock.Now()
+	openCart, err := s.cartRepository.GetOpenCart(ctx, nil, userID, tableID, now)
+	if err == nil {
+		return s.joinedCartResponse(ctx, userID, openCart)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.Cart{}, err
+	}
+
+	cartEntity := cart.Cart{
+		ID:        identity.NewID(uuid.New()),
+		TableID:   tableIdentity,
+		OrderType: orderType,
+		ExpiresAt: now.Add(s.idleTimeout),
+		Timestamp: shared.Timestamp{CreatedAt: now, UpdatedAt: now},
+	}
+	if tableIdentity.IsEmpty() {
+		userIdentity, err := uuid.Parse(userID)
+		if err != nil {
+			return response.Cart{}, cart.ErrorCreateCart
+		}
+		cartEntity.UserID = identity.NewID(userIdentity)
+	}
+
+	createdCart, err := s.cartRepository.CreateCart(ctx, nil, cartEntity)
+	if errors.Is(err, cart.ErrorCartAlreadyOpen) {
+		// Another guest at the table opened it first.
+		createdCart, err = s.cartRepository.GetOpenCart(ctx, nil, userID, tableID, now)
+	}
+	if err != nil {
+		return response.Cart{}, cart.ErrorCreateCart
+	}
+
+	return s.joinedCartResponse(ctx, userID, createdCart)
+}
+
+// joinedCartResponse lets a guest who opened the table's cart keep using it
+// by its ID.
+func (s *cartService) joinedCartResponse(ctx context.Context, userID string, cartEntity cart.Cart) (response.Cart, error) {
+	if cartEntity.IsShared() && userID != "" && !cartEntity.HasMember(userID) {
+		if err := s.cartRepository.JoinCart(ctx, nil, cartEntity.ID.String(), userID); err != nil {
+			return response.Cart{}, cart.ErrorUpdateCart
+		}
+	}
+
+	return s.cartResponse(ctx, userID, cartEntity)
+}
+
+func (s *cartService) GetCart(ctx context.Context, userID string, cartID string) (response.Cart, error) {
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	return s.cartResponse(ctx, userID, cartEntity)
+}
+
+func (s *cartService) UpdateCart(ctx context.Context, userID string, cartID string, req request.UpdateCart) (response.Cart, error) {
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	orderType, err := transaction.NewOrderType(req.OrderType)
+	if err != nil {
+		return response.Cart{}, err
+	}
+	cartEntity.OrderType = orderType
+
+	return s.saveCart(ctx, userID, cartEntity)
+}
+
+// AddCartLine adds a menu to the cart. A menu already in the cart gets its
+// quantity raised instead of a second line.
+func (s *cartService) AddCartLine(ctx context.Context, userID string, cartID string, req request.AddCartLine) (response.Cart, error) {
+	if err := cart.ValidateQuantity(req.Quantity); err != nil {
+		return response.Cart{}, err
+	}
+
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	menuEntity, err := s.menuRepository.GetMenuByID(ctx, nil, req.MenuID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Cart{}, menu.ErrorMenuNotFound
+		}
+		return response.Cart{}, err
+	}
+	if err = menuEntity.CheckAvailableAt(s.clock.Now()); err != nil {
+		return response.Cart{}, fmt.Errorf("%w: %s", err, menuEntity.Name)
+	}
+
+	line := cart.Line{
+		ID:       identity.NewID(uuid.New()),
+		CartID:   cartEntity.ID,
+		MenuID:   menuEntity.ID,
+		Quantity: req.Quantity,
+	}
+	if addedBy, err := uuid.Parse(userID); err == nil {
+		line.AddedBy = identity.NewID(addedBy)
+	}
+	if err = s.cartRepository.AddCartLine(ctx, nil, line); err != nil {
+		return response.Cart{}, cart.ErrorUpdateCart
+	}
+
+	return s.reloadCart(ctx, userID, cartID)
+}
+
+func (s *cartService) UpdateCartLine(ctx context.Context, userID string, cartID string, lineID string, req request.UpdateCartLine) (response.Cart, error) {
+	if err := cart.ValidateQuantity(req.Quantity); err != nil {
+		return response.Cart{}, err
+	}
+
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	if _, ok := cartEntity.Line(lineID); !ok {
+		return response.Cart{}, cart.ErrorCartLineNotFound
+	}
+
+	if err = s.cartRepository.UpdateCartLineQuantity(ctx, nil, lineID, req.Quantity); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Cart{}, cart.ErrorCartLineNotFound
+		}
+		return response.Cart{}, cart.ErrorUpdateCart
+	}
+
+	return s.reloadCart(ctx, userID, cartID)
+}
+
+func (s *cartService) RemoveCartLine(ctx context.Context, userID string, cartID string, lineID string) (response.Cart, error) {
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	if _, ok := cartEntity.Line(lineID); !ok {
+		return response.Cart{}, cart.ErrorCartLineNotFound
+	}
+
+	if err = s.cartRepository.DeleteCartLine(ctx, nil, lineID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Cart{}, cart.ErrorCartLineNotFound
+		}
+		return response.Cart{}, cart.ErrorUpdateCart
+	}
+
+	return s.reloadCart(ctx, userID, cartID)
+}
+
+// ApplyCartVoucher keeps the voucher only if it applies to the cart as it is
+// now. It is checked again on every later change.
+func (s *cartService) ApplyCartVoucher(ctx context.Context, userID string, cartID string, req request.ApplyCartVoucher) (response.Cart, error) {
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	contents, err := s.revalidate(ctx, cartEntity)
+	if err != nil {
+		return response.Cart{}, err
+	}
+	if len(contents.orders) == 0 {
+		return response.Cart{}, cart.ErrorCartEmpty
+	}
+
+	if _, err = s.orderService.CalculatePriceBreakdown(ctx, userID, contents.orders, req.VoucherCode, cartEntity.OrderType.Type, nil); err != nil {
+		return response.Cart{}, err
+	}
+
+	cartEntity.VoucherCode = promotion.NormalizeVoucherCode(req.VoucherCode)
+	return s.saveCart(ctx, userID, cartEntity)
+}
+
+func (s *cartService) RemoveCartVoucher(ctx context.Context, userID string, cartID string) (response.Cart, error) {
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	cartEntity.VoucherCode = ""
+	return s.saveCart(ctx, userID, cartEntity)
+}
+
+// CheckoutCart turns the cart into a transaction through CreateTransaction,
+// which prices and validates the orders once more. The cart is claimed first
+// so guests sharing it cannot check it out twice, and handed back if the
+// transaction cannot be created.
+func (s *cartService) CheckoutCart(ctx context.Context, userID string, cartID string, req request.CheckoutCart) (response.TransactionCreate, error) {
+	cartEntity, err := s.getOpenCart(ctx, userID, cartID)
+	if err != nil {
+		return response.TransactionCreate{}, err
+	}
+
+	contents, err := s.revalidate(ctx, cartEntity)
+	if err != nil {
+		return response.TransactionCreate{}, err
+	}
+	if len(contents.orders) == 0 {
+		return response.TransactionCreate{}, cart.ErrorCartEmpty
+	}
+	if len(contents.orders) != len(contents.lines) {
+		return response.TransactionCreate{}, cart.ErrorCartHasUnavailableItems
+	}
+
+	now := s.clock.Now()
+	if err = s.cartRepository.CheckOutCart(ctx, nil, cartID, now); err != nil {
+		return response.TransactionCreate{}, err
+	}
+
+	tableID := req.TableID
+	if cartEntity.IsShared() {
+		tableID = cartEntity.TableID.String()
+	}
+
+	createdTransaction, err := s.transactionService.CreateTransaction(ctx, userID, request.TransactionCreate{
+		OrderType:       cartEntity.OrderType.Type,
+		TableID:         tableID,
+		PickupAt:        req.PickupAt,
+		Orders:          contents.orders,
+		PaymentProvider: req.PaymentProvider,
+		VoucherCode:     cartEntity.VoucherCode,
+		GuestName:       req.GuestName,
+		GuestPhone:      req.GuestPhone,
+	})
+	if err != nil {
+		if reopenErr := s.cartRepository.ReopenCart(ctx, nil, cartID); reopenErr != nil {
+			log.Printf("failed to reopen cart %s: %v", cartID, reopenErr)
+		}
+		return response.TransactionCreate{}, err
+	}
+
+	if err = s.cartRepository.LinkCartTransaction(ctx, nil, cartID, createdTransaction.TransactionID); err != nil {
+		log.Printf("failed to link cart %s to transaction %s: %v", cartID, createdTransaction.TransactionID, err)
+	}
+
+	return createdTransaction, nil
+}
+
+// getOpenCart loads a cart the user may still change.
+func (s *cartService) getOpenCart(ctx context.Context, userID string, cartID string) (cart.Cart, error) {
+	cartEntity, err := s.cartRepository.GetCartByID(ctx, nil, cartID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return cart.Cart{}, cart.ErrorCartNotFound
+		}
+		return cart.Cart{}, err
+	}
+
+	if !cartEntity.CanAccess(userID, application.ActorFromContext(ctx).TableID) {
+		return cart.Cart{}, cart.ErrorNotCartMember
+	}
+
+	if err = cartEntity.CheckOpen(s.clock.Now()); err != nil {
+		return cart.Cart{}, err
+	}
+
+	return cartEntity, nil
+}
+
+// reloadCart reads the cart back after one of its lines changed and keeps
+// it from expiring.
+func (s *cartService) reloadCart(ctx context.Context, userID string, cartID string) (response.Cart, error) {
+	cartEntity, err := s.cartRepository.GetCartByID(ctx, nil, cartID)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	return s.saveCart(ctx, userID, cartEntity)
+}
+
+// saveCart stores a change to the cart and pushes its expiry back, since
+// carts expire after a while without changes.
+func (s *cartService) saveCart(ctx context.Context, userID string, cartEntity cart.Cart) (response.Cart, error) {
+	now := s.clock.Now()
+	cartEntity.UpdatedAt = now
+	cartEntity.ExpiresAt = now.Add(s.idleTimeout)
+
+	updatedCart, err := s.cartRepository.UpdateCart(ctx, nil, cartEntity)
+	if err != nil {
+		if errors.Is(err, cart.ErrorCartCheckedOut) {
+			return response.Cart{}, err
+		}
+		return response.Cart{}, cart.ErrorUpdateCart
+	}
+
+	return s.cartResponse(ctx, userID, updatedCart)
+}
+
+// revalidate checks each line against its menu as it is now. Lines whose
+// menu was switched off, is out of its schedule or was deleted are kept in
+// the cart but left out of the orders.
+func (s *cartService) revalidate(ctx context.Context, cartEntity cart.Cart) (cartContents, error) {
+	now := s.clock.Now()
+	contents := cartContents{
+		lines:  make([]response.CartLine, 0, len(cartEntity.Lines)),
+		orders: make([]request.Order, 0, len(cartEntity.Lines)),
+	}
+
+	for _, line := range cartEntity.Lines {
+		lineResponse := response.CartLine{
+			ID:       line.ID.String(),
+			Menu:     response.MenuForTransaction{ID: line.MenuID.String()},
+			Quantity: line.Quantity,
+		}
+
+		menuEntity, err := s.menuRepository.GetMenuByID(ctx, nil, line.MenuID.String())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return cartContents{}, err
+		}
+		if err != nil {
+			err = menu.ErrorMenuNotFound
+		} else {
+			lineResponse.Menu.Name = menuEntity.Name
+			lineResponse.Menu.Price = menuEntity.PriceAt(now).Price.String()
+			err = menuEntity.CheckAvailableAt(now)
+		}
+
+		if err != nil {
+			lineResponse.Reason = err.Error()
+		} else {
+			lineResponse.IsAvailable = true
+			contents.orders = append(contents.orders, request.Order{
+				MenuID:   line.MenuID.String(),
+				Quantity: line.Quantity,
+			})
+		}
+		contents.lines = append(contents.lines, lineResponse)
+	}
+
+	return contents, nil
+}
+
+func (s *cartService) cartResponse(ctx context.Context, userID string, cartEntity cart.Cart) (response.Cart, error) {
+	contents, err := s.revalidate(ctx, cartEntity)
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	result := response.Cart{
+		ID:          cartEntity.ID.String(),
+		IsShared:    cartEntity.IsShared(),
+		OrderType:   cartEntity.OrderType.Type,
+		Lines:       contents.lines,
+		VoucherCode: cartEntity.VoucherCode,
+		ExpiresAt:   cartEntity.ExpiresAt,
+	}
+	if cartEntity.IsShared() {
+		result.TableID = cartEntity.TableID.String()
+	}
+	if len(contents.orders) == 0 {
+		return result, nil
+	}
+
+	pricing, err := s.orderService.CalculatePriceBreakdown(ctx, userID, contents.orders, cartEntity.VoucherCode, cartEntity.OrderType.Type, nil)
+	if err != nil && cartEntity.VoucherCode != "" && isVoucherError(err) {
+		result.VoucherError = err.Error()
+		pricing, err = s.orderService.CalculatePriceBreakdown(ctx, userID, contents.orders, "", cartEntity.OrderType.Type, nil)
+	}
+	if err != nil {
+		return response.Cart{}, err
+	}
+
+	pricingResponse := priceBreakdownResponse(pricing)
+	result.Pricing = &pricingResponse
+	result.CanCheckout = len(contents.orders) == len(contents.lines) && result.VoucherError == ""
+
+	return result, nil
+}
+
+// isVoucherError tells whether pricing failed because of the voucher rather
+// than the cart, in which case the cart is still priced without it.
+func isVoucherError(err error) bool {
+	return errors.Is(err, promotion.ErrorVoucherNotFound) ||
+		errors.Is(err, promotion.ErrorVoucherNotApplicable) ||
+		errors.Is(err, promotion.ErrorUsageLimitReached) ||
+		errors.Is(err, promotion.ErrorUserLimitReached) ||
+		errors.Is(err, promotion.ErrorAccountRequired)
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/shift"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/validation"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type (
+	CashierService interface {
+		OpenShift(ctx context.Context, cashierID string, req request.OpenShift) (response.Shift, error)
+		GetCurrentShift(ctx context.Context, cashierID string) (response.Shift, error)
+		CloseShift(ctx context.Context, cashierID string, req request.CloseShift) (response.Shift, error)
+		ConfirmPayment(ctx context.Context, cashierID string, transactionID string, req request.ConfirmPayment) (response.ConfirmPayment, error)
+	}
+
+	cashierService struct {
+		shiftRepository        shift.Repository
+		transactionRepository  transaction.Repository
+		paymentGatewayRegistry port.PaymentGatewayRegistry
+		clock                  shared.Clock
+		transaction            interface{}
+	}
+)
+
+func NewCashierService(
+	shiftRepository shift.Repository,
+	transactionRepository transaction.Repository,
+	paymentGatewayRegistry port.PaymentGatewayRegistry,
+	clock shared.Clock,
+	transaction interface{},
+) CashierService {
+	return &cashierService{
+		shiftRepository:        shiftRepository,
+		transactionRepository:  transactionRepository,
+		paymentGatewayRegistry: paymentGatewayRegistry,
+		clock:                  clock,
+		transaction:            transaction,
+	}
+}
+
+func (s *cashierService) OpenShift(ctx context.Context, cashierID string, req request.OpenShift) (response.Shift, error) {
+	_, err := s.shiftRepository.GetOpenShiftByCashierID(ctx, nil, cashierID)
+	if err == nil {
+		return response.Shift{}, shift.ErrorShiftAlreadyOpen
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.Shift{}, err
+	}
+
+	openingFloat := decimal.Zero
+	if req.OpeningFloat != "" {
+		openingFloat, err = decimal.NewFromString(req.OpeningFloat)
+		if err != nil {
+			return response.Shift{}, err
+		}
+	}
+	price, err := shared.NewPrice(openingFloat)
+	if err != nil {
+		return response.Shift{}, err
+	}
+
+	openedShift, err := s.shiftRepository.OpenShift(ctx, nil, shift.Shift{
+		CashierID:    identity.NewID(uuid.MustParse(cashierID)),
+		OpeningFloat: price,
+		OpenedAt:     s.clock.Now(),
+	})
+	if err != nil {
+		return response.Shift{}, shift.ErrorOpenShift
+	}
+
+	return shiftResponse(openedShift, shift.Totals{}), nil
+}
+
+func (s *cashierService) GetCurrentShift(ctx context.Context, cashierID string) (response.Shift, error) {
+	currentShift, err := s.getOpenShift(ctx, nil, cashierID)
+	if err != nil {
+		return response.Shift{}, err
+	}
+
+	totals, err := s.shiftRepository.GetShiftTotals(ctx, nil, currentShift.ID.String())
+	if err != nil {
+		return response.Shift{}, shift.ErrorGetShiftTotals
+	}
+
+	return shiftResponse(currentShift, totals), nil
+}
+
+// CloseShift closes the cashier's open shift and reports the difference
+// between the cash counted in the drawer and what the shift expected.
+func (s *cashierService) CloseShift(ctx context.Context, cashierID string, req request.CloseShift) (response.Shift, error) {
+	currentShift, err := s.getOpenShift(ctx, nil, cashierID)
+	if err != nil {
+		return response.Shift{}, err
+	}
+
+	countedCash, err := decimal.NewFromString(req.CountedCash)
+	if err != nil {
+		return response.Shift{}, err
+	}
+	price, err := shared.NewPrice(countedCash)
+	if err != nil {
+		return response.Shift{}, err
+	}
+
+	totals, err := s.shiftRepository.GetShiftTotals(ctx, nil, currentShift.ID.String())
+	if err != nil {
+		return response.Shift{}, shift.ErrorGetShiftTotals
+	}
+
+	closedShift, err := s.shiftRepository.CloseShift(ctx, nil, currentShift.ID.String(), price)
+	if err != nil {
+		return response.Shift{}, shift.ErrorCloseShift
+	}
+
+	return shiftResponse(closedShift, totals), nil
+}
+
+// ConfirmPayment takes payment at the counter for a transaction created with
+// the counter provider. The confirmation goes through the counter gateway's
+// HookPayment, so the queue code is assigned exactly as for a webhook.
+func (s *cashierService) ConfirmPayment(ctx context.Context, cashierID string, transactionID string, req request.ConfirmPayment) (response.ConfirmPayment, error) {
+	method, err := shift.NewMethod(req.Method)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	tendered := decimal.Zero
+	if req.AmountTendered != "" {
+		tendered, err = decimal.NewFromString(req.AmountTendered)
+		if err != nil {
+			return response.ConfirmPayment{}, err
+		}
+	}
+
+	paymentGateway, err := s.paymentGatewayRegistry.Gateway(transaction.PaymentProviderCounter)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	currentShift, err := s.getOpenShift(ctx, tx, cashierID)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	// Locked so a webhook or another cashier cannot settle it between the
+	// status check and the update.
+	if err = s.transactionRepository.LockTransaction(ctx, tx, transactionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.ConfirmPayment{}, transaction.ErrorTransactionNotFound
+		}
+		return response.ConfirmPayment{}, err
+	}
+
+	retrievedTransaction, err := s.transactionRepository.GetDetailedTransactionByID(ctx, tx, transactionID)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	transactionEntity := retrievedTransaction.Transaction
+	if transactionEntity.PaymentProvider != transaction.PaymentProviderCounter {
+		return response.ConfirmPayment{}, transaction.ErrorNotCounterPayment
+	}
+	if transactionEntity.Payment.Status != transaction.PaymentStatusPending {
+		return response.ConfirmPayment{}, fmt.Errorf("%w: %s", transaction.ErrorPaymentAlreadyFinal, transactionEntity.Payment.Status)
+	}
+
+	amountTendered, change, err := method.Settle(transactionEntity.TotalPrice, tendered)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	counterPayment, err := s.shiftRepository.CreateCounterPayment(ctx, tx, shift.CounterPayment{
+		TransactionID:  transactionEntity.ID,
+		ShiftID:        currentShift.ID,
+		CashierID:      currentShift.CashierID,
+		Method:         method,
+		Amount:         transactionEntity.TotalPrice,
+		AmountTendered: amountTendered,
+		Change:         change,
+	})
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	err = paymentGateway.HookPayment(ctx, tx, transactionEntity.ID.ID, map[string]interface{}{
+		"order_id":           transactionEntity.ID.String(),
+		"transaction_id":     counterPayment.ID.String(),
+		"transaction_status": transaction.PaymentStatusSettlement,
+		"gross_amount":       transactionEntity.TotalPrice.Price.StringFixed(2),
+	})
+	if err != nil {
+		return response.ConfirmPayment{}, fmt.Errorf("failed to confirm payment: %w", err)
+	}
+
+	paidTransaction, err := s.transactionRepository.GetDetailedTransactionByID(ctx, tx, transactionID)
+	if err != nil {
+		return response.ConfirmPayment{}, err
+	}
+
+	return response.ConfirmPayment{
+		TransactionID:  transactionEntity.ID.String(),
+		QueueCode:      paidTransaction.Transaction.QueueCode.Code,
+		ShiftID:        currentShift.ID.String(),
+		Method:         method.Method,
+		TotalPrice:     transactionEntity.TotalPrice.Price.String(),
+		AmountTendered: amountTendered.Price.String(),
+		Change:         change.Price.String(),
+	}, nil
+}
+
+func (s *cashierService) getOpenShift(ctx context.Context, tx interface{}, cashierID string) (shift.Shift, error) {
+	currentShift, err := s.shiftRepository.GetOpenShiftByCashierID(ctx, tx, cashierID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return shift.Shift{}, shift.ErrorShiftNotFound
+		}
+		return shift.Shift{}, err
+	}
+	return currentShift, nil
+}
+
+func shiftResponse(shiftEntity shift.Shift, totals shift.Totals) response.Shift {
+	shiftResponse := response.Shift{
+		ID:           shiftEntity.ID.String(),
+		CashierID:    shiftEntity.CashierID.String(),
+		OpeningFloat: shiftEntity.OpeningFloat.Price.String(),
+		CashTotal:    totals.Cash.Price.String(),
+		CardTotal:    totals.Card.Price.String(),
+		Transactions: totals.Transactions,
+		ExpectedCash: shiftEntity.ExpectedCash(totals).Price.String(),
+		OpenedAt:     shiftEntity.OpenedAt,
+		ClosedAt:     shiftEntity.ClosedAt,
+	}
+	if shiftEntity.CountedCash != nil {
+		shiftResponse.CountedCash = shiftEntity.CountedCash.Price.String()
+		shiftResponse.Variance = shiftEntity.CountedCash.Price.Sub(shiftEntity.ExpectedCash(totals).Price).String()
+	}
+	return shiftResponse
+}
@@ -2,23 +2,28 @@ package service
 
 import (
 	"context"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
 	"fp-kpl/application/response"
 	"fp-kpl/domain/menu/category"
+	"fp-kpl/infrastructure/database/validation"
 )
 
 type (
 	CategoryService interface {
 		GetAllCategories(ctx context.Context) ([]response.Category, error)
 		GetCategoryByID(ctx context.Context, id string) (response.Category, error)
+		UpdateCategorySchedule(ctx context.Context, id string, req request.UpdateSchedule) (response.Category, error)
 	}
 
 	categoryService struct {
 		categoryRepository category.Repository
+		transaction        interface{}
 	}
 )
 
-func NewCategoryService(categoryRepository category.Repository) CategoryService {
-	return &categoryService{categoryRepository: categoryRepository}
+func NewCategoryService(categoryRepository category.Repository, transaction interface{}) CategoryService {
+	return &categoryService{categoryRepository: categoryRepository, transaction: transaction}
 }
 
 func (s *categoryService) GetAllCategories(ctx context.Context) ([]response.Category, error) {
@@ -44,8 +49,50 @@ func (s *categoryService) GetCategoryByID(ctx context.Context, id string) (respo
 		return response.Category{}, category.ErrorGetCategoryByID
 	}
 
+	return categoryResponse(retrievedCategory), nil
+}
+
+// UpdateCategorySchedule replaces the schedule shared by every menu in the
+// category that has no schedule of its own.
+func (s *categoryService) UpdateCategorySchedule(ctx context.Context, id string, req request.UpdateSchedule) (response.Category, error) {
+	windows, err := timeWindows(req.Windows)
+	if err != nil {
+		return response.Category{}, err
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.Category{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.Category{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	if _, err = s.categoryRepository.GetCategoryByID(ctx, tx, id); err != nil {
+		return response.Category{}, category.ErrorCategoryNotFound
+	}
+
+	updatedCategory, err := s.categoryRepository.UpdateCategorySchedule(ctx, tx, id, windows)
+	if err != nil {
+		return response.Category{}, category.ErrorUpdateSchedule
+	}
+
+	return categoryResponse(updatedCategory), nil
+}
+
+func categoryResponse(categoryEntity category.Category) response.Category {
 	return response.Category{
-		ID:   retrievedCategory.ID.String(),
-		Name: retrievedCategory.Name,
-	}, nil
+		ID:       categoryEntity.ID.String(),
+		Name:     categoryEntity.Name,
+		Schedule: timeWindowResponses(categoryEntity.Schedule),
+	}
 }
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/device"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/station"
+	"fp-kpl/domain/table"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceLastSeenInterval is how stale a device's last-seen time may get
+// before a request refreshes it, so busy screens do not write on every call.
+const DeviceLastSeenInterval = time.Minute
+
+type (
+	DeviceService interface {
+		CreateDevice(ctx context.Context, req request.CreateDevice) (response.CreatedDevice, error)
+		GetAllDevices(ctx context.Context) ([]response.Device, error)
+		RevokeDevice(ctx context.Context, id string) (response.Device, error)
+		AuthenticateDevice(ctx context.Context, key string) (application.Actor, error)
+	}
+
+	deviceService struct {
+		deviceRepository  device.Repository
+		stationRepository station.Repository
+		tableRepository   table.Repository
+		clock             shared.Clock
+	}
+)
+
+func NewDeviceService(
+	deviceRepository device.Repository,
+	stationRepository station.Repository,
+	tableRepository table.Repository,
+	clock shared.Clock,
+) DeviceService {
+	return &deviceService{
+		deviceRepository:  deviceRepository,
+		stationRepository: stationRepository,
+		tableRepository:   tableRepository,
+		clock:             clock,
+	}
+}
+
+// CreateDevice registers a device and returns its API key. Only the key's
+// hash is stored, so the key cannot be shown again.
+func (s *deviceService) CreateDevice(ctx context.Context, req request.CreateDevice) (response.CreatedDevice, error) {
+	role, err := device.NewRole(req.Role)
+	if err != nil {
+		return response.CreatedDevice{}, err
+	}
+
+	deviceEntity := device.Device{
+		Name: req.Name,
+		Role: role,
+	}
+
+	if req.StationID != "" {
+		retrievedStation, err := s.stationRepository.GetStationByID(ctx, nil, req.StationID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return response.CreatedDevice{}, station.ErrorStationNotFound
+			}
+			return response.CreatedDevice{}, err
+		}
+		deviceEntity.StationID = retrievedStation.ID
+	}
+
+	if req.TableID != "" {
+		retrievedTable, err := s.tableRepository.GetTableByID(ctx, nil, req.TableID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return response.CreatedDevice{}, table.ErrorTableNotFound
+			}
+			return response.CreatedDevice{}, err
+		}
+		deviceEntity.TableID = retrievedTable.ID
+	}
+
+	key, keyPrefix, err := device.GenerateKey()
+	if err != nil {
+		return response.CreatedDevice{}, device.ErrorCreateDevice
+	}
+	deviceEntity.ID = identity.NewID(uuid.New())
+	deviceEntity.KeyPrefix = keyPrefix
+	deviceEntity.KeyHash = device.HashKey(key)
+
+	createdDevice, err := s.deviceRepository.CreateDevice(ctx, nil, deviceEntity)
+	if err != nil {
+		return response.CreatedDevice{}, device.ErrorCreateDevice
+	}
+
+	return response.CreatedDevice{
+		Device: deviceResponse(createdDevice),
+		Key:    key,
+	}, nil
+}
+
+func (s *deviceService) GetAllDevices(ctx context.Context) ([]response.Device, error) {
+	retrievedDevices, err := s.deviceRepository.GetAllDevices(ctx, nil)
+	if err != nil {
+		return nil, device.ErrorGetAllDevices
+	}
+
+	devices := make([]response.Device, 0, len(retrievedDevices))
+	for _, retrievedDevice := range retrievedDevices {
+		devices = append(devices, deviceResponse(retrievedDevice))
+	}
+
+	return devices, nil
+}
+
+// RevokeDevice disables a device key for good. Revoked devices stay listed so
+// the status transitions they made can still be traced.
+func (s *deviceService) RevokeDevice(ctx context.Context, id string) (response.Device, error) {
+	revokedDevice, err := s.deviceRepository.RevokeDevice(ctx, nil, id, s.clock.Now())
+	if err != nil {
+		if errors.Is(err, device.ErrorDeviceNotFound) {
+			return response.Device{}, err
+		}
+		return response.Device{}, device.ErrorRevokeDevice
+	}
+
+	return deviceResponse(revokedDevice), nil
+}
+
+// AuthenticateDevice resolves an API key to the device using it and notes
+// when the device was last seen.
+func (s *deviceService) AuthenticateDevice(ctx context.Context, key string) (application.Actor, error) {
+	if !device.IsKey(key) {
+		return application.Actor{}, device.ErrorInvalidDeviceKey
+	}
+
+	retrievedDevice, err := s.deviceRepository.GetDeviceByKeyHash(ctx, nil, device.HashKey(key))
+	if err != nil {
+		if errors.Is(err, device.ErrorDeviceNotFound) {
+			return application.Actor{}, device.ErrorInvalidDeviceKey
+		}
+		return application.Actor{}, err
+	}
+
+	if retrievedDevice.IsRevoked() {
+		return application.Actor{}, device.ErrorDeviceRevoked
+	}
+
+	now := s.clock.Now()
+	if retrievedDevice.LastSeenAt == nil || now.Sub(*retrievedDevice.LastSeenAt) >= DeviceLastSeenInterval {
+		if err = s.deviceRepository.UpdateLastSeen(ctx, nil, retrievedDevice.ID.String(), now); err != nil {
+			log.Printf("failed to update last seen of device %s: %v", retrievedDevice.ID.String(), err)
+		}
+	}
+
+	actor := application.Actor{
+		DeviceID: retrievedDevice.ID.String(),
+		Role:     retrievedDevice.Role.Name,
+	}
+	if !retrievedDevice.StationID.IsEmpty() {
+		actor.StationID = retrievedDevice.StationID.String()
+	}
+	if !retrievedDevice.TableID.IsEmpty() {
+		actor.TableID = retrievedDevice.TableID.String()
+	}
+
+	return actor, nil
+}
+
+func deviceResponse(deviceEntity device.Device) response.Device {
+	deviceResponse := response.Device{
+		ID:         deviceEntity.ID.String(),
+		Name:       deviceEntity.Name,
+		Role:       deviceEntity.Role.Name,
+		KeyPrefix:  deviceEntity.KeyPrefix,
+		LastSeenAt: deviceEntity.LastSeenAt,
+		RevokedAt:  deviceEntity.RevokedAt,
+		CreatedAt:  deviceEntity.CreatedAt,
+	}
+	if !deviceEntity.StationID.IsEmpty() {
+		deviceResponse.StationID = deviceEntity.StationID.String()
+	}
+	if !deviceEntity.TableID.IsEmpty() {
+		deviceResponse.TableID = deviceEntity.TableID.String()
+	}
+	return deviceResponse
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"time"
+)
+
+type (
+	DisplayService interface {
+		GetBoard(ctx context.Context) (response.DisplayBoard, error)
+	}
+
+	displayService struct {
+		transactionRepository transaction.Repository
+		clock                 shared.Clock
+		highlightDuration     time.Duration
+	}
+)
+
+func NewDisplayService(
+	transactionRepository transaction.Repository,
+	clock shared.Clock,
+	highlightDuration time.Duration,
+) DisplayService {
+	if highlightDuration <= 0 {
+		highlightDuration = transaction.DefaultDisplayHighlightDuration
+	}
+	return &displayService{
+		transactionRepository: transactionRepository,
+		clock:                 clock,
+		highlightDuration:     highlightDuration,
+	}
+}
+
+// GetBoard lists the queue codes of paid orders that have not been served,
+// which covers today's queue and orders carried over the business-day cutoff,
+// up to transaction.DisplayBoardLimit codes.
+func (s *displayService) GetBoard(ctx context.Context) (response.DisplayBoard, error) {
+	transactions, err := s.transactionRepository.GetDisplayBoardTransactions(ctx, nil, transaction.DisplayBoardLimit)
+	if err != nil {
+		return response.DisplayBoard{}, err
+	}
+
+	now := s.clock.Now()
+	board := transaction.NewDisplayBoard(transactions, now, s.highlightDuration)
+
+	return response.DisplayBoard{
+		Preparing: displayEntryResponses(board.Preparing),
+		Ready:     displayEntryResponses(board.Ready),
+		UpdatedAt: now,
+	}, nil
+}
+
+func displayEntryResponses(entries []transaction.DisplayEntry) []response.DisplayEntry {
+	responses := make([]response.DisplayEntry, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, response.DisplayEntry{
+			QueueCode:   entry.QueueCode,
+			Highlighted: entry.Highlighted,
+		})
+	}
+	return responses
+}
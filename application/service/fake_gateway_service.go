@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/port"
+)
+
+type (
+	FakeGatewayService interface {
+		GetPayment(ctx context.Context, token string) (response.FakePayment, error)
+		// CompletePayment returns the payment even when the notification
+		// could not be delivered, so the page can show what was attempted.
+		CompletePayment(ctx context.Context, token string, action string) (response.FakePayment, error)
+	}
+
+	fakeGatewayService struct {
+		fakePaymentGateway port.FakePaymentGatewayPort
+	}
+)
+
+func NewFakeGatewayService(fakePaymentGateway port.FakePaymentGatewayPort) FakeGatewayService {
+	return &fakeGatewayService{fakePaymentGateway: fakePaymentGateway}
+}
+
+func (s *fakeGatewayService) GetPayment(ctx context.Context, token string) (response.FakePayment, error) {
+	payment, err := s.fakePaymentGateway.GetPayment(token)
+	if err != nil {
+		return response.FakePayment{}, err
+	}
+
+	return fakePaymentResponse(payment), nil
+}
+
+func (s *fakeGatewayService) CompletePayment(ctx context.Context, token string, action string) (response.FakePayment, error) {
+	payment, err := s.fakePaymentGateway.CompletePayment(ctx, token, action)
+	return fakePaymentResponse(payment), err
+}
+
+func fakePaymentResponse(payment port.FakePayment) response.FakePayment {
+	return response.FakePayment{
+		Token:       payment.Token,
+		OrderID:     payment.OrderID,
+		Status:      payment.Status,
+		GrossAmount: payment.GrossAmount.StringFixed(2),
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/validation"
+	"fp-kpl/platform/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	FeedbackService interface {
+		SubmitFeedback(ctx context.Context, userID string, req request.SubmitFeedback) (response.Feedback, error)
+		GetAllFeedback(ctx context.Context, req request.GetAllFeedback) (pagination.ResponseWithData, error)
+	}
+
+	feedbackService struct {
+		feedbackRepository    feedback.Repository
+		transactionRepository transaction.Repository
+		clock                 shared.Clock
+		transaction           interface{}
+		submissionWindow      time.Duration
+	}
+)
+
+func NewFeedbackService(
+	feedbackRepository feedback.Repository,
+	transactionRepository transaction.Repository,
+	clock shared.Clock,
+	transaction interface{},
+	submissionWindow time.Duration,
+) FeedbackService {
+	if submissionWindow <= 0 {
+		submissionWindow = feedback.DefaultSubmissionWindow
+	}
+
+	return &feedbackService{
+		feedbackRepository:    feedbackRepository,
+		transactionRepository: transactionRepository,
+		clock:                 clock,
+		transaction:           transaction,
+		submissionWindow:      submissionWindow,
+	}
+}
+
+// SubmitFeedback records the customer's feedback on a transaction they
+// ordered, once it is served and within the submission window. Menu ratings
+// may only cover menus of that transaction.
+func (s *feedbackService) SubmitFeedback(ctx context.Context, userID string, req request.SubmitFeedback) (response.Feedback, error) {
+	retrievedData, err := s.transactionRepository.GetDetailedTransactionByID(ctx, nil, req.TransactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Feedback{}, transaction.ErrorTransactionNotFound
+		}
+		return response.Feedback{}, err
+	}
+
+	now := s.clock.Now()
+	if err = feedback.CheckSubmittable(retrievedData.Transaction, userID, now, s.submissionWindow); err != nil {
+		return response.Feedback{}, err
+	}
+
+	rating, err := feedback.NewRating(req.Rating)
+	if err != nil {
+		return response.Feedback{}, err
+	}
+
+	menuRatings := make([]feedback.MenuRating, 0, len(req.MenuRatings))
+	for _, reqMenuRating := range req.MenuRatings {
+		menuRating, err := feedback.NewRating(reqMenuRating.Rating)
+		if err != nil {
+			return response.Feedback{}, err
+		}
+
+		menuID, err := uuid.Parse(reqMenuRating.MenuID)
+		if err != nil {
+			return response.Feedback{}, feedback.ErrorInvalidMenuRating
+		}
+
+		menuRatings = append(menuRatings, feedback.MenuRating{
+			MenuID: identity.NewID(menuID),
+			Rating: menuRating,
+		})
+	}
+
+	feedbackEntity := feedback.Feedback{
+		ID:            identity.NewID(uuid.New()),
+		TransactionID: retrievedData.Transaction.ID,
+		UserID:        retrievedData.Transaction.UserID,
+		Rating:        rating,
+		Comment:       req.Comment,
+		MenuRatings:   menuRatings,
+		Timestamp: shared.Timestamp{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+
+	orderedMenuIDs := make([]identity.ID, 0, len(retrievedData.Orders))
+	menuNames := make(map[string]string, len(retrievedData.Orders))
+	for _, orderQuery := range retrievedData.Orders {
+		orderedMenuIDs = append(orderedMenuIDs, orderQuery.Order.MenuID)
+		menuNames[orderQuery.Order.MenuID.String()] = orderQuery.Menu.Name
+	}
+	if err = feedbackEntity.ValidateMenuRatings(orderedMenuIDs); err != nil {
+		return response.Feedback{}, err
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.Feedback{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.Feedback{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	createdFeedback, err := s.feedbackRepository.CreateFeedback(ctx, tx, feedbackEntity)
+	if err != nil {
+		if errors.Is(err, feedback.ErrorFeedbackAlreadySubmitted) {
+			return response.Feedback{}, err
+		}
+		return response.Feedback{}, feedback.ErrorCreateFeedback
+	}
+
+	return feedbackResponse(feedback.Query{
+		Feedback:  createdFeedback,
+		QueueCode: retrievedData.Transaction.QueueCode.Code,
+		MenuNames: menuNames,
+	}), nil
+}
+
+// GetAllFeedback lists submitted feedback, newest first.
+func (s *feedbackService) GetAllFeedback(ctx context.Context, req request.GetAllFeedback) (pagination.ResponseWithData, error) {
+	if req.MinRating > 0 && req.MaxRating > 0 && req.MinRating > req.MaxRating {
+		return pagination.ResponseWithData{}, feedback.ErrorInvalidRating
+	}
+
+	retrievedData, err := s.feedbackRepository.GetAllFeedback(ctx, nil, feedback.Filter{
+		MenuID:     req.MenuID,
+		MinRating:  req.MinRating,
+		MaxRating:  req.MaxRating,
+		From:       req.From,
+		To:         req.To,
+		HasComment: req.HasComment,
+	}, req.Request)
+	if err != nil {
+		return pagination.ResponseWithData{}, feedback.ErrorGetAllFeedback
+	}
+
+	data := make([]any, 0, len(retrievedData.Data))
+	for _, item := range retrievedData.Data {
+		feedbackQuery, ok := item.(feedback.Query)
+		if !ok {
+			return pagination.ResponseWithData{}, feedback.ErrorGetAllFeedback
+		}
+		data = append(data, feedbackResponse(feedbackQuery))
+	}
+
+	return pagination.ResponseWithData{
+		Data:     data,
+		Response: retrievedData.Response,
+	}, nil
+}
+
+func feedbackResponse(feedbackQuery feedback.Query) response.Feedback {
+	feedbackEntity := feedbackQuery.Feedback
+
+	menuRatings := make([]response.FeedbackMenuRating, 0, len(feedbackEntity.MenuRatings))
+	for _, menuRating := range feedbackEntity.MenuRatings {
+		menuRatings = append(menuRatings, response.FeedbackMenuRating{
+			MenuID:   menuRating.MenuID.String(),
+			MenuName: feedbackQuery.MenuNames[menuRating.MenuID.String()],
+			Rating:   menuRating.Rating.Value,
+		})
+	}
+
+	return response.Feedback{
+		ID:            feedbackEntity.ID.String(),
+		TransactionID: feedbackEntity.TransactionID.String(),
+		QueueCode:     feedbackQuery.QueueCode,
+		UserID:        feedbackEntity.UserID.String(),
+		UserName:      feedbackQuery.UserName,
+		Rating:        feedbackEntity.Rating.Value,
+		MenuRatings:   menuRatings,
+		Comment:       feedbackEntity.Comment,
+		CreatedAt:     feedbackEntity.CreatedAt,
+	}
+}
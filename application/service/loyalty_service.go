@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fp-kpl/application"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/loyalty"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/infrastructure/database/validation"
+	"log"
+	"sort"
+	"time"
+)
+
+const DefaultLoyaltySyncInterval = 5 * time.Minute
+
+type (
+	LoyaltyService interface {
+		GetAccount(ctx context.Context, userID string) (response.LoyaltyAccount, error)
+		RedeemPoints(ctx context.Context, tx interface{}, userID string, points int64, breakdown order.PriceBreakdown) (order.PriceBreakdown, error)
+		RecordRedemption(ctx context.Context, tx interface{}, userID string, transactionID identity.ID, points int64) error
+		SyncLedgers(ctx context.Context) (response.LoyaltySync, error)
+		Start(ctx context.Context, interval time.Duration)
+	}
+
+	loyaltyService struct {
+		loyaltyRepository  loyalty.Repository
+		orderDomainService order.Service
+		policy             loyalty.Policy
+		clock              shared.Clock
+		transaction        interface{}
+		syncLookback       time.Duration
+	}
+)
+
+func NewLoyaltyService(
+	loyaltyRepository loyalty.Repository,
+	orderDomainService order.Service,
+	policy loyalty.Policy,
+	clock shared.Clock,
+	transaction interface{},
+	syncLookback time.Duration,
+) LoyaltyService {
+	if syncLookback <= 0 {
+		syncLookback = loyalty.DefaultSyncLookback
+	}
+
+	return &loyaltyService{
+		loyaltyRepository:  loyaltyRepository,
+		orderDomainService: orderDomainService,
+		policy:             policy,
+		clock:              clock,
+		transaction:        transaction,
+		syncLookback:       syncLookback,
+	}
+}
+
+// GetAccount returns the customer's balance and latest ledger entries.
+func (s *loyaltyService) GetAccount(ctx context.Context, userID string) (response.LoyaltyAccount, error) {
+	account, err := s.loyaltyRepository.GetAccount(ctx, nil, userID)
+	if err != nil {
+		return response.LoyaltyAccount{}, loyalty.ErrorGetAccount
+	}
+
+	now := s.clock.Now()
+	expiringPoints, expiringAt := account.NextExpiry(now)
+
+	history := make([]response.LoyaltyEntry, 0, min(len(account.Entries), loyalty.DefaultHistoryLimit))
+	for i := len(account.Entries) - 1; i >= 0 && len(history) < loyalty.DefaultHistoryLimit; i-- {
+		entry := account.Entries[i]
+		var transactionID string
+		if !entry.TransactionID.IsEmpty() {
+			transactionID = entry.TransactionID.String()
+		}
+		history = append(history, response.LoyaltyEntry{
+			ID:            entry.ID.String(),
+			TransactionID: transactionID,
+			Type:          entry.Type,
+			Points:        entry.Points,
+			ExpiresAt:     entry.ExpiresAt,
+			CreatedAt:     entry.CreatedAt,
+		})
+	}
+
+	return response.LoyaltyAccount{
+		Balance:        account.Balance(now),
+		PointValue:     s.policy.PointValue.String(),
+		ExpiringPoints: expiringPoints,
+		ExpiringAt:     expiringAt,
+		History:        history,
+	}, nil
+}
+
+// RedeemPoints takes the value of the points off an order priced within tx.
+// The customer's ledger stays locked until tx ends, so two orders cannot
+// spend the same points. Only as many points as the order can absorb are
+// used; the breakdown tells how many. RecordRedemption must follow once the
+// transaction exists.
+func (s *loyaltyService) RedeemPoints(ctx context.Context, tx interface{}, userID string, points int64, breakdown order.PriceBreakdown) (order.PriceBreakdown, error) {
+	if userID == "" {
+		return order.PriceBreakdown{}, loyalty.ErrorAccountRequired
+	}
+
+	if err := s.loyaltyRepository.LockAccount(ctx, tx, userID); err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	account, err := s.loyaltyRepository.GetAccount(ctx, tx, userID)
+	if err != nil {
+		return order.PriceBreakdown{}, loyalty.ErrorGetAccount
+	}
+
+	if err = account.CanRedeem(points, s.clock.Now()); err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	points = min(points, s.policy.PointsWithin(breakdown.Subtotal.Price.Sub(breakdown.Discount.Price)))
+	if points <= 0 {
+		return breakdown, nil
+	}
+
+	amount, err := shared.NewPrice(s.policy.Value(points))
+	if err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	return s.orderDomainService.ApplyLoyalty(ctx, breakdown, points, amount)
+}
+
+// RecordRedemption writes the redemption of a transaction to the ledger
+// locked by RedeemPoints.
+func (s *loyaltyService) RecordRedemption(ctx context.Context, tx interface{}, userID string, transactionID identity.ID, points int64) error {
+	account, err := s.loyaltyRepository.GetAccount(ctx, tx, userID)
+	if err != nil {
+		return loyalty.ErrorGetAccount
+	}
+
+	entry, err := account.Redeem(transactionID, points, s.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if err = s.loyaltyRepository.CreateEntries(ctx, tx, []loyalty.Entry{entry}); err != nil {
+		return loyalty.ErrorCreateEntries
+	}
+	return nil
+}
+
+// SyncLedgers brings the ledgers in line with the transactions that changed
+// within the lookback window: paid ones earn points, voided and refunded ones
+// are reversed, and credits that lapsed are expired. Each customer is synced
+// in a database transaction of their own, and a failure is logged and counted
+// so the others are still synced.
+func (s *loyaltyService) SyncLedgers(ctx context.Context) (response.LoyaltySync, error) {
+	now := s.clock.Now()
+	since := now.Add(-s.syncLookback)
+
+	settlements, err := s.loyaltyRepository.GetSettlements(ctx, nil, since)
+	if err != nil {
+		return response.LoyaltySync{}, err
+	}
+
+	expiringUserIDs, err := s.loyaltyRepository.GetExpiringUserIDs(ctx, nil, since, now)
+	if err != nil {
+		return response.LoyaltySync{}, err
+	}
+
+	settlementsByUser := make(map[string][]loyalty.Settlement)
+	for _, settlement := range settlements {
+		userID := settlement.UserID.String()
+		settlementsByUser[userID] = append(settlementsByUser[userID], settlement)
+	}
+	for _, userID := range expiringUserIDs {
+		if _, ok := settlementsByUser[userID]; !ok {
+			settlementsByUser[userID] = nil
+		}
+	}
+
+	userIDs := make([]string, 0, len(settlementsByUser))
+	for userID := range settlementsByUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	var report response.LoyaltySync
+	for _, userID := range userIDs {
+		report.Accounts++
+
+		written, err := s.syncAccount(ctx, userID, settlementsByUser[userID], now)
+		if err != nil {
+			log.Printf("failed to sync loyalty ledger of user %s: %v", userID, err)
+			report.Failed++
+			continue
+		}
+		report.Entries += written
+	}
+
+	return report, nil
+}
+
+func (s *loyaltyService) syncAccount(ctx context.Context, userID string, settlements []loyalty.Settlement, now time.Time) (written int, err error) {
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	if err = s.loyaltyRepository.LockAccount(ctx, tx, userID); err != nil {
+		return 0, err
+	}
+
+	account, err := s.loyaltyRepository.GetAccount(ctx, tx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []loyalty.Entry
+	for _, settlement := range settlements {
+		entries = append(entries, account.Settle(settlement, s.policy, now)...)
+	}
+	entries = append(entries, account.Expire(now)...)
+
+	if err = s.loyaltyRepository.CreateEntries(ctx, tx, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func (s *loyaltyService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultLoyaltySyncInterval
+	}
+
+	runPeriodically(ctx, "loyalty ledger sync", interval, func(ctx context.Context) error {
+		_, err := s.SyncLedgers(ctx)
+		return err
+	})
+}
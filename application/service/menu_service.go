@@ -2,9 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
 	"fp-kpl/application/response"
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/identity"
 	"fp-kpl/domain/menu/category"
+	"fp-kpl/domain/menu/favourite"
 	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/shared"
+	"fp-kpl/infrastructure/database/validation"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 type (
@@ -13,24 +27,55 @@ type (
 		GetMenuByID(ctx context.Context, id string) (response.Menu, error)
 		GetMenusByCategoryID(ctx context.Context, categoryID string) ([]response.Menu, error)
 		UpdateMenuAvailability(ctx context.Context, id string, isAvailable bool) (response.Menu, error)
+		UpdateMenuSchedule(ctx context.Context, id string, req request.UpdateSchedule) (response.Menu, error)
+		UpdateMenuPriceOverrides(ctx context.Context, id string, req request.UpdatePriceOverrides) (response.Menu, error)
+		GetFavouriteMenus(ctx context.Context, userID string) ([]response.Menu, error)
+		AddFavouriteMenu(ctx context.Context, userID string, menuID string) (response.Menu, error)
+		RemoveFavouriteMenu(ctx context.Context, userID string, menuID string) error
 	}
 
 	menuService struct {
-		menuRepository     menu.Repository
-		categoryRepository category.Repository
+		menuRepository      menu.Repository
+		categoryRepository  category.Repository
+		favouriteRepository favourite.Repository
+		feedbackRepository  feedback.Repository
+		clock               shared.Clock
+		transaction         interface{}
 	}
 )
 
-func NewMenuService(menuRepository menu.Repository, categoryRepository category.Repository) MenuService {
-	return &menuService{menuRepository: menuRepository, categoryRepository: categoryRepository}
+func NewMenuService(
+	menuRepository menu.Repository,
+	categoryRepository category.Repository,
+	favouriteRepository favourite.Repository,
+	feedbackRepository feedback.Repository,
+	clock shared.Clock,
+	transaction interface{},
+) MenuService {
+	return &menuService{
+		menuRepository:      menuRepository,
+		categoryRepository:  categoryRepository,
+		favouriteRepository: favouriteRepository,
+		feedbackRepository:  feedbackRepository,
+		clock:               clock,
+		transaction:         transaction,
+	}
 }
 
+// GetAllMenus reports each menu as it is right now: menus outside their
+// schedule show as unavailable and happy hour prices replace the regular one.
 func (s *menuService) GetAllMenus(ctx context.Context) ([]response.Menu, error) {
 	retrievedMenus, err := s.menuRepository.GetAllMenus(ctx, nil)
 	if err != nil {
 		return nil, menu.ErrorCategoryNotFound
 	}
 
+	ratings, err := s.menuRatings(ctx, retrievedMenus...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
 	responseMenus := make([]response.Menu, 0, len(retrievedMenus))
 	for _, menu := range retrievedMenus {
 		categoryDetail, err := s.categoryRepository.GetCategoryByID(ctx, nil, menu.CategoryID.String())
@@ -39,18 +84,7 @@ func (s *menuService) GetAllMenus(ctx context.Context) ([]response.Menu, error)
 			return nil, category.ErrorGetCategoryByID
 		}
 
-		responseMenus = append(responseMenus, response.Menu{
-			ID:          menu.ID.String(),
-			Name:        menu.Name,
-			Description: menu.Description,
-			ImageUrl:    menu.ImageURL.Path,
-			IsAvailable: menu.IsAvailable,
-			Price:       menu.Price.Price,
-			Category: response.Category{
-				ID:   categoryDetail.ID.String(),
-				Name: categoryDetail.Name,
-			},
-		})
+		responseMenus = append(responseMenus, menuResponse(menu, categoryDetail, ratings[menu.ID.String()], now))
 	}
 
 	return responseMenus, nil
@@ -67,20 +101,12 @@ func (s *menuService) GetMenuByID(ctx context.Context, id string) (response.Menu
 		return response.Menu{}, category.ErrorGetCategoryByID
 	}
 
-	responseMenu := response.Menu{
-		ID:          retrievedMenu.ID.String(),
-		Name:        retrievedMenu.Name,
-		Description: retrievedMenu.Description,
-		ImageUrl:    retrievedMenu.ImageURL.Path,
-		IsAvailable: retrievedMenu.IsAvailable,
-		Price:       retrievedMenu.Price.Price,
-		Category: response.Category{
-			ID:   categoryDetail.ID.String(),
-			Name: categoryDetail.Name,
-		},
+	ratings, err := s.menuRatings(ctx, retrievedMenu)
+	if err != nil {
+		return response.Menu{}, err
 	}
 
-	return responseMenu, nil
+	return menuResponse(retrievedMenu, categoryDetail, ratings[retrievedMenu.ID.String()], s.clock.Now()), nil
 }
 
 func (s *menuService) GetMenusByCategoryID(ctx context.Context, categoryID string) ([]response.Menu, error) {
@@ -89,6 +115,12 @@ func (s *menuService) GetMenusByCategoryID(ctx context.Context, categoryID strin
 		return nil, menu.ErrorGetAllMenus
 	}
 
+	ratings, err := s.menuRatings(ctx, retrievedMenus...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
 	responseMenus := make([]response.Menu, 0, len(retrievedMenus))
 	for _, menu := range retrievedMenus {
 		categoryDetail, err := s.categoryRepository.GetCategoryByID(ctx, nil, menu.CategoryID.String())
@@ -97,18 +129,7 @@ func (s *menuService) GetMenusByCategoryID(ctx context.Context, categoryID strin
 			return nil, category.ErrorGetCategoryByID
 		}
 
-		responseMenus = append(responseMenus, response.Menu{
-			ID:          menu.ID.String(),
-			Name:        menu.Name,
-			Description: menu.Description,
-			ImageUrl:    menu.ImageURL.Path,
-			IsAvailable: menu.IsAvailable,
-			Price:       menu.Price.Price,
-			Category: response.Category{
-				ID:   categoryDetail.ID.String(),
-				Name: categoryDetail.Name,
-			},
-		})
+		responseMenus = append(responseMenus, menuResponse(menu, categoryDetail, ratings[menu.ID.String()], now))
 	}
 
 	return responseMenus, nil
@@ -125,18 +146,299 @@ func (s *menuService) UpdateMenuAvailability(ctx context.Context, id string, isA
 		return response.Menu{}, category.ErrorGetCategoryByID
 	}
 
-	responseMenu := response.Menu{
-		ID:          updatedMenu.ID.String(),
-		Name:        updatedMenu.Name,
-		Description: updatedMenu.Description,
-		ImageUrl:    updatedMenu.ImageURL.Path,
-		IsAvailable: updatedMenu.IsAvailable,
-		Price:       updatedMenu.Price.Price,
-		Category: response.Category{
-			ID:   categoryDetail.ID.String(),
-			Name: categoryDetail.Name,
-		},
+	ratings, err := s.menuRatings(ctx, updatedMenu)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	return menuResponse(updatedMenu, categoryDetail, ratings[updatedMenu.ID.String()], s.clock.Now()), nil
+}
+
+// UpdateMenuSchedule replaces the menu's own schedule. An empty schedule
+// falls back to the category's.
+func (s *menuService) UpdateMenuSchedule(ctx context.Context, id string, req request.UpdateSchedule) (response.Menu, error) {
+	windows, err := timeWindows(req.Windows)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	if _, err = s.menuRepository.GetMenuByID(ctx, tx, id); err != nil {
+		return response.Menu{}, menu.ErrorMenuNotFound
+	}
+
+	updatedMenu, err := s.menuRepository.UpdateMenuSchedule(ctx, tx, id, windows)
+	if err != nil {
+		return response.Menu{}, menu.ErrorUpdateMenuSchedule
+	}
+
+	categoryDetail, err := s.categoryRepository.GetCategoryByID(ctx, tx, updatedMenu.CategoryID.String())
+	if err != nil {
+		return response.Menu{}, category.ErrorGetCategoryByID
+	}
+
+	ratings, err := s.menuRatings(ctx, updatedMenu)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	return menuResponse(updatedMenu, categoryDetail, ratings[updatedMenu.ID.String()], s.clock.Now()), nil
+}
+
+// UpdateMenuPriceOverrides replaces the menu's time-bound prices.
+func (s *menuService) UpdateMenuPriceOverrides(ctx context.Context, id string, req request.UpdatePriceOverrides) (response.Menu, error) {
+	overrides := make([]menu.PriceOverride, 0, len(req.Overrides))
+	for _, reqOverride := range req.Overrides {
+		price, err := decimal.NewFromString(reqOverride.Price)
+		if err != nil {
+			return response.Menu{}, fmt.Errorf("%w: %s", menu.ErrorInvalidPriceOverride, err.Error())
+		}
+
+		windows, err := timeWindows([]request.TimeWindow{reqOverride.TimeWindow})
+		if err != nil {
+			return response.Menu{}, err
+		}
+
+		override, err := menu.NewPriceOverride(reqOverride.Name, shared.NewPriceFromSchema(price), windows[0])
+		if err != nil {
+			return response.Menu{}, err
+		}
+		overrides = append(overrides, override)
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	if _, err = s.menuRepository.GetMenuByID(ctx, tx, id); err != nil {
+		return response.Menu{}, menu.ErrorMenuNotFound
+	}
+
+	updatedMenu, err := s.menuRepository.UpdateMenuPriceOverrides(ctx, tx, id, overrides)
+	if err != nil {
+		return response.Menu{}, menu.ErrorUpdateMenuSchedule
+	}
+
+	categoryDetail, err := s.categoryRepository.GetCategoryByID(ctx, tx, updatedMenu.CategoryID.String())
+	if err != nil {
+		return response.Menu{}, category.ErrorGetCategoryByID
+	}
+
+	ratings, err := s.menuRatings(ctx, updatedMenu)
+	if err != nil {
+		return response.Menu{}, err
+	}
+
+	return menuResponse(updatedMenu, categoryDetail, ratings[updatedMenu.ID.String()], s.clock.Now()), nil
+}
+
+// GetFavouriteMenus reports the user's favourites the same way as the menu
+// list, so favourites that cannot be ordered right now show as unavailable.
+func (s *menuService) GetFavouriteMenus(ctx context.Context, userID string) ([]response.Menu, error) {
+	favourites, err := s.favouriteRepository.GetFavouritesByUserID(ctx, nil, userID)
+	if err != nil {
+		return nil, favourite.ErrorGetFavourites
+	}
+
+	favouriteMenus := make([]menu.Menu, 0, len(favourites))
+	for _, favouriteEntity := range favourites {
+		retrievedMenu, err := s.menuRepository.GetMenuByID(ctx, nil, favouriteEntity.MenuID.String())
+		if err != nil {
+			return nil, favourite.ErrorGetFavourites
+		}
+		favouriteMenus = append(favouriteMenus, retrievedMenu)
+	}
+
+	ratings, err := s.menuRatings(ctx, favouriteMenus...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	responseMenus := make([]response.Menu, 0, len(favouriteMenus))
+	for _, retrievedMenu := range favouriteMenus {
+		categoryDetail, err := s.categoryRepository.GetCategoryByID(ctx, nil, retrievedMenu.CategoryID.String())
+		if err != nil {
+			return nil, category.ErrorGetCategoryByID
+		}
+
+		responseMenus = append(responseMenus, menuResponse(retrievedMenu, categoryDetail, ratings[retrievedMenu.ID.String()], now))
+	}
+
+	return responseMenus, nil
+}
+
+// AddFavouriteMenu saves the menu as one of the user's favourites. Adding a
+// favourite twice keeps the first one.
+func (s *menuService) AddFavouriteMenu(ctx context.Context, userID string, menuID string) (response.Menu, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return response.Menu{}, favourite.ErrorAddFavourite
+	}
+
+	retrievedMenu, err := s.menuRepository.GetMenuByID(ctx, nil, menuID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Menu{}, menu.ErrorMenuNotFound
+		}
+		return response.Menu{}, menu.ErrorGetMenuByID
+	}
+
+	_, err = s.favouriteRepository.AddFavourite(ctx, nil, favourite.Favourite{
+		UserID:    identity.NewID(userUUID),
+		MenuID:    retrievedMenu.ID,
+		CreatedAt: s.clock.Now(),
+	})
+	if err != nil {
+		return response.Menu{}, favourite.ErrorAddFavourite
+	}
+
+	categoryDetail, err := s.categoryRepository.GetCategoryByID(ctx, nil, retrievedMenu.CategoryID.String())
+	if err != nil {
+		return response.Menu{}, category.ErrorGetCategoryByID
+	}
+
+	ratings, err := s.menuRatings(ctx, retrievedMenu)
+	if err != nil {
+		return response.Menu{}, err
 	}
 
-	return responseMenu, nil
+	return menuResponse(retrievedMenu, categoryDetail, ratings[retrievedMenu.ID.String()], s.clock.Now()), nil
+}
+
+func (s *menuService) RemoveFavouriteMenu(ctx context.Context, userID string, menuID string) error {
+	if err := s.favouriteRepository.RemoveFavourite(ctx, nil, userID, menuID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return favourite.ErrorFavouriteNotFound
+		}
+		return favourite.ErrorRemoveFavourite
+	}
+
+	return nil
+}
+
+// menuRatings aggregates the feedback ratings of the menus by menu ID.
+func (s *menuService) menuRatings(ctx context.Context, menus ...menu.Menu) (map[string]feedback.MenuSummary, error) {
+	menuIDs := make([]string, 0, len(menus))
+	for _, menuEntity := range menus {
+		menuIDs = append(menuIDs, menuEntity.ID.String())
+	}
+
+	summaries, err := s.feedbackRepository.GetMenuSummaries(ctx, nil, menuIDs)
+	if err != nil {
+		return nil, feedback.ErrorGetMenuRatings
+	}
+
+	ratings := make(map[string]feedback.MenuSummary, len(summaries))
+	for _, summary := range summaries {
+		ratings[summary.MenuID.String()] = summary
+	}
+	return ratings, nil
+}
+
+func menuResponse(menuEntity menu.Menu, categoryDetail category.Category, rating feedback.MenuSummary, now time.Time) response.Menu {
+	overrides := make([]response.PriceOverride, 0, len(menuEntity.PriceOverrides))
+	for _, override := range menuEntity.PriceOverrides {
+		overrides = append(overrides, response.PriceOverride{
+			Name:       override.Name,
+			Price:      override.Price.Price,
+			TimeWindow: timeWindowResponse(override.Window),
+		})
+	}
+
+	return response.Menu{
+		ID:             menuEntity.ID.String(),
+		Name:           menuEntity.Name,
+		Description:    menuEntity.Description,
+		ImageUrl:       menuEntity.ImageURL.Path,
+		IsAvailable:    menuEntity.IsAvailableAt(now),
+		Price:          menuEntity.PriceAt(now).Price,
+		RegularPrice:   menuEntity.Price.Price,
+		Category:       categoryResponse(categoryDetail),
+		Schedule:       timeWindowResponses(menuEntity.Schedule),
+		PriceOverrides: overrides,
+		Rating:         menuRatingResponse(rating),
+	}
+}
+
+func menuRatingResponse(rating feedback.MenuSummary) *response.MenuRating {
+	if rating.Count == 0 {
+		return nil
+	}
+
+	return &response.MenuRating{
+		Average: rating.Average.Round(1),
+		Count:   rating.Count,
+	}
+}
+
+func timeWindows(reqWindows []request.TimeWindow) ([]shared.TimeWindow, error) {
+	windows := make([]shared.TimeWindow, 0, len(reqWindows))
+	for _, reqWindow := range reqWindows {
+		days := make([]time.Weekday, 0, len(reqWindow.Days))
+		for _, day := range reqWindow.Days {
+			days = append(days, time.Weekday(day))
+		}
+
+		window, err := shared.NewTimeWindow(days, reqWindow.StartTime, reqWindow.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func timeWindowResponse(window shared.TimeWindow) response.TimeWindow {
+	days := make([]int, 0, len(window.Days))
+	for _, day := range window.Days {
+		days = append(days, int(day))
+	}
+
+	return response.TimeWindow{
+		Days:      days,
+		StartTime: window.StartTime(),
+		EndTime:   window.EndTime(),
+	}
+}
+
+func timeWindowResponses(windows []shared.TimeWindow) []response.TimeWindow {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	result := make([]response.TimeWindow, 0, len(windows))
+	for _, window := range windows {
+		result = append(result, timeWindowResponse(window))
+	}
+	return result
 }
@@ -2,22 +2,33 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"fp-kpl/application/request"
 	menu "fp-kpl/domain/menu/menu_item"
 	"fp-kpl/domain/order"
+	"fp-kpl/domain/promotion"
 	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"sort"
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
 type (
 	OrderService interface {
 		CalculateTotalPrice(ctx context.Context, orders []request.Order) (shared.Price, error)
+		CalculatePriceBreakdown(ctx context.Context, userID string, orders []request.Order, voucherCode string, orderType string, pickupAt *time.Time) (order.PriceBreakdown, error)
+		ClaimPromotions(ctx context.Context, tx interface{}, userID string, breakdown order.PriceBreakdown) error
 	}
 
 	orderService struct {
-		orderRepository    order.Repository
-		menuRepository     menu.Repository
-		orderDomainService order.Service
+		orderRepository     order.Repository
+		menuRepository      menu.Repository
+		orderDomainService  order.Service
+		promotionRepository promotion.Repository
+		clock               shared.Clock
 	}
 )
 
@@ -25,32 +36,197 @@ func NewOrderService(
 	orderRepository order.Repository,
 	menuRepository menu.Repository,
 	orderDomainService order.Service,
+	promotionRepository promotion.Repository,
+	clock shared.Clock,
 ) OrderService {
 	return &orderService{
-		orderRepository:    orderRepository,
-		menuRepository:     menuRepository,
-		orderDomainService: orderDomainService,
+		orderRepository:     orderRepository,
+		menuRepository:      menuRepository,
+		orderDomainService:  orderDomainService,
+		promotionRepository: promotionRepository,
+		clock:               clock,
 	}
 }
 
+// CalculateTotalPrice returns what the customer pays for the orders, running
+// promotions, service charge, tax and rounding included.
 func (s *orderService) CalculateTotalPrice(ctx context.Context, orders []request.Order) (shared.Price, error) {
+	breakdown, err := s.CalculatePriceBreakdown(ctx, "", orders, "", "", nil)
+	if err != nil {
+		return shared.Price{}, err
+	}
+
+	return breakdown.Total, nil
+}
+
+// CalculatePriceBreakdown prices the orders for the user at the current time,
+// so happy hour prices apply. Menus out of their schedule when the order is
+// served, at pickupAt for a scheduled pickup and now otherwise, are rejected.
+// The promotions that apply on their own are taken off first, then the
+// voucher if one is given. Packed orders add the packaging fee.
+func (s *orderService) CalculatePriceBreakdown(ctx context.Context, userID string, orders []request.Order, voucherCode string, orderType string, pickupAt *time.Time) (order.PriceBreakdown, error) {
+	validatedOrderType, err := transaction.NewOrderType(orderType)
+	if err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	at := s.clock.Now()
+	servedAt := at
+	if pickupAt != nil {
+		servedAt = *pickupAt
+	}
 	totalPrice := decimal.NewFromInt(0)
+	cart := promotion.Cart{At: at}
+	lines := make([]order.Line, 0, len(orders))
 
 	for _, orderItem := range orders {
 		menuEntity, err := s.menuRepository.GetMenuByID(ctx, nil, orderItem.MenuID)
 		if err != nil {
-			return shared.Price{}, menu.ErrorMenuNotFound
+			return order.PriceBreakdown{}, menu.ErrorMenuNotFound
+		}
+
+		if !menuEntity.IsScheduledAt(servedAt) {
+			return order.PriceBreakdown{}, fmt.Errorf("%w: %s", menu.ErrorMenuOutOfSchedule, menuEntity.Name)
 		}
 
-		menuPrice := menuEntity.Price
+		menuPrice := menuEntity.PriceAt(at)
 
 		orderPrice, err := s.orderDomainService.CalculatePrice(ctx, menuPrice, int64(orderItem.Quantity))
 		if err != nil {
-			return shared.Price{}, err
+			return order.PriceBreakdown{}, err
 		}
 
 		totalPrice = totalPrice.Add(orderPrice.Price)
+		cart.Lines = append(cart.Lines, promotion.Line{
+			MenuID:     menuEntity.ID,
+			CategoryID: menuEntity.CategoryID,
+			UnitPrice:  menuPrice.Price,
+			Quantity:   orderItem.Quantity,
+		})
+		lines = append(lines, order.Line{
+			MenuID:    menuEntity.ID,
+			UnitPrice: menuPrice,
+			Quantity:  orderItem.Quantity,
+		})
+	}
+
+	subtotal, err := shared.NewPrice(totalPrice)
+	if err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	discounts, err := s.applyPromotions(ctx, userID, cart, voucherCode)
+	if err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	breakdown, err := s.orderDomainService.CalculateBreakdown(ctx, subtotal, discounts, validatedOrderType.IsPackaged())
+	if err != nil {
+		return order.PriceBreakdown{}, err
+	}
+
+	breakdown.Lines = lines
+	return breakdown, nil
+}
+
+// applyPromotions evaluates the running promotions and the voucher. Running
+// promotions that are used up are left out quietly, while a voucher that
+// cannot be used is an error the customer should see.
+func (s *orderService) applyPromotions(ctx context.Context, userID string, cart promotion.Cart, voucherCode string) ([]order.Discount, error) {
+	activePromotions, err := s.promotionRepository.GetActivePromotions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := make([]promotion.Promotion, 0, len(activePromotions)+1)
+	for _, activePromotion := range activePromotions {
+		if err = s.checkUsage(ctx, nil, activePromotion, userID); err != nil {
+			if errors.Is(err, promotion.ErrorUsageLimitReached) ||
+				errors.Is(err, promotion.ErrorUserLimitReached) ||
+				errors.Is(err, promotion.ErrorAccountRequired) {
+				continue
+			}
+			return nil, err
+		}
+		promotions = append(promotions, activePromotion)
+	}
+
+	var voucher promotion.Promotion
+	if code := promotion.NormalizeVoucherCode(voucherCode); code != "" {
+		voucher, err = s.promotionRepository.GetPromotionByVoucherCode(ctx, nil, code)
+		if err != nil {
+			return nil, err
+		}
+		if !voucher.IsActive {
+			return nil, promotion.ErrorVoucherNotFound
+		}
+		if err = s.checkUsage(ctx, nil, voucher, userID); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, voucher)
+	}
+
+	voucherApplied := false
+	var discounts []order.Discount
+	for _, applied := range promotion.Evaluate(promotions, cart) {
+		if applied.Promotion.IsVoucher() {
+			voucherApplied = true
+		}
+		discounts = append(discounts, order.Discount{
+			PromotionID: applied.Promotion.ID,
+			Name:        applied.Promotion.Name,
+			VoucherCode: applied.Promotion.VoucherCode,
+			Amount:      shared.NewPriceFromSchema(applied.Amount),
+		})
+	}
+
+	if voucher.IsVoucher() && !voucherApplied {
+		return nil, promotion.ErrorVoucherNotApplicable
+	}
+
+	return discounts, nil
+}
+
+// ClaimPromotions checks the usage limits of the breakdown's promotions again
+// within tx, the transaction that writes their redemptions. Each limited
+// promotion stays locked until tx ends, so two checkouts at once cannot both
+// take its last use. Promotions are locked in ID order to avoid deadlocks.
+func (s *orderService) ClaimPromotions(ctx context.Context, tx interface{}, userID string, breakdown order.PriceBreakdown) error {
+	promotionIDs := make([]string, 0, len(breakdown.Discounts))
+	for _, discount := range breakdown.Discounts {
+		if !discount.PromotionID.IsEmpty() {
+			promotionIDs = append(promotionIDs, discount.PromotionID.String())
+		}
+	}
+	sort.Strings(promotionIDs)
+
+	for _, promotionID := range promotionIDs {
+		lockedPromotion, err := s.promotionRepository.LockPromotion(ctx, tx, promotionID)
+		if err != nil {
+			return err
+		}
+		if err = s.checkUsage(ctx, tx, lockedPromotion, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkUsage counts the promotion's redemptions. Guests have no account to
+// count against, so promotions limited per customer are not open to them.
+func (s *orderService) checkUsage(ctx context.Context, tx interface{}, promotionEntity promotion.Promotion, userID string) error {
+	if promotionEntity.UsageLimit == 0 && promotionEntity.PerUserLimit == 0 {
+		return nil
+	}
+	if userID == "" && promotionEntity.PerUserLimit > 0 {
+		return promotion.ErrorAccountRequired
+	}
+
+	usage, err := s.promotionRepository.GetUsage(ctx, tx, promotionEntity.ID.String(), userID)
+	if err != nil {
+		return err
 	}
 
-	return shared.NewPrice(totalPrice)
+	return promotionEntity.CheckUsage(usage)
 }
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"log"
+	"time"
+)
+
+const DefaultPaymentExpirySweepInterval = time.Minute
+
+type (
+	PaymentExpiryService interface {
+		ExpireStaleTransactions(ctx context.Context) ([]response.ExpiredTransaction, error)
+		Start(ctx context.Context, interval time.Duration)
+	}
+
+	paymentExpiryService struct {
+		transactionRepository transaction.Repository
+		eventPublisherPort    port.EventPublisherPort
+		clock                 shared.Clock
+		paymentTimeout        time.Duration
+	}
+)
+
+func NewPaymentExpiryService(
+	transactionRepository transaction.Repository,
+	eventPublisherPort port.EventPublisherPort,
+	clock shared.Clock,
+	paymentTimeout time.Duration,
+) PaymentExpiryService {
+	if paymentTimeout <= 0 {
+		paymentTimeout = transaction.DefaultPaymentTimeout
+	}
+	return &paymentExpiryService{
+		transactionRepository: transactionRepository,
+		eventPublisherPort:    eventPublisherPort,
+		clock:                 clock,
+		paymentTimeout:        paymentTimeout,
+	}
+}
+
+// ExpireStaleTransactions marks every transaction still waiting for payment
+// after the payment timeout as expired. Orders do not reserve stock (menus only
+// carry an availability flag), so there is nothing else to release.
+func (s *paymentExpiryService) ExpireStaleTransactions(ctx context.Context) ([]response.ExpiredTransaction, error) {
+	expiredTransactions, err := s.transactionRepository.ExpireUnpaidTransactions(ctx, nil, s.clock.Now().Add(-s.paymentTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make([]response.ExpiredTransaction, 0, len(expiredTransactions))
+	for _, expiredTransaction := range expiredTransactions {
+		event := transaction.NewEvent(transaction.EventTransactionExpired, expiredTransaction.ID, s.clock.Now(), map[string]string{
+			"total_price": expiredTransaction.TotalPrice.Price.String(),
+		})
+		if err := s.eventPublisherPort.Publish(ctx, event); err != nil {
+			log.Printf("failed to publish %s for transaction %s: %v", event.Type, expiredTransaction.ID.String(), err)
+		}
+
+		expired = append(expired, response.ExpiredTransaction{
+			TransactionID: expiredTransaction.ID.String(),
+			TotalPrice:    expiredTransaction.TotalPrice.Price.String(),
+			CreatedAt:     expiredTransaction.CreatedAt,
+		})
+	}
+
+	return expired, nil
+}
+
+func (s *paymentExpiryService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPaymentExpirySweepInterval
+	}
+
+	runPeriodically(ctx, "payment expiry sweep", interval, func(ctx context.Context) error {
+		_, err := s.ExpireStaleTransactions(ctx)
+		return err
+	})
+}
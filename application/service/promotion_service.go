@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/shared"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	PromotionService interface {
+		CreatePromotion(ctx context.Context, req request.CreatePromotion) (response.Promotion, error)
+		GetAllPromotions(ctx context.Context) ([]response.Promotion, error)
+		UpdatePromotionStatus(ctx context.Context, id string, req request.UpdatePromotionStatus) (response.Promotion, error)
+	}
+
+	promotionService struct {
+		promotionRepository promotion.Repository
+	}
+)
+
+func NewPromotionService(promotionRepository promotion.Repository) PromotionService {
+	return &promotionService{promotionRepository: promotionRepository}
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, req request.CreatePromotion) (response.Promotion, error) {
+	promotionType, err := promotion.NewType(req.Type)
+	if err != nil {
+		return response.Promotion{}, err
+	}
+
+	value, err := optionalDecimal(req.Value)
+	if err != nil {
+		return response.Promotion{}, err
+	}
+	minimumSpend, err := optionalDecimal(req.MinimumSpend)
+	if err != nil {
+		return response.Promotion{}, err
+	}
+
+	days := make([]time.Weekday, 0, len(req.Days))
+	for _, day := range req.Days {
+		days = append(days, time.Weekday(day))
+	}
+	window, err := shared.NewTimeWindow(days, req.StartTime, req.EndTime)
+	if err != nil {
+		return response.Promotion{}, fmt.Errorf("%w: %s", promotion.ErrorInvalidPromotion, err.Error())
+	}
+	schedule, err := promotion.NewSchedule(req.StartsAt, req.EndsAt, window)
+	if err != nil {
+		return response.Promotion{}, err
+	}
+
+	promotionEntity := promotion.Promotion{
+		Name:         req.Name,
+		Type:         promotionType,
+		Value:        value,
+		BuyQuantity:  req.BuyQuantity,
+		FreeQuantity: req.FreeQuantity,
+		MinimumSpend: shared.NewPriceFromSchema(minimumSpend),
+		Schedule:     schedule,
+		VoucherCode:  promotion.NormalizeVoucherCode(req.VoucherCode),
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		IsActive:     true,
+	}
+	if req.CategoryID != "" {
+		promotionEntity.CategoryID = identity.NewID(uuid.MustParse(req.CategoryID))
+	}
+	if req.MenuID != "" {
+		promotionEntity.MenuID = identity.NewID(uuid.MustParse(req.MenuID))
+	}
+
+	if err = promotionEntity.Validate(); err != nil {
+		return response.Promotion{}, err
+	}
+
+	if promotionEntity.IsVoucher() {
+		_, err = s.promotionRepository.GetPromotionByVoucherCode(ctx, nil, promotionEntity.VoucherCode)
+		if err == nil {
+			return response.Promotion{}, promotion.ErrorVoucherAlreadyExists
+		}
+		if !errors.Is(err, promotion.ErrorVoucherNotFound) {
+			return response.Promotion{}, err
+		}
+	}
+
+	createdPromotion, err := s.promotionRepository.CreatePromotion(ctx, nil, promotionEntity)
+	if err != nil {
+		return response.Promotion{}, promotion.ErrorCreatePromotion
+	}
+
+	return promotionResponse(createdPromotion), nil
+}
+
+func (s *promotionService) GetAllPromotions(ctx context.Context) ([]response.Promotion, error) {
+	retrievedPromotions, err := s.promotionRepository.GetAllPromotions(ctx, nil)
+	if err != nil {
+		return nil, promotion.ErrorGetAllPromotions
+	}
+
+	promotions := make([]response.Promotion, 0, len(retrievedPromotions))
+	for _, retrievedPromotion := range retrievedPromotions {
+		promotions = append(promotions, promotionResponse(retrievedPromotion))
+	}
+
+	return promotions, nil
+}
+
+// UpdatePromotionStatus turns a promotion on or off. Promotions are never
+// deleted, since their redemptions stay on past transactions.
+func (s *promotionService) UpdatePromotionStatus(ctx context.Context, id string, req request.UpdatePromotionStatus) (response.Promotion, error) {
+	updatedPromotion, err := s.promotionRepository.UpdatePromotionStatus(ctx, nil, id, *req.IsActive)
+	if err != nil {
+		if errors.Is(err, promotion.ErrorPromotionNotFound) {
+			return response.Promotion{}, err
+		}
+		return response.Promotion{}, promotion.ErrorUpdatePromotionStatus
+	}
+
+	return promotionResponse(updatedPromotion), nil
+}
+
+func optionalDecimal(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+
+	parsed, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s", promotion.ErrorInvalidPromotion, err.Error())
+	}
+	return parsed, nil
+}
+
+func promotionResponse(promotionEntity promotion.Promotion) response.Promotion {
+	days := make([]int, 0, len(promotionEntity.Schedule.Window.Days))
+	for _, day := range promotionEntity.Schedule.Window.Days {
+		days = append(days, int(day))
+	}
+
+	result := response.Promotion{
+		ID:           promotionEntity.ID.String(),
+		Name:         promotionEntity.Name,
+		Type:         promotionEntity.Type.Type,
+		Value:        promotionEntity.Value.String(),
+		BuyQuantity:  promotionEntity.BuyQuantity,
+		FreeQuantity: promotionEntity.FreeQuantity,
+		MinimumSpend: promotionEntity.MinimumSpend.Price.String(),
+		StartsAt:     promotionEntity.Schedule.StartsAt,
+		EndsAt:       promotionEntity.Schedule.EndsAt,
+		Days:         days,
+		StartTime:    promotionEntity.Schedule.Window.StartTime(),
+		EndTime:      promotionEntity.Schedule.Window.EndTime(),
+		VoucherCode:  promotionEntity.VoucherCode,
+		UsageLimit:   promotionEntity.UsageLimit,
+		PerUserLimit: promotionEntity.PerUserLimit,
+		IsActive:     promotionEntity.IsActive,
+	}
+	if !promotionEntity.CategoryID.IsEmpty() {
+		result.CategoryID = promotionEntity.CategoryID.String()
+	}
+	if !promotionEntity.MenuID.IsEmpty() {
+		result.MenuID = promotionEntity.MenuID.String()
+	}
+	return result
+}
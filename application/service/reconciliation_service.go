@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"log"
+	"time"
+)
+
+const DefaultReconciliationInterval = 5 * time.Minute
+
+type (
+	ReconciliationService interface {
+		Reconcile(ctx context.Context) (response.Reconciliation, error)
+		Start(ctx context.Context, interval time.Duration)
+	}
+
+	// PaymentHookHandler applies a payment notification. TransactionService
+	// implements it, so reconciliation goes through the same path as the
+	// gateway webhook once its signature has been checked.
+	PaymentHookHandler interface {
+		ApplyPaymentStatus(ctx context.Context, provider string, datas map[string]interface{}) error
+	}
+
+	reconciliationService struct {
+		transactionRepository  transaction.Repository
+		paymentGatewayRegistry port.PaymentGatewayRegistry
+		paymentHookHandler     PaymentHookHandler
+		clock                  shared.Clock
+		lookback               time.Duration
+	}
+)
+
+func NewReconciliationService(
+	transactionRepository transaction.Repository,
+	paymentGatewayRegistry port.PaymentGatewayRegistry,
+	paymentHookHandler PaymentHookHandler,
+	clock shared.Clock,
+	lookback time.Duration,
+) ReconciliationService {
+	if lookback <= 0 {
+		lookback = transaction.DefaultReconciliationLookback
+	}
+	return &reconciliationService{
+		transactionRepository:  transactionRepository,
+		paymentGatewayRegistry: paymentGatewayRegistry,
+		paymentHookHandler:     paymentHookHandler,
+		clock:                  clock,
+		lookback:               lookback,
+	}
+}
+
+// Reconcile polls the gateway for every unsettled transaction created within
+// the lookback window and replays any status change as if the webhook had
+// delivered it. A failure on one transaction is logged and counted so the rest
+// are still checked.
+func (s *reconciliationService) Reconcile(ctx context.Context) (response.Reconciliation, error) {
+	transactions, err := s.transactionRepository.GetUnsettledTransactions(ctx, nil, s.clock.Now().Add(-s.lookback))
+	if err != nil {
+		return response.Reconciliation{}, err
+	}
+
+	report := response.Reconciliation{
+		Discrepancies: []response.PaymentDiscrepancy{},
+	}
+	for _, transactionEntity := range transactions {
+		report.Checked++
+
+		paymentGateway, err := s.paymentGatewayRegistry.Gateway(transactionEntity.PaymentProvider)
+		if err != nil {
+			log.Printf("failed to reconcile transaction %s: %v", transactionEntity.ID.String(), err)
+			report.Failed++
+			continue
+		}
+
+		paymentStatus, err := paymentGateway.CheckPaymentStatus(ctx, transactionEntity.ID.ID)
+		if errors.Is(err, port.ErrorPaymentNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Printf("failed to check payment status of transaction %s: %v", transactionEntity.ID.String(), err)
+			report.Failed++
+			continue
+		}
+
+		apply, discrepancy := transaction.Reconcile(transactionEntity, paymentStatus.Status, paymentStatus.GrossAmount)
+		if apply {
+			err = s.paymentHookHandler.ApplyPaymentStatus(ctx, paymentGateway.Provider(), map[string]interface{}{
+				"order_id":           transactionEntity.ID.String(),
+				"transaction_id":     paymentStatus.TransactionID,
+				"transaction_status": paymentStatus.Status,
+				"gross_amount":       paymentStatus.GrossAmount.String(),
+			})
+			if err != nil {
+				log.Printf("failed to apply payment status of transaction %s: %v", transactionEntity.ID.String(), err)
+				report.Failed++
+			} else {
+				report.Updated++
+			}
+		}
+
+		if discrepancy != nil {
+			discrepancy.Resolved = apply && err == nil
+			log.Printf("payment discrepancy %s on transaction %s: local %s %s, gateway %s %s",
+				discrepancy.Type, discrepancy.TransactionID.String(),
+				discrepancy.LocalStatus, discrepancy.LocalAmount.String(),
+				discrepancy.GatewayStatus, discrepancy.GatewayAmount.String())
+			report.Discrepancies = append(report.Discrepancies, response.PaymentDiscrepancy{
+				TransactionID: discrepancy.TransactionID.String(),
+				Type:          discrepancy.Type,
+				LocalStatus:   discrepancy.LocalStatus,
+				GatewayStatus: discrepancy.GatewayStatus,
+				LocalAmount:   discrepancy.LocalAmount.String(),
+				GatewayAmount: discrepancy.GatewayAmount.String(),
+				Resolved:      discrepancy.Resolved,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+func (s *reconciliationService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultReconciliationInterval
+	}
+
+	runPeriodically(ctx, "payment reconciliation", interval, func(ctx context.Context) error {
+		_, err := s.Reconcile(ctx)
+		return err
+	})
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runPeriodically calls job immediately and then every interval until ctx is
+// cancelled. Failures are logged so a single bad run does not stop the loop.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			log.Printf("failed to run %s: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/sla"
+	"fp-kpl/domain/transaction"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultSLAScanInterval = time.Minute
+
+type (
+	SLAService interface {
+		Scan(ctx context.Context) ([]response.SLABreach, error)
+		Start(ctx context.Context, interval time.Duration)
+		GetCurrentBreaches(ctx context.Context) []response.SLABreach
+	}
+
+	slaService struct {
+		transactionRepository    transaction.Repository
+		slaRepository            sla.Repository
+		transactionDomainService transaction.Service
+		slaDomainService         sla.Service
+		notifierPort             port.NotifierPort
+		mu                       sync.RWMutex
+		breaches                 map[string]sla.Breach
+	}
+)
+
+func NewSLAService(
+	transactionRepository transaction.Repository,
+	slaRepository sla.Repository,
+	transactionDomainService transaction.Service,
+	slaDomainService sla.Service,
+	notifierPort port.NotifierPort,
+) SLAService {
+	return &slaService{
+		transactionRepository:    transactionRepository,
+		slaRepository:            slaRepository,
+		transactionDomainService: transactionDomainService,
+		slaDomainService:         slaDomainService,
+		notifierPort:             notifierPort,
+		breaches:                 make(map[string]sla.Breach),
+	}
+}
+
+// Scan evaluates every in-flight transaction, escalates breaches that were
+// never alerted before and replaces the current breach list.
+func (s *slaService) Scan(ctx context.Context) ([]response.SLABreach, error) {
+	queries, err := s.transactionRepository.GetInFlightTransactions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.transactionDomainService.GetKitchenSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	detected := s.slaDomainService.Evaluate(queries, snapshot, snapshot.Now)
+
+	current := make(map[string]sla.Breach, len(detected))
+	for _, breach := range detected {
+		current[breach.Key()] = breach
+	}
+	s.mu.Lock()
+	s.breaches = current
+	s.mu.Unlock()
+
+	for _, breach := range detected {
+		alerted, err := s.slaRepository.RecordAlert(ctx, nil, breach)
+		if err != nil {
+			log.Printf("failed to record sla breach %s: %v", breach.Key(), err)
+			continue
+		}
+		if !alerted {
+			continue
+		}
+
+		if err := s.notifierPort.Notify(ctx, breach); err != nil {
+			log.Printf("failed to notify sla breach %s: %v", breach.Key(), err)
+		}
+	}
+
+	return slaBreachResponses(detected), nil
+}
+
+// Start scans immediately and then every interval until ctx is cancelled.
+func (s *slaService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSLAScanInterval
+	}
+
+	runPeriodically(ctx, "sla scan", interval, func(ctx context.Context) error {
+		_, err := s.Scan(ctx)
+		return err
+	})
+}
+
+func (s *slaService) GetCurrentBreaches(ctx context.Context) []response.SLABreach {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	breaches := make([]sla.Breach, 0, len(s.breaches))
+	for _, breach := range s.breaches {
+		breaches = append(breaches, breach)
+	}
+	return slaBreachResponses(breaches)
+}
+
+func slaBreachResponses(breaches []sla.Breach) []response.SLABreach {
+	sort.Slice(breaches, func(i, j int) bool {
+		return breaches[i].Deadline.Before(breaches[j].Deadline)
+	})
+
+	responses := make([]response.SLABreach, 0, len(breaches))
+	for _, breach := range breaches {
+		responses = append(responses, response.SLABreach{
+			TransactionID: breach.TransactionID.String(),
+			QueueCode:     breach.QueueCode,
+			Type:          breach.Type.Type,
+			Since:         breach.Since,
+			Deadline:      breach.Deadline,
+			OverdueBy:     breach.Overdue().Round(time.Second).String(),
+			DetectedAt:    breach.DetectedAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/domain/user"
+	"fp-kpl/infrastructure/database/validation"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type (
+	SplitPaymentService interface {
+		SplitBill(ctx context.Context, userID string, transactionID string, req request.SplitBill) (response.SplitBill, error)
+		GetPayments(ctx context.Context, userID string, transactionID string) (response.SplitBill, error)
+		RefundPayment(ctx context.Context, transactionID string, paymentID string, req request.RefundPayment) (response.Refund, error)
+	}
+
+	splitPaymentService struct {
+		splitPaymentRepository transaction.SplitPaymentRepository
+		transactionRepository  transaction.Repository
+		userRepository         user.Repository
+		paymentGatewayRegistry port.PaymentGatewayRegistry
+		transaction            interface{}
+	}
+)
+
+func NewSplitPaymentService(
+	splitPaymentRepository transaction.SplitPaymentRepository,
+	transactionRepository transaction.Repository,
+	userRepository user.Repository,
+	paymentGatewayRegistry port.PaymentGatewayRegistry,
+	transaction interface{},
+) SplitPaymentService {
+	return &splitPaymentService{
+		splitPaymentRepository: splitPaymentRepository,
+		transactionRepository:  transactionRepository,
+		userRepository:         userRepository,
+		paymentGatewayRegistry: paymentGatewayRegistry,
+		transaction:            transaction,
+	}
+}
+
+// SplitBill adds shares to an unpaid transaction and charges each one
+// through its own gateway. The first split cancels the charge for the whole
+// bill. It can be called again for whatever is left, for instance after one
+// of the shares was declined.
+func (s *splitPaymentService) SplitBill(ctx context.Context, userID string, transactionID string, req request.SplitBill) (response.SplitBill, error) {
+	byLines, err := splitMode(req.Splits)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	// Locked so a payment of the whole bill is either seen here or, once it
+	// arrives, sees the shares.
+	if err = s.transactionRepository.LockTransaction(ctx, tx, transactionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.SplitBill{}, transaction.ErrorTransactionNotFound
+		}
+		return response.SplitBill{}, err
+	}
+
+	retrievedTransaction, err := s.getOwnTransaction(ctx, tx, userID, transactionID)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+
+	transactionEntity := retrievedTransaction.Transaction
+	if transactionEntity.Payment.Status != transaction.PaymentStatusPending {
+		return response.SplitBill{}, fmt.Errorf("%w: %s", transaction.ErrorPaymentAlreadyFinal, transactionEntity.Payment.Status)
+	}
+
+	splitPayments, err := s.splitPaymentRepository.GetSplitPaymentsByTransactionID(ctx, tx, transactionID)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+	remaining := transaction.RemainingAmount(transactionEntity.TotalPrice, splitPayments)
+
+	var amounts []decimal.Decimal
+	orderIDs := make([][]identity.ID, len(req.Splits))
+	if byLines {
+		lines := make([]transaction.SplitLine, 0, len(retrievedTransaction.Orders))
+		for _, orderQuery := range retrievedTransaction.Orders {
+			lines = append(lines, transaction.SplitLine{
+				OrderID:  orderQuery.Order.ID,
+				Subtotal: orderQuery.UnitPrice().Price.Mul(decimal.NewFromInt(int64(orderQuery.Order.Quantity))),
+			})
+		}
+
+		var taken []identity.ID
+		for _, splitPayment := range splitPayments {
+			if splitPayment.IsLive() {
+				taken = append(taken, splitPayment.OrderIDs...)
+			}
+		}
+
+		for i, share := range req.Splits {
+			for _, orderID := range share.OrderIDs {
+				orderIDs[i] = append(orderIDs[i], identity.NewID(uuid.MustParse(orderID)))
+			}
+		}
+
+		amounts, err = transaction.SplitByLines(transactionEntity.TotalPrice, lines, taken, orderIDs, remaining)
+		if err != nil {
+			return response.SplitBill{}, err
+		}
+	} else {
+		for _, share := range req.Splits {
+			amount, err := decimal.NewFromString(share.Amount)
+			if err != nil {
+				return response.SplitBill{}, fmt.Errorf("%w: %s", transaction.ErrorInvalidSplit, err.Error())
+			}
+			amounts = append(amounts, amount)
+		}
+
+		if err = transaction.SplitByAmount(remaining, amounts); err != nil {
+			return response.SplitBill{}, err
+		}
+	}
+
+	if len(splitPayments) == 0 {
+		if err = s.cancelFullCharge(ctx, transactionEntity); err != nil {
+			return response.SplitBill{}, err
+		}
+	}
+
+	charged := make(map[string]port.ProcessPaymentResponse, len(req.Splits))
+	for i, share := range req.Splits {
+		provider := share.PaymentProvider
+		if provider == "" {
+			provider = transactionEntity.PaymentProvider
+		}
+
+		paymentGateway, err := s.paymentGatewayRegistry.Gateway(provider)
+		if err != nil {
+			return response.SplitBill{}, err
+		}
+
+		payment, err := transaction.NewPayment("", transaction.PaymentStatusPending)
+		if err != nil {
+			return response.SplitBill{}, err
+		}
+
+		createdSplitPayment, err := s.splitPaymentRepository.CreateSplitPayment(ctx, tx, transaction.SplitPayment{
+			TransactionID: transactionEntity.ID,
+			Provider:      paymentGateway.Provider(),
+			Amount:        shared.NewPriceFromSchema(amounts[i]),
+			OrderIDs:      orderIDs[i],
+			Payment:       payment,
+		})
+		if err != nil {
+			return response.SplitBill{}, err
+		}
+
+		charge, err := paymentGateway.ProcessSplitPayment(ctx, tx, transactionEntity, createdSplitPayment)
+		if err != nil {
+			return response.SplitBill{}, err
+		}
+
+		charged[createdSplitPayment.ID.String()] = charge
+		splitPayments = append(splitPayments, createdSplitPayment)
+	}
+
+	return splitBillResponse(transactionEntity, splitPayments, charged), nil
+}
+
+// cancelFullCharge stops the charge for the whole bill, made when the
+// transaction was created, so nobody pays it on top of the shares.
+func (s *splitPaymentService) cancelFullCharge(ctx context.Context, transactionEntity transaction.Transaction) error {
+	paymentGateway, err := s.paymentGatewayRegistry.Gateway(transactionEntity.PaymentProvider)
+	if err != nil {
+		return err
+	}
+
+	err = paymentGateway.CancelPayment(ctx, transactionEntity.ID.ID)
+	if errors.Is(err, port.ErrorPaymentAlreadyPaid) {
+		return fmt.Errorf("%w: the full bill was already paid", transaction.ErrorPaymentAlreadyFinal)
+	}
+	return err
+}
+
+func (s *splitPaymentService) GetPayments(ctx context.Context, userID string, transactionID string) (response.SplitBill, error) {
+	retrievedTransaction, err := s.getOwnTransaction(ctx, nil, userID, transactionID)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+
+	splitPayments, err := s.splitPaymentRepository.GetSplitPaymentsByTransactionID(ctx, nil, transactionID)
+	if err != nil {
+		return response.SplitBill{}, err
+	}
+
+	return splitBillResponse(retrievedTransaction.Transaction, splitPayments, nil), nil
+}
+
+// RefundPayment records a partial or full refund against one split payment,
+// so it is clear whose share the money goes back to. The split payment stays
+// locked until the refund is written, so concurrent refunds cannot together
+// exceed its amount.
+func (s *splitPaymentService) RefundPayment(ctx context.Context, transactionID string, paymentID string, req request.RefundPayment) (response.Refund, error) {
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return response.Refund{}, fmt.Errorf("%w: %s", transaction.ErrorInvalidRefund, err.Error())
+	}
+
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.Refund{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.Refund{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	splitPayment, err := s.splitPaymentRepository.LockSplitPayment(ctx, tx, paymentID)
+	if err != nil {
+		return response.Refund{}, err
+	}
+	if splitPayment.TransactionID.String() != transactionID {
+		return response.Refund{}, transaction.ErrorSplitPaymentNotFound
+	}
+
+	refund, err := splitPayment.Refund(amount, req.Reason)
+	if err != nil {
+		return response.Refund{}, err
+	}
+
+	createdRefund, err := s.splitPaymentRepository.CreateRefund(ctx, tx, refund)
+	if err != nil {
+		return response.Refund{}, err
+	}
+
+	refundedAmount := splitPayment.RefundedAmount.Price.Add(createdRefund.Amount.Price)
+	return response.Refund{
+		ID:             createdRefund.ID.String(),
+		PaymentID:      splitPayment.ID.String(),
+		TransactionID:  transactionID,
+		Amount:         createdRefund.Amount.Price.String(),
+		Reason:         createdRefund.Reason,
+		RefundedAmount: refundedAmount.String(),
+		Refundable:     splitPayment.Amount.Price.Sub(refundedAmount).String(),
+		CreatedAt:      createdRefund.CreatedAt,
+	}, nil
+}
+
+// getOwnTransaction loads the transaction, making sure customers only reach
+// their own. Staff can reach any transaction.
+func (s *splitPaymentService) getOwnTransaction(ctx context.Context, tx interface{}, userID string, transactionID string) (transaction.Query, error) {
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, tx, userID)
+	if err != nil {
+		return transaction.Query{}, err
+	}
+
+	retrievedTransaction, err := s.transactionRepository.GetDetailedTransactionByID(ctx, tx, transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return transaction.Query{}, transaction.ErrorTransactionNotFound
+		}
+		return transaction.Query{}, err
+	}
+
+	if retrievedUser.Role.Name == user.RoleCustomer && retrievedTransaction.Transaction.UserID != retrievedUser.ID {
+		return transaction.Query{}, transaction.ErrorNotTransactionOwner
+	}
+
+	return retrievedTransaction, nil
+}
+
+// splitMode tells whether the shares are split by order lines rather than by
+// amount. Both ways cannot be mixed in one request.
+func splitMode(shares []request.SplitShare) (bool, error) {
+	if len(shares) == 0 {
+		return false, fmt.Errorf("%w: no shares", transaction.ErrorInvalidSplit)
+	}
+
+	byLines := len(shares[0].OrderIDs) > 0
+	for _, share := range shares {
+		if (share.Amount != "") == byLines || (len(share.OrderIDs) > 0) != byLines {
+			return false, fmt.Errorf("%w: every share needs either an amount or order lines", transaction.ErrorInvalidSplit)
+		}
+	}
+	return byLines, nil
+}
+
+func splitBillResponse(transactionEntity transaction.Transaction, splitPayments []transaction.SplitPayment, charged map[string]port.ProcessPaymentResponse) response.SplitBill {
+	payments := make([]response.SplitPayment, 0, len(splitPayments))
+	for _, splitPayment := range splitPayments {
+		orderIDs := make([]string, 0, len(splitPayment.OrderIDs))
+		for _, orderID := range splitPayment.OrderIDs {
+			orderIDs = append(orderIDs, orderID.String())
+		}
+
+		charge := charged[splitPayment.ID.String()]
+		payments = append(payments, response.SplitPayment{
+			ID:             splitPayment.ID.String(),
+			Provider:       splitPayment.Provider,
+			Amount:         splitPayment.Amount.Price.String(),
+			Status:         splitPayment.Payment.Status,
+			OrderIDs:       orderIDs,
+			RefundedAmount: splitPayment.RefundedAmount.Price.String(),
+			Token:          charge.Token,
+			PaymentLink:    charge.PaymentLink,
+			PaidAt:         splitPayment.PaidAt,
+		})
+	}
+
+	return response.SplitBill{
+		TransactionID:   transactionEntity.ID.String(),
+		TotalPrice:      transactionEntity.TotalPrice.Price.String(),
+		PaidAmount:      transaction.PaidAmount(splitPayments).String(),
+		RemainingAmount: transaction.RemainingAmount(transactionEntity.TotalPrice, splitPayments).String(),
+		PaymentStatus:   transactionEntity.Payment.Status,
+		Payments:        payments,
+	}
+}
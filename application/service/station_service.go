@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/station"
+
+	"gorm.io/gorm"
+)
+
+type (
+	StationService interface {
+		GetAllStations(ctx context.Context) ([]response.Station, error)
+		GetStationByID(ctx context.Context, id string) (response.Station, error)
+		AssignMenuStation(ctx context.Context, menuID string, req request.AssignStation) (response.StationAssignment, error)
+		AssignCategoryStation(ctx context.Context, categoryID string, req request.AssignStation) (response.StationAssignment, error)
+		AssignUserStation(ctx context.Context, userID string, req request.AssignStation) (response.StationAssignment, error)
+	}
+
+	stationService struct {
+		stationRepository station.Repository
+	}
+)
+
+func NewStationService(stationRepository station.Repository) StationService {
+	return &stationService{stationRepository: stationRepository}
+}
+
+func (s *stationService) GetAllStations(ctx context.Context) ([]response.Station, error) {
+	retrievedStations, err := s.stationRepository.GetAllStations(ctx, nil)
+	if err != nil {
+		return nil, station.ErrorGetAllStations
+	}
+
+	responseStations := make([]response.Station, 0, len(retrievedStations))
+	for _, retrievedStation := range retrievedStations {
+		responseStations = append(responseStations, response.Station{
+			ID:   retrievedStation.ID.String(),
+			Name: retrievedStation.Name,
+		})
+	}
+
+	return responseStations, nil
+}
+
+func (s *stationService) GetStationByID(ctx context.Context, id string) (response.Station, error) {
+	retrievedStation, err := s.stationRepository.GetStationByID(ctx, nil, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Station{}, station.ErrorStationNotFound
+		}
+		return response.Station{}, station.ErrorGetStationByID
+	}
+
+	return response.Station{
+		ID:   retrievedStation.ID.String(),
+		Name: retrievedStation.Name,
+	}, nil
+}
+
+func (s *stationService) AssignMenuStation(ctx context.Context, menuID string, req request.AssignStation) (response.StationAssignment, error) {
+	if err := s.validateStation(ctx, req.StationID); err != nil {
+		return response.StationAssignment{}, err
+	}
+
+	if err := s.stationRepository.UpdateMenuStation(ctx, nil, menuID, req.StationID); err != nil {
+		return response.StationAssignment{}, station.ErrorAssignStation
+	}
+
+	return response.StationAssignment{
+		ID:        menuID,
+		StationID: req.StationID,
+	}, nil
+}
+
+func (s *stationService) AssignCategoryStation(ctx context.Context, categoryID string, req request.AssignStation) (response.StationAssignment, error) {
+	if err := s.validateStation(ctx, req.StationID); err != nil {
+		return response.StationAssignment{}, err
+	}
+
+	if err := s.stationRepository.UpdateCategoryStation(ctx, nil, categoryID, req.StationID); err != nil {
+		return response.StationAssignment{}, station.ErrorAssignStation
+	}
+
+	return response.StationAssignment{
+		ID:        categoryID,
+		StationID: req.StationID,
+	}, nil
+}
+
+func (s *stationService) AssignUserStation(ctx context.Context, userID string, req request.AssignStation) (response.StationAssignment, error) {
+	if err := s.validateStation(ctx, req.StationID); err != nil {
+		return response.StationAssignment{}, err
+	}
+
+	if err := s.stationRepository.UpdateUserStation(ctx, nil, userID, req.StationID); err != nil {
+		return response.StationAssignment{}, station.ErrorAssignStation
+	}
+
+	return response.StationAssignment{
+		ID:        userID,
+		StationID: req.StationID,
+	}, nil
+}
+
+// validateStation accepts an empty ID, which clears the assignment.
+func (s *stationService) validateStation(ctx context.Context, stationID string) error {
+	if stationID == "" {
+		return nil
+	}
+
+	_, err := s.GetStationByID(ctx, stationID)
+	return err
+}
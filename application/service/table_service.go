@@ -13,6 +13,7 @@ type (
 	TableService interface {
 		GetAllTables(ctx context.Context) ([]response.Table, error)
 		GetTableByID(ctx context.Context, id string) (response.Table, error)
+		GetTableToken(ctx context.Context, id string) (response.TableToken, error)
 	}
 
 	tableService struct {
@@ -54,3 +55,19 @@ func (s *tableService) GetTableByID(ctx context.Context, id string) (response.Ta
 		TableNumber: retrievedTable.TableNumber,
 	}, nil
 }
+
+func (s *tableService) GetTableToken(ctx context.Context, id string) (response.TableToken, error) {
+	retrievedTable, err := s.tableRepository.GetTableByID(ctx, nil, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.TableToken{}, table.ErrorTableNotFound
+		}
+		return response.TableToken{}, table.ErrorGetTableByID
+	}
+
+	return response.TableToken{
+		ID:          retrievedTable.ID.String(),
+		TableNumber: retrievedTable.TableNumber,
+		Token:       retrievedTable.Token.String(),
+	}, nil
+}
@@ -2,33 +2,49 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"fp-kpl/application"
 	"fp-kpl/application/request"
 	"fp-kpl/application/response"
+	"fp-kpl/domain/identity"
 	menu "fp-kpl/domain/menu/menu_item"
 	"fp-kpl/domain/order"
 	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/station"
 	"fp-kpl/domain/table"
 	"fp-kpl/domain/transaction"
 	"fp-kpl/domain/user"
 	"fp-kpl/infrastructure/database/validation"
 	"fp-kpl/platform/pagination"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
 	TransactionService interface {
 		CreateTransaction(ctx context.Context, userID string, req request.TransactionCreate) (response.TransactionCreate, error)
-		HookTransaction(ctx context.Context, datas map[string]interface{}) error
+		HookTransaction(ctx context.Context, provider string, headers map[string]string, body map[string]interface{}) error
+		ApplyPaymentStatus(ctx context.Context, provider string, datas map[string]interface{}) error
 		GetAllTransactionsWithPagination(ctx context.Context, userID string, req pagination.Request) (pagination.ResponseWithData, error)
 		GetTransactionByID(ctx context.Context, id string) (response.Transaction, error)
+		LookupTransaction(ctx context.Context, reference string) (response.Transaction, error)
+		GetGuestTransaction(ctx context.Context, token string) (response.GuestTransaction, error)
+		Reorder(ctx context.Context, userID string, transactionID string) (response.Reorder, error)
 		GetAllReadyToServeTransactionList(ctx context.Context, req pagination.Request) (pagination.ResponseWithData, error)
-		GetNextOrder(ctx context.Context) (response.NextOrder, error)
-		StartCooking(ctx context.Context, req request.StartCooking) (response.StartCooking, error)
-		FinishCooking(ctx context.Context, req request.FinishCooking) (response.FinishCooking, error)
+		GetNextOrder(ctx context.Context, userID string) (response.NextOrder, error)
+		GetStationNextOrder(ctx context.Context, userID string, stationID string) (response.NextOrder, error)
+		StartCooking(ctx context.Context, userID string, req request.StartCooking) (response.StartCooking, error)
+		FinishCooking(ctx context.Context, userID string, req request.FinishCooking) (response.FinishCooking, error)
 		StartDelivering(ctx context.Context, req request.StartDelivering) (response.StartDelivering, error)
 		FinishDelivering(ctx context.Context, req request.FinishDelivering) (response.FinishDelivering, error)
+		CollectOrder(ctx context.Context, req request.CollectOrder) (response.CollectOrder, error)
+		UpdatePriority(ctx context.Context, transactionID string, req request.UpdatePriority) (response.UpdatePriority, error)
+		GetStatusChanges(ctx context.Context, transactionID string) ([]response.StatusChange, error)
 	}
 
 	transactionService struct {
@@ -37,10 +53,16 @@ type (
 		tableRepository          table.Repository
 		orderRepository          order.Repository
 		menuRepository           menu.Repository
+		stationRepository        station.Repository
 		transactionDomainService transaction.Service
-		paymentGatewayPort       port.PaymentGatewayPort
+		paymentGatewayRegistry   port.PaymentGatewayRegistry
 		transaction              interface{}
 		orderService             OrderService
+		schedulingStrategy       transaction.SchedulingStrategy
+		eventPublisherPort       port.EventPublisherPort
+		clock                    shared.Clock
+		statusChangeRepository   transaction.StatusChangeRepository
+		loyaltyService           LoyaltyService
 	}
 )
 
@@ -51,9 +73,15 @@ func NewTransactionService(
 	orderRepository order.Repository,
 	menuRepository menu.Repository,
 	transactionDomainService transaction.Service,
-	paymentGatewayPort port.PaymentGatewayPort,
+	paymentGatewayRegistry port.PaymentGatewayRegistry,
 	transaction interface{},
 	orderService OrderService,
+	stationRepository station.Repository,
+	schedulingStrategy transaction.SchedulingStrategy,
+	eventPublisherPort port.EventPublisherPort,
+	clock shared.Clock,
+	statusChangeRepository transaction.StatusChangeRepository,
+	loyaltyService LoyaltyService,
 ) TransactionService {
 	return &transactionService{
 		transactionRepository:    transactionRepository,
@@ -62,9 +90,15 @@ func NewTransactionService(
 		orderRepository:          orderRepository,
 		menuRepository:           menuRepository,
 		transactionDomainService: transactionDomainService,
-		paymentGatewayPort:       paymentGatewayPort,
+		paymentGatewayRegistry:   paymentGatewayRegistry,
 		transaction:              transaction,
 		orderService:             orderService,
+		stationRepository:        stationRepository,
+		schedulingStrategy:       schedulingStrategy,
+		eventPublisherPort:       eventPublisherPort,
+		clock:                    clock,
+		statusChangeRepository:   statusChangeRepository,
+		loyaltyService:           loyaltyService,
 	}
 }
 
@@ -86,36 +120,123 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID strin
 		validatedTransaction.CommitOrRollback(ctx, tx, err)
 	}()
 
-	retrievedUser, err := s.userRepository.GetUserByID(ctx, tx, userID)
+	// Orders placed without a login, e.g. at a kiosk, belong to a guest who
+	// follows them with the token returned below.
+	var userIdentity identity.ID
+	var guest transaction.Guest
+	var guestToken string
+	if userID != "" {
+		retrievedUser, err := s.userRepository.GetUserByID(ctx, tx, userID)
+		if err != nil {
+			return response.TransactionCreate{}, err
+		}
+		userIdentity = retrievedUser.ID
+	} else {
+		guestToken, err = transaction.NewGuestToken()
+		if err != nil {
+			return response.TransactionCreate{}, err
+		}
+		guest = transaction.Guest{
+			Name:      req.GuestName,
+			Phone:     req.GuestPhone,
+			TokenHash: transaction.HashGuestToken(guestToken),
+		}
+	}
+
+	orderType, err := transaction.NewOrderType(req.OrderType)
 	if err != nil {
 		return response.TransactionCreate{}, err
 	}
-	retrievedTable, err := s.tableRepository.GetTableByID(ctx, tx, req.TableID)
-	if err != nil {
+	if err = orderType.ValidatePickupAt(req.PickupAt, s.clock.Now(), s.clock.Today()); err != nil {
 		return response.TransactionCreate{}, err
 	}
 
+	// Only dine-in orders need a table. A kiosk bound to a table orders for
+	// that table unless told otherwise.
+	tableID := req.TableID
+	if tableID == "" && orderType.IsDineIn() {
+		tableID = application.ActorFromContext(ctx).TableID
+		if tableID == "" {
+			return response.TransactionCreate{}, table.ErrorTableRequired
+		}
+	}
+	var tableIdentity identity.ID
+	if tableID != "" {
+		retrievedTable, err := s.tableRepository.GetTableByID(ctx, tx, tableID)
+		if err != nil {
+			return response.TransactionCreate{}, err
+		}
+		tableIdentity = retrievedTable.ID
+	}
+
 	orderStatus, err := transaction.NewOrderStatus(transaction.OrderStatusPending)
 	if err != nil {
 		return response.TransactionCreate{}, err
 	}
 
-	totalPrice, err := s.orderService.CalculateTotalPrice(ctx, req.Orders)
+	pricing, err := s.orderService.CalculatePriceBreakdown(ctx, userID, req.Orders, req.VoucherCode, orderType.Type, req.PickupAt)
 	if err != nil {
 		return response.TransactionCreate{}, err
 	}
+	if err = s.orderService.ClaimPromotions(ctx, tx, userID, pricing); err != nil {
+		return response.TransactionCreate{}, err
+	}
+	if req.RedeemPoints > 0 {
+		pricing, err = s.loyaltyService.RedeemPoints(ctx, tx, userID, req.RedeemPoints, pricing)
+		if err != nil {
+			return response.TransactionCreate{}, err
+		}
+	}
+	totalPrice := pricing.Total
+
+	retrievedMenus := make([]menu.Menu, 0, len(req.Orders))
+	var cookingTime time.Duration
+	for _, orderItem := range req.Orders {
+		retrievedMenu, err := s.menuRepository.GetMenuByID(ctx, tx, orderItem.MenuID)
+		if err != nil {
+			return response.TransactionCreate{}, err
+		}
+		retrievedMenus = append(retrievedMenus, retrievedMenu)
+		if retrievedMenu.CookingTime > cookingTime {
+			cookingTime = retrievedMenu.CookingTime
+		}
+	}
+
+	// A pickup order is held out of the kitchen queue until it is time to
+	// cook it for the pickup time.
+	var cookAfter *time.Time
+	if req.PickupAt != nil {
+		at := transaction.CookAfter(*req.PickupAt, cookingTime)
+		cookAfter = &at
+	}
 
 	paymentStatus, err := transaction.NewPayment("", transaction.PaymentStatusPending)
 	if err != nil {
 		return response.TransactionCreate{}, err
 	}
 
+	cookingStatus, err := order.NewCookingStatus(order.CookingStatusPending)
+	if err != nil {
+		return response.TransactionCreate{}, err
+	}
+
+	paymentGateway, err := s.paymentGatewayRegistry.Gateway(req.PaymentProvider)
+	if err != nil {
+		return response.TransactionCreate{}, err
+	}
+
 	transactionEntity := transaction.Transaction{
-		UserID:      retrievedUser.ID,
-		TableID:     retrievedTable.ID,
-		OrderStatus: orderStatus,
-		Payment:     paymentStatus,
-		TotalPrice:  totalPrice,
+		UserID:          userIdentity,
+		Guest:           guest,
+		TableID:         tableIdentity,
+		OrderType:       orderType,
+		PickupAt:        req.PickupAt,
+		CookAfter:       cookAfter,
+		OrderStatus:     orderStatus,
+		Payment:         paymentStatus,
+		PaymentProvider: paymentGateway.Provider(),
+		TotalPrice:      totalPrice,
+		Pricing:         pricing,
 	}
 
 	createdTransaction, err := s.transactionRepository.CreateTransaction(ctx, tx, transactionEntity)
@@ -123,17 +244,28 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID strin
 		return response.TransactionCreate{}, err
 	}
 
+	if pricing.LoyaltyPoints > 0 {
+		if err = s.loyaltyService.RecordRedemption(ctx, tx, userID, createdTransaction.ID, pricing.LoyaltyPoints); err != nil {
+			return response.TransactionCreate{}, err
+		}
+	}
+
 	var createdOrders []response.OrderForTransactionCreate
-	for _, orderItem := range req.Orders {
-		retrievedMenu, err := s.menuRepository.GetMenuByID(ctx, tx, orderItem.MenuID)
-		if err != nil {
+	for i, orderItem := range req.Orders {
+		retrievedMenu := retrievedMenus[i]
+
+		retrievedStation, err := s.stationRepository.GetStationByMenuID(ctx, tx, orderItem.MenuID)
+		if err != nil && !errors.Is(err, station.ErrorStationNotFound) {
 			return response.TransactionCreate{}, err
 		}
 
 		orderEntity := order.Order{
 			TransactionID: createdTransaction.ID,
 			MenuID:        retrievedMenu.ID,
+			StationID:     retrievedStation.ID,
 			Quantity:      orderItem.Quantity,
+			Price:         pricing.Lines[i].UnitPrice,
+			CookingStatus: cookingStatus,
 		}
 
 		createdOrder, err := s.orderRepository.CreateOrder(ctx, tx, orderEntity)
@@ -145,13 +277,13 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID strin
 			Menu: response.MenuForTransaction{
 				ID:    retrievedMenu.ID.String(),
 				Name:  retrievedMenu.Name,
-				Price: retrievedMenu.Price.Price.String(),
+				Price: orderEntity.Price.Price.String(),
 			},
 			Quantity: createdOrder.Quantity,
 		})
 	}
 
-	payment, err := s.paymentGatewayPort.ProcessPayment(ctx, tx, createdTransaction)
+	payment, err := paymentGateway.ProcessPayment(ctx, tx, createdTransaction)
 	if err != nil {
 		return response.TransactionCreate{}, err
 	}
@@ -159,13 +291,42 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID strin
 	return response.TransactionCreate{
 		TransactionID: createdTransaction.ID.String(),
 		TotalPrice:    totalPrice.Price.String(),
+		Provider:      paymentGateway.Provider(),
 		Token:         payment.Token,
 		PaymentLink:   payment.PaymentLink,
+		Pricing:       priceBreakdownResponse(pricing),
 		Orders:        createdOrders,
+		OrderType:     orderType.Type,
+		PickupAt:      req.PickupAt,
+		GuestToken:    guestToken,
 	}, nil
 }
 
-func (s *transactionService) HookTransaction(ctx context.Context, datas map[string]interface{}) error {
+// HookTransaction handles a webhook from the provider's payment gateway. The
+// payload is only applied once the gateway has verified its signature.
+func (s *transactionService) HookTransaction(ctx context.Context, provider string, headers map[string]string, body map[string]interface{}) error {
+	paymentGateway, err := s.paymentGatewayRegistry.Gateway(provider)
+	if err != nil {
+		return err
+	}
+
+	datas, err := paymentGateway.ParseNotification(headers, body)
+	if err != nil {
+		return err
+	}
+
+	return s.ApplyPaymentStatus(ctx, paymentGateway.Provider(), datas)
+}
+
+// ApplyPaymentStatus writes a payment status reported by the gateway. Callers
+// that already trust the source, such as reconciliation polling the gateway
+// itself, use it directly instead of HookTransaction.
+func (s *transactionService) ApplyPaymentStatus(ctx context.Context, provider string, datas map[string]interface{}) error {
+	paymentGateway, err := s.paymentGatewayRegistry.Gateway(provider)
+	if err != nil {
+		return err
+	}
+
 	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
 	if err != nil {
 		return err
@@ -188,7 +349,20 @@ func (s *transactionService) HookTransaction(ctx context.Context, datas map[stri
 		return fmt.Errorf("order_id is required in datas")
 	}
 
-	err = s.paymentGatewayPort.HookPayment(ctx, tx, uuid.MustParse(transactionID), datas)
+	err = paymentGateway.HookPayment(ctx, tx, uuid.MustParse(transactionID), datas)
+	if errors.Is(err, transaction.ErrorPaymentAlreadyFinal) {
+		// The transaction was already settled or expired, e.g. by the payment
+		// expiry sweeper. Acknowledge the webhook so the gateway stops retrying
+		// and leave the follow-up (such as a refund) to whoever handles the event.
+		err = s.publishPaymentEvent(ctx, transaction.EventLatePaymentWebhook, transactionID, datas)
+		return err
+	}
+	if errors.Is(err, transaction.ErrorPaidAfterSplit) {
+		// The shares already cover the bill. Acknowledge the webhook and flag
+		// the payment for a refund.
+		err = s.publishPaymentEvent(ctx, transaction.EventPaidAfterSplit, transactionID, datas)
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to hook payment: %w", err)
 	}
@@ -196,18 +370,46 @@ func (s *transactionService) HookTransaction(ctx context.Context, datas map[stri
 	return nil
 }
 
+// publishPaymentEvent reports a payment notification that was acknowledged
+// without being applied, with what is needed to follow it up.
+func (s *transactionService) publishPaymentEvent(ctx context.Context, eventType string, transactionID string, datas map[string]interface{}) error {
+	if s.eventPublisherPort == nil {
+		return nil
+	}
+
+	receivedStatus, _ := datas["transaction_status"].(string)
+	paymentCode, _ := datas["transaction_id"].(string)
+	grossAmount, _ := datas["gross_amount"].(string)
+	event := transaction.NewEvent(eventType, identity.NewID(uuid.MustParse(transactionID)), s.clock.Now(), map[string]string{
+		"received_status": receivedStatus,
+		"payment_code":    paymentCode,
+		"gross_amount":    grossAmount,
+	})
+	return s.eventPublisherPort.Publish(ctx, event)
+}
+
 func (s *transactionService) GetAllTransactionsWithPagination(ctx context.Context, userID string, req pagination.Request) (pagination.ResponseWithData, error) {
 	retrievedData, err := s.transactionRepository.GetAllTransactionsWithPagination(ctx, nil, userID, req)
 	if err != nil {
 		return pagination.ResponseWithData{}, err
 	}
 
-	data := make([]any, 0, len(retrievedData.Data))
+	queries := make([]transaction.Query, 0, len(retrievedData.Data))
 	for _, retrievedTransaction := range retrievedData.Data {
 		transactionQuery, ok := retrievedTransaction.(transaction.Query)
 		if !ok {
 			return pagination.ResponseWithData{}, transaction.ErrorInvalidTransaction
 		}
+		queries = append(queries, transactionQuery)
+	}
+
+	snapshot, err := s.kitchenSnapshot(ctx, queries...)
+	if err != nil {
+		return pagination.ResponseWithData{}, err
+	}
+
+	data := make([]any, 0, len(queries))
+	for _, transactionQuery := range queries {
 
 		var orderResponses []response.OrderForTransaction
 		for _, orderQuery := range transactionQuery.Orders {
@@ -215,27 +417,29 @@ func (s *transactionService) GetAllTransactionsWithPagination(ctx context.Contex
 				Menu: response.MenuForTransaction{
 					ID:    orderQuery.Menu.ID.String(),
 					Name:  orderQuery.Menu.Name,
-					Price: orderQuery.Menu.Price.Price.String(),
+					Price: orderQuery.UnitPrice().Price.String(),
 				},
 				Quantity: orderQuery.Order.Quantity,
 			})
 		}
 
-		maxCookingTime := s.transactionDomainService.CalculateMaxCookingTime(transactionQuery.Orders)
-		isDelayed := s.transactionDomainService.GetOrderDelayStatus(maxCookingTime, transactionQuery.Transaction.CookedAt, transactionQuery.Transaction.ServedAt)
+		estimate := s.transactionDomainService.EstimateWaitTime(snapshot, transactionQuery)
+		isDelayed := s.transactionDomainService.GetOrderDelayStatus(estimate.CookingTime, transactionQuery.Transaction.CookedAt, transactionQuery.Transaction.ServedAt)
 
 		data = append(data, response.Transaction{
 			ID:           transactionQuery.Transaction.ID.String(),
 			QueueCode:    transactionQuery.Transaction.QueueCode.Code,
-			EstimateTime: maxCookingTime.String(),
+			BusinessDay:  transactionQuery.Transaction.QueueCode.BusinessDay,
+			EstimateTime: estimate.Remaining(snapshot.Now).Round(time.Second).String(),
 			Orders:       orderResponses,
 			TotalPrice:   transactionQuery.Transaction.TotalPrice.Price,
-			Table: response.Table{
-				ID:          transactionQuery.Table.ID.String(),
-				TableNumber: transactionQuery.Table.TableNumber,
-			},
-			OrderStatus: transactionQuery.Transaction.OrderStatus.Status,
-			IsDelayed:   isDelayed,
+			Pricing:      priceBreakdownResponse(transactionQuery.Transaction.Pricing),
+			Table:        tableResponse(transactionQuery.Table),
+			OrderType:    transactionQuery.Transaction.OrderType.Type,
+			PickupAt:     transactionQuery.Transaction.PickupAt,
+			OrderStatus:  transactionQuery.Transaction.OrderStatus.Status,
+			IsDelayed:    isDelayed,
+			Estimate:     waitEstimateResponse(estimate),
 		})
 	}
 
@@ -251,36 +455,228 @@ func (s *transactionService) GetTransactionByID(ctx context.Context, id string)
 		return response.Transaction{}, err
 	}
 
+	return s.transactionResponse(ctx, retrievedData)
+}
+
+// LookupTransaction lets the kitchen and waiters find a transaction by its ID
+// or by the queue code called out in the restaurant.
+func (s *transactionService) LookupTransaction(ctx context.Context, reference string) (response.Transaction, error) {
+	transactionID, queueCode := "", reference
+	if _, err := uuid.Parse(reference); err == nil {
+		transactionID, queueCode = reference, ""
+	}
+
+	retrievedData, err := s.findTransaction(ctx, nil, transactionID, queueCode)
+	if err != nil {
+		return response.Transaction{}, err
+	}
+
+	return s.transactionResponse(ctx, retrievedData)
+}
+
+// GetGuestTransaction lets a guest follow their order with the token they
+// were given at checkout. Only the order's progress is returned, since the
+// token travels in links that may be shared.
+func (s *transactionService) GetGuestTransaction(ctx context.Context, token string) (response.GuestTransaction, error) {
+	if !strings.HasPrefix(token, transaction.GuestTokenPrefix) {
+		return response.GuestTransaction{}, transaction.ErrorTransactionNotFound
+	}
+
+	retrievedData, err := s.transactionRepository.GetTransactionByGuestTokenHash(ctx, nil, transaction.HashGuestToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.GuestTransaction{}, transaction.ErrorTransactionNotFound
+		}
+		return response.GuestTransaction{}, err
+	}
+
+	snapshot, err := s.kitchenSnapshot(ctx, retrievedData)
+	if err != nil {
+		return response.GuestTransaction{}, err
+	}
+
+	estimate := s.transactionDomainService.EstimateWaitTime(snapshot, retrievedData)
+
+	return response.GuestTransaction{
+		QueueCode:    retrievedData.Transaction.QueueCode.Code,
+		OrderStatus:  retrievedData.Transaction.OrderStatus.Status,
+		OrderType:    retrievedData.Transaction.OrderType.Type,
+		PickupAt:     retrievedData.Transaction.PickupAt,
+		EstimateTime: estimate.Remaining(snapshot.Now).Round(time.Second).String(),
+		Estimate:     waitEstimateResponse(estimate),
+	}, nil
+}
+
+// Reorder builds a new cart from the lines of a past transaction. The lines
+// are priced the way CreateTransaction would price them now, and lines whose
+// menu cannot be ordered any more are reported instead of failing the cart.
+func (s *transactionService) Reorder(ctx context.Context, userID string, transactionID string) (response.Reorder, error) {
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return response.Reorder{}, err
+	}
+
+	retrievedData, err := s.findTransaction(ctx, nil, transactionID, "")
+	if err != nil {
+		return response.Reorder{}, err
+	}
+
+	if retrievedUser.Role.Name == user.RoleCustomer && retrievedData.Transaction.UserID != retrievedUser.ID {
+		return response.Reorder{}, transaction.ErrorNotTransactionOwner
+	}
+
+	now := s.clock.Now()
+	orders := make([]request.Order, 0, len(retrievedData.Orders))
+	menuNames := make([]string, 0, len(retrievedData.Orders))
+	unavailable := make([]response.UnavailableOrder, 0)
+	for _, orderQuery := range retrievedData.Orders {
+		menuID := orderQuery.Order.MenuID.String()
+
+		currentMenu, err := s.menuRepository.GetMenuByID(ctx, nil, menuID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Reorder{}, err
+		}
+		if err != nil {
+			err = menu.ErrorMenuNotFound
+		} else {
+			err = currentMenu.CheckAvailableAt(now)
+		}
+		if err != nil {
+			unavailable = append(unavailable, response.UnavailableOrder{
+				Menu: response.MenuForWaiter{
+					ID:   menuID,
+					Name: orderQuery.Menu.Name,
+				},
+				Quantity: orderQuery.Order.Quantity,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+
+		orders = append(orders, request.Order{
+			MenuID:   menuID,
+			Quantity: orderQuery.Order.Quantity,
+		})
+		menuNames = append(menuNames, currentMenu.Name)
+	}
+
+	orderType := retrievedData.Transaction.OrderType.Type
+	result := response.Reorder{
+		SourceTransactionID: retrievedData.Transaction.ID.String(),
+		OrderType:           orderType,
+		Orders:              make([]response.OrderForTransactionCreate, 0, len(orders)),
+		Unavailable:         unavailable,
+	}
+	if len(orders) == 0 {
+		return result, nil
+	}
+
+	pricing, err := s.orderService.CalculatePriceBreakdown(ctx, userID, orders, "", orderType, nil)
+	if err != nil {
+		return response.Reorder{}, err
+	}
+
+	for i, orderItem := range orders {
+		result.Orders = append(result.Orders, response.OrderForTransactionCreate{
+			Menu: response.MenuForTransaction{
+				ID:    orderItem.MenuID,
+				Name:  menuNames[i],
+				Price: pricing.Lines[i].UnitPrice.Price.String(),
+			},
+			Quantity: orderItem.Quantity,
+		})
+	}
+	pricingResponse := priceBreakdownResponse(pricing)
+	result.Pricing = &pricingResponse
+
+	return result, nil
+}
+
+// findTransaction resolves a kitchen or waiter action to its transaction.
+// Queue codes are looked up in the current business day and among the orders
+// still in flight.
+func (s *transactionService) findTransaction(ctx context.Context, tx interface{}, transactionID string, queueCode string) (transaction.Query, error) {
+	var retrievedData transaction.Query
+	var err error
+	if transactionID != "" {
+		retrievedData, err = s.transactionRepository.GetDetailedTransactionByID(ctx, tx, transactionID)
+	} else {
+		retrievedData, err = s.transactionRepository.GetTransactionByQueueCode(ctx, tx, queueCode)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return transaction.Query{}, transaction.ErrorTransactionNotFound
+		}
+		return transaction.Query{}, err
+	}
+
+	return retrievedData, nil
+}
+
+func (s *transactionService) transactionResponse(ctx context.Context, retrievedData transaction.Query) (response.Transaction, error) {
 	var orderResponses []response.OrderForTransaction
 	for _, orderQuery := range retrievedData.Orders {
 		orderResponses = append(orderResponses, response.OrderForTransaction{
 			Menu: response.MenuForTransaction{
 				ID:    orderQuery.Menu.ID.String(),
 				Name:  orderQuery.Menu.Name,
-				Price: orderQuery.Menu.Price.Price.String(),
+				Price: orderQuery.UnitPrice().Price.String(),
 			},
 			Quantity: orderQuery.Order.Quantity,
 		})
 	}
 
-	maxCookingTime := s.transactionDomainService.CalculateMaxCookingTime(retrievedData.Orders)
-	isDelayed := s.transactionDomainService.GetOrderDelayStatus(maxCookingTime, retrievedData.Transaction.CookedAt, retrievedData.Transaction.ServedAt)
+	snapshot, err := s.kitchenSnapshot(ctx, retrievedData)
+	if err != nil {
+		return response.Transaction{}, err
+	}
+
+	estimate := s.transactionDomainService.EstimateWaitTime(snapshot, retrievedData)
+	isDelayed := s.transactionDomainService.GetOrderDelayStatus(estimate.CookingTime, retrievedData.Transaction.CookedAt, retrievedData.Transaction.ServedAt)
 
 	return response.Transaction{
 		ID:           retrievedData.Transaction.ID.String(),
 		QueueCode:    retrievedData.Transaction.QueueCode.Code,
-		EstimateTime: maxCookingTime.String(),
+		BusinessDay:  retrievedData.Transaction.QueueCode.BusinessDay,
+		EstimateTime: estimate.Remaining(snapshot.Now).Round(time.Second).String(),
 		Orders:       orderResponses,
 		OrderStatus:  retrievedData.Transaction.OrderStatus.Status,
+		GuestName:    retrievedData.Transaction.Guest.Name,
 		TotalPrice:   retrievedData.Transaction.TotalPrice.Price,
-		Table: response.Table{
-			ID:          retrievedData.Table.ID.String(),
-			TableNumber: retrievedData.Table.TableNumber,
-		},
-		IsDelayed: isDelayed,
+		Pricing:      priceBreakdownResponse(retrievedData.Transaction.Pricing),
+		Table:        tableResponse(retrievedData.Table),
+		OrderType:    retrievedData.Transaction.OrderType.Type,
+		PickupAt:     retrievedData.Transaction.PickupAt,
+		IsDelayed:    isDelayed,
+		Estimate:     waitEstimateResponse(estimate),
 	}, nil
 }
 
+// kitchenSnapshot loads the kitchen queue only when one of queries still waits
+// on the kitchen. The estimate of any other transaction needs nothing but the
+// learned throughput.
+func (s *transactionService) kitchenSnapshot(ctx context.Context, queries ...transaction.Query) (transaction.KitchenSnapshot, error) {
+	now := s.clock.Now()
+	for _, query := range queries {
+		if !query.AwaitsKitchen(now) {
+			continue
+		}
+
+		snapshot, err := s.transactionDomainService.GetKitchenSnapshot(ctx)
+		if err != nil {
+			return transaction.KitchenSnapshot{}, err
+		}
+		snapshot.Strategy = s.schedulingStrategy
+		return snapshot, nil
+	}
+
+	throughput, err := s.transactionDomainService.GetThroughput(ctx)
+	if err != nil {
+		return transaction.KitchenSnapshot{}, err
+	}
+	return transaction.KitchenSnapshot{Throughput: throughput, Now: now}, nil
+}
+
 func (s *transactionService) GetAllReadyToServeTransactionList(ctx context.Context, req pagination.Request) (pagination.ResponseWithData, error) {
 	retrievedData, err := s.transactionRepository.GetAllReadyToServeTransactionList(ctx, nil, req)
 	if err != nil {
@@ -323,7 +719,20 @@ func (s *transactionService) GetAllReadyToServeTransactionList(ctx context.Conte
 	}, nil
 }
 
-func (s *transactionService) GetNextOrder(ctx context.Context) (response.NextOrder, error) {
+func (s *transactionService) GetNextOrder(ctx context.Context, userID string) (response.NextOrder, error) {
+	boundStationID, err := s.boundKitchenStation(ctx, nil, userID)
+	if err != nil {
+		return response.NextOrder{}, err
+	}
+
+	if boundStationID != "" {
+		return s.getStationNextOrder(ctx, boundStationID)
+	}
+
+	if s.isScheduledByStrategy() {
+		return s.scheduleNextOrder(ctx, "")
+	}
+
 	retrievedNextOrder, err := s.transactionRepository.GetNextOrder(ctx, nil)
 	if err != nil {
 		return response.NextOrder{}, err
@@ -336,7 +745,75 @@ func (s *transactionService) GetNextOrder(ctx context.Context) (response.NextOrd
 	return retrievedNextOrder, nil
 }
 
-func (s *transactionService) StartCooking(ctx context.Context, req request.StartCooking) (response.StartCooking, error) {
+func (s *transactionService) GetStationNextOrder(ctx context.Context, userID string, stationID string) (response.NextOrder, error) {
+	stationID, err := s.resolveKitchenStation(ctx, nil, userID, stationID)
+	if err != nil {
+		return response.NextOrder{}, err
+	}
+
+	return s.getStationNextOrder(ctx, stationID)
+}
+
+func (s *transactionService) getStationNextOrder(ctx context.Context, stationID string) (response.NextOrder, error) {
+	if s.isScheduledByStrategy() {
+		return s.scheduleNextOrder(ctx, stationID)
+	}
+
+	retrievedNextOrder, err := s.transactionRepository.GetNextOrderByStation(ctx, nil, stationID)
+	if err != nil {
+		return response.NextOrder{}, err
+	}
+
+	if retrievedNextOrder.QueueCode == "" {
+		return response.NextOrder{}, transaction.ErrorNextOrderNotFound
+	}
+
+	return retrievedNextOrder, nil
+}
+
+// isScheduledByStrategy reports whether the next order has to be ranked in
+// memory. FIFO is served straight from the repository's created_at ordering.
+func (s *transactionService) isScheduledByStrategy() bool {
+	return s.schedulingStrategy != nil && s.schedulingStrategy.Name() != transaction.SchedulingFIFO
+}
+
+func (s *transactionService) scheduleNextOrder(ctx context.Context, stationID string) (response.NextOrder, error) {
+	candidates, err := s.transactionRepository.GetPendingTransactions(ctx, nil, stationID)
+	if err != nil {
+		return response.NextOrder{}, err
+	}
+
+	picked := s.schedulingStrategy.Pick(candidates, s.clock.Now())
+	if picked < 0 {
+		return response.NextOrder{}, transaction.ErrorNextOrderNotFound
+	}
+
+	nextOrder := response.NextOrder{
+		QueueCode: candidates[picked].Transaction.QueueCode.Code,
+	}
+	for _, orderQuery := range candidates[picked].Orders {
+		nextOrder.Orders = append(nextOrder.Orders, response.OrderForTransaction{
+			Menu: response.MenuForTransaction{
+				ID:    orderQuery.Menu.ID.String(),
+				Name:  orderQuery.Menu.Name,
+				Price: orderQuery.UnitPrice().Price.String(),
+			},
+			Quantity: orderQuery.Order.Quantity,
+		})
+	}
+
+	if stationID != "" {
+		retrievedStation, err := s.stationRepository.GetStationByID(ctx, nil, stationID)
+		if err != nil {
+			return response.NextOrder{}, err
+		}
+		nextOrder.Station = retrievedStation.Name
+	}
+
+	return nextOrder, nil
+}
+
+func (s *transactionService) StartCooking(ctx context.Context, userID string, req request.StartCooking) (response.StartCooking, error) {
 	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
 	if err != nil {
 		return response.StartCooking{}, err
@@ -354,32 +831,49 @@ func (s *transactionService) StartCooking(ctx context.Context, req request.Start
 		validatedTransaction.CommitOrRollback(ctx, tx, err)
 	}()
 
-	retrievedData, err := s.transactionRepository.GetTransactionByQueueCode(ctx, tx, req.QueueCode)
+	stationID, err := s.resolveKitchenStation(ctx, tx, userID, req.StationID)
 	if err != nil {
 		return response.StartCooking{}, err
 	}
 
-	if retrievedData.Transaction.OrderStatus.Status != transaction.OrderStatusPending {
-		return response.StartCooking{}, transaction.ErrorInvalidOrderStatus
+	retrievedData, err := s.findTransaction(ctx, tx, req.TransactionID, req.QueueCode)
+	if err != nil {
+		return response.StartCooking{}, err
 	}
 
-	_, err = s.transactionRepository.UpdateTransactionCookingStatusStart(ctx, tx, retrievedData.Transaction.ID.String())
+	stationOrders, err := retrievedData.CheckStartCooking(stationID)
 	if err != nil {
 		return response.StartCooking{}, err
 	}
 
-	_, err = s.transactionRepository.UpdateCookedAt(ctx, tx, retrievedData.Transaction.ID.String())
+	if retrievedData.Transaction.OrderStatus.Status == transaction.OrderStatusPending {
+		_, err = s.transactionRepository.UpdateTransactionCookingStatusStart(ctx, tx, retrievedData.Transaction.ID.String())
+		if err != nil {
+			return response.StartCooking{}, err
+		}
+
+		_, err = s.transactionRepository.UpdateCookedAt(ctx, tx, retrievedData.Transaction.ID.String())
+		if err != nil {
+			return response.StartCooking{}, err
+		}
+
+		if err = s.recordStatusChange(ctx, tx, retrievedData.Transaction.ID, transaction.OrderStatusPreparing); err != nil {
+			return response.StartCooking{}, err
+		}
+	}
+
+	_, err = s.orderRepository.UpdateOrdersCookingStatus(ctx, tx, retrievedData.Transaction.ID.String(), stationID, order.CookingStatusPreparing)
 	if err != nil {
 		return response.StartCooking{}, err
 	}
 
 	var orderResponses []response.OrderForTransaction
-	for _, orderQuery := range retrievedData.Orders {
+	for _, orderQuery := range stationOrders {
 		orderResponses = append(orderResponses, response.OrderForTransaction{
 			Menu: response.MenuForTransaction{
 				ID:    orderQuery.Menu.ID.String(),
 				Name:  orderQuery.Menu.Name,
-				Price: orderQuery.Menu.Price.Price.String(),
+				Price: orderQuery.UnitPrice().Price.String(),
 			},
 			Quantity: orderQuery.Order.Quantity,
 		})
@@ -391,28 +885,69 @@ func (s *transactionService) StartCooking(ctx context.Context, req request.Start
 	}, nil
 }
 
-func (s *transactionService) FinishCooking(ctx context.Context, req request.FinishCooking) (response.FinishCooking, error) {
-	retrievedData, err := s.transactionRepository.GetTransactionByQueueCode(ctx, nil, req.QueueCode)
+func (s *transactionService) FinishCooking(ctx context.Context, userID string, req request.FinishCooking) (response.FinishCooking, error) {
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.FinishCooking{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
 	if err != nil {
 		return response.FinishCooking{}, err
 	}
 
-	if retrievedData.Transaction.OrderStatus.Status != transaction.OrderStatusPreparing {
-		return response.FinishCooking{}, transaction.ErrorInvalidOrderStatus
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	stationID, err := s.resolveKitchenStation(ctx, tx, userID, req.StationID)
+	if err != nil {
+		return response.FinishCooking{}, err
 	}
 
-	_, err = s.transactionRepository.UpdateTransactionCookingStatusFinish(ctx, nil, retrievedData.Transaction.ID.String())
+	retrievedData, err := s.findTransaction(ctx, tx, req.TransactionID, req.QueueCode)
 	if err != nil {
 		return response.FinishCooking{}, err
 	}
 
+	stationOrders, err := retrievedData.CheckFinishCooking(stationID)
+	if err != nil {
+		return response.FinishCooking{}, err
+	}
+
+	// Stations finishing at the same time would otherwise each miss the
+	// other's lines and leave the transaction preparing.
+	if err = s.transactionRepository.LockTransaction(ctx, tx, retrievedData.Transaction.ID.String()); err != nil {
+		return response.FinishCooking{}, err
+	}
+
+	updatedOrders, err := s.orderRepository.UpdateOrdersCookingStatus(ctx, tx, retrievedData.Transaction.ID.String(), stationID, order.CookingStatusDone)
+	if err != nil {
+		return response.FinishCooking{}, err
+	}
+
+	// The transaction is only ready to serve once every station has finished its lines.
+	if order.IsCookingFinished(updatedOrders) {
+		_, err = s.transactionRepository.UpdateTransactionCookingStatusFinish(ctx, tx, retrievedData.Transaction.ID.String())
+		if err != nil {
+			return response.FinishCooking{}, err
+		}
+
+		if err = s.recordStatusChange(ctx, tx, retrievedData.Transaction.ID, transaction.OrderStatusReadyToServe); err != nil {
+			return response.FinishCooking{}, err
+		}
+	}
+
 	var orderResponses []response.OrderForTransaction
-	for _, orderQuery := range retrievedData.Orders {
+	for _, orderQuery := range stationOrders {
 		orderResponses = append(orderResponses, response.OrderForTransaction{
 			Menu: response.MenuForTransaction{
 				ID:    orderQuery.Menu.ID.String(),
 				Name:  orderQuery.Menu.Name,
-				Price: orderQuery.Menu.Price.Price.String(),
+				Price: orderQuery.UnitPrice().Price.String(),
 			},
 			Quantity: orderQuery.Order.Quantity,
 		})
@@ -425,11 +960,15 @@ func (s *transactionService) FinishCooking(ctx context.Context, req request.Fini
 }
 
 func (s *transactionService) StartDelivering(ctx context.Context, req request.StartDelivering) (response.StartDelivering, error) {
-	retrievedData, err := s.transactionRepository.GetTransactionByQueueCode(ctx, nil, req.QueueCode)
+	retrievedData, err := s.findTransaction(ctx, nil, req.TransactionID, req.QueueCode)
 	if err != nil {
 		return response.StartDelivering{}, err
 	}
 
+	if retrievedData.Transaction.OrderType.IsPackaged() {
+		return response.StartDelivering{}, transaction.ErrorNotDineIn
+	}
+
 	if retrievedData.Transaction.OrderStatus.Status != transaction.OrderStatusReadyToServe {
 		return response.StartDelivering{}, transaction.ErrorInvalidOrderStatus
 	}
@@ -439,13 +978,17 @@ func (s *transactionService) StartDelivering(ctx context.Context, req request.St
 		return response.StartDelivering{}, err
 	}
 
+	if err = s.recordStatusChange(ctx, nil, retrievedData.Transaction.ID, transaction.OrderStatusDelivering); err != nil {
+		return response.StartDelivering{}, err
+	}
+
 	var orderResponses []response.OrderForTransaction
 	for _, orderQuery := range retrievedData.Orders {
 		orderResponses = append(orderResponses, response.OrderForTransaction{
 			Menu: response.MenuForTransaction{
 				ID:    orderQuery.Menu.ID.String(),
 				Name:  orderQuery.Menu.Name,
-				Price: orderQuery.Menu.Price.Price.String(),
+				Price: orderQuery.UnitPrice().Price.String(),
 			},
 			Quantity: orderQuery.Order.Quantity,
 		})
@@ -475,11 +1018,15 @@ func (s *transactionService) FinishDelivering(ctx context.Context, req request.F
 		validatedTransaction.CommitOrRollback(ctx, tx, err)
 	}()
 
-	retrievedData, err := s.transactionRepository.GetTransactionByQueueCode(ctx, nil, req.QueueCode)
+	retrievedData, err := s.findTransaction(ctx, nil, req.TransactionID, req.QueueCode)
 	if err != nil {
 		return response.FinishDelivering{}, err
 	}
 
+	if retrievedData.Transaction.OrderType.IsPackaged() {
+		return response.FinishDelivering{}, transaction.ErrorNotDineIn
+	}
+
 	if retrievedData.Transaction.OrderStatus.Status != transaction.OrderStatusDelivering {
 		return response.FinishDelivering{}, transaction.ErrorInvalidOrderStatus
 	}
@@ -494,5 +1041,252 @@ func (s *transactionService) FinishDelivering(ctx context.Context, req request.F
 		return response.FinishDelivering{}, err
 	}
 
+	if err = s.recordStatusChange(ctx, tx, retrievedData.Transaction.ID, transaction.OrderStatusServed); err != nil {
+		return response.FinishDelivering{}, err
+	}
+
 	return response.FinishDelivering{}, nil
 }
+
+// CollectOrder serves a takeaway or pickup order once the customer has
+// collected it at the counter, in place of the waiter's delivery.
+func (s *transactionService) CollectOrder(ctx context.Context, req request.CollectOrder) (response.CollectOrder, error) {
+	validatedTransaction, err := validation.ValidateTransaction(s.transaction)
+	if err != nil {
+		return response.CollectOrder{}, err
+	}
+
+	tx, err := validatedTransaction.Begin(ctx)
+	if err != nil {
+		return response.CollectOrder{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = application.RecoveredFromPanic(r)
+		}
+		validatedTransaction.CommitOrRollback(ctx, tx, err)
+	}()
+
+	retrievedData, err := s.findTransaction(ctx, tx, req.TransactionID, req.QueueCode)
+	if err != nil {
+		return response.CollectOrder{}, err
+	}
+
+	if !retrievedData.Transaction.OrderType.IsPackaged() {
+		return response.CollectOrder{}, transaction.ErrorNotPackaged
+	}
+
+	if retrievedData.Transaction.OrderStatus.Status != transaction.OrderStatusReadyToServe {
+		return response.CollectOrder{}, transaction.ErrorInvalidOrderStatus
+	}
+
+	_, err = s.transactionRepository.UpdateTransactionDeliveringStatusFinish(ctx, tx, retrievedData.Transaction.ID.String())
+	if err != nil {
+		return response.CollectOrder{}, err
+	}
+
+	_, err = s.transactionRepository.UpdateServedAt(ctx, tx, retrievedData.Transaction.ID.String())
+	if err != nil {
+		return response.CollectOrder{}, err
+	}
+
+	if err = s.recordStatusChange(ctx, tx, retrievedData.Transaction.ID, transaction.OrderStatusServed); err != nil {
+		return response.CollectOrder{}, err
+	}
+
+	var orderResponses []response.OrderForTransaction
+	for _, orderQuery := range retrievedData.Orders {
+		orderResponses = append(orderResponses, response.OrderForTransaction{
+			Menu: response.MenuForTransaction{
+				ID:    orderQuery.Menu.ID.String(),
+				Name:  orderQuery.Menu.Name,
+				Price: orderQuery.UnitPrice().Price.String(),
+			},
+			Quantity: orderQuery.Order.Quantity,
+		})
+	}
+
+	return response.CollectOrder{
+		QueueCode: retrievedData.Transaction.QueueCode.Code,
+		Orders:    orderResponses,
+	}, nil
+}
+
+// resolveKitchenStation returns the station a kitchen action applies to. Users
+// and devices bound to a station always act on it; unbound ones may pick one
+// explicitly or act on the whole transaction when requestedStationID is empty.
+func (s *transactionService) resolveKitchenStation(ctx context.Context, tx interface{}, userID string, requestedStationID string) (string, error) {
+	boundStationID, err := s.boundKitchenStation(ctx, tx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if boundStationID != "" {
+		if requestedStationID != "" && requestedStationID != boundStationID {
+			return "", station.ErrorStationNotAllowed
+		}
+		return boundStationID, nil
+	}
+
+	if requestedStationID == "" {
+		return "", nil
+	}
+
+	retrievedStation, err := s.stationRepository.GetStationByID(ctx, tx, requestedStationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", station.ErrorStationNotFound
+		}
+		return "", err
+	}
+
+	return retrievedStation.ID.String(), nil
+}
+
+// boundKitchenStation is the station the caller is tied to: the station of a
+// device key, or the one assigned to the signed-in user.
+func (s *transactionService) boundKitchenStation(ctx context.Context, tx interface{}, userID string) (string, error) {
+	if actor := application.ActorFromContext(ctx); actor.IsDevice() {
+		return actor.StationID, nil
+	}
+
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, tx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if retrievedUser.StationID.IsEmpty() {
+		return "", nil
+	}
+	return retrievedUser.StationID.String(), nil
+}
+
+// recordStatusChange notes which user or device moved a transaction to status.
+func (s *transactionService) recordStatusChange(ctx context.Context, tx interface{}, transactionID identity.ID, status string) error {
+	if s.statusChangeRepository == nil {
+		return nil
+	}
+
+	actor := application.ActorFromContext(ctx)
+	statusChange := transaction.StatusChange{
+		ID:            identity.NewID(uuid.New()),
+		TransactionID: transactionID,
+		Status:        transaction.NewOrderStatusFromSchema(status),
+		CreatedAt:     s.clock.Now(),
+	}
+	if userID, err := uuid.Parse(actor.UserID); err == nil {
+		statusChange.UserID = identity.NewID(userID)
+	}
+	if deviceID, err := uuid.Parse(actor.DeviceID); err == nil {
+		statusChange.DeviceID = identity.NewID(deviceID)
+	}
+
+	_, err := s.statusChangeRepository.CreateStatusChange(ctx, tx, statusChange)
+	return err
+}
+
+func (s *transactionService) UpdatePriority(ctx context.Context, transactionID string, req request.UpdatePriority) (response.UpdatePriority, error) {
+	if _, err := s.transactionRepository.GetDetailedTransactionByID(ctx, nil, transactionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.UpdatePriority{}, transaction.ErrorTransactionNotFound
+		}
+		return response.UpdatePriority{}, err
+	}
+
+	updatedTransaction, err := s.transactionRepository.UpdatePriority(ctx, nil, transactionID, *req.Priority)
+	if err != nil {
+		return response.UpdatePriority{}, err
+	}
+
+	return response.UpdatePriority{
+		ID:        updatedTransaction.ID.String(),
+		QueueCode: updatedTransaction.QueueCode.Code,
+		Priority:  updatedTransaction.Priority,
+	}, nil
+}
+
+// GetStatusChanges lists the order status transitions of a transaction with
+// the user or device that made each of them.
+func (s *transactionService) GetStatusChanges(ctx context.Context, transactionID string) ([]response.StatusChange, error) {
+	if _, err := s.transactionRepository.GetDetailedTransactionByID(ctx, nil, transactionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, transaction.ErrorTransactionNotFound
+		}
+		return nil, err
+	}
+
+	statusChanges := make([]response.StatusChange, 0)
+	if s.statusChangeRepository == nil {
+		return statusChanges, nil
+	}
+
+	retrievedStatusChanges, err := s.statusChangeRepository.GetStatusChangesByTransactionID(ctx, nil, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statusChange := range retrievedStatusChanges {
+		statusChangeResponse := response.StatusChange{
+			Status:    statusChange.Status.Status,
+			ChangedAt: statusChange.CreatedAt,
+		}
+		if !statusChange.UserID.IsEmpty() {
+			statusChangeResponse.UserID = statusChange.UserID.String()
+		}
+		if !statusChange.DeviceID.IsEmpty() {
+			statusChangeResponse.DeviceID = statusChange.DeviceID.String()
+		}
+		statusChanges = append(statusChanges, statusChangeResponse)
+	}
+
+	return statusChanges, nil
+}
+
+func waitEstimateResponse(estimate transaction.WaitEstimate) response.WaitEstimate {
+	return response.WaitEstimate{
+		QueuePosition: estimate.QueuePosition,
+		ETA:           estimate.ETA,
+		EarliestETA:   estimate.EarliestETA,
+		LatestETA:     estimate.LatestETA,
+	}
+}
+
+// tableResponse leaves the table out of takeaway and pickup orders placed
+// without one.
+func tableResponse(tableEntity table.Table) *response.Table {
+	if tableEntity.ID.IsEmpty() {
+		return nil
+	}
+	return &response.Table{
+		ID:          tableEntity.ID.String(),
+		TableNumber: tableEntity.TableNumber,
+	}
+}
+
+func priceBreakdownResponse(breakdown order.PriceBreakdown) response.PriceBreakdown {
+	discounts := make([]response.Discount, 0, len(breakdown.Discounts))
+	for _, discount := range breakdown.Discounts {
+		discounts = append(discounts, response.Discount{
+			PromotionID: discount.PromotionID.String(),
+			Name:        discount.Name,
+			VoucherCode: discount.VoucherCode,
+			Amount:      discount.Amount.Price.String(),
+		})
+	}
+
+	return response.PriceBreakdown{
+		Subtotal:          breakdown.Subtotal.Price.String(),
+		Discounts:         discounts,
+		Discount:          breakdown.Discount.Price.String(),
+		LoyaltyPoints:     breakdown.LoyaltyPoints,
+		LoyaltyDiscount:   breakdown.LoyaltyDiscount.Price.String(),
+		PackagingFee:      breakdown.PackagingFee.Price.String(),
+		ServiceChargeRate: breakdown.ServiceChargeRate.String(),
+		ServiceCharge:     breakdown.ServiceCharge.Price.String(),
+		TaxRate:           breakdown.TaxRate.String(),
+		Tax:               breakdown.Tax.Price.String(),
+		Rounding:          breakdown.Rounding.Price.String(),
+		Total:             breakdown.Total.Price.String(),
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"fp-kpl/application"
 	"fp-kpl/application/request"
 	"fp-kpl/application/response"
+	"fp-kpl/domain/shared"
 	"fp-kpl/domain/user"
+	"fp-kpl/domain/verification"
 	"fp-kpl/infrastructure/database/validation"
+	"log"
+
 	"gorm.io/gorm"
 )
 
@@ -15,29 +19,44 @@ type (
 	UserService interface {
 		Register(ctx context.Context, req request.UserRegister) (response.UserRegister, error)
 		GetUserByID(ctx context.Context, userID string) (response.User, error)
+		Me(ctx context.Context, userID string) (response.User, error)
+		UpdateProfile(ctx context.Context, userID string, req request.UserUpdateProfile) (response.User, error)
+		DeleteAccount(ctx context.Context, userID string, req request.UserDelete) error
 		GetUserByEmail(ctx context.Context, email string) (response.User, error)
 		Verify(ctx context.Context, req request.UserLogin) (response.AccessToken, error)
 	}
 
 	userService struct {
-		userRepository user.Repository
-		jwtService     JWTService
-		transaction    interface{}
+		userRepository      user.Repository
+		loyaltyService      LoyaltyService
+		verificationService VerificationService
+		jwtService          JWTService
+		clock               shared.Clock
+		transaction         interface{}
 	}
 )
 
 func NewUserService(
 	userRepository user.Repository,
+	loyaltyService LoyaltyService,
+	verificationService VerificationService,
 	jwtService JWTService,
+	clock shared.Clock,
 	transaction interface{},
 ) UserService {
 	return &userService{
-		userRepository: userRepository,
-		jwtService:     jwtService,
-		transaction:    transaction,
+		userRepository:      userRepository,
+		loyaltyService:      loyaltyService,
+		verificationService: verificationService,
+		jwtService:          jwtService,
+		clock:               clock,
+		transaction:         transaction,
 	}
 }
 
+// Register creates a customer and sends codes to verify their email and, if
+// they gave one, their phone number. The account is created even when the
+// codes cannot be sent; the customer can ask for them again.
 func (s *userService) Register(ctx context.Context, req request.UserRegister) (response.UserRegister, error) {
 	_, alreadyExists, err := s.userRepository.CheckEmail(ctx, nil, req.Email)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -70,6 +89,8 @@ func (s *userService) Register(ctx context.Context, req request.UserRegister) (r
 		return response.UserRegister{}, user.ErrorCreateUser
 	}
 
+	s.sendVerificationCodes(ctx, registeredUser, verification.Channels...)
+
 	return response.UserRegister{
 		ID:          registeredUser.ID.String(),
 		Email:       registeredUser.Email,
@@ -85,13 +106,24 @@ func (s *userService) GetUserByID(ctx context.Context, userID string) (response.
 		return response.User{}, user.ErrorGetUserById
 	}
 
-	return response.User{
-		ID:          retrievedUser.ID.String(),
-		Email:       retrievedUser.Email,
-		Name:        retrievedUser.Name,
-		PhoneNumber: retrievedUser.PhoneNumber,
-		Role:        retrievedUser.Role.Name,
-	}, nil
+	return userResponse(retrievedUser), nil
+}
+
+// Me is the customer's own profile, with their loyalty points balance and
+// history.
+func (s *userService) Me(ctx context.Context, userID string) (response.User, error) {
+	retrievedUser, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return response.User{}, err
+	}
+
+	account, err := s.loyaltyService.GetAccount(ctx, userID)
+	if err != nil {
+		return response.User{}, err
+	}
+	retrievedUser.Loyalty = &account
+
+	return retrievedUser, nil
 }
 
 func (s *userService) GetUserByEmail(ctx context.Context, email string) (response.User, error) {
@@ -100,13 +132,109 @@ func (s *userService) GetUserByEmail(ctx context.Context, email string) (respons
 		return response.User{}, user.ErrorGetUserByEmail
 	}
 
+	return userResponse(retrievedUser), nil
+}
+
+// UpdateProfile changes the customer's name, phone number or email. A new
+// email needs the current password and must not belong to another account.
+// A new email or phone number is unverified until confirmed again, and a code
+// is sent to it.
+func (s *userService) UpdateProfile(ctx context.Context, userID string, req request.UserUpdateProfile) (response.User, error) {
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return response.User{}, user.ErrorGetUserById
+	}
+	previousEmail, previousPhoneNumber := retrievedUser.Email, retrievedUser.PhoneNumber
+
+	if req.Name != "" {
+		retrievedUser.Name = req.Name
+	}
+	if req.PhoneNumber != "" {
+		retrievedUser.ChangePhone(req.PhoneNumber)
+	}
+
+	if req.Email != "" && req.Email != retrievedUser.Email {
+		if req.CurrentPassword == "" {
+			return response.User{}, user.ErrorPasswordRequired
+		}
+		if match, _ := retrievedUser.Password.IsPasswordMatch([]byte(req.CurrentPassword)); !match {
+			return response.User{}, user.ErrorInvalidPassword
+		}
+
+		_, alreadyExists, err := s.userRepository.CheckEmail(ctx, nil, req.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.User{}, err
+		}
+		if alreadyExists {
+			return response.User{}, user.ErrorEmailAlreadyExists
+		}
+
+		retrievedUser.ChangeEmail(req.Email)
+	}
+
+	updatedUser, err := s.userRepository.UpdateUser(ctx, nil, retrievedUser)
+	if err != nil {
+		return response.User{}, user.ErrorUpdateUser
+	}
+
+	var changedChannels []string
+	if updatedUser.Email != previousEmail {
+		changedChannels = append(changedChannels, verification.ChannelEmail)
+	}
+	if updatedUser.PhoneNumber != previousPhoneNumber {
+		changedChannels = append(changedChannels, verification.ChannelPhone)
+	}
+	s.sendVerificationCodes(ctx, updatedUser, changedChannels...)
+
+	return userResponse(updatedUser), nil
+}
+
+// DeleteAccount anonymises the customer after checking their password. Past
+// transactions keep pointing at the anonymised account, so revenue, payments
+// and refunds still add up in reports.
+func (s *userService) DeleteAccount(ctx context.Context, userID string, req request.UserDelete) error {
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return user.ErrorGetUserById
+	}
+
+	if !retrievedUser.CanDeleteItself() {
+		return user.ErrorDeletionNotAllowed
+	}
+	if match, _ := retrievedUser.Password.IsPasswordMatch([]byte(req.Password)); !match {
+		return user.ErrorInvalidPassword
+	}
+
+	if err = s.userRepository.DeleteUser(ctx, nil, retrievedUser.Anonymise(s.clock.Now())); err != nil {
+		return user.ErrorDeleteUser
+	}
+	return nil
+}
+
+// sendVerificationCodes sends a code for each channel the user has an
+// unverified address on. A failure is only logged, since the user can request
+// the code again.
+func (s *userService) sendVerificationCodes(ctx context.Context, userEntity user.User, channels ...string) {
+	for _, channel := range channels {
+		if verification.Destination(userEntity, channel) == "" || verification.IsVerified(userEntity, channel) {
+			continue
+		}
+		if _, err := s.verificationService.SendCode(ctx, userEntity.ID.String(), channel); err != nil {
+			log.Printf("failed to send %s verification code to user %s: %v", channel, userEntity.ID.String(), err)
+		}
+	}
+}
+
+func userResponse(userEntity user.User) response.User {
 	return response.User{
-		ID:          retrievedUser.ID.String(),
-		Email:       retrievedUser.Email,
-		Name:        retrievedUser.Name,
-		PhoneNumber: retrievedUser.PhoneNumber,
-		Role:        retrievedUser.Role.Name,
-	}, nil
+		ID:            userEntity.ID.String(),
+		Email:         userEntity.Email,
+		EmailVerified: userEntity.IsEmailVerified(),
+		Name:          userEntity.Name,
+		PhoneNumber:   userEntity.PhoneNumber,
+		PhoneVerified: userEntity.IsPhoneVerified(),
+		Role:          userEntity.Role.Name,
+	}
 }
 
 func (s *userService) Verify(ctx context.Context, req request.UserLogin) (response.AccessToken, error) {
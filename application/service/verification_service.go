@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/user"
+	"fp-kpl/domain/verification"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationLinkPath is where the link sent by email points, relative to
+// the app URL.
+const VerificationLinkPath = "/api/user/verification/email"
+
+type (
+	VerificationService interface {
+		GetStatus(ctx context.Context, userID string) (response.Verification, error)
+		SendCode(ctx context.Context, userID string, channel string) (response.VerificationSent, error)
+		Verify(ctx context.Context, userID string, req request.VerificationVerify) (response.Verification, error)
+		VerifyLink(ctx context.Context, req request.VerificationLink) (response.Verification, error)
+		// CheckCanOrder returns user.ErrorUnverified when orders require a
+		// verified account and the user's is not.
+		CheckCanOrder(ctx context.Context, userID string) error
+	}
+
+	verificationService struct {
+		verificationRepository verification.Repository
+		userRepository         user.Repository
+		notifier               port.VerificationNotifierPort
+		policy                 verification.Policy
+		clock                  shared.Clock
+		appURL                 string
+		requireVerifiedToOrder bool
+	}
+)
+
+func NewVerificationService(
+	verificationRepository verification.Repository,
+	userRepository user.Repository,
+	notifier port.VerificationNotifierPort,
+	policy verification.Policy,
+	clock shared.Clock,
+	appURL string,
+	requireVerifiedToOrder bool,
+) VerificationService {
+	return &verificationService{
+		verificationRepository: verificationRepository,
+		userRepository:         userRepository,
+		notifier:               notifier,
+		policy:                 policy,
+		clock:                  clock,
+		appURL:                 appURL,
+		requireVerifiedToOrder: requireVerifiedToOrder,
+	}
+}
+
+func (s *verificationService) GetStatus(ctx context.Context, userID string) (response.Verification, error) {
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return response.Verification{}, user.ErrorGetUserById
+	}
+
+	return s.status(retrievedUser), nil
+}
+
+// SendCode sends a new code to the user's email or phone number. It replaces
+// any earlier code of the channel, and is throttled by the policy. The new
+// code is stored as the successor of the latest one, so concurrent sends
+// checked against the same latest code cannot both get through.
+func (s *verificationService) SendCode(ctx context.Context, userID string, channel string) (response.VerificationSent, error) {
+	channel, err := verification.NewChannel(channel)
+	if err != nil {
+		return response.VerificationSent{}, err
+	}
+
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return response.VerificationSent{}, user.ErrorGetUserById
+	}
+
+	destination := verification.Destination(retrievedUser, channel)
+	if destination == "" {
+		return response.VerificationSent{}, verification.ErrorNoDestination
+	}
+	if verification.IsVerified(retrievedUser, channel) {
+		return response.VerificationSent{}, verification.ErrorAlreadyVerified
+	}
+
+	now := s.clock.Now()
+
+	var latest *verification.Challenge
+	latestChallenge, err := s.verificationRepository.GetLatestChallenge(ctx, nil, userID, channel)
+	if err == nil {
+		latest = &latestChallenge
+	} else if !errors.Is(err, verification.ErrorChallengeNotFound) {
+		return response.VerificationSent{}, verification.ErrorGetChallenge
+	}
+
+	sentLastHour, err := s.verificationRepository.CountChallengesSince(ctx, nil, userID, channel, now.Add(-time.Hour))
+	if err != nil {
+		return response.VerificationSent{}, verification.ErrorGetChallenge
+	}
+
+	if err = s.policy.CheckResend(latest, sentLastHour, now); err != nil {
+		return response.VerificationSent{}, err
+	}
+
+	code, err := verification.NewCode()
+	if err != nil {
+		return response.VerificationSent{}, verification.ErrorCreateChallenge
+	}
+
+	challenge := s.policy.NewChallenge(retrievedUser.ID, channel, destination, code, now)
+	challenge.ID = identity.NewID(uuid.New())
+	if latest != nil {
+		challenge.ReplacesID = latest.ID
+	}
+	challenge, err = s.verificationRepository.CreateChallenge(ctx, nil, challenge)
+	if err != nil {
+		if errors.Is(err, verification.ErrorResendTooSoon) {
+			return response.VerificationSent{}, err
+		}
+		return response.VerificationSent{}, verification.ErrorCreateChallenge
+	}
+
+	msg := verification.Message{
+		Channel:     channel,
+		Destination: destination,
+		Code:        code,
+		ExpiresAt:   challenge.ExpiresAt,
+	}
+	if channel == verification.ChannelEmail {
+		msg.Link = s.link(challenge.ID.String(), code)
+	}
+
+	if err = s.notifier.SendVerification(ctx, msg); err != nil {
+		log.Printf("failed to send %s verification to user %s: %v", channel, userID, err)
+		return response.VerificationSent{}, verification.ErrorSendCode
+	}
+
+	return response.VerificationSent{
+		Channel:     channel,
+		Destination: verification.MaskDestination(destination),
+		ExpiresAt:   challenge.ExpiresAt,
+		ResendAfter: s.policy.ResendAfter(challenge),
+	}, nil
+}
+
+// Verify checks a code typed by the user against the latest one sent to the
+// channel.
+func (s *verificationService) Verify(ctx context.Context, userID string, req request.VerificationVerify) (response.Verification, error) {
+	channel, err := verification.NewChannel(req.Channel)
+	if err != nil {
+		return response.Verification{}, err
+	}
+
+	challenge, err := s.verificationRepository.GetLatestChallenge(ctx, nil, userID, channel)
+	if err != nil {
+		if errors.Is(err, verification.ErrorChallengeNotFound) {
+			return response.Verification{}, err
+		}
+		return response.Verification{}, verification.ErrorGetChallenge
+	}
+
+	return s.verify(ctx, challenge, req.Code)
+}
+
+// VerifyLink confirms an email from the link in the message. The link stops
+// working once a newer code is sent.
+func (s *verificationService) VerifyLink(ctx context.Context, req request.VerificationLink) (response.Verification, error) {
+	challenge, err := s.verificationRepository.GetChallengeByID(ctx, nil, req.Challenge)
+	if err != nil {
+		if errors.Is(err, verification.ErrorChallengeNotFound) {
+			return response.Verification{}, err
+		}
+		return response.Verification{}, verification.ErrorGetChallenge
+	}
+
+	latest, err := s.verificationRepository.GetLatestChallenge(ctx, nil, challenge.UserID.String(), challenge.Channel)
+	if err != nil {
+		return response.Verification{}, verification.ErrorGetChallenge
+	}
+	if latest.ID != challenge.ID {
+		return response.Verification{}, verification.ErrorCodeExpired
+	}
+
+	return s.verify(ctx, challenge, req.Code)
+}
+
+func (s *verificationService) CheckCanOrder(ctx context.Context, userID string) error {
+	if !s.requireVerifiedToOrder {
+		return nil
+	}
+
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		return user.ErrorGetUserById
+	}
+
+	return retrievedUser.CheckCanOrder(s.requireVerifiedToOrder)
+}
+
+// verify counts the guess before comparing the code, so concurrent guesses
+// cannot get past the attempt limit.
+func (s *verificationService) verify(ctx context.Context, challenge verification.Challenge, code string) (response.Verification, error) {
+	retrievedUser, err := s.userRepository.GetUserByID(ctx, nil, challenge.UserID.String())
+	if err != nil {
+		return response.Verification{}, user.ErrorGetUserById
+	}
+
+	now := s.clock.Now()
+	if err = challenge.CheckUsable(verification.Destination(retrievedUser, challenge.Channel), now, s.policy.MaxAttempts); err != nil {
+		return response.Verification{}, err
+	}
+
+	if err = s.verificationRepository.RecordAttempt(ctx, nil, challenge.ID.String(), s.policy.MaxAttempts); err != nil {
+		return response.Verification{}, err
+	}
+
+	if !challenge.Matches(code) {
+		attemptsLeft := s.policy.MaxAttempts - challenge.Attempts - 1
+		if attemptsLeft <= 0 {
+			return response.Verification{}, verification.ErrorTooManyAttempts
+		}
+		return response.Verification{}, fmt.Errorf("%w, %d attempts left", verification.ErrorInvalidCode, attemptsLeft)
+	}
+
+	if err = s.verificationRepository.MarkVerified(ctx, nil, challenge, now); err != nil {
+		return response.Verification{}, err
+	}
+
+	if challenge.Channel == verification.ChannelPhone {
+		retrievedUser.PhoneVerifiedAt = &now
+	} else {
+		retrievedUser.EmailVerifiedAt = &now
+	}
+	return s.status(retrievedUser), nil
+}
+
+func (s *verificationService) status(userEntity user.User) response.Verification {
+	return response.Verification{
+		EmailVerified: userEntity.IsEmailVerified(),
+		PhoneVerified: userEntity.IsPhoneVerified(),
+		CanOrder:      userEntity.CheckCanOrder(s.requireVerifiedToOrder) == nil,
+	}
+}
+
+func (s *verificationService) link(challengeID string, code string) string {
+	query := url.Values{}
+	query.Set("challenge", challengeID)
+	query.Set("code", code)
+	return s.appURL + VerificationLinkPath + "?" + query.Encode()
+}
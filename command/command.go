@@ -1,6 +1,8 @@
 package command
 
 import (
+	"context"
+	"fp-kpl/application/service"
 	"fp-kpl/infrastructure/database/migration"
 	"log"
 	"os"
@@ -8,9 +10,10 @@ import (
 	"gorm.io/gorm"
 )
 
-func Commands(db *gorm.DB) bool {
+func Commands(db *gorm.DB, reconciliationService service.ReconciliationService) bool {
 	migrate := false
 	seed := false
+	reconcile := false
 	run := false
 
 	for _, arg := range os.Args[1:] {
@@ -20,6 +23,9 @@ func Commands(db *gorm.DB) bool {
 		if arg == "--seed" {
 			seed = true
 		}
+		if arg == "--reconcile" {
+			reconcile = true
+		}
 		if arg == "--run" {
 			run = true
 		}
@@ -39,6 +45,15 @@ func Commands(db *gorm.DB) bool {
 		log.Println("seeder completed successfully")
 	}
 
+	if reconcile {
+		report, err := reconciliationService.Reconcile(context.Background())
+		if err != nil {
+			log.Fatalf("error reconciliation: %v", err)
+		}
+		log.Printf("reconciliation completed: %d checked, %d updated, %d failed, %d discrepancies",
+			report.Checked, report.Updated, report.Failed, len(report.Discrepancies))
+	}
+
 	if run {
 		return true
 	}
@@ -0,0 +1,93 @@
+package cart
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"time"
+)
+
+// DefaultIdleTimeout is how long a cart is kept after its last change.
+const DefaultIdleTimeout = 2 * time.Hour
+
+type (
+	// Cart holds what a customer is about to order. A cart belongs either to
+	// one user or, when TableID is set, to a table whose guests share it.
+	Cart struct {
+		ID          identity.ID
+		UserID      identity.ID
+		TableID     identity.ID
+		OrderType   transaction.OrderType
+		VoucherCode string
+		Lines       []Line
+		// MemberIDs are the users who joined a shared cart with the
+		// table's token.
+		MemberIDs     []identity.ID
+		ExpiresAt     time.Time
+		CheckedOutAt  *time.Time
+		TransactionID identity.ID
+		shared.Timestamp
+	}
+
+	Line struct {
+		ID       identity.ID
+		CartID   identity.ID
+		MenuID   identity.ID
+		Quantity int
+		// AddedBy is the user who added the line to a shared cart, if any.
+		AddedBy identity.ID
+	}
+)
+
+func (c Cart) IsShared() bool {
+	return !c.TableID.IsEmpty()
+}
+
+// CanAccess tells whether a user, or a kiosk bound to a table, may use the
+// cart. A shared cart is open to the guests who joined it and to the
+// table's kiosk.
+func (c Cart) CanAccess(userID string, tableID string) bool {
+	if c.IsShared() {
+		return tableID == c.TableID.String() || c.HasMember(userID)
+	}
+	return userID != "" && c.UserID.String() == userID
+}
+
+func (c Cart) HasMember(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, memberID := range c.MemberIDs {
+		if memberID.String() == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOpen tells why the cart can no longer be changed, if it cannot.
+func (c Cart) CheckOpen(now time.Time) error {
+	if c.CheckedOutAt != nil {
+		return ErrorCartCheckedOut
+	}
+	if !now.Before(c.ExpiresAt) {
+		return ErrorCartExpired
+	}
+	return nil
+}
+
+func (c Cart) Line(lineID string) (Line, bool) {
+	for _, line := range c.Lines {
+		if line.ID.String() == lineID {
+			return line, true
+		}
+	}
+	return Line{}, false
+}
+
+func ValidateQuantity(quantity int) error {
+	if quantity <= 0 {
+		return ErrorInvalidQuantity
+	}
+	return nil
+}
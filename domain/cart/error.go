@@ -0,0 +1,17 @@
+package cart
+
+import "errors"
+
+var (
+	ErrorCreateCart              = errors.New("failed to create cart")
+	ErrorUpdateCart              = errors.New("failed to update cart")
+	ErrorCartNotFound            = errors.New("cart not found")
+	ErrorCartLineNotFound        = errors.New("cart line not found")
+	ErrorCartExpired             = errors.New("cart has expired")
+	ErrorCartCheckedOut          = errors.New("cart is already checked out")
+	ErrorCartAlreadyOpen         = errors.New("table already has an open cart")
+	ErrorNotCartMember           = errors.New("cart belongs to another user")
+	ErrorCartEmpty               = errors.New("cart is empty")
+	ErrorCartHasUnavailableItems = errors.New("cart has items that cannot be ordered")
+	ErrorInvalidQuantity         = errors.New("quantity must be above 0")
+)
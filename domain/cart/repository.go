@@ -0,0 +1,42 @@
+package cart
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	Repository interface {
+		// CreateCart returns ErrorCartAlreadyOpen when the cart's table has
+		// an open cart already, giving up any of its carts that expired.
+		CreateCart(ctx context.Context, tx interface{}, cartEntity Cart) (Cart, error)
+		GetCartByID(ctx context.Context, tx interface{}, id string) (Cart, error)
+		// GetOpenCart finds the table's cart when tableID is set and the
+		// user's own cart otherwise, skipping carts checked out or expired
+		// at the time.
+		GetOpenCart(ctx context.Context, tx interface{}, userID string, tableID string, at time.Time) (Cart, error)
+		// JoinCart lets a guest use the table's shared cart. Joining twice
+		// is a no-op.
+		JoinCart(ctx context.Context, tx interface{}, id string, userID string) error
+		// UpdateCart saves the order type, voucher and expiry of an open
+		// cart, and returns ErrorCartCheckedOut once it is checked out or
+		// given up. Its
+		// lines are changed on their own so guests sharing a cart do not
+		// overwrite each other.
+		UpdateCart(ctx context.Context, tx interface{}, cartEntity Cart) (Cart, error)
+		// CheckOutCart marks the cart checked out unless it already is, so a
+		// shared cart cannot be checked out twice.
+		CheckOutCart(ctx context.Context, tx interface{}, id string, at time.Time) error
+		// ReopenCart undoes CheckOutCart when no transaction came of it.
+		ReopenCart(ctx context.Context, tx interface{}, id string) error
+		// LinkCartTransaction records the transaction a checked out cart
+		// became.
+		LinkCartTransaction(ctx context.Context, tx interface{}, id string, transactionID string) error
+		// AddCartLine adds the line, or its quantity to the line already
+		// holding the menu, in one statement so guests adding at the same
+		// time do not lose each other's quantities.
+		AddCartLine(ctx context.Context, tx interface{}, line Line) error
+		UpdateCartLineQuantity(ctx context.Context, tx interface{}, lineID string, quantity int) error
+		DeleteCartLine(ctx context.Context, tx interface{}, lineID string) error
+	}
+)
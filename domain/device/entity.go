@@ -0,0 +1,28 @@
+package device
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/user"
+	"time"
+)
+
+// Device is a kitchen display, waiter tablet, queue board or kiosk that
+// signs in with a long-lived API key instead of a user account. Only a hash
+// of the key is stored; KeyPrefix lets staff tell keys apart.
+type Device struct {
+	ID         identity.ID
+	Name       string
+	Role       user.Role
+	StationID  identity.ID
+	TableID    identity.ID
+	KeyPrefix  string
+	KeyHash    string
+	LastSeenAt *time.Time
+	RevokedAt  *time.Time
+	shared.Timestamp
+}
+
+func (d Device) IsRevoked() bool {
+	return d.RevokedAt != nil
+}
@@ -0,0 +1,13 @@
+package device
+
+import "errors"
+
+var (
+	ErrorDeviceNotFound    = errors.New("device not found")
+	ErrorInvalidDeviceRole = errors.New("invalid device role")
+	ErrorInvalidDeviceKey  = errors.New("invalid device key")
+	ErrorDeviceRevoked     = errors.New("device has been revoked")
+	ErrorCreateDevice      = errors.New("failed to create device")
+	ErrorGetAllDevices     = errors.New("failed to get all devices")
+	ErrorRevokeDevice      = errors.New("failed to revoke device")
+)
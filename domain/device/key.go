@@ -0,0 +1,37 @@
+package device
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	KeyPrefix       = "dk_"
+	keyBytes        = 32
+	keyPrefixLength = len(KeyPrefix) + 8
+)
+
+// GenerateKey returns a new random API key with the prefix shown in device
+// listings. The key itself is only handed out once.
+func GenerateKey() (key string, prefix string, err error) {
+	secret := make([]byte, keyBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	key = KeyPrefix + hex.EncodeToString(secret)
+	return key, key[:keyPrefixLength], nil
+}
+
+// HashKey is the digest stored for a key. The keys are random and long, so a
+// plain SHA-256 is enough to keep a database leak from exposing them.
+func HashKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+func IsKey(value string) bool {
+	return strings.HasPrefix(value, KeyPrefix) && len(value) == len(KeyPrefix)+2*keyBytes
+}
@@ -0,0 +1,15 @@
+package device
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateDevice(ctx context.Context, tx interface{}, deviceEntity Device) (Device, error)
+	GetAllDevices(ctx context.Context, tx interface{}) ([]Device, error)
+	GetDeviceByID(ctx context.Context, tx interface{}, id string) (Device, error)
+	GetDeviceByKeyHash(ctx context.Context, tx interface{}, keyHash string) (Device, error)
+	RevokeDevice(ctx context.Context, tx interface{}, id string, revokedAt time.Time) (Device, error)
+	UpdateLastSeen(ctx context.Context, tx interface{}, id string, lastSeenAt time.Time) error
+}
@@ -0,0 +1,29 @@
+package device
+
+import (
+	"fmt"
+	"fp-kpl/domain/user"
+)
+
+const (
+	RoleDisplay = "display"
+	RoleKiosk   = "kiosk"
+)
+
+// Roles are the permission sets a device key can carry. Devices never act as
+// customers, cashiers or administrators.
+var Roles = []user.Role{
+	{Name: user.RoleKitchen},
+	{Name: user.RoleWaiter},
+	{Name: RoleDisplay},
+	{Name: RoleKiosk},
+}
+
+func NewRole(name string) (user.Role, error) {
+	for _, role := range Roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return user.Role{}, fmt.Errorf("%w: %s", ErrorInvalidDeviceRole, name)
+}
@@ -0,0 +1,99 @@
+package feedback
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultSubmissionWindow is how long after an order is served its customer
+// may still leave feedback.
+const DefaultSubmissionWindow = 24 * time.Hour
+
+type (
+	// Feedback is a customer's opinion of a served transaction: an overall
+	// rating, optional ratings of the menus ordered and an optional comment.
+	// Each transaction has at most one.
+	Feedback struct {
+		ID            identity.ID
+		TransactionID identity.ID
+		UserID        identity.ID
+		Rating        Rating
+		Comment       string
+		MenuRatings   []MenuRating
+		shared.Timestamp
+	}
+
+	MenuRating struct {
+		MenuID identity.ID
+		Rating Rating
+	}
+
+	// Query is a feedback with the names a reviewer reads it by.
+	Query struct {
+		Feedback  Feedback
+		UserName  string
+		QueueCode string
+		// MenuNames maps the ID of each rated menu to its name.
+		MenuNames map[string]string
+	}
+
+	// MenuSummary aggregates every rating a menu has received.
+	MenuSummary struct {
+		MenuID  identity.ID
+		Average decimal.Decimal
+		Count   int
+	}
+
+	// Filter narrows the feedback listing; zero values do not filter.
+	Filter struct {
+		MenuID     string
+		MinRating  int
+		MaxRating  int
+		From       *time.Time
+		To         *time.Time
+		HasComment *bool
+	}
+)
+
+// CheckSubmittable tells whether userID may leave feedback on the
+// transaction at now: only its customer, once it is served and until the
+// window has passed.
+func CheckSubmittable(transactionEntity transaction.Transaction, userID string, now time.Time, window time.Duration) error {
+	if transactionEntity.UserID.IsEmpty() || transactionEntity.UserID.String() != userID {
+		return transaction.ErrorNotTransactionOwner
+	}
+
+	if transactionEntity.OrderStatus.Status != transaction.OrderStatusServed || transactionEntity.ServedAt == nil {
+		return ErrorNotServed
+	}
+
+	if now.After(transactionEntity.ServedAt.Add(window)) {
+		return ErrorSubmissionClosed
+	}
+
+	return nil
+}
+
+// ValidateMenuRatings checks that every rated menu was ordered in the
+// transaction and is rated only once.
+func (f Feedback) ValidateMenuRatings(orderedMenuIDs []identity.ID) error {
+	ordered := make(map[string]bool, len(orderedMenuIDs))
+	for _, menuID := range orderedMenuIDs {
+		ordered[menuID.String()] = true
+	}
+
+	rated := make(map[string]bool, len(f.MenuRatings))
+	for _, menuRating := range f.MenuRatings {
+		menuID := menuRating.MenuID.String()
+		if !ordered[menuID] || rated[menuID] {
+			return ErrorInvalidMenuRating
+		}
+		rated[menuID] = true
+	}
+
+	return nil
+}
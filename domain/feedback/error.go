@@ -0,0 +1,14 @@
+package feedback
+
+import "errors"
+
+var (
+	ErrorCreateFeedback           = errors.New("failed to create feedback")
+	ErrorGetAllFeedback           = errors.New("failed to get all feedback")
+	ErrorGetMenuRatings           = errors.New("failed to get menu ratings")
+	ErrorInvalidRating            = errors.New("rating must be between 1 and 5")
+	ErrorInvalidMenuRating        = errors.New("rated menu was not ordered or is rated twice")
+	ErrorNotServed                = errors.New("transaction has not been served")
+	ErrorSubmissionClosed         = errors.New("feedback can no longer be submitted for this transaction")
+	ErrorFeedbackAlreadySubmitted = errors.New("feedback was already submitted for this transaction")
+)
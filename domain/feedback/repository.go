@@ -0,0 +1,18 @@
+package feedback
+
+import (
+	"context"
+	"fp-kpl/platform/pagination"
+)
+
+type Repository interface {
+	// CreateFeedback stores the feedback with its menu ratings, or returns
+	// ErrorFeedbackAlreadySubmitted if the transaction already has one. It
+	// writes more than one row, so the caller runs it in tx.
+	CreateFeedback(ctx context.Context, tx interface{}, feedbackEntity Feedback) (Feedback, error)
+	// GetAllFeedback lists feedback as Query values, newest first.
+	GetAllFeedback(ctx context.Context, tx interface{}, filter Filter, req pagination.Request) (pagination.ResponseWithData, error)
+	// GetMenuSummaries aggregates the ratings of the given menus; menus
+	// that were never rated are left out.
+	GetMenuSummaries(ctx context.Context, tx interface{}, menuIDs []string) ([]MenuSummary, error)
+}
@@ -0,0 +1,28 @@
+package feedback
+
+import "fmt"
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Rating is a score from MinRating to MaxRating stars.
+type Rating struct {
+	Value int
+}
+
+func NewRating(value int) (Rating, error) {
+	if value < MinRating || value > MaxRating {
+		return Rating{}, fmt.Errorf("%w: %d", ErrorInvalidRating, value)
+	}
+	return Rating{
+		Value: value,
+	}, nil
+}
+
+func NewRatingFromSchema(value int) Rating {
+	return Rating{
+		Value: value,
+	}
+}
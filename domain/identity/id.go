@@ -21,3 +21,7 @@ func NewIDFromSchema(id uuid.UUID) ID {
 func (i ID) String() string {
 	return i.ID.String()
 }
+
+func (i ID) IsEmpty() bool {
+	return i.ID == uuid.Nil
+}
@@ -0,0 +1,73 @@
+package loyalty
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	EntryTypeEarn     = "earn"
+	EntryTypeRedeem   = "redeem"
+	EntryTypeReversal = "reversal"
+	EntryTypeExpire   = "expire"
+
+	// DefaultSyncLookback is how far back the ledger sync looks for paid,
+	// cancelled and refunded transactions. Runs are idempotent, so a window
+	// longer than the sync interval only makes missed runs harmless.
+	DefaultSyncLookback = 24 * time.Hour
+
+	// DefaultHistoryLimit is how many of the latest entries a customer sees.
+	DefaultHistoryLimit = 50
+)
+
+type (
+	// Entry is one line of a customer's points ledger. Entries are never
+	// changed or deleted: a refund or a cancellation is undone by a reversal
+	// pointing at the entry it undoes. Points are positive when credited and
+	// negative when redeemed, reversed or expired.
+	Entry struct {
+		ID            identity.ID
+		UserID        identity.ID
+		TransactionID identity.ID
+		Type          string
+		Points        int64
+		// ExpiresAt is only set on credited points.
+		ExpiresAt *time.Time
+		// RelatedEntryID is the entry a reversal undoes or whose points
+		// expired.
+		RelatedEntryID identity.ID
+		CreatedAt      time.Time
+	}
+
+	// Account is the ledger of one customer in the order it was written.
+	Account struct {
+		UserID  identity.ID
+		Entries []Entry
+	}
+
+	// Settlement is what the ledger needs to know of a customer's
+	// transaction: whether it was paid and how much of it was refunded.
+	Settlement struct {
+		TransactionID identity.ID
+		UserID        identity.ID
+		Payment       transaction.Payment
+		Total         shared.Price
+		Refunded      decimal.Decimal
+	}
+)
+
+// IsVoided reports whether the transaction ended without being paid, or its
+// payment was cancelled after all.
+func (s Settlement) IsVoided() bool {
+	return !s.Payment.IsPaid() && s.Payment.Status != transaction.PaymentStatusPending
+}
+
+// IsFullyRefunded reports whether everything paid for the transaction was
+// given back.
+func (s Settlement) IsFullyRefunded() bool {
+	return s.Payment.IsPaid() && s.Refunded.GreaterThanOrEqual(s.Total.Price)
+}
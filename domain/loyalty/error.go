@@ -0,0 +1,12 @@
+package loyalty
+
+import "errors"
+
+var (
+	ErrorInvalidPolicy      = errors.New("invalid loyalty policy")
+	ErrorGetAccount         = errors.New("failed to get loyalty account")
+	ErrorCreateEntries      = errors.New("failed to create loyalty entries")
+	ErrorInvalidPoints      = errors.New("points must be positive")
+	ErrorInsufficientPoints = errors.New("not enough loyalty points")
+	ErrorAccountRequired    = errors.New("points can only be redeemed by a registered customer")
+)
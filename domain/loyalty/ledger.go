@@ -0,0 +1,278 @@
+package loyalty
+
+import (
+	"fp-kpl/domain/identity"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// lot is what is left of one credit entry after replaying the ledger.
+// Expired counts the points that lapsed unspent.
+type lot struct {
+	entryID   identity.ID
+	remaining int64
+	expired   int64
+	expiresAt time.Time
+}
+
+// Balance is the points the customer can redeem at the given time. It is
+// negative when a reversal took back points that were already spent; later
+// credits pay that off first.
+func (a Account) Balance(at time.Time) int64 {
+	lots, deficit := a.replay()
+
+	var balance int64
+	for _, l := range lots {
+		if l.expiresAt.After(at) {
+			balance += l.remaining
+		}
+	}
+	return balance - deficit
+}
+
+// NextExpiry is the earliest date points lapse after the given time and how
+// many points lapse then.
+func (a Account) NextExpiry(at time.Time) (int64, *time.Time) {
+	lots, _ := a.replay()
+
+	var points int64
+	var expiresAt *time.Time
+	for _, l := range lots {
+		if l.remaining <= 0 || !l.expiresAt.After(at) {
+			continue
+		}
+		switch {
+		case expiresAt == nil || l.expiresAt.Before(*expiresAt):
+			lotExpiresAt := l.expiresAt
+			expiresAt = &lotExpiresAt
+			points = l.remaining
+		case l.expiresAt.Equal(*expiresAt):
+			points += l.remaining
+		}
+	}
+	return points, expiresAt
+}
+
+// CanRedeem checks that the points are available at the given time.
+func (a Account) CanRedeem(points int64, at time.Time) error {
+	if points <= 0 {
+		return ErrorInvalidPoints
+	}
+	if a.Balance(at) < points {
+		return ErrorInsufficientPoints
+	}
+	return nil
+}
+
+// Redeem spends points on a transaction.
+func (a *Account) Redeem(transactionID identity.ID, points int64, at time.Time) (Entry, error) {
+	if err := a.CanRedeem(points, at); err != nil {
+		return Entry{}, err
+	}
+
+	return a.append(Entry{
+		TransactionID: transactionID,
+		Type:          EntryTypeRedeem,
+		Points:        -points,
+	}, at), nil
+}
+
+// Settle returns the entries that bring the ledger in line with a
+// transaction, and adds them to the account. A paid transaction earns points
+// once. A voided one takes back what it earned and gives back what it
+// redeemed. Refunds take back earned points in proportion to the amount
+// refunded, and a full refund gives back the redeemed points too. Settling
+// the same state again returns nothing.
+func (a *Account) Settle(settlement Settlement, policy Policy, at time.Time) []Entry {
+	var earn, redeem *Entry
+	for i := range a.Entries {
+		entry := a.Entries[i]
+		if entry.TransactionID != settlement.TransactionID {
+			continue
+		}
+		switch entry.Type {
+		case EntryTypeEarn:
+			earn = &entry
+		case EntryTypeRedeem:
+			redeem = &entry
+		}
+	}
+
+	var entries []Entry
+	if earn == nil && settlement.Payment.IsPaid() {
+		if points := policy.PointsEarned(settlement.Total); points > 0 {
+			expiresAt := policy.ExpiresAt(at)
+			entry := a.append(Entry{
+				TransactionID: settlement.TransactionID,
+				Type:          EntryTypeEarn,
+				Points:        points,
+				ExpiresAt:     &expiresAt,
+			}, at)
+			earn = &entry
+			entries = append(entries, entry)
+		}
+	}
+
+	if earn != nil {
+		due := earn.Points
+		if !settlement.IsVoided() {
+			due = refundedShare(earn.Points, settlement)
+		}
+		if owed := due - a.reversed(earn.ID); owed > 0 {
+			entries = append(entries, a.append(Entry{
+				TransactionID:  settlement.TransactionID,
+				Type:           EntryTypeReversal,
+				Points:         -owed,
+				RelatedEntryID: earn.ID,
+			}, at))
+		}
+	}
+
+	if redeem != nil && (settlement.IsVoided() || settlement.IsFullyRefunded()) {
+		if owed := -redeem.Points - a.reversed(redeem.ID); owed > 0 {
+			expiresAt := policy.ExpiresAt(at)
+			entries = append(entries, a.append(Entry{
+				TransactionID:  settlement.TransactionID,
+				Type:           EntryTypeReversal,
+				Points:         owed,
+				ExpiresAt:      &expiresAt,
+				RelatedEntryID: redeem.ID,
+			}, at))
+		}
+	}
+
+	return entries
+}
+
+// Expire returns an expire entry for every credit that lapsed unspent by the
+// given time, and adds them to the account.
+func (a *Account) Expire(at time.Time) []Entry {
+	lots, _ := a.replay()
+
+	var entries []Entry
+	for _, l := range lots {
+		if l.remaining <= 0 || l.expiresAt.After(at) {
+			continue
+		}
+		entries = append(entries, a.append(Entry{
+			Type:           EntryTypeExpire,
+			Points:         -l.remaining,
+			RelatedEntryID: l.entryID,
+		}, at))
+	}
+	return entries
+}
+
+func (a *Account) append(entry Entry, at time.Time) Entry {
+	entry.ID = identity.NewID(uuid.New())
+	entry.UserID = a.UserID
+	entry.CreatedAt = at
+	a.Entries = append(a.Entries, entry)
+	return entry
+}
+
+// reversed is how many points were already reversed for an entry.
+func (a Account) reversed(entryID identity.ID) int64 {
+	var points int64
+	for _, entry := range a.Entries {
+		if entry.Type == EntryTypeReversal && entry.RelatedEntryID == entryID {
+			points += entry.Points
+		}
+	}
+	if points < 0 {
+		return -points
+	}
+	return points
+}
+
+// replay walks the ledger in order, keeping each credit as a lot. Spending
+// takes from the lots that lapse first; a reversal takes from the lot it
+// undoes first, forgives what lapsed of it, and takes the rest from the other
+// lots. What cannot be covered is the deficit.
+func (a Account) replay() ([]*lot, int64) {
+	var lots []*lot
+	var deficit int64
+
+	for _, entry := range a.Entries {
+		switch {
+		case entry.Points > 0:
+			l := &lot{entryID: entry.ID, remaining: entry.Points, expiresAt: entry.CreatedAt}
+			if entry.ExpiresAt != nil {
+				l.expiresAt = *entry.ExpiresAt
+			}
+			settled := min(deficit, l.remaining)
+			l.remaining -= settled
+			deficit -= settled
+			lots = append(lots, l)
+		case entry.Type == EntryTypeExpire:
+			if l := findLot(lots, entry.RelatedEntryID); l != nil {
+				lapsed := min(-entry.Points, l.remaining)
+				l.remaining -= lapsed
+				l.expired += lapsed
+			}
+		default:
+			owed := -entry.Points
+			if entry.Type == EntryTypeReversal {
+				if l := findLot(lots, entry.RelatedEntryID); l != nil {
+					taken := min(owed, l.remaining)
+					l.remaining -= taken
+					owed -= taken
+					forgiven := min(owed, l.expired)
+					l.expired -= forgiven
+					owed -= forgiven
+				}
+			}
+			deficit += spend(lots, owed, entry.CreatedAt)
+		}
+	}
+
+	return lots, deficit
+}
+
+// spend takes points from the live lots, soonest to lapse first, and returns
+// what they could not cover.
+func spend(lots []*lot, points int64, at time.Time) int64 {
+	live := make([]*lot, 0, len(lots))
+	for _, l := range lots {
+		if l.remaining > 0 && l.expiresAt.After(at) {
+			live = append(live, l)
+		}
+	}
+	sort.SliceStable(live, func(i, j int) bool {
+		return live[i].expiresAt.Before(live[j].expiresAt)
+	})
+
+	for _, l := range live {
+		if points <= 0 {
+			break
+		}
+		taken := min(points, l.remaining)
+		l.remaining -= taken
+		points -= taken
+	}
+	return points
+}
+
+func findLot(lots []*lot, entryID identity.ID) *lot {
+	for _, l := range lots {
+		if l.entryID == entryID {
+			return l
+		}
+	}
+	return nil
+}
+
+// refundedShare is the part of the earned points matching the share of the
+// total that was refunded, rounded down in the customer's favour.
+func refundedShare(points int64, settlement Settlement) int64 {
+	if !settlement.Refunded.IsPositive() || !settlement.Total.Price.IsPositive() {
+		return 0
+	}
+	if settlement.Refunded.GreaterThanOrEqual(settlement.Total.Price) {
+		return points
+	}
+	return decimal.NewFromInt(points).Mul(settlement.Refunded).Div(settlement.Total.Price).Floor().IntPart()
+}
@@ -0,0 +1,69 @@
+package loyalty
+
+import (
+	"fmt"
+	"fp-kpl/domain/shared"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultEarnUnit is the IDR spent for each point earned.
+	DefaultEarnUnit = 10000
+	// DefaultPointValue is the IDR a point takes off an order.
+	DefaultPointValue = 100
+	// DefaultExpiryMonths is how long earned points can be redeemed.
+	DefaultExpiryMonths = 12
+)
+
+// Policy is how points are earned, what they are worth and when they lapse.
+type Policy struct {
+	EarnUnit     decimal.Decimal
+	PointValue   decimal.Decimal
+	ExpiryMonths int
+}
+
+func NewPolicy(earnUnit decimal.Decimal, pointValue decimal.Decimal, expiryMonths int) (Policy, error) {
+	if !earnUnit.IsPositive() {
+		return Policy{}, fmt.Errorf("%w: earn unit %s", ErrorInvalidPolicy, earnUnit.String())
+	}
+	if !pointValue.IsPositive() {
+		return Policy{}, fmt.Errorf("%w: point value %s", ErrorInvalidPolicy, pointValue.String())
+	}
+	if expiryMonths <= 0 {
+		return Policy{}, fmt.Errorf("%w: expiry %d months", ErrorInvalidPolicy, expiryMonths)
+	}
+
+	return Policy{
+		EarnUnit:     earnUnit,
+		PointValue:   pointValue,
+		ExpiryMonths: expiryMonths,
+	}, nil
+}
+
+// PointsEarned is one point for every full earn unit of the total paid.
+func (p Policy) PointsEarned(total shared.Price) int64 {
+	if !p.EarnUnit.IsPositive() {
+		return 0
+	}
+	return total.Price.Div(p.EarnUnit).Floor().IntPart()
+}
+
+// Value is the IDR the points take off an order.
+func (p Policy) Value(points int64) decimal.Decimal {
+	return p.PointValue.Mul(decimal.NewFromInt(points))
+}
+
+// PointsWithin is the most points whose value does not exceed amount.
+func (p Policy) PointsWithin(amount decimal.Decimal) int64 {
+	if !p.PointValue.IsPositive() || !amount.IsPositive() {
+		return 0
+	}
+	return amount.Div(p.PointValue).Floor().IntPart()
+}
+
+// ExpiresAt is when points credited at the given time lapse.
+func (p Policy) ExpiresAt(creditedAt time.Time) time.Time {
+	return creditedAt.AddDate(0, p.ExpiryMonths, 0)
+}
@@ -0,0 +1,23 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// LockAccount holds the customer's ledger until tx ends, so redemptions
+	// and syncs of the same customer run one after another.
+	LockAccount(ctx context.Context, tx interface{}, userID string) error
+	// GetAccount returns every entry of the customer's ledger, oldest first.
+	GetAccount(ctx context.Context, tx interface{}, userID string) (Account, error)
+	// CreateEntries appends entries to the ledger. Entries are never updated
+	// or deleted.
+	CreateEntries(ctx context.Context, tx interface{}, entries []Entry) error
+	// GetSettlements returns the transactions of registered customers that
+	// changed or were refunded since the given time.
+	GetSettlements(ctx context.Context, tx interface{}, since time.Time) ([]Settlement, error)
+	// GetExpiringUserIDs returns the customers with credits that lapsed in
+	// the given period.
+	GetExpiringUserIDs(ctx context.Context, tx interface{}, from time.Time, to time.Time) ([]string, error)
+}
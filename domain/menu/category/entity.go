@@ -6,7 +6,9 @@ import (
 )
 
 type Category struct {
-	ID   identity.ID
-	Name string
+	ID        identity.ID
+	Name      string
+	StationID identity.ID
+	Schedule  []shared.TimeWindow
 	shared.Timestamp
 }
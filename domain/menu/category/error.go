@@ -6,4 +6,5 @@ var (
 	ErrorGetAllCategories = errors.New("failed to get all categories")
 	ErrorGetCategoryByID  = errors.New("failed to get category by id")
 	ErrorCategoryNotFound = errors.New("category not found")
+	ErrorUpdateSchedule   = errors.New("failed to update category schedule")
 )
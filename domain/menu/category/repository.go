@@ -1,10 +1,16 @@
 package category
 
-import "context"
+import (
+	"context"
+	"fp-kpl/domain/shared"
+)
 
 type (
 	Repository interface {
 		GetAllCategories(ctx context.Context, tx interface{}) ([]Category, error)
 		GetCategoryByID(ctx context.Context, tx interface{}, id string) (Category, error)
+		// UpdateCategorySchedule replaces the category's schedule; an empty
+		// schedule makes it available all the time.
+		UpdateCategorySchedule(ctx context.Context, tx interface{}, id string, schedule []shared.TimeWindow) (Category, error)
 	}
 )
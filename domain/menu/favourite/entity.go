@@ -0,0 +1,13 @@
+package favourite
+
+import (
+	"fp-kpl/domain/identity"
+	"time"
+)
+
+// Favourite is a menu a customer saved to find again quickly.
+type Favourite struct {
+	UserID    identity.ID
+	MenuID    identity.ID
+	CreatedAt time.Time
+}
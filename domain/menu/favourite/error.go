@@ -0,0 +1,10 @@
+package favourite
+
+import "errors"
+
+var (
+	ErrorGetFavourites     = errors.New("failed to get favourites")
+	ErrorAddFavourite      = errors.New("failed to add favourite")
+	ErrorRemoveFavourite   = errors.New("failed to remove favourite")
+	ErrorFavouriteNotFound = errors.New("favourite not found")
+)
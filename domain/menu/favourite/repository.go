@@ -0,0 +1,12 @@
+package favourite
+
+import "context"
+
+type (
+	Repository interface {
+		GetFavouritesByUserID(ctx context.Context, tx interface{}, userID string) ([]Favourite, error)
+		// AddFavourite does nothing when the menu is already a favourite.
+		AddFavourite(ctx context.Context, tx interface{}, favouriteEntity Favourite) (Favourite, error)
+		RemoveFavourite(ctx context.Context, tx interface{}, userID string, menuID string) error
+	}
+)
@@ -9,11 +9,17 @@ import (
 type Menu struct {
 	ID          identity.ID
 	CategoryID  identity.ID
+	StationID   identity.ID
 	Name        string
 	ImageURL    shared.URL
 	Price       shared.Price
 	IsAvailable bool
 	CookingTime time.Duration
 	Description string
+	// Schedule limits when the menu can be ordered; without one the
+	// category's schedule applies.
+	Schedule         []shared.TimeWindow
+	CategorySchedule []shared.TimeWindow
+	PriceOverrides   []PriceOverride
 	shared.Timestamp
 }
@@ -9,4 +9,8 @@ var (
 	ErrorCategoryNotFound       = errors.New("category not found")
 	ErrorMenuNotFound           = errors.New("menu not found")
 	ErrorUpdateMenuAvailability = errors.New("failed to update menu availability")
+	ErrorMenuOutOfSchedule      = errors.New("menu is not served at this time")
+	ErrorMenuUnavailable        = errors.New("menu is not available")
+	ErrorInvalidPriceOverride   = errors.New("invalid price override")
+	ErrorUpdateMenuSchedule     = errors.New("failed to update menu schedule")
 )
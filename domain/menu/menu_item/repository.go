@@ -1,6 +1,9 @@
 package menu
 
-import "context"
+import (
+	"context"
+	"fp-kpl/domain/shared"
+)
 
 type (
 	Repository interface {
@@ -8,5 +11,9 @@ type (
 		GetMenuByID(ctx context.Context, tx interface{}, id string) (Menu, error)
 		GetMenusByCategoryID(ctx context.Context, tx interface{}, categoryID string) ([]Menu, error)
 		UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (Menu, error)
+		// UpdateMenuSchedule and UpdateMenuPriceOverrides replace what the
+		// menu had before.
+		UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, schedule []shared.TimeWindow) (Menu, error)
+		UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []PriceOverride) (Menu, error)
 	}
 )
@@ -0,0 +1,71 @@
+package menu
+
+import (
+	"fmt"
+	"fp-kpl/domain/shared"
+	"time"
+)
+
+// PriceOverride replaces the menu price inside its window, such as a happy
+// hour price.
+type PriceOverride struct {
+	Name   string
+	Price  shared.Price
+	Window shared.TimeWindow
+}
+
+func NewPriceOverride(name string, price shared.Price, window shared.TimeWindow) (PriceOverride, error) {
+	if name == "" {
+		return PriceOverride{}, fmt.Errorf("%w: name is required", ErrorInvalidPriceOverride)
+	}
+	if !price.Price.IsPositive() {
+		return PriceOverride{}, fmt.Errorf("%w: price must be above 0", ErrorInvalidPriceOverride)
+	}
+
+	return PriceOverride{
+		Name:   name,
+		Price:  price,
+		Window: window,
+	}, nil
+}
+
+// IsScheduledAt reports whether the menu is served at the time. The menu's
+// own schedule wins over its category's, the same way a menu's station does.
+func (m Menu) IsScheduledAt(at time.Time) bool {
+	if len(m.Schedule) > 0 {
+		return shared.WithinAny(m.Schedule, at)
+	}
+	return shared.WithinAny(m.CategorySchedule, at)
+}
+
+// IsAvailableAt combines the availability switch with the schedule.
+func (m Menu) IsAvailableAt(at time.Time) bool {
+	return m.IsAvailable && m.IsScheduledAt(at)
+}
+
+// CheckAvailableAt tells why the menu cannot be ordered at the time, if it
+// cannot.
+func (m Menu) CheckAvailableAt(at time.Time) error {
+	if !m.IsAvailable {
+		return ErrorMenuUnavailable
+	}
+	if !m.IsScheduledAt(at) {
+		return ErrorMenuOutOfSchedule
+	}
+	return nil
+}
+
+// PriceAt returns the price charged at the time. When overrides overlap the
+// lowest of them wins.
+func (m Menu) PriceAt(at time.Time) shared.Price {
+	var price *shared.Price
+	for i, override := range m.PriceOverrides {
+		if override.Window.Contains(at) && (price == nil || override.Price.Price.LessThan(price.Price)) {
+			price = &m.PriceOverrides[i].Price
+		}
+	}
+	if price == nil {
+		return m.Price
+	}
+	return *price
+}
@@ -0,0 +1,54 @@
+package order
+
+import "fmt"
+
+const (
+	CookingStatusPending   = "pending"
+	CookingStatusPreparing = "preparing"
+	CookingStatusDone      = "done"
+)
+
+var (
+	CookingStatuses = []string{
+		CookingStatusPending,
+		CookingStatusPreparing,
+		CookingStatusDone,
+	}
+)
+
+type CookingStatus struct {
+	Status string
+}
+
+func NewCookingStatus(status string) (CookingStatus, error) {
+	if !isValidCookingStatus(status) {
+		return CookingStatus{}, fmt.Errorf("invalid cooking status: %s", status)
+	}
+	return CookingStatus{
+		Status: status,
+	}, nil
+}
+
+func NewCookingStatusFromSchema(status string) CookingStatus {
+	return CookingStatus{
+		Status: status,
+	}
+}
+
+func isValidCookingStatus(status string) bool {
+	for _, cookingStatus := range CookingStatuses {
+		if cookingStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsCookingFinished(orders []Order) bool {
+	for _, orderEntity := range orders {
+		if orderEntity.CookingStatus.Status != CookingStatusDone {
+			return false
+		}
+	}
+	return true
+}
@@ -5,10 +5,15 @@ import (
 	"fp-kpl/domain/shared"
 )
 
+// Order is one line of a transaction. Price is the unit price charged when it
+// was ordered, which may be a happy hour price.
 type Order struct {
 	ID            identity.ID
 	TransactionID identity.ID
 	MenuID        identity.ID
+	StationID     identity.ID
 	Quantity      int
+	Price         shared.Price
+	CookingStatus CookingStatus
 	shared.Timestamp
 }
@@ -0,0 +1,202 @@
+package order
+
+import (
+	"fmt"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// TaxAfterServiceCharge charges tax on the subtotal plus service charge,
+	// the usual way PB1 is applied.
+	TaxAfterServiceCharge = "after_service"
+	// TaxBeforeServiceCharge charges tax on the subtotal only and service
+	// charge on the subtotal plus tax.
+	TaxBeforeServiceCharge = "before_service"
+)
+
+var (
+	TaxOrders = []string{
+		TaxAfterServiceCharge,
+		TaxBeforeServiceCharge,
+	}
+
+	// RoundingUnits are the IDR amounts a total can be rounded to; zero
+	// leaves the total as it is.
+	RoundingUnits = []int64{0, 100, 500}
+
+	hundred = decimal.NewFromInt(100)
+)
+
+type (
+	// PricingPolicy turns a subtotal into what the customer pays. Rates are
+	// percentages; the packaging fee is a flat IDR amount per packed order.
+	PricingPolicy struct {
+		ServiceChargeRate decimal.Decimal
+		TaxRate           decimal.Decimal
+		TaxOrder          string
+		RoundingUnit      int64
+		PackagingFee      decimal.Decimal
+	}
+
+	// Discount is what one promotion took off the subtotal.
+	Discount struct {
+		PromotionID identity.ID
+		Name        string
+		VoucherCode string
+		Amount      shared.Price
+	}
+
+	// Line is one ordered menu at the unit price charged for it.
+	Line struct {
+		MenuID    identity.ID
+		UnitPrice shared.Price
+		Quantity  int
+	}
+
+	// PriceBreakdown is every step between the subtotal and the total. The
+	// rounding is negative when the total was rounded down.
+	PriceBreakdown struct {
+		Lines             []Line
+		Subtotal          shared.Price
+		Discounts         []Discount
+		Discount          shared.Price
+		LoyaltyPoints     int64
+		LoyaltyDiscount   shared.Price
+		PackagingFee      shared.Price
+		ServiceChargeRate decimal.Decimal
+		ServiceCharge     shared.Price
+		TaxRate           decimal.Decimal
+		Tax               shared.Price
+		Rounding          shared.Price
+		Total             shared.Price
+	}
+)
+
+func NewPricingPolicy(serviceChargeRate decimal.Decimal, taxRate decimal.Decimal, taxOrder string, roundingUnit int64, packagingFee decimal.Decimal) (PricingPolicy, error) {
+	if !isValidRate(serviceChargeRate) {
+		return PricingPolicy{}, fmt.Errorf("%w: service charge %s%%", ErrorInvalidPricingPolicy, serviceChargeRate.String())
+	}
+	if !isValidRate(taxRate) {
+		return PricingPolicy{}, fmt.Errorf("%w: tax %s%%", ErrorInvalidPricingPolicy, taxRate.String())
+	}
+	if taxOrder == "" {
+		taxOrder = TaxAfterServiceCharge
+	}
+	if !isValidTaxOrder(taxOrder) {
+		return PricingPolicy{}, fmt.Errorf("%w: tax order %s", ErrorInvalidPricingPolicy, taxOrder)
+	}
+	if !isValidRoundingUnit(roundingUnit) {
+		return PricingPolicy{}, fmt.Errorf("%w: rounding unit %d", ErrorInvalidPricingPolicy, roundingUnit)
+	}
+	if packagingFee.IsNegative() {
+		return PricingPolicy{}, fmt.Errorf("%w: packaging fee %s", ErrorInvalidPricingPolicy, packagingFee.String())
+	}
+
+	return PricingPolicy{
+		ServiceChargeRate: serviceChargeRate,
+		TaxRate:           taxRate,
+		TaxOrder:          taxOrder,
+		RoundingUnit:      roundingUnit,
+		PackagingFee:      packagingFee,
+	}, nil
+}
+
+// Apply runs the pipeline: service charge and tax in the configured order,
+// each rounded to whole rupiah, then the total rounded half up to the
+// rounding unit.
+func (p PricingPolicy) Apply(subtotal shared.Price) PriceBreakdown {
+	return p.ApplyDiscounts(subtotal, nil)
+}
+
+// ApplyDiscounts takes the discounts off the subtotal before the pipeline, so
+// service charge and tax are charged on what the customer actually buys.
+// Discounts beyond the subtotal are ignored.
+func (p PricingPolicy) ApplyDiscounts(subtotal shared.Price, discounts []Discount) PriceBreakdown {
+	return p.apply(subtotal, discounts, decimal.Zero, decimal.Zero)
+}
+
+// ApplyPackaged prices an order packed for the counter. The packaging fee is
+// added after the discounts, so it is never discounted but is charged service
+// charge and tax like the food.
+func (p PricingPolicy) ApplyPackaged(subtotal shared.Price, discounts []Discount) PriceBreakdown {
+	return p.apply(subtotal, discounts, decimal.Zero, p.PackagingFee)
+}
+
+// ApplyLoyalty prices a breakdown again with redeemed loyalty points taken
+// off after the promotions, so they are never worth more than what is left of
+// the subtotal and service charge and tax follow the lower amount.
+func (p PricingPolicy) ApplyLoyalty(breakdown PriceBreakdown, points int64, amount shared.Price) PriceBreakdown {
+	repriced := p.apply(breakdown.Subtotal, breakdown.Discounts, amount.Price, breakdown.PackagingFee.Price)
+	repriced.Lines = breakdown.Lines
+	repriced.LoyaltyPoints = points
+	return repriced
+}
+
+func (p PricingPolicy) apply(subtotal shared.Price, discounts []Discount, loyaltyDiscount decimal.Decimal, packagingFee decimal.Decimal) PriceBreakdown {
+	discount := decimal.Zero
+	for _, d := range discounts {
+		discount = discount.Add(d.Amount.Price)
+	}
+	discount = decimal.Min(discount, subtotal.Price)
+	loyaltyDiscount = decimal.Min(loyaltyDiscount, subtotal.Price.Sub(discount))
+	base := subtotal.Price.Sub(discount).Sub(loyaltyDiscount).Add(packagingFee)
+
+	var serviceCharge, tax decimal.Decimal
+	if p.TaxOrder == TaxBeforeServiceCharge {
+		tax = percentOf(base, p.TaxRate)
+		serviceCharge = percentOf(base.Add(tax), p.ServiceChargeRate)
+	} else {
+		serviceCharge = percentOf(base, p.ServiceChargeRate)
+		tax = percentOf(base.Add(serviceCharge), p.TaxRate)
+	}
+
+	beforeRounding := base.Add(serviceCharge).Add(tax)
+	total := beforeRounding
+	if p.RoundingUnit > 0 {
+		unit := decimal.NewFromInt(p.RoundingUnit)
+		total = beforeRounding.Div(unit).Round(0).Mul(unit)
+	}
+
+	return PriceBreakdown{
+		Subtotal:          subtotal,
+		Discounts:         discounts,
+		Discount:          shared.NewPriceFromSchema(discount),
+		LoyaltyDiscount:   shared.NewPriceFromSchema(loyaltyDiscount),
+		PackagingFee:      shared.NewPriceFromSchema(packagingFee),
+		ServiceChargeRate: p.ServiceChargeRate,
+		ServiceCharge:     shared.NewPriceFromSchema(serviceCharge),
+		TaxRate:           p.TaxRate,
+		Tax:               shared.NewPriceFromSchema(tax),
+		Rounding:          shared.NewPriceFromSchema(total.Sub(beforeRounding)),
+		Total:             shared.NewPriceFromSchema(total),
+	}
+}
+
+func percentOf(amount decimal.Decimal, rate decimal.Decimal) decimal.Decimal {
+	return amount.Mul(rate).Div(hundred).Round(0)
+}
+
+func isValidRate(rate decimal.Decimal) bool {
+	return !rate.IsNegative() && rate.LessThanOrEqual(hundred)
+}
+
+func isValidTaxOrder(taxOrder string) bool {
+	for _, order := range TaxOrders {
+		if order == taxOrder {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidRoundingUnit(unit int64) bool {
+	for _, roundingUnit := range RoundingUnits {
+		if roundingUnit == unit {
+			return true
+		}
+	}
+	return false
+}
@@ -8,5 +8,6 @@ type (
 	Repository interface {
 		CreateOrder(ctx context.Context, tx interface{}, orderEntity Order) (Order, error)
 		GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]Order, error)
+		UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]Order, error)
 	}
 )
@@ -9,13 +9,19 @@ import (
 type (
 	Service interface {
 		CalculatePrice(ctx context.Context, price shared.Price, quantity int64) (shared.Price, error)
+		CalculateBreakdown(ctx context.Context, subtotal shared.Price, discounts []Discount, packaged bool) (PriceBreakdown, error)
+		ApplyLoyalty(ctx context.Context, breakdown PriceBreakdown, points int64, amount shared.Price) (PriceBreakdown, error)
 	}
 
-	service struct{}
+	service struct {
+		pricingPolicy PricingPolicy
+	}
 )
 
-func NewService() Service {
-	return &service{}
+func NewService(pricingPolicy PricingPolicy) Service {
+	return &service{
+		pricingPolicy: pricingPolicy,
+	}
 }
 
 func (s service) CalculatePrice(ctx context.Context, price shared.Price, quantity int64) (shared.Price, error) {
@@ -26,3 +32,18 @@ func (s service) CalculatePrice(ctx context.Context, price shared.Price, quantit
 	orderPrice := price.Price.Mul(decimal.NewFromInt(quantity))
 	return shared.NewPrice(orderPrice)
 }
+
+// CalculateBreakdown takes the discounts off the subtotal of an order and adds
+// the packaging fee of packed orders, service charge, tax and rounding.
+func (s service) CalculateBreakdown(ctx context.Context, subtotal shared.Price, discounts []Discount, packaged bool) (PriceBreakdown, error) {
+	if packaged {
+		return s.pricingPolicy.ApplyPackaged(subtotal, discounts), nil
+	}
+	return s.pricingPolicy.ApplyDiscounts(subtotal, discounts), nil
+}
+
+// ApplyLoyalty takes the value of redeemed loyalty points off a breakdown
+// that was already calculated.
+func (s service) ApplyLoyalty(ctx context.Context, breakdown PriceBreakdown, points int64, amount shared.Price) (PriceBreakdown, error) {
+	return s.pricingPolicy.ApplyLoyalty(breakdown, points, amount), nil
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"fp-kpl/domain/transaction"
+)
+
+type (
+	EventPublisherPort interface {
+		Publish(ctx context.Context, event transaction.Event) error
+	}
+)
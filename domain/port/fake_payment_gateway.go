@@ -0,0 +1,50 @@
+package port
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FakeActionPay    = "pay"
+	FakeActionFail   = "fail"
+	FakeActionExpire = "expire"
+
+	// FakePaymentPath is where the fake gateway's payment pages are served.
+	FakePaymentPath = "/fake-gateway/pay"
+)
+
+var (
+	FakeActions = []string{
+		FakeActionPay,
+		FakeActionFail,
+		FakeActionExpire,
+	}
+
+	ErrorFakePaymentNotFound   = errors.New("fake payment not found")
+	ErrorFakeInvalidAction     = errors.New("invalid fake payment action")
+	ErrorFakePaymentNotPending = errors.New("fake payment is no longer pending")
+)
+
+type (
+	// FakePaymentGatewayPort is a payment gateway that never leaves the
+	// machine. Its payment link points at a local page, and completing a
+	// payment there delivers a signed notification to the webhook.
+	FakePaymentGatewayPort interface {
+		PaymentGatewayPort
+		GetPayment(token string) (FakePayment, error)
+		// CompletePayment pays, fails or expires the pending payment behind
+		// token, one of FakeActions, and sends the matching notification.
+		CompletePayment(ctx context.Context, token string, action string) (FakePayment, error)
+	}
+
+	FakePayment struct {
+		Token         string
+		OrderID       string
+		TransactionID string
+		Status        string
+		GrossAmount   decimal.Decimal
+	}
+)
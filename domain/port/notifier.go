@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"fp-kpl/domain/sla"
+)
+
+type (
+	NotifierPort interface {
+		Notify(ctx context.Context, breach sla.Breach) error
+	}
+)
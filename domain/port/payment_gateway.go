@@ -2,18 +2,64 @@ package port
 
 import (
 	"context"
+	"errors"
 	"fp-kpl/domain/transaction"
+
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrorPaymentNotFound         = errors.New("payment not found at gateway")
+	ErrorInvalidSignature        = errors.New("invalid payment notification signature")
+	ErrorPaymentProviderNotFound = errors.New("payment provider not found")
+	ErrorPaymentProviderMismatch = errors.New("payment notification from a different provider")
+	ErrorPaymentAlreadyPaid      = errors.New("payment already paid at gateway")
 )
 
 type (
 	PaymentGatewayPort interface {
+		// Provider is the name the transaction records, one of
+		// transaction.PaymentProviders.
+		Provider() string
 		ProcessPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (ProcessPaymentResponse, error)
+		// ProcessSplitPayment charges one share of a split bill. The share's
+		// ID is sent as the order ID, so its notifications come back with it
+		// instead of the transaction ID.
+		ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (ProcessPaymentResponse, error)
+		// HookPayment applies a notification that ParseNotification already
+		// verified and normalised.
 		HookPayment(ctx context.Context, tx interface{}, transactionId uuid.UUID, datas map[string]interface{}) error
+		// CheckPaymentStatus asks the gateway for the current state of a
+		// transaction. It returns ErrorPaymentNotFound when the customer never
+		// got as far as choosing a payment method.
+		CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (PaymentStatusResponse, error)
+		// CancelPayment stops a pending charge of the transaction from being
+		// paid. It does nothing when the gateway has no pending charge, and
+		// returns ErrorPaymentAlreadyPaid when the charge was already paid.
+		CancelPayment(ctx context.Context, transactionId uuid.UUID) error
+		// ParseNotification checks that a webhook was sent by the gateway and
+		// returns ErrorInvalidSignature otherwise. The payload is returned in
+		// the Midtrans shape (order_id, transaction_id, transaction_status,
+		// gross_amount) whatever the provider sent.
+		ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error)
+	}
+
+	// PaymentGatewayRegistry resolves the gateway handling a provider. An
+	// empty provider resolves to the default one.
+	PaymentGatewayRegistry interface {
+		Gateway(provider string) (PaymentGatewayPort, error)
 	}
 
 	ProcessPaymentResponse struct {
 		Token       string
 		PaymentLink string
 	}
+
+	PaymentStatusResponse struct {
+		OrderID       string
+		TransactionID string
+		Status        string
+		GrossAmount   decimal.Decimal
+	}
 )
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+	"fp-kpl/domain/verification"
+)
+
+type (
+	// VerificationNotifierPort delivers verification codes, by email or SMS
+	// depending on the message's channel.
+	VerificationNotifierPort interface {
+		SendVerification(ctx context.Context, message verification.Message) error
+	}
+)
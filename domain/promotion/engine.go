@@ -0,0 +1,114 @@
+package promotion
+
+import (
+	"fp-kpl/domain/identity"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+type (
+	// Line is one menu in the cart at its unit price.
+	Line struct {
+		MenuID     identity.ID
+		CategoryID identity.ID
+		UnitPrice  decimal.Decimal
+		Quantity   int
+	}
+
+	Cart struct {
+		Lines []Line
+		At    time.Time
+	}
+
+	// Applied is the discount one promotion gives a cart.
+	Applied struct {
+		Promotion Promotion
+		Amount    decimal.Decimal
+	}
+)
+
+func (c Cart) Subtotal() decimal.Decimal {
+	subtotal := decimal.Zero
+	for _, line := range c.Lines {
+		subtotal = subtotal.Add(line.UnitPrice.Mul(decimal.NewFromInt(int64(line.Quantity))))
+	}
+	return subtotal
+}
+
+// Covers reports whether the line falls within the promotion's category and
+// menu scope.
+func (p Promotion) Covers(line Line) bool {
+	if !p.CategoryID.IsEmpty() && p.CategoryID != line.CategoryID {
+		return false
+	}
+	if !p.MenuID.IsEmpty() && p.MenuID != line.MenuID {
+		return false
+	}
+	return true
+}
+
+// Discount is what the promotion takes off the cart, zero when it does not
+// apply. Percentages are rounded to whole rupiah and a fixed amount never
+// exceeds what it is scoped to.
+func (p Promotion) Discount(cart Cart) decimal.Decimal {
+	if !p.IsActive || !p.Schedule.Contains(cart.At) {
+		return decimal.Zero
+	}
+	if cart.Subtotal().LessThan(p.MinimumSpend.Price) {
+		return decimal.Zero
+	}
+
+	scoped := decimal.Zero
+	free := decimal.Zero
+	for _, line := range cart.Lines {
+		if !p.Covers(line) {
+			continue
+		}
+		scoped = scoped.Add(line.UnitPrice.Mul(decimal.NewFromInt(int64(line.Quantity))))
+
+		if p.Type.Type == TypeBOGO && p.BuyQuantity > 0 && p.FreeQuantity > 0 {
+			sets := line.Quantity / (p.BuyQuantity + p.FreeQuantity)
+			free = free.Add(line.UnitPrice.Mul(decimal.NewFromInt(int64(sets * p.FreeQuantity))))
+		}
+	}
+
+	switch p.Type.Type {
+	case TypePercentage:
+		return scoped.Mul(p.Value).Div(hundred).Round(0)
+	case TypeFixedAmount:
+		return decimal.Min(p.Value, scoped)
+	case TypeBOGO:
+		return free
+	default:
+		return decimal.Zero
+	}
+}
+
+// Evaluate applies the promotions to the cart in order. Promotions stack, but
+// together they never take off more than the subtotal, so the last one may be
+// cut short.
+func Evaluate(promotions []Promotion, cart Cart) []Applied {
+	remaining := cart.Subtotal()
+
+	var applied []Applied
+	for _, p := range promotions {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		amount := decimal.Min(p.Discount(cart), remaining)
+		if !amount.IsPositive() {
+			continue
+		}
+
+		applied = append(applied, Applied{
+			Promotion: p,
+			Amount:    amount,
+		})
+		remaining = remaining.Sub(amount)
+	}
+	return applied
+}
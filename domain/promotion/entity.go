@@ -0,0 +1,84 @@
+package promotion
+
+import (
+	"fmt"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+
+	"github.com/shopspring/decimal"
+)
+
+type (
+	// Promotion is a discount rule. Without a voucher code it applies to
+	// every eligible order; with one it only applies when the code is
+	// redeemed. An empty CategoryID or MenuID leaves the rule unscoped.
+	Promotion struct {
+		ID           identity.ID
+		Name         string
+		Type         Type
+		Value        decimal.Decimal
+		BuyQuantity  int
+		FreeQuantity int
+		CategoryID   identity.ID
+		MenuID       identity.ID
+		MinimumSpend shared.Price
+		Schedule     Schedule
+		VoucherCode  string
+		UsageLimit   int
+		PerUserLimit int
+		IsActive     bool
+		shared.Timestamp
+	}
+
+	// Usage counts the redemptions of a promotion that still stand, that is
+	// whose transaction was not cancelled, denied or expired.
+	Usage struct {
+		Total  int
+		ByUser int
+	}
+)
+
+// IsVoucher reports whether the promotion needs a code.
+func (p Promotion) IsVoucher() bool {
+	return p.VoucherCode != ""
+}
+
+// CheckUsage returns an error when the promotion has been used up, in total or
+// by the user.
+func (p Promotion) CheckUsage(usage Usage) error {
+	if p.UsageLimit > 0 && usage.Total >= p.UsageLimit {
+		return ErrorUsageLimitReached
+	}
+	if p.PerUserLimit > 0 && usage.ByUser >= p.PerUserLimit {
+		return ErrorUserLimitReached
+	}
+	return nil
+}
+
+// Validate checks that the rule can be applied as configured.
+func (p Promotion) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrorInvalidPromotion)
+	}
+	if p.MinimumSpend.Price.IsNegative() || p.UsageLimit < 0 || p.PerUserLimit < 0 {
+		return fmt.Errorf("%w: limits cannot be negative", ErrorInvalidPromotion)
+	}
+
+	switch p.Type.Type {
+	case TypePercentage:
+		if !p.Value.IsPositive() || p.Value.GreaterThan(hundred) {
+			return fmt.Errorf("%w: percentage must be above 0 and at most 100", ErrorInvalidPromotion)
+		}
+	case TypeFixedAmount:
+		if !p.Value.IsPositive() {
+			return fmt.Errorf("%w: amount must be above 0", ErrorInvalidPromotion)
+		}
+	case TypeBOGO:
+		if p.BuyQuantity <= 0 || p.FreeQuantity <= 0 {
+			return fmt.Errorf("%w: buy and free quantities must be above 0", ErrorInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrorInvalidPromotionType, p.Type.Type)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package promotion
+
+import "errors"
+
+var (
+	ErrorPromotionNotFound     = errors.New("promotion not found")
+	ErrorInvalidPromotion      = errors.New("invalid promotion")
+	ErrorInvalidPromotionType  = errors.New("invalid promotion type")
+	ErrorVoucherNotFound       = errors.New("voucher not found")
+	ErrorVoucherAlreadyExists  = errors.New("voucher code already exists")
+	ErrorVoucherNotApplicable  = errors.New("voucher does not apply to this order")
+	ErrorUsageLimitReached     = errors.New("promotion usage limit reached")
+	ErrorUserLimitReached      = errors.New("promotion already used the maximum number of times")
+	ErrorAccountRequired       = errors.New("promotion is limited per customer and needs an account")
+	ErrorCreatePromotion       = errors.New("failed to create promotion")
+	ErrorGetAllPromotions      = errors.New("failed to get all promotions")
+	ErrorUpdatePromotionStatus = errors.New("failed to update promotion status")
+)
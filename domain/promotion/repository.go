@@ -0,0 +1,24 @@
+package promotion
+
+import (
+	"context"
+)
+
+type (
+	Repository interface {
+		CreatePromotion(ctx context.Context, tx interface{}, promotionEntity Promotion) (Promotion, error)
+		GetAllPromotions(ctx context.Context, tx interface{}) ([]Promotion, error)
+		GetPromotionByID(ctx context.Context, tx interface{}, id string) (Promotion, error)
+		// LockPromotion returns the promotion and locks it until tx ends, so
+		// its usage cannot change while a redemption is being written.
+		LockPromotion(ctx context.Context, tx interface{}, id string) (Promotion, error)
+		// GetActivePromotions returns the active promotions that apply
+		// without a voucher code.
+		GetActivePromotions(ctx context.Context, tx interface{}) ([]Promotion, error)
+		GetPromotionByVoucherCode(ctx context.Context, tx interface{}, code string) (Promotion, error)
+		UpdatePromotionStatus(ctx context.Context, tx interface{}, id string, isActive bool) (Promotion, error)
+		// GetUsage counts the standing redemptions of a promotion, in total
+		// and by the user. An empty userID only counts the total.
+		GetUsage(ctx context.Context, tx interface{}, promotionID string, userID string) (Usage, error)
+	}
+)
@@ -0,0 +1,87 @@
+package promotion
+
+import (
+	"fmt"
+	"fp-kpl/domain/shared"
+	"strings"
+	"time"
+)
+
+const (
+	TypePercentage  = "percentage"
+	TypeFixedAmount = "fixed_amount"
+	TypeBOGO        = "bogo"
+)
+
+var (
+	Types = []string{
+		TypePercentage,
+		TypeFixedAmount,
+		TypeBOGO,
+	}
+)
+
+type (
+	Type struct {
+		Type string
+	}
+
+	// Schedule limits when a promotion runs. StartsAt and EndsAt bound the
+	// campaign and the window picks the weekdays and time of day.
+	Schedule struct {
+		StartsAt *time.Time
+		EndsAt   *time.Time
+		Window   shared.TimeWindow
+	}
+)
+
+func NewType(promotionType string) (Type, error) {
+	if !isValidType(promotionType) {
+		return Type{}, fmt.Errorf("%w: %s", ErrorInvalidPromotionType, promotionType)
+	}
+	return Type{
+		Type: promotionType,
+	}, nil
+}
+
+func NewTypeFromSchema(promotionType string) Type {
+	return Type{
+		Type: promotionType,
+	}
+}
+
+func NewSchedule(startsAt *time.Time, endsAt *time.Time, window shared.TimeWindow) (Schedule, error) {
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return Schedule{}, fmt.Errorf("%w: campaign ends before it starts", ErrorInvalidPromotion)
+	}
+
+	return Schedule{
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Window:   window,
+	}, nil
+}
+
+func (s Schedule) Contains(at time.Time) bool {
+	if s.StartsAt != nil && at.Before(*s.StartsAt) {
+		return false
+	}
+	if s.EndsAt != nil && !at.Before(*s.EndsAt) {
+		return false
+	}
+	return s.Window.Contains(at)
+}
+
+// NormalizeVoucherCode makes codes case and whitespace insensitive.
+func NormalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func isValidType(promotionType string) bool {
+	for _, t := range Types {
+		if t == promotionType {
+			return true
+		}
+	}
+	return false
+}
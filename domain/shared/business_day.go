@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultBusinessDayCutoff lets orders taken shortly after midnight count
+// toward the evening before.
+const DefaultBusinessDayCutoff = 4 * time.Hour
+
+// BusinessDay is the restaurant's trading day. It runs from the cutoff on
+// Date until the cutoff on the following day, so it is named after the
+// calendar date it started on.
+type BusinessDay struct {
+	Date  time.Time
+	Start time.Time
+	End   time.Time
+}
+
+// NewBusinessDay returns the business day at belongs to, in at's location.
+func NewBusinessDay(at time.Time, cutoff time.Duration) BusinessDay {
+	shifted := at.Add(-cutoff)
+	year, month, day := shifted.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, at.Location())
+
+	return BusinessDay{
+		Date:  date,
+		Start: date.Add(cutoff),
+		End:   time.Date(year, month, day+1, 0, 0, 0, 0, at.Location()).Add(cutoff),
+	}
+}
+
+// ValidateBusinessDayCutoff checks the cutoff falls within the first day.
+func ValidateBusinessDayCutoff(cutoff time.Duration) error {
+	if cutoff < 0 || cutoff >= 24*time.Hour {
+		return fmt.Errorf("business day cutoff %s must be between 0 and 24h", cutoff)
+	}
+	return nil
+}
+
+func (d BusinessDay) Contains(at time.Time) bool {
+	return !at.Before(d.Start) && at.Before(d.End)
+}
+
+// String is the date the business day started on, e.g. "2024-05-15".
+func (d BusinessDay) String() string {
+	return d.Date.Format("2006-01-02")
+}
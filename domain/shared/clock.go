@@ -0,0 +1,28 @@
+package shared
+
+import "time"
+
+// Clock tells the time in the restaurant's timezone and which business day
+// it is. Services and repositories take one so anything that depends on the
+// time of day can be tested at a fixed time.
+type Clock interface {
+	Now() time.Time
+	Today() BusinessDay
+}
+
+type systemClock struct {
+	location *time.Location
+	cutoff   time.Duration
+}
+
+func NewSystemClock(location *time.Location, cutoff time.Duration) Clock {
+	return systemClock{location: location, cutoff: cutoff}
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now().In(c.location)
+}
+
+func (c systemClock) Today() BusinessDay {
+	return NewBusinessDay(c.Now(), c.cutoff)
+}
@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+var ErrorInvalidTimeWindow = errors.New("invalid time window")
+
+// TimeWindow is a weekly recurring window. Days picks the weekdays (all when
+// empty) and the minutes give the time of day (all day when both are zero).
+// A window ending before it starts runs past midnight.
+type TimeWindow struct {
+	Days        []time.Weekday
+	StartMinute int
+	EndMinute   int
+}
+
+// NewTimeWindow parses the time of day from "15:04" strings.
+func NewTimeWindow(days []time.Weekday, startTime string, endTime string) (TimeWindow, error) {
+	for _, day := range days {
+		if day < time.Sunday || day > time.Saturday {
+			return TimeWindow{}, fmt.Errorf("%w: weekday %d", ErrorInvalidTimeWindow, day)
+		}
+	}
+
+	startMinute, err := parseMinute(startTime)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+	endMinute, err := parseMinute(endTime)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+
+	return TimeWindow{
+		Days:        days,
+		StartMinute: startMinute,
+		EndMinute:   endMinute,
+	}, nil
+}
+
+// Contains checks the weekday and time of day of at in its own location, so
+// callers pass times in the restaurant's timezone. The part of an overnight
+// window after midnight belongs to the day it started on, so a Friday
+// 22:00-02:00 window covers early Saturday, not early Friday.
+func (w TimeWindow) Contains(at time.Time) bool {
+	minute := at.Hour()*60 + at.Minute()
+	day := at.Weekday()
+
+	switch {
+	case w.StartMinute == w.EndMinute:
+	case w.StartMinute < w.EndMinute:
+		if minute < w.StartMinute || minute >= w.EndMinute {
+			return false
+		}
+	case minute < w.EndMinute:
+		day = (day + 6) % 7
+	case minute < w.StartMinute:
+		return false
+	}
+
+	return w.onDay(day)
+}
+
+func (w TimeWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, windowDay := range w.Days {
+		if windowDay == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (w TimeWindow) StartTime() string {
+	return formatMinute(w.StartMinute)
+}
+
+func (w TimeWindow) EndTime() string {
+	return formatMinute(w.EndMinute)
+}
+
+// WithinAny reports whether at falls in one of the windows. No windows means
+// no restriction.
+func WithinAny(windows []TimeWindow, at time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, window := range windows {
+		if window.Contains(at) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMinute(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: time %q must be HH:MM", ErrorInvalidTimeWindow, value)
+	}
+	return (parsed.Hour()*60 + parsed.Minute()) % minutesPerDay, nil
+}
+
+func formatMinute(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
@@ -0,0 +1,51 @@
+package shift
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"time"
+)
+
+type (
+	// Shift is a cashier's session at the counter. The drawer starts with
+	// OpeningFloat; CountedCash is what the cashier counted when closing.
+	Shift struct {
+		ID           identity.ID
+		CashierID    identity.ID
+		OpeningFloat shared.Price
+		CountedCash  *shared.Price
+		OpenedAt     time.Time
+		ClosedAt     *time.Time
+		shared.Timestamp
+	}
+
+	// CounterPayment records a payment taken at the counter during a shift.
+	CounterPayment struct {
+		ID             identity.ID
+		TransactionID  identity.ID
+		ShiftID        identity.ID
+		CashierID      identity.ID
+		Method         Method
+		Amount         shared.Price
+		AmountTendered shared.Price
+		Change         shared.Price
+		shared.Timestamp
+	}
+
+	// Totals summarises the counter payments taken during a shift.
+	Totals struct {
+		Cash         shared.Price
+		Card         shared.Price
+		Transactions int
+	}
+)
+
+func (s Shift) IsOpen() bool {
+	return s.ClosedAt == nil
+}
+
+// ExpectedCash is what should be in the drawer: the opening float plus every
+// cash payment. Change handed back is already netted out of Amount.
+func (s Shift) ExpectedCash(totals Totals) shared.Price {
+	return shared.NewPriceFromSchema(s.OpeningFloat.Price.Add(totals.Cash.Price))
+}
@@ -0,0 +1,13 @@
+package shift
+
+import "errors"
+
+var (
+	ErrorShiftNotFound        = errors.New("no open shift for this cashier")
+	ErrorShiftAlreadyOpen     = errors.New("cashier already has an open shift")
+	ErrorOpenShift            = errors.New("failed to open shift")
+	ErrorCloseShift           = errors.New("failed to close shift")
+	ErrorGetShiftTotals       = errors.New("failed to get shift totals")
+	ErrorInvalidPaymentMethod = errors.New("invalid counter payment method")
+	ErrorInsufficientAmount   = errors.New("amount tendered does not cover the total")
+)
@@ -0,0 +1,14 @@
+package shift
+
+import (
+	"context"
+	"fp-kpl/domain/shared"
+)
+
+type Repository interface {
+	OpenShift(ctx context.Context, tx interface{}, shiftEntity Shift) (Shift, error)
+	GetOpenShiftByCashierID(ctx context.Context, tx interface{}, cashierID string) (Shift, error)
+	CloseShift(ctx context.Context, tx interface{}, shiftID string, countedCash shared.Price) (Shift, error)
+	CreateCounterPayment(ctx context.Context, tx interface{}, counterPayment CounterPayment) (CounterPayment, error)
+	GetShiftTotals(ctx context.Context, tx interface{}, shiftID string) (Totals, error)
+}
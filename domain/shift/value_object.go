@@ -0,0 +1,63 @@
+package shift
+
+import (
+	"fmt"
+	"fp-kpl/domain/shared"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	MethodCash = "cash"
+	MethodCard = "card"
+)
+
+var (
+	Methods = []string{
+		MethodCash,
+		MethodCard,
+	}
+)
+
+type Method struct {
+	Method string
+}
+
+func NewMethod(method string) (Method, error) {
+	if !isValidMethod(method) {
+		return Method{}, fmt.Errorf("%w: %s", ErrorInvalidPaymentMethod, method)
+	}
+	return Method{
+		Method: method,
+	}, nil
+}
+
+func NewMethodFromSchema(method string) Method {
+	return Method{
+		Method: method,
+	}
+}
+
+// Settle works out what the cashier takes for a total. Cash must cover the
+// total and the difference is given back as change; a card is charged the
+// exact total whatever was entered as tendered.
+func (m Method) Settle(total shared.Price, tendered decimal.Decimal) (amountTendered shared.Price, change shared.Price, err error) {
+	if m.Method == MethodCard {
+		return total, shared.NewPriceFromSchema(decimal.Zero), nil
+	}
+
+	if tendered.LessThan(total.Price) {
+		return shared.Price{}, shared.Price{}, fmt.Errorf("%w: tendered %s for %s", ErrorInsufficientAmount, tendered.String(), total.Price.String())
+	}
+
+	return shared.NewPriceFromSchema(tendered), shared.NewPriceFromSchema(tendered.Sub(total.Price)), nil
+}
+
+func isValidMethod(method string) bool {
+	for _, m := range Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
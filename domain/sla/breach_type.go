@@ -0,0 +1,39 @@
+package sla
+
+import "fmt"
+
+const (
+	BreachTypeCookingOverdue = "cooking_overdue"
+	BreachTypePickupOverdue  = "pickup_overdue"
+	BreachTypePendingOverdue = "pending_overdue"
+)
+
+var (
+	BreachTypes = []string{
+		BreachTypeCookingOverdue,
+		BreachTypePickupOverdue,
+		BreachTypePendingOverdue,
+	}
+)
+
+type BreachType struct {
+	Type string
+}
+
+func NewBreachType(breachType string) (BreachType, error) {
+	if !isValidBreachType(breachType) {
+		return BreachType{}, fmt.Errorf("invalid breach type: %s", breachType)
+	}
+	return BreachType{
+		Type: breachType,
+	}, nil
+}
+
+func isValidBreachType(breachType string) bool {
+	for _, validType := range BreachTypes {
+		if validType == breachType {
+			return true
+		}
+	}
+	return false
+}
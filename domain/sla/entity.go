@@ -0,0 +1,27 @@
+package sla
+
+import (
+	"fp-kpl/domain/identity"
+	"time"
+)
+
+// Breach is an in-flight transaction that has run past one of its service
+// level deadlines.
+type Breach struct {
+	TransactionID identity.ID
+	QueueCode     string
+	Type          BreachType
+	Since         time.Time
+	Deadline      time.Time
+	DetectedAt    time.Time
+}
+
+// Key identifies a breach across scans, so an ongoing breach is only escalated
+// once.
+func (b Breach) Key() string {
+	return b.TransactionID.String() + ":" + b.Type.Type
+}
+
+func (b Breach) Overdue() time.Duration {
+	return b.DetectedAt.Sub(b.Deadline)
+}
@@ -0,0 +1,29 @@
+package sla
+
+import "time"
+
+const (
+	DefaultPickupTimeout  = 10 * time.Minute
+	DefaultPendingTimeout = 15 * time.Minute
+)
+
+// Policy holds how long food may wait at the pass before a waiter picks it up
+// and how long a paid order may wait before the kitchen starts on it. Cooking
+// deadlines come from the wait-time estimator instead.
+type Policy struct {
+	PickupTimeout  time.Duration
+	PendingTimeout time.Duration
+}
+
+func NewPolicy(pickupTimeout time.Duration, pendingTimeout time.Duration) Policy {
+	if pickupTimeout <= 0 {
+		pickupTimeout = DefaultPickupTimeout
+	}
+	if pendingTimeout <= 0 {
+		pendingTimeout = DefaultPendingTimeout
+	}
+	return Policy{
+		PickupTimeout:  pickupTimeout,
+		PendingTimeout: pendingTimeout,
+	}
+}
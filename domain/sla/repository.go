@@ -0,0 +1,11 @@
+package sla
+
+import "context"
+
+// Repository remembers which breaches were already escalated, so a restart or
+// a second instance does not alert on them again.
+type Repository interface {
+	// RecordAlert stores breach as escalated and reports false when it
+	// already was.
+	RecordAlert(ctx context.Context, tx interface{}, breach Breach) (bool, error)
+}
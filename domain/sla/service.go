@@ -0,0 +1,81 @@
+package sla
+
+import (
+	"fp-kpl/domain/transaction"
+	"time"
+)
+
+type (
+	Service interface {
+		Evaluate(queries []transaction.Query, snapshot transaction.KitchenSnapshot, now time.Time) []Breach
+	}
+
+	service struct {
+		policy                   Policy
+		transactionDomainService transaction.Service
+	}
+)
+
+func NewService(policy Policy, transactionDomainService transaction.Service) Service {
+	return &service{
+		policy:                   policy,
+		transactionDomainService: transactionDomainService,
+	}
+}
+
+func (s *service) Evaluate(queries []transaction.Query, snapshot transaction.KitchenSnapshot, now time.Time) []Breach {
+	var breaches []Breach
+	for _, query := range queries {
+		transactionEntity := query.Transaction
+		if !transactionEntity.Payment.IsPaid() {
+			continue
+		}
+
+		switch transactionEntity.OrderStatus.Status {
+		case transaction.OrderStatusPending:
+			since := transactionEntity.CreatedAt
+			if transactionEntity.PaidAt != nil {
+				since = *transactionEntity.PaidAt
+			}
+			// Scheduled pickup orders only start waiting once they are due
+			// in the kitchen.
+			if transactionEntity.CookAfter != nil && transactionEntity.CookAfter.After(since) {
+				since = *transactionEntity.CookAfter
+			}
+			if deadline := since.Add(s.policy.PendingTimeout); now.After(deadline) {
+				breaches = append(breaches, newBreach(transactionEntity, BreachTypePendingOverdue, since, deadline, now))
+			}
+
+		case transaction.OrderStatusPreparing:
+			if transactionEntity.CookedAt == nil {
+				continue
+			}
+			estimate := s.transactionDomainService.EstimateWaitTime(snapshot, query)
+			if s.transactionDomainService.GetOrderDelayStatus(estimate.CookingTime, transactionEntity.CookedAt, nil) {
+				deadline := transactionEntity.CookedAt.Add(estimate.CookingTime)
+				breaches = append(breaches, newBreach(transactionEntity, BreachTypeCookingOverdue, *transactionEntity.CookedAt, deadline, now))
+			}
+
+		case transaction.OrderStatusReadyToServe:
+			if transactionEntity.ReadyAt == nil {
+				continue
+			}
+			if deadline := transactionEntity.ReadyAt.Add(s.policy.PickupTimeout); now.After(deadline) {
+				breaches = append(breaches, newBreach(transactionEntity, BreachTypePickupOverdue, *transactionEntity.ReadyAt, deadline, now))
+			}
+		}
+	}
+
+	return breaches
+}
+
+func newBreach(transactionEntity transaction.Transaction, breachType string, since time.Time, deadline time.Time, now time.Time) Breach {
+	return Breach{
+		TransactionID: transactionEntity.ID,
+		QueueCode:     transactionEntity.QueueCode.Code,
+		Type:          BreachType{Type: breachType},
+		Since:         since,
+		Deadline:      deadline,
+		DetectedAt:    now,
+	}
+}
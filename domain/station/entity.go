@@ -0,0 +1,12 @@
+package station
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+)
+
+type Station struct {
+	ID   identity.ID
+	Name string
+	shared.Timestamp
+}
@@ -0,0 +1,11 @@
+package station
+
+import "errors"
+
+var (
+	ErrorGetAllStations    = errors.New("failed to get all stations")
+	ErrorGetStationByID    = errors.New("failed to get station by id")
+	ErrorStationNotFound   = errors.New("station not found")
+	ErrorAssignStation     = errors.New("failed to assign station")
+	ErrorStationNotAllowed = errors.New("user is not bound to this station")
+)
@@ -0,0 +1,14 @@
+package station
+
+import "context"
+
+type (
+	Repository interface {
+		GetAllStations(ctx context.Context, tx interface{}) ([]Station, error)
+		GetStationByID(ctx context.Context, tx interface{}, id string) (Station, error)
+		GetStationByMenuID(ctx context.Context, tx interface{}, menuID string) (Station, error)
+		UpdateMenuStation(ctx context.Context, tx interface{}, menuID string, stationID string) error
+		UpdateCategoryStation(ctx context.Context, tx interface{}, categoryID string, stationID string) error
+		UpdateUserStation(ctx context.Context, tx interface{}, userID string, stationID string) error
+	}
+)
@@ -8,5 +8,8 @@ import (
 type Table struct {
 	ID          identity.ID
 	TableNumber string
+	// Token is printed in the table's QR code. Guests who scan it may share
+	// the table's cart.
+	Token identity.ID
 	shared.Timestamp
 }
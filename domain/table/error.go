@@ -6,4 +6,5 @@ var (
 	ErrorGetAllTables  = errors.New("failed to get all tables")
 	ErrorGetTableByID  = errors.New("failed to get table by id")
 	ErrorTableNotFound = errors.New("table not found")
+	ErrorTableRequired = errors.New("table is required")
 )
@@ -6,5 +6,6 @@ type (
 	Repository interface {
 		GetAllTables(ctx context.Context, tx interface{}) ([]Table, error)
 		GetTableByID(ctx context.Context, tx interface{}, id string) (Table, error)
+		GetTableByToken(ctx context.Context, tx interface{}, token string) (Table, error)
 	}
 )
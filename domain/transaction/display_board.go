@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	DefaultDisplayHighlightDuration = 2 * time.Minute
+	DisplayBoardLimit               = 60
+)
+
+type (
+	// DisplayBoard is the queue as shown on the restaurant's TV board. It
+	// carries queue codes only, never customer or order details.
+	DisplayBoard struct {
+		Preparing []DisplayEntry
+		Ready     []DisplayEntry
+	}
+
+	// DisplayEntry is one queue code on the board. Highlighted marks codes
+	// that were called for pickup recently.
+	DisplayEntry struct {
+		QueueCode   string
+		Highlighted bool
+	}
+)
+
+// NewDisplayBoard groups paid, unserved transactions by order status. Ready
+// codes are listed newest first and stay highlighted for highlightFor after
+// the kitchen finished them.
+func NewDisplayBoard(transactions []Transaction, now time.Time, highlightFor time.Duration) DisplayBoard {
+	board := DisplayBoard{
+		Preparing: []DisplayEntry{},
+		Ready:     []DisplayEntry{},
+	}
+
+	var ready []Transaction
+	for _, transactionEntity := range transactions {
+		// Pickup orders scheduled for later are not in the kitchen yet.
+		if transactionEntity.QueueCode.Code == "" || transactionEntity.IsHeld(now) {
+			continue
+		}
+
+		switch transactionEntity.OrderStatus.Status {
+		case OrderStatusPending, OrderStatusPreparing:
+			board.Preparing = append(board.Preparing, DisplayEntry{QueueCode: transactionEntity.QueueCode.Code})
+		case OrderStatusReadyToServe:
+			ready = append(ready, transactionEntity)
+		}
+	}
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		return readyTime(ready[i]).After(readyTime(ready[j]))
+	})
+	for _, transactionEntity := range ready {
+		readyAt := readyTime(transactionEntity)
+		board.Ready = append(board.Ready, DisplayEntry{
+			QueueCode:   transactionEntity.QueueCode.Code,
+			Highlighted: !readyAt.IsZero() && now.Sub(readyAt) < highlightFor,
+		})
+	}
+
+	return board
+}
+
+func readyTime(transactionEntity Transaction) time.Time {
+	if transactionEntity.ReadyAt == nil {
+		return time.Time{}
+	}
+	return *transactionEntity.ReadyAt
+}
@@ -2,19 +2,39 @@ package transaction
 
 import (
 	"fp-kpl/domain/identity"
+	"fp-kpl/domain/order"
 	"fp-kpl/domain/shared"
 	"time"
 )
 
 type Transaction struct {
-	ID          identity.ID
-	UserID      identity.ID
-	TableID     identity.ID
-	Payment     Payment
-	OrderStatus OrderStatus
-	CookedAt    *time.Time
-	ServedAt    *time.Time
-	QueueCode   QueueCode
-	TotalPrice  shared.Price
+	ID        identity.ID
+	UserID    identity.ID
+	Guest     Guest
+	TableID   identity.ID
+	OrderType OrderType
+	// PickupAt is when a pickup order will be collected, and CookAfter is
+	// when the kitchen should start on it; both are nil for other orders.
+	PickupAt        *time.Time
+	CookAfter       *time.Time
+	Payment         Payment
+	PaymentProvider string
+	OrderStatus     OrderStatus
+	PaidAt          *time.Time
+	CookedAt        *time.Time
+	ReadyAt         *time.Time
+	ServedAt        *time.Time
+	QueueCode       QueueCode
+	Priority        int
+	TotalPrice      shared.Price
+	Pricing         order.PriceBreakdown
 	shared.Timestamp
 }
+
+// IsPaymentOverdue reports whether the transaction is still unpaid although
+// it was created before createdBefore, and its provider lets it lapse.
+func (t Transaction) IsPaymentOverdue(createdBefore time.Time) bool {
+	return t.Payment.Status == PaymentStatusPending &&
+		HasPaymentTimeout(t.PaymentProvider) &&
+		t.CreatedAt.Before(createdBefore)
+}
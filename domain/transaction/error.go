@@ -5,8 +5,25 @@ import (
 )
 
 var (
-	ErrorInvalidTransaction = errors.New("invalid transaction")
-	ErrorGetAllTransactions = errors.New("failed to get all transactions")
-	ErrorInvalidOrderStatus = errors.New("invalid order status")
-	ErrorNextOrderNotFound  = errors.New("next order not found")
+	ErrorInvalidTransaction   = errors.New("invalid transaction")
+	ErrorGetAllTransactions   = errors.New("failed to get all transactions")
+	ErrorInvalidOrderStatus   = errors.New("invalid order status")
+	ErrorNextOrderNotFound    = errors.New("next order not found")
+	ErrorTransactionNotFound  = errors.New("transaction not found")
+	ErrorPaymentAlreadyFinal  = errors.New("payment status is already final")
+	ErrorNotCounterPayment    = errors.New("transaction is not paid at the counter")
+	ErrorInvalidSplit         = errors.New("invalid split bill")
+	ErrorSplitExceedsTotal    = errors.New("split payments exceed the remaining amount")
+	ErrorSplitNotSupported    = errors.New("payment provider does not support split payments")
+	ErrorSplitPaymentNotFound = errors.New("split payment not found")
+	ErrorNotTransactionOwner  = errors.New("transaction belongs to another user")
+	ErrorPaymentNotPaid       = errors.New("payment is not paid")
+	ErrorInvalidRefund        = errors.New("invalid refund")
+	ErrorInvalidOrderType     = errors.New("invalid order type")
+	ErrorPickupTimeRequired   = errors.New("pickup time is required for pickup orders")
+	ErrorInvalidPickupTime    = errors.New("pickup time must be in the future and only set for pickup orders")
+	ErrorPickupTooFarAhead    = errors.New("pickup time is too far ahead")
+	ErrorNotDineIn            = errors.New("order is collected at the counter")
+	ErrorNotPackaged          = errors.New("order is served at the table")
+	ErrorPaidAfterSplit       = errors.New("the whole bill was paid after it was split")
 )
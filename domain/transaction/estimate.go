@@ -0,0 +1,344 @@
+package transaction
+
+import (
+	"fp-kpl/domain/order"
+	"math"
+	"time"
+)
+
+const (
+	DefaultStationCapacity = 1
+	DefaultEstimateSpread  = 0.25
+	MinThroughputSamples   = 5
+	ThroughputHistorySize  = 50
+)
+
+type (
+	// Throughput describes how long the kitchen actually takes compared to the
+	// nominal menu cooking time. Ratio is the mean of (served_at - cooked_at) /
+	// max cooking time over recent transactions and Spread is its relative
+	// standard deviation.
+	Throughput struct {
+		Ratio   float64
+		Spread  float64
+		Samples int
+	}
+
+	// KitchenSnapshot is the state of the kitchen an estimate is computed
+	// against: every paid transaction still waiting for or being cooked today.
+	// Strategy decides the order the pending ones are cooked in and falls back
+	// to FIFO when nil.
+	KitchenSnapshot struct {
+		Queue           []Query
+		Throughput      Throughput
+		StationCapacity int
+		Strategy        SchedulingStrategy
+		Now             time.Time
+	}
+
+	WaitEstimate struct {
+		QueuePosition int
+		CookingTime   time.Duration
+		ETA           time.Time
+		EarliestETA   time.Time
+		LatestETA     time.Time
+	}
+)
+
+// LearnThroughput derives the kitchen throughput from served transactions.
+// Below MinThroughputSamples the nominal cooking time is trusted as is.
+func LearnThroughput(history []Query) Throughput {
+	var ratios []float64
+	for _, query := range history {
+		if query.Transaction.CookedAt == nil || query.Transaction.ServedAt == nil {
+			continue
+		}
+
+		nominal := maxCookingTime(query.Orders)
+		actual := query.Transaction.ServedAt.Sub(*query.Transaction.CookedAt)
+		if nominal <= 0 || actual <= 0 {
+			continue
+		}
+		ratios = append(ratios, float64(actual)/float64(nominal))
+	}
+
+	if len(ratios) < MinThroughputSamples {
+		return Throughput{Ratio: 1, Spread: DefaultEstimateSpread, Samples: len(ratios)}
+	}
+
+	var sum float64
+	for _, ratio := range ratios {
+		sum += ratio
+	}
+	mean := sum / float64(len(ratios))
+
+	var variance float64
+	for _, ratio := range ratios {
+		variance += (ratio - mean) * (ratio - mean)
+	}
+	variance /= float64(len(ratios))
+
+	return Throughput{
+		Ratio:   mean,
+		Spread:  math.Sqrt(variance) / mean,
+		Samples: len(ratios),
+	}
+}
+
+func (t Throughput) normalized() Throughput {
+	if t.Ratio <= 0 {
+		t.Ratio = 1
+	}
+	if t.Spread <= 0 {
+		t.Spread = DefaultEstimateSpread
+	}
+	return t
+}
+
+func (t Throughput) scale(duration time.Duration) time.Duration {
+	return time.Duration(float64(duration) * t.Ratio)
+}
+
+// Estimate predicts when target will be served. Every station the target
+// still has lines at is replayed separately: lines already being cooked
+// occupy one of the station's slots first, then the lines queued ahead of the
+// target take the earliest free slot in order. The target is ready once its
+// slowest station finishes.
+func (k KitchenSnapshot) Estimate(target Query) WaitEstimate {
+	throughput := k.Throughput.normalized()
+	now := k.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	estimate := WaitEstimate{
+		CookingTime: throughput.scale(maxCookingTime(target.Orders)),
+	}
+
+	if servedAt := target.Transaction.ServedAt; servedAt != nil {
+		estimate.ETA = *servedAt
+		estimate.EarliestETA = *servedAt
+		estimate.LatestETA = *servedAt
+		return estimate
+	}
+
+	// A pickup order the kitchen has not started on yet is cooked to be
+	// ready by its pickup time.
+	if target.Transaction.IsHeld(now) {
+		estimate.ETA = *target.Transaction.PickupAt
+		estimate.EarliestETA = *target.Transaction.PickupAt
+		estimate.LatestETA = *target.Transaction.PickupAt
+		return estimate
+	}
+
+	ahead := k.ahead(target, now)
+
+	if isPending(target) {
+		estimate.QueuePosition = 1
+		for _, query := range ahead {
+			if isPending(query) {
+				estimate.QueuePosition++
+			}
+		}
+	}
+
+	var wait time.Duration
+	for _, stationID := range unfinishedStations(target.Orders) {
+		finish := k.stationFinish(stationID, ahead, target, throughput, now)
+		if finish > wait {
+			wait = finish
+		}
+	}
+
+	spread := time.Duration(float64(wait) * throughput.Spread)
+	estimate.ETA = now.Add(wait)
+	estimate.EarliestETA = now.Add(wait - spread)
+	estimate.LatestETA = now.Add(wait + spread)
+	if estimate.EarliestETA.Before(now) {
+		estimate.EarliestETA = now
+	}
+
+	return estimate
+}
+
+// AwaitsKitchen tells whether the transaction still has lines the kitchen has
+// to cook, which is the only case its estimate depends on the kitchen queue.
+func (q Query) AwaitsKitchen(now time.Time) bool {
+	if q.Transaction.ServedAt != nil || q.Transaction.IsHeld(now) {
+		return false
+	}
+	return len(unfinishedLines(q.Orders)) > 0
+}
+
+// Remaining returns how long is left until the estimated time of arrival.
+func (e WaitEstimate) Remaining(now time.Time) time.Duration {
+	if e.ETA.IsZero() || !e.ETA.After(now) {
+		return 0
+	}
+	return e.ETA.Sub(now)
+}
+
+// ahead returns the transactions the kitchen gets to before target: the ones
+// it started on earlier, followed by the pending ones the scheduling strategy
+// picks before the target.
+func (k KitchenSnapshot) ahead(target Query, now time.Time) []Query {
+	strategy := k.Strategy
+	if strategy == nil {
+		strategy = NewFIFOStrategy()
+	}
+
+	var ahead []Query
+	candidates := []Query{target}
+	for _, query := range k.Queue {
+		if query.Transaction.ID == target.Transaction.ID {
+			continue
+		}
+		if isPending(query) {
+			candidates = append(candidates, query)
+		} else if isPending(target) || startedBefore(query, target) {
+			ahead = append(ahead, query)
+		}
+	}
+
+	for len(candidates) > 0 {
+		picked := strategy.Pick(candidates, now)
+		if picked < 0 || candidates[picked].Transaction.ID == target.Transaction.ID {
+			break
+		}
+		ahead = append(ahead, candidates[picked])
+		candidates = append(candidates[:picked], candidates[picked+1:]...)
+	}
+
+	return ahead
+}
+
+func isPending(query Query) bool {
+	status := query.Transaction.OrderStatus.Status
+	return status == OrderStatusPending || status == ""
+}
+
+func startedBefore(query Query, target Query) bool {
+	if query.Transaction.CookedAt != nil && target.Transaction.CookedAt != nil {
+		return query.Transaction.CookedAt.Before(*target.Transaction.CookedAt)
+	}
+	return query.Transaction.CreatedAt.Before(target.Transaction.CreatedAt)
+}
+
+func (k KitchenSnapshot) stationFinish(stationID string, ahead []Query, target Query, throughput Throughput, now time.Time) time.Duration {
+	capacity := k.StationCapacity
+	if capacity <= 0 {
+		capacity = DefaultStationCapacity
+	}
+	slots := make([]time.Duration, capacity)
+
+	for _, query := range ahead {
+		lines := unfinishedLines(linesAtStation(query.Orders, stationID))
+		if len(lines) > 0 && isCooking(lines) {
+			occupy(slots, remainingCookingTime(query.Transaction, throughput.scale(maxCookingTime(lines)), now))
+		}
+	}
+
+	for _, query := range ahead {
+		lines := unfinishedLines(linesAtStation(query.Orders, stationID))
+		if len(lines) > 0 && !isCooking(lines) {
+			occupy(slots, throughput.scale(maxCookingTime(lines)))
+		}
+	}
+
+	lines := unfinishedLines(linesAtStation(target.Orders, stationID))
+	cookingTime := throughput.scale(maxCookingTime(lines))
+	if isCooking(lines) {
+		return remainingCookingTime(target.Transaction, cookingTime, now)
+	}
+
+	return slots[earliestSlot(slots)] + cookingTime
+}
+
+func occupy(slots []time.Duration, duration time.Duration) {
+	slots[earliestSlot(slots)] += duration
+}
+
+func earliestSlot(slots []time.Duration) int {
+	earliest := 0
+	for i, slot := range slots {
+		if slot < slots[earliest] {
+			earliest = i
+		}
+	}
+	return earliest
+}
+
+func remainingCookingTime(transactionEntity Transaction, cookingTime time.Duration, now time.Time) time.Duration {
+	if transactionEntity.CookedAt == nil {
+		return cookingTime
+	}
+
+	remaining := transactionEntity.CookedAt.Add(cookingTime).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// linesAtStation returns the lines routed to stationID, where an empty
+// stationID groups the lines that are not routed to any station.
+func linesAtStation(orders []OrderQuery, stationID string) []OrderQuery {
+	var lines []OrderQuery
+	for _, orderQuery := range orders {
+		if lineStationID(orderQuery) == stationID {
+			lines = append(lines, orderQuery)
+		}
+	}
+	return lines
+}
+
+func lineStationID(orderQuery OrderQuery) string {
+	if orderQuery.Order.StationID.IsEmpty() {
+		return ""
+	}
+	return orderQuery.Order.StationID.String()
+}
+
+func unfinishedStations(orders []OrderQuery) []string {
+	var stationIDs []string
+	seen := make(map[string]bool)
+	for _, orderQuery := range unfinishedLines(orders) {
+		stationID := lineStationID(orderQuery)
+		if !seen[stationID] {
+			seen[stationID] = true
+			stationIDs = append(stationIDs, stationID)
+		}
+	}
+	return stationIDs
+}
+
+func unfinishedLines(orders []OrderQuery) []OrderQuery {
+	var lines []OrderQuery
+	for _, orderQuery := range orders {
+		if orderQuery.Order.CookingStatus.Status != order.CookingStatusDone {
+			lines = append(lines, orderQuery)
+		}
+	}
+	return lines
+}
+
+func isCooking(orders []OrderQuery) bool {
+	for _, orderQuery := range orders {
+		if orderQuery.Order.CookingStatus.Status == order.CookingStatusPreparing {
+			return true
+		}
+	}
+	return false
+}
+
+func maxCookingTime(orders []OrderQuery) time.Duration {
+	maxCookingTime := time.Duration(0)
+
+	for _, orderQuery := range orders {
+		if orderQuery.Menu.CookingTime > maxCookingTime {
+			maxCookingTime = orderQuery.Menu.CookingTime
+		}
+	}
+
+	return maxCookingTime
+}
@@ -0,0 +1,34 @@
+package transaction
+
+import (
+	"fp-kpl/domain/identity"
+	"time"
+)
+
+const (
+	EventTransactionExpired = "transaction.expired"
+	EventLatePaymentWebhook = "transaction.late_payment_webhook"
+	// EventPaidAfterSplit flags a payment of the whole bill, made through the
+	// original charge after the bill was split, for a refund.
+	EventPaidAfterSplit = "transaction.paid_after_split"
+)
+
+// Event is published when a transaction changes outside of a customer or
+// staff request, e.g. by a background job or a gateway webhook.
+type Event struct {
+	Type          string
+	TransactionID identity.ID
+	OccurredAt    time.Time
+	Data          map[string]string
+}
+
+// NewEvent takes occurredAt from the caller's clock, so events follow the
+// restaurant's time like the rest of the transaction.
+func NewEvent(eventType string, transactionID identity.ID, occurredAt time.Time, data map[string]string) Event {
+	return Event{
+		Type:          eventType,
+		TransactionID: transactionID,
+		OccurredAt:    occurredAt,
+		Data:          data,
+	}
+}
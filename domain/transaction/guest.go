@@ -0,0 +1,37 @@
+package transaction
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const GuestTokenPrefix = "gt_"
+
+// Guest is the pickup contact of a transaction placed without a customer
+// account, e.g. at a self-service kiosk. Both fields are optional; the guest
+// token the customer polls with is only kept as a hash.
+type Guest struct {
+	Name      string
+	Phone     string
+	TokenHash string
+}
+
+// IsGuest reports whether the transaction was placed without an account.
+func (t Transaction) IsGuest() bool {
+	return t.UserID.IsEmpty()
+}
+
+// NewGuestToken returns a random token for a guest to follow their order with.
+func NewGuestToken() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return GuestTokenPrefix + hex.EncodeToString(secret), nil
+}
+
+func HashGuestToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
@@ -1,6 +1,9 @@
 package transaction
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	OrderStatusPending      = "pending"
@@ -8,6 +11,11 @@ const (
 	OrderStatusReadyToServe = "ready_to_serve"
 	OrderStatusDelivering   = "delivering"
 	OrderStatusServed       = "served"
+
+	// MaxInFlightAge bounds how long a paid order may stay open and still be
+	// carried over into later business days. Older open orders are treated as
+	// abandoned.
+	MaxInFlightAge = 24 * time.Hour
 )
 
 var (
@@ -18,6 +26,15 @@ var (
 		OrderStatusDelivering,
 		OrderStatusServed,
 	}
+
+	// OpenOrderStatuses are the statuses of orders still on their way to the
+	// table, which stay reachable by queue code across business days.
+	OpenOrderStatuses = []string{
+		OrderStatusPending,
+		OrderStatusPreparing,
+		OrderStatusReadyToServe,
+		OrderStatusDelivering,
+	}
 )
 
 type OrderStatus struct {
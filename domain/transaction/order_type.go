@@ -0,0 +1,110 @@
+package transaction
+
+import (
+	"fmt"
+	"fp-kpl/domain/shared"
+	"time"
+)
+
+const (
+	OrderTypeDineIn   = "dine_in"
+	OrderTypeTakeaway = "takeaway"
+	OrderTypePickup   = "pickup"
+
+	// PickupLeadTime is how long before its cooking must start a scheduled
+	// pickup order joins the kitchen queue, leaving room for the orders
+	// already being cooked.
+	PickupLeadTime = 10 * time.Minute
+
+	// MaxPickupDaysAhead is how many business days after the current one a
+	// pickup order may be scheduled for. Menus and prices are only known to
+	// hold for the coming days.
+	MaxPickupDaysAhead = 2
+)
+
+var (
+	OrderTypes = []string{
+		OrderTypeDineIn,
+		OrderTypeTakeaway,
+		OrderTypePickup,
+	}
+)
+
+// OrderType tells where the order ends up: served at a table, or packed and
+// collected at the counter, either right away or at a scheduled time.
+type OrderType struct {
+	Type string
+}
+
+// NewOrderType defaults to dine-in, which is what every order was before
+// order types existed.
+func NewOrderType(orderType string) (OrderType, error) {
+	if orderType == "" {
+		orderType = OrderTypeDineIn
+	}
+	if !isValidOrderType(orderType) {
+		return OrderType{}, fmt.Errorf("%w: %s", ErrorInvalidOrderType, orderType)
+	}
+	return OrderType{
+		Type: orderType,
+	}, nil
+}
+
+func NewOrderTypeFromSchema(orderType string) OrderType {
+	return OrderType{
+		Type: orderType,
+	}
+}
+
+func (o OrderType) IsDineIn() bool {
+	return o.Type == OrderTypeDineIn
+}
+
+// IsPackaged reports whether the order is packed to be collected at the
+// counter instead of served at a table.
+func (o OrderType) IsPackaged() bool {
+	return o.Type == OrderTypeTakeaway || o.Type == OrderTypePickup
+}
+
+// ValidatePickupAt checks the pickup time against the order type: pickup
+// orders need one in the future, no later than MaxPickupDaysAhead business
+// days after today, and other orders must not have one.
+func (o OrderType) ValidatePickupAt(pickupAt *time.Time, now time.Time, today shared.BusinessDay) error {
+	if o.Type != OrderTypePickup {
+		if pickupAt != nil {
+			return ErrorInvalidPickupTime
+		}
+		return nil
+	}
+	if pickupAt == nil {
+		return ErrorPickupTimeRequired
+	}
+	if !pickupAt.After(now) {
+		return ErrorInvalidPickupTime
+	}
+	if !pickupAt.Before(today.End.AddDate(0, 0, MaxPickupDaysAhead)) {
+		return fmt.Errorf("%w: at most %d business days ahead", ErrorPickupTooFarAhead, MaxPickupDaysAhead)
+	}
+	return nil
+}
+
+// CookAfter is when the kitchen should start on an order to be picked up at
+// pickupAt, given how long its dishes take.
+func CookAfter(pickupAt time.Time, cookingTime time.Duration) time.Time {
+	return pickupAt.Add(-cookingTime - PickupLeadTime)
+}
+
+// IsHeld reports whether a scheduled pickup order is still being kept out of
+// the kitchen queue.
+func (t Transaction) IsHeld(now time.Time) bool {
+	return t.PickupAt != nil && t.CookAfter != nil && t.CookAfter.After(now)
+}
+
+func isValidOrderType(orderType string) bool {
+	for _, validOrderType := range OrderTypes {
+		if validOrderType == orderType {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package transaction
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	PaymentStatusCapture    = "capture"
@@ -9,6 +12,13 @@ const (
 	PaymentStatusDeny       = "deny"
 	PaymentStatusExpire     = "expire"
 	PaymentStatusPending    = "pending"
+
+	PaymentProviderMidtrans = "midtrans"
+	PaymentProviderXendit   = "xendit"
+	PaymentProviderFake     = "fake"
+	PaymentProviderCounter  = "counter"
+
+	DefaultPaymentTimeout = 15 * time.Minute
 )
 
 var (
@@ -20,6 +30,13 @@ var (
 		PaymentStatusExpire,
 		PaymentStatusPending,
 	}
+
+	PaymentProviders = []string{
+		PaymentProviderMidtrans,
+		PaymentProviderXendit,
+		PaymentProviderFake,
+		PaymentProviderCounter,
+	}
 )
 
 type Payment struct {
@@ -44,6 +61,47 @@ func NewPaymentFromSchema(code, status string) Payment {
 	}
 }
 
+// CanTransitionTo reports whether a webhook may move the payment to status.
+// Pending payments accept anything and a capture can still settle or be
+// reversed, while settlement, expire, cancel and deny are final.
+func (p Payment) CanTransitionTo(status string) bool {
+	if p.Status == status {
+		return false
+	}
+
+	switch p.Status {
+	case PaymentStatusPending, "":
+		return true
+	case PaymentStatusCapture:
+		return status == PaymentStatusSettlement || status == PaymentStatusCancel || status == PaymentStatusDeny
+	default:
+		return false
+	}
+}
+
+// IsPaid reports whether the gateway has confirmed the payment.
+func (p Payment) IsPaid() bool {
+	return p.Status == PaymentStatusSettlement || p.Status == PaymentStatusCapture
+}
+
+// HasPaymentTimeout reports whether an unpaid transaction of the provider
+// lapses after the payment timeout. Counter orders are paid by a customer
+// standing at the till, so they wait for the cashier instead.
+func HasPaymentTimeout(provider string) bool {
+	return provider != PaymentProviderCounter
+}
+
+// UntimedPaymentProviders are the providers HasPaymentTimeout exempts.
+func UntimedPaymentProviders() []string {
+	var providers []string
+	for _, provider := range PaymentProviders {
+		if !HasPaymentTimeout(provider) {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
 func isValidPaymentStatus(status string) bool {
 	for _, paymentStatus := range PaymentStatuses {
 		if paymentStatus == status {
@@ -3,6 +3,7 @@ package transaction
 import (
 	menu "fp-kpl/domain/menu/menu_item"
 	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
 	"fp-kpl/domain/table"
 )
 
@@ -18,3 +19,74 @@ type (
 		Menu  menu.Menu   `json:"menu"`
 	}
 )
+
+// UnitPrice is the price the line was charged at. Lines ordered before prices
+// were stored on the order fall back to the menu price.
+func (q OrderQuery) UnitPrice() shared.Price {
+	if q.Order.Price.Price.IsPositive() {
+		return q.Order.Price
+	}
+	return q.Menu.Price
+}
+
+// StationOrders returns the lines routed to the given station, or every line
+// when stationID is empty. Lines whose menu has no station are offered to
+// every station, and the first one to start cooking them takes them.
+func (q Query) StationOrders(stationID string) []OrderQuery {
+	if stationID == "" {
+		return q.Orders
+	}
+
+	var stationOrders []OrderQuery
+	for _, orderQuery := range q.Orders {
+		if orderQuery.Order.StationID.IsEmpty() || orderQuery.Order.StationID.String() == stationID {
+			stationOrders = append(stationOrders, orderQuery)
+		}
+	}
+	return stationOrders
+}
+
+// CheckStartCooking returns the lines the station, or the whole kitchen when
+// stationID is empty, is about to start. A station may join a transaction
+// another station already moved to preparing.
+func (q Query) CheckStartCooking(stationID string) ([]OrderQuery, error) {
+	orderStatus := q.Transaction.OrderStatus.Status
+	stationOrders := q.StationOrders(stationID)
+	if stationID == "" {
+		if orderStatus != OrderStatusPending {
+			return nil, ErrorInvalidOrderStatus
+		}
+		return stationOrders, nil
+	}
+
+	if orderStatus != OrderStatusPending && orderStatus != OrderStatusPreparing {
+		return nil, ErrorInvalidOrderStatus
+	}
+	if !hasCookingStatus(stationOrders, order.CookingStatusPending) {
+		return nil, ErrorInvalidOrderStatus
+	}
+	return stationOrders, nil
+}
+
+// CheckFinishCooking returns the lines the station, or the whole kitchen when
+// stationID is empty, is about to finish.
+func (q Query) CheckFinishCooking(stationID string) ([]OrderQuery, error) {
+	if q.Transaction.OrderStatus.Status != OrderStatusPreparing {
+		return nil, ErrorInvalidOrderStatus
+	}
+
+	stationOrders := q.StationOrders(stationID)
+	if stationID != "" && !hasCookingStatus(stationOrders, order.CookingStatusPreparing) {
+		return nil, ErrorInvalidOrderStatus
+	}
+	return stationOrders, nil
+}
+
+func hasCookingStatus(orders []OrderQuery, status string) bool {
+	for _, orderQuery := range orders {
+		if orderQuery.Order.CookingStatus.Status == status {
+			return true
+		}
+	}
+	return false
+}
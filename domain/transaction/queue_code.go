@@ -5,9 +5,12 @@ import (
 	"strings"
 )
 
+// QueueCode numbers paid transactions within a business day. BusinessDay is
+// the day the code was issued in, since the numbering restarts every day.
 type QueueCode struct {
-	Code  string
-	Valid bool
+	Code        string
+	BusinessDay string
+	Valid       bool
 }
 
 func NewQueueCode(code string) (QueueCode, error) {
@@ -0,0 +1,70 @@
+package transaction
+
+import (
+	"fp-kpl/domain/identity"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DiscrepancyPaidNotRecorded = "paid_not_recorded"
+	DiscrepancyAmountMismatch  = "amount_mismatch"
+	DiscrepancyStatusMismatch  = "status_mismatch"
+
+	DefaultReconciliationLookback = 24 * time.Hour
+)
+
+// Discrepancy is a difference between what the gateway reports for a payment
+// and what is stored locally. Resolved is set when reconciliation was able to
+// apply the gateway state.
+type Discrepancy struct {
+	TransactionID identity.ID
+	Type          string
+	LocalStatus   string
+	GatewayStatus string
+	LocalAmount   decimal.Decimal
+	GatewayAmount decimal.Decimal
+	Resolved      bool
+}
+
+// Reconcile compares a local transaction with the state reported by the
+// gateway. apply is true when the gateway status should be written through the
+// webhook path; a non-nil discrepancy is returned when the two disagree in a
+// way an operator should know about. A paid amount that does not match the
+// total is never applied.
+func Reconcile(local Transaction, gatewayStatus string, gatewayAmount decimal.Decimal) (apply bool, discrepancy *Discrepancy) {
+	if local.Payment.Status == gatewayStatus && gatewayAmount.Equal(local.TotalPrice.Price) {
+		return false, nil
+	}
+
+	newDiscrepancy := func(discrepancyType string) *Discrepancy {
+		return &Discrepancy{
+			TransactionID: local.ID,
+			Type:          discrepancyType,
+			LocalStatus:   local.Payment.Status,
+			GatewayStatus: gatewayStatus,
+			LocalAmount:   local.TotalPrice.Price,
+			GatewayAmount: gatewayAmount,
+		}
+	}
+
+	gatewayPayment := NewPaymentFromSchema(local.Payment.Code, gatewayStatus)
+	if gatewayPayment.IsPaid() && !gatewayAmount.Equal(local.TotalPrice.Price) {
+		return false, newDiscrepancy(DiscrepancyAmountMismatch)
+	}
+
+	if local.Payment.Status == gatewayStatus {
+		return false, nil
+	}
+
+	if !isValidPaymentStatus(gatewayStatus) || !local.Payment.CanTransitionTo(gatewayStatus) {
+		return false, newDiscrepancy(DiscrepancyStatusMismatch)
+	}
+
+	if gatewayPayment.IsPaid() && !local.Payment.IsPaid() {
+		return true, newDiscrepancy(DiscrepancyPaidNotRecorded)
+	}
+
+	return true, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fp-kpl/application/response"
 	"fp-kpl/platform/pagination"
+	"time"
 )
 
 type Repository interface {
@@ -11,13 +12,46 @@ type Repository interface {
 	GetAllTransactionsWithPagination(ctx context.Context, tx interface{}, userID string, req pagination.Request) (pagination.ResponseWithData, error)
 	GetAllReadyToServeTransactionList(ctx context.Context, tx interface{}, req pagination.Request) (pagination.ResponseWithData, error)
 	GetDetailedTransactionByID(ctx context.Context, tx interface{}, id string) (Query, error)
-	GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error)
+	// LockTransaction locks the transaction row until tx ends, so its payment
+	// status cannot change between checking and updating it.
+	LockTransaction(ctx context.Context, tx interface{}, id string) error
+	GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error)
 	GetNextOrder(ctx context.Context, tx interface{}) (response.NextOrder, error)
+	GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error)
+	GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]Query, error)
+	GetKitchenQueue(ctx context.Context, tx interface{}) ([]Query, error)
+	GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]Query, error)
+	GetInFlightTransactions(ctx context.Context, tx interface{}) ([]Query, error)
+	// GetDisplayBoardTransactions returns at most limit of the newest paid,
+	// unserved transactions, oldest first, without their orders.
+	GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]Transaction, error)
+	ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]Transaction, error)
+	GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]Transaction, error)
 	UpdateCookedAt(ctx context.Context, tx interface{}, transactionID string) (Transaction, error)
 	UpdateTransactionCookingStatusStart(ctx context.Context, tx interface{}, transactionID string) (Transaction, error)
 	UpdateTransactionCookingStatusFinish(ctx context.Context, tx interface{}, transactionID string) (Transaction, error)
 	UpdateTransactionDeliveringStatusStart(ctx context.Context, tx interface{}, transactionID string) (Transaction, error)
 	UpdateTransactionDeliveringStatusFinish(ctx context.Context, tx interface{}, transactionID string) (Transaction, error)
 	UpdateServedAt(ctx context.Context, tx interface{}, transactionID string) (Transaction, error)
+	UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (Transaction, error)
 	GetTransactionByQueueCode(ctx context.Context, tx interface{}, queueCode string) (Query, error)
+	GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (Query, error)
+}
+
+// SplitPaymentRepository stores the shares of split bills and the refunds
+// made against them.
+type SplitPaymentRepository interface {
+	CreateSplitPayment(ctx context.Context, tx interface{}, splitPayment SplitPayment) (SplitPayment, error)
+	GetSplitPaymentByID(ctx context.Context, tx interface{}, id string) (SplitPayment, error)
+	// LockSplitPayment returns the split payment and locks it until tx ends,
+	// so its refunded amount cannot change while a refund is being written.
+	LockSplitPayment(ctx context.Context, tx interface{}, id string) (SplitPayment, error)
+	GetSplitPaymentsByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]SplitPayment, error)
+	CreateRefund(ctx context.Context, tx interface{}, refund Refund) (Refund, error)
+}
+
+// StatusChangeRepository keeps the audit trail of order status transitions.
+type StatusChangeRepository interface {
+	CreateStatusChange(ctx context.Context, tx interface{}, statusChange StatusChange) (StatusChange, error)
+	GetStatusChangesByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]StatusChange, error)
 }
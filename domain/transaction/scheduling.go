@@ -0,0 +1,177 @@
+package transaction
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	SchedulingFIFO             = "fifo"
+	SchedulingShortestCookTime = "shortest_cook_time"
+	SchedulingAging            = "aging"
+	SchedulingPriority         = "priority"
+
+	DefaultAgingRate = 1.0
+)
+
+var (
+	SchedulingStrategies = []string{
+		SchedulingFIFO,
+		SchedulingShortestCookTime,
+		SchedulingAging,
+		SchedulingPriority,
+	}
+)
+
+type (
+	// SchedulingStrategy decides which paid, pending transaction the kitchen
+	// should cook next.
+	SchedulingStrategy interface {
+		Name() string
+		// Pick returns the index of the candidate to cook next, or -1 when
+		// there are no candidates.
+		Pick(candidates []Query, now time.Time) int
+	}
+
+	CookingTimeFunc func(orders []OrderQuery) time.Duration
+
+	fifoStrategy struct{}
+
+	shortestCookTimeStrategy struct {
+		cookingTime CookingTimeFunc
+	}
+
+	agingStrategy struct {
+		cookingTime CookingTimeFunc
+		agingRate   float64
+	}
+
+	priorityStrategy struct {
+		fallback SchedulingStrategy
+	}
+)
+
+func NewSchedulingStrategy(name string, cookingTime CookingTimeFunc, agingRate float64) (SchedulingStrategy, error) {
+	switch name {
+	case "", SchedulingFIFO:
+		return NewFIFOStrategy(), nil
+	case SchedulingShortestCookTime:
+		return NewShortestCookTimeStrategy(cookingTime), nil
+	case SchedulingAging:
+		return NewAgingStrategy(cookingTime, agingRate), nil
+	case SchedulingPriority:
+		return NewPriorityStrategy(NewFIFOStrategy()), nil
+	default:
+		return nil, fmt.Errorf("invalid scheduling strategy: %s", name)
+	}
+}
+
+func NewFIFOStrategy() SchedulingStrategy {
+	return fifoStrategy{}
+}
+
+func (s fifoStrategy) Name() string {
+	return SchedulingFIFO
+}
+
+func (s fifoStrategy) Pick(candidates []Query, now time.Time) int {
+	return pickLowest(candidates, func(candidate Query) float64 {
+		return 0
+	})
+}
+
+// NewShortestCookTimeStrategy cooks the transaction whose longest line is the
+// quickest to prepare first, which minimises the average wait but can starve
+// long dishes while short ones keep arriving.
+func NewShortestCookTimeStrategy(cookingTime CookingTimeFunc) SchedulingStrategy {
+	return shortestCookTimeStrategy{cookingTime: cookingTime}
+}
+
+func (s shortestCookTimeStrategy) Name() string {
+	return SchedulingShortestCookTime
+}
+
+func (s shortestCookTimeStrategy) Pick(candidates []Query, now time.Time) int {
+	return pickLowest(candidates, func(candidate Query) float64 {
+		return s.cookingTime(candidate.Orders).Seconds()
+	})
+}
+
+// NewAgingStrategy behaves like shortest-cook-time-first, except that every
+// second a transaction waits takes agingRate seconds off its cooking time, so
+// long dishes eventually reach the front of the queue.
+func NewAgingStrategy(cookingTime CookingTimeFunc, agingRate float64) SchedulingStrategy {
+	if agingRate <= 0 {
+		agingRate = DefaultAgingRate
+	}
+	return agingStrategy{
+		cookingTime: cookingTime,
+		agingRate:   agingRate,
+	}
+}
+
+func (s agingStrategy) Name() string {
+	return SchedulingAging
+}
+
+func (s agingStrategy) Pick(candidates []Query, now time.Time) int {
+	return pickLowest(candidates, func(candidate Query) float64 {
+		waited := now.Sub(candidate.Transaction.CreatedAt).Seconds()
+		return s.cookingTime(candidate.Orders).Seconds() - s.agingRate*waited
+	})
+}
+
+// NewPriorityStrategy serves transactions with the highest priority flag first
+// and uses fallback to order transactions sharing the same priority.
+func NewPriorityStrategy(fallback SchedulingStrategy) SchedulingStrategy {
+	return priorityStrategy{fallback: fallback}
+}
+
+func (s priorityStrategy) Name() string {
+	return SchedulingPriority
+}
+
+func (s priorityStrategy) Pick(candidates []Query, now time.Time) int {
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	highest := candidates[0].Transaction.Priority
+	for _, candidate := range candidates {
+		if candidate.Transaction.Priority > highest {
+			highest = candidate.Transaction.Priority
+		}
+	}
+
+	var indexes []int
+	var prioritized []Query
+	for i, candidate := range candidates {
+		if candidate.Transaction.Priority == highest {
+			indexes = append(indexes, i)
+			prioritized = append(prioritized, candidate)
+		}
+	}
+
+	picked := s.fallback.Pick(prioritized, now)
+	if picked < 0 {
+		return -1
+	}
+	return indexes[picked]
+}
+
+// pickLowest returns the candidate with the lowest score, breaking ties by the
+// oldest creation time.
+func pickLowest(candidates []Query, score func(candidate Query) float64) int {
+	picked := -1
+	var pickedScore float64
+	for i, candidate := range candidates {
+		candidateScore := score(candidate)
+		if picked < 0 ||
+			candidateScore < pickedScore ||
+			(candidateScore == pickedScore && candidate.Transaction.CreatedAt.Before(candidates[picked].Transaction.CreatedAt)) {
+			picked = i
+			pickedScore = candidateScore
+		}
+	}
+	return picked
+}
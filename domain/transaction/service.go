@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"fp-kpl/domain/shared"
 	"time"
 
 	"fmt"
@@ -9,48 +10,95 @@ import (
 
 type (
 	Service interface {
-		GenerateQueueCode(ctx context.Context, transactionID string) (string, error)
+		GenerateQueueCode(ctx context.Context, tx interface{}, transactionID string) (QueueCode, error)
 		CalculateMaxCookingTime(orders []OrderQuery) time.Duration
-		GetOrderDelayStatus(maxCookingTime time.Duration, cookedAt *time.Time, servedAt *time.Time) bool
+		GetKitchenSnapshot(ctx context.Context) (KitchenSnapshot, error)
+		GetThroughput(ctx context.Context) (Throughput, error)
+		EstimateWaitTime(snapshot KitchenSnapshot, target Query) WaitEstimate
+		GetOrderDelayStatus(expectedCookingTime time.Duration, cookedAt *time.Time, servedAt *time.Time) bool
 	}
 
 	service struct {
 		transactionRepository Repository
+		stationCapacity       int
+		clock                 shared.Clock
 	}
 )
 
-func NewService(transactionRepository Repository) Service {
+func NewService(transactionRepository Repository, stationCapacity int, clock shared.Clock) Service {
+	if stationCapacity <= 0 {
+		stationCapacity = DefaultStationCapacity
+	}
 	return &service{
 		transactionRepository: transactionRepository,
+		stationCapacity:       stationCapacity,
+		clock:                 clock,
 	}
 }
 
-func (s *service) GenerateQueueCode(ctx context.Context, transactionID string) (string, error) {
-	latestCode, err := s.transactionRepository.GetLatestQueueCode(ctx, nil, transactionID)
+// GenerateQueueCode issues the next queue code of the current business day.
+func (s *service) GenerateQueueCode(ctx context.Context, tx interface{}, transactionID string) (QueueCode, error) {
+	businessDay := s.clock.Today().String()
+	latestCode, err := s.transactionRepository.GetLatestQueueCode(ctx, tx, transactionID, businessDay)
 	if err != nil {
-		return "", fmt.Errorf("failed to get latest queue code: %w", err)
+		return QueueCode{}, fmt.Errorf("failed to get latest queue code: %w", err)
 	}
 
-	return latestCode, nil
+	queueCode, err := NewQueueCode(latestCode)
+	if err != nil {
+		return QueueCode{}, err
+	}
+	queueCode.BusinessDay = businessDay
+
+	return queueCode, nil
 }
 
 func (s *service) CalculateMaxCookingTime(orders []OrderQuery) time.Duration {
-	maxCookingTime := time.Duration(0)
+	return maxCookingTime(orders)
+}
 
-	for _, orderQuery := range orders {
-		if orderQuery.Menu.CookingTime > maxCookingTime {
-			maxCookingTime = orderQuery.Menu.CookingTime
-		}
+func (s *service) GetKitchenSnapshot(ctx context.Context) (KitchenSnapshot, error) {
+	queue, err := s.transactionRepository.GetKitchenQueue(ctx, nil)
+	if err != nil {
+		return KitchenSnapshot{}, fmt.Errorf("failed to get kitchen queue: %w", err)
 	}
 
-	return maxCookingTime
+	throughput, err := s.GetThroughput(ctx)
+	if err != nil {
+		return KitchenSnapshot{}, err
+	}
+
+	return KitchenSnapshot{
+		Queue:           queue,
+		Throughput:      throughput,
+		StationCapacity: s.stationCapacity,
+		Now:             s.clock.Now(),
+	}, nil
+}
+
+// GetThroughput learns the kitchen throughput without loading the queue, for
+// estimates of transactions that no longer wait on the kitchen.
+func (s *service) GetThroughput(ctx context.Context) (Throughput, error) {
+	history, err := s.transactionRepository.GetCookingHistory(ctx, nil, ThroughputHistorySize)
+	if err != nil {
+		return Throughput{}, fmt.Errorf("failed to get cooking history: %w", err)
+	}
+
+	return LearnThroughput(history), nil
+}
+
+func (s *service) EstimateWaitTime(snapshot KitchenSnapshot, target Query) WaitEstimate {
+	return snapshot.Estimate(target)
 }
 
-func (s *service) GetOrderDelayStatus(maxCookingTime time.Duration, cookedAt *time.Time, servedAt *time.Time) bool {
-	now := time.Now()
+// GetOrderDelayStatus expects expectedCookingTime to come from
+// EstimateWaitTime, so a transaction only counts as delayed once it runs past
+// what the kitchen usually needs rather than the nominal menu cooking time.
+func (s *service) GetOrderDelayStatus(expectedCookingTime time.Duration, cookedAt *time.Time, servedAt *time.Time) bool {
+	now := s.clock.Now()
 	isDelayed := false
 	if cookedAt != nil {
-		expectedFinishTime := cookedAt.Add(maxCookingTime)
+		expectedFinishTime := cookedAt.Add(expectedCookingTime)
 		if servedAt != nil {
 			isDelayed = servedAt.After(expectedFinishTime)
 		} else {
@@ -0,0 +1,197 @@
+package transaction
+
+import (
+	"fmt"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type (
+	// SplitPayment is one share of a split bill. Every share is charged
+	// separately and its ID is the order ID the gateway reports back.
+	SplitPayment struct {
+		ID             identity.ID
+		TransactionID  identity.ID
+		Provider       string
+		Amount         shared.Price
+		OrderIDs       []identity.ID
+		Payment        Payment
+		RefundedAmount shared.Price
+		PaidAt         *time.Time
+		shared.Timestamp
+	}
+
+	// Refund records money given back from one split payment.
+	Refund struct {
+		ID             identity.ID
+		SplitPaymentID identity.ID
+		TransactionID  identity.ID
+		Amount         shared.Price
+		Reason         string
+		CreatedAt      time.Time
+	}
+
+	// SplitLine is an order line with the amount it adds to the bill.
+	SplitLine struct {
+		OrderID  identity.ID
+		Subtotal decimal.Decimal
+	}
+)
+
+// CheckFullChargeAfterSplit decides what a status reported for the charge of
+// the whole bill does once the bill has been split. From then on only the
+// shares count, so it changes nothing, but a payment is money taken twice and
+// is refused with ErrorPaidAfterSplit so it can be refunded.
+func CheckFullChargeAfterSplit(status string) error {
+	if NewPaymentFromSchema("", status).IsPaid() {
+		return fmt.Errorf("%w: %s", ErrorPaidAfterSplit, status)
+	}
+	return nil
+}
+
+// IsLive reports whether the share still counts towards the bill, that is it
+// is either paid or can still be paid.
+func (p SplitPayment) IsLive() bool {
+	return p.Payment.IsPaid() || p.Payment.Status == PaymentStatusPending
+}
+
+// Refundable is what is left of a paid share after earlier refunds.
+func (p SplitPayment) Refundable() decimal.Decimal {
+	if !p.Payment.IsPaid() {
+		return decimal.Zero
+	}
+	return p.Amount.Price.Sub(p.RefundedAmount.Price)
+}
+
+// Refund gives part of a paid share back. A share can be refunded several
+// times as long as the total stays within its amount.
+func (p SplitPayment) Refund(amount decimal.Decimal, reason string) (Refund, error) {
+	if !p.Payment.IsPaid() {
+		return Refund{}, ErrorPaymentNotPaid
+	}
+	if !amount.IsPositive() {
+		return Refund{}, fmt.Errorf("%w: refund must be greater than zero", ErrorInvalidRefund)
+	}
+	if amount.GreaterThan(p.Refundable()) {
+		return Refund{}, fmt.Errorf("%w: %s requested, %s refundable", ErrorInvalidRefund, amount.String(), p.Refundable().String())
+	}
+
+	return Refund{
+		SplitPaymentID: p.ID,
+		TransactionID:  p.TransactionID,
+		Amount:         shared.NewPriceFromSchema(amount),
+		Reason:         reason,
+	}, nil
+}
+
+// PaidAmount sums the shares the gateway has confirmed.
+func PaidAmount(payments []SplitPayment) decimal.Decimal {
+	paid := decimal.Zero
+	for _, payment := range payments {
+		if payment.Payment.IsPaid() {
+			paid = paid.Add(payment.Amount.Price)
+		}
+	}
+	return paid
+}
+
+// IsCovered reports whether the confirmed shares add up to total, which is
+// when a split transaction counts as paid.
+func IsCovered(total shared.Price, payments []SplitPayment) bool {
+	return PaidAmount(payments).GreaterThanOrEqual(total.Price)
+}
+
+// RemainingAmount is the part of total not yet claimed by a live share.
+// Shares that were denied, cancelled or expired free their amount again.
+func RemainingAmount(total shared.Price, payments []SplitPayment) decimal.Decimal {
+	remaining := total.Price
+	for _, payment := range payments {
+		if payment.IsLive() {
+			remaining = remaining.Sub(payment.Amount.Price)
+		}
+	}
+	return remaining
+}
+
+// SplitByAmount checks the requested shares. Every share must be positive and
+// together they may not claim more than what is remaining.
+func SplitByAmount(remaining decimal.Decimal, amounts []decimal.Decimal) error {
+	if len(amounts) == 0 {
+		return fmt.Errorf("%w: no shares", ErrorInvalidSplit)
+	}
+
+	sum := decimal.Zero
+	for _, amount := range amounts {
+		if !amount.IsPositive() {
+			return fmt.Errorf("%w: share must be greater than zero", ErrorInvalidSplit)
+		}
+		sum = sum.Add(amount)
+	}
+	if sum.GreaterThan(remaining) {
+		return fmt.Errorf("%w: shares add up to %s, %s remaining", ErrorSplitExceedsTotal, sum.String(), remaining.String())
+	}
+
+	return nil
+}
+
+// SplitByLines turns groups of order lines into share amounts. The total is
+// spread over the lines in proportion to their subtotal, so the shares still
+// add up to the total when it differs from the sum of the lines. Lines may
+// only be claimed once; taken lists the lines already held by a live share.
+// When the groups claim the last free lines, the final share absorbs the
+// rounding so nothing is left over.
+func SplitByLines(total shared.Price, lines []SplitLine, taken []identity.ID, groups [][]identity.ID, remaining decimal.Decimal) ([]decimal.Decimal, error) {
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("%w: no shares", ErrorInvalidSplit)
+	}
+
+	subtotals := make(map[identity.ID]decimal.Decimal, len(lines))
+	linesTotal := decimal.Zero
+	for _, line := range lines {
+		subtotals[line.OrderID] = line.Subtotal
+		linesTotal = linesTotal.Add(line.Subtotal)
+	}
+	if !linesTotal.IsPositive() {
+		return nil, fmt.Errorf("%w: transaction has no priced lines", ErrorInvalidSplit)
+	}
+
+	claimed := make(map[identity.ID]bool, len(lines))
+	for _, orderID := range taken {
+		claimed[orderID] = true
+	}
+
+	amounts := make([]decimal.Decimal, 0, len(groups))
+	sum := decimal.Zero
+	for _, group := range groups {
+		if len(group) == 0 {
+			return nil, fmt.Errorf("%w: share has no order lines", ErrorInvalidSplit)
+		}
+
+		amount := decimal.Zero
+		for _, orderID := range group {
+			subtotal, ok := subtotals[orderID]
+			if !ok {
+				return nil, fmt.Errorf("%w: order %s is not part of the transaction", ErrorInvalidSplit, orderID.String())
+			}
+			if claimed[orderID] {
+				return nil, fmt.Errorf("%w: order %s is already in a share", ErrorInvalidSplit, orderID.String())
+			}
+			claimed[orderID] = true
+			amount = amount.Add(subtotal.Mul(total.Price).Div(linesTotal).Round(2))
+		}
+		amounts = append(amounts, amount)
+		sum = sum.Add(amount)
+	}
+
+	if len(claimed) == len(lines) {
+		amounts[len(amounts)-1] = amounts[len(amounts)-1].Add(remaining.Sub(sum))
+	}
+
+	if err := SplitByAmount(remaining, amounts); err != nil {
+		return nil, err
+	}
+	return amounts, nil
+}
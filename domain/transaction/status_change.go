@@ -0,0 +1,17 @@
+package transaction
+
+import (
+	"fp-kpl/domain/identity"
+	"time"
+)
+
+// StatusChange records who moved a transaction to an order status: a
+// signed-in user, a device using its API key, or neither for background jobs.
+type StatusChange struct {
+	ID            identity.ID
+	TransactionID identity.ID
+	Status        OrderStatus
+	UserID        identity.ID
+	DeviceID      identity.ID
+	CreatedAt     time.Time
+}
@@ -1,10 +1,16 @@
 package user
 
 import (
+	"fmt"
 	"fp-kpl/domain/identity"
 	"fp-kpl/domain/shared"
+	"time"
 )
 
+// DeletedUserName is what a deleted account is shown as on the records that
+// still point at it.
+const DeletedUserName = "Deleted User"
+
 type User struct {
 	ID          identity.ID
 	Email       string
@@ -12,5 +18,79 @@ type User struct {
 	Name        string
 	PhoneNumber string
 	Role        Role
+	StationID   identity.ID
+	// EmailVerifiedAt is nil until the owner of the email confirms it.
+	EmailVerifiedAt *time.Time
+	// PhoneVerifiedAt is nil until the owner of the phone number confirms it.
+	PhoneVerifiedAt *time.Time
 	shared.Timestamp
 }
+
+func (u User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u User) IsPhoneVerified() bool {
+	return u.PhoneVerifiedAt != nil
+}
+
+// IsVerified reports whether the user confirmed their email and, when they
+// gave one, their phone number.
+func (u User) IsVerified() bool {
+	return u.IsEmailVerified() && (u.PhoneNumber == "" || u.IsPhoneVerified())
+}
+
+// CheckCanOrder rejects an unverified customer when orders require a
+// verified account. Staff accounts are created by a superadmin and are not
+// checked.
+func (u User) CheckCanOrder(requireVerified bool) error {
+	if !requireVerified || u.Role.Name != RoleCustomer || u.IsVerified() {
+		return nil
+	}
+	return ErrorUnverified
+}
+
+// ChangeEmail replaces the email. A new address is unverified until its owner
+// confirms it again.
+func (u *User) ChangeEmail(email string) {
+	if email == u.Email {
+		return
+	}
+	u.Email = email
+	u.EmailVerifiedAt = nil
+}
+
+// ChangePhone replaces the phone number. A new number is unverified until its
+// owner confirms it again.
+func (u *User) ChangePhone(phoneNumber string) {
+	if phoneNumber == u.PhoneNumber {
+		return
+	}
+	u.PhoneNumber = phoneNumber
+	u.PhoneVerifiedAt = nil
+}
+
+// CanDeleteItself reports whether the account may be deleted by its owner.
+// Staff accounts are managed by a superadmin instead.
+func (u User) CanDeleteItself() bool {
+	return u.Role.Name == RoleCustomer
+}
+
+// Anonymise strips the personal data off the user. The ID and role stay, so
+// transactions, payments and loyalty entries that point at the user still add
+// up in reports, and the email is replaced with one that cannot be reached
+// so the address can be registered again.
+func (u User) Anonymise(now time.Time) User {
+	return User{
+		ID:        u.ID,
+		Email:     fmt.Sprintf("deleted-%s@deleted.invalid", u.ID.String()),
+		Name:      DeletedUserName,
+		Role:      u.Role,
+		StationID: u.StationID,
+		Timestamp: shared.Timestamp{
+			CreatedAt: u.CreatedAt,
+			UpdatedAt: now,
+			DeletedAt: &now,
+		},
+	}
+}
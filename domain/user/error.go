@@ -12,6 +12,10 @@ var (
 	ErrorUserNotFound       = errors.New("user not found")
 	ErrorEmailNotFound      = errors.New("email not found")
 	ErrorDeleteUser         = errors.New("failed to delete user")
+	ErrorInvalidPassword    = errors.New("invalid password")
+	ErrorPasswordRequired   = errors.New("current password is required to change the email")
+	ErrorDeletionNotAllowed = errors.New("staff accounts can only be deleted by a superadmin")
+	ErrorUnverified         = errors.New("verify your email and phone number before placing an order")
 	ErrorTokenInvalid       = errors.New("token invalid")
 	ErrorTokenExpired       = errors.New("token expired")
 )
@@ -10,5 +10,13 @@ type (
 		GetUserByID(ctx context.Context, tx interface{}, id string) (User, error)
 		GetUserByEmail(ctx context.Context, tx interface{}, email string) (User, error)
 		CheckEmail(ctx context.Context, tx interface{}, email string) (User, bool, error)
+		// UpdateUser saves the profile: name, email, its verification and
+		// phone number.
+		UpdateUser(ctx context.Context, tx interface{}, userEntity User) (User, error)
+		// DeleteUser overwrites the user with its anonymised copy and soft
+		// deletes it, then clears the personal data linked to it: feedback
+		// comments, carts and favourites. Transactions, payments, refunds
+		// and loyalty entries are kept for reporting.
+		DeleteUser(ctx context.Context, tx interface{}, anonymised User) error
 	}
 )
@@ -7,6 +7,7 @@ const (
 	RoleCustomer   = "customer"
 	RoleKitchen    = "kitchen"
 	RoleWaiter     = "waiter"
+	RoleCashier    = "cashier"
 )
 
 var (
@@ -15,6 +16,7 @@ var (
 		{RoleCustomer},
 		{RoleKitchen},
 		{RoleWaiter},
+		{RoleCashier},
 	}
 )
 
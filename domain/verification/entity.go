@@ -0,0 +1,128 @@
+package verification
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/user"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	ChannelEmail = "email"
+	ChannelPhone = "phone"
+
+	// CodeLength is the number of digits in a code.
+	CodeLength = 6
+)
+
+var Channels = []string{ChannelEmail, ChannelPhone}
+
+type (
+	// Challenge is one code sent to an email or a phone number. Only the
+	// latest challenge of a channel can be used, and only its hash is kept.
+	Challenge struct {
+		ID          identity.ID
+		UserID      identity.ID
+		Channel     string
+		Destination string
+		CodeHash    string
+		Attempts    int
+		ExpiresAt   time.Time
+		VerifiedAt  *time.Time
+		// ReplacesID is the challenge that was the latest when this one was
+		// sent. Two sends racing past the same one cannot both be stored.
+		ReplacesID identity.ID
+		CreatedAt  time.Time
+	}
+
+	// Message is what the notification port delivers. Link is only set for
+	// emails, which can be confirmed by opening it instead of typing the code.
+	Message struct {
+		Channel     string
+		Destination string
+		Code        string
+		Link        string
+		ExpiresAt   time.Time
+	}
+)
+
+func NewChannel(channel string) (string, error) {
+	for _, validChannel := range Channels {
+		if validChannel == channel {
+			return channel, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrorInvalidChannel, channel)
+}
+
+// NewCode returns a random numeric code of CodeLength digits.
+func NewCode() (string, error) {
+	upper := big.NewInt(1)
+	for i := 0; i < CodeLength; i++ {
+		upper.Mul(upper, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, upper)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", CodeLength, n), nil
+}
+
+func HashCode(code string) string {
+	digest := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(digest[:])
+}
+
+// Destination is where the user receives codes of the channel.
+func Destination(userEntity user.User, channel string) string {
+	if channel == ChannelPhone {
+		return userEntity.PhoneNumber
+	}
+	return userEntity.Email
+}
+
+// IsVerified reports whether the user already confirmed the channel.
+func IsVerified(userEntity user.User, channel string) bool {
+	if channel == ChannelPhone {
+		return userEntity.IsPhoneVerified()
+	}
+	return userEntity.IsEmailVerified()
+}
+
+// CheckUsable rejects a challenge that was used, lapsed, ran out of attempts
+// or was sent to an address the user no longer has.
+func (c Challenge) CheckUsable(destination string, now time.Time, maxAttempts int) error {
+	if c.VerifiedAt != nil {
+		return ErrorAlreadyVerified
+	}
+	if !now.Before(c.ExpiresAt) || c.Destination != destination {
+		return ErrorCodeExpired
+	}
+	if c.Attempts >= maxAttempts {
+		return ErrorTooManyAttempts
+	}
+	return nil
+}
+
+func (c Challenge) Matches(code string) bool {
+	return subtle.ConstantTimeCompare([]byte(c.CodeHash), []byte(HashCode(strings.TrimSpace(code)))) == 1
+}
+
+// MaskDestination hides most of an email or phone number so it can be shown
+// back to the user.
+func MaskDestination(destination string) string {
+	if at := strings.Index(destination, "@"); at > 0 {
+		return destination[:1] + strings.Repeat("*", at-1) + destination[at:]
+	}
+	if len(destination) <= 4 {
+		return destination
+	}
+	return strings.Repeat("*", len(destination)-4) + destination[len(destination)-4:]
+}
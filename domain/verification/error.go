@@ -0,0 +1,19 @@
+package verification
+
+import "errors"
+
+var (
+	ErrorInvalidPolicy     = errors.New("invalid verification policy")
+	ErrorInvalidChannel    = errors.New("verification channel must be email or phone")
+	ErrorNoDestination     = errors.New("there is no address to send the code to")
+	ErrorAlreadyVerified   = errors.New("already verified")
+	ErrorChallengeNotFound = errors.New("no verification code was sent")
+	ErrorCodeExpired       = errors.New("verification code has expired, request a new one")
+	ErrorInvalidCode       = errors.New("invalid verification code")
+	ErrorTooManyAttempts   = errors.New("too many attempts, request a new code")
+	ErrorResendTooSoon     = errors.New("a code was sent recently, try again later")
+	ErrorTooManyCodes      = errors.New("too many codes requested, try again later")
+	ErrorSendCode          = errors.New("failed to send verification code")
+	ErrorCreateChallenge   = errors.New("failed to create verification code")
+	ErrorGetChallenge      = errors.New("failed to get verification code")
+)
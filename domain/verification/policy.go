@@ -0,0 +1,73 @@
+package verification
+
+import (
+	"fmt"
+	"fp-kpl/domain/identity"
+	"time"
+)
+
+const (
+	DefaultCodeTTL         = 15 * time.Minute
+	DefaultResendCooldown  = time.Minute
+	DefaultMaxAttempts     = 5
+	DefaultMaxSendsPerHour = 5
+)
+
+// Policy is how long a code lasts, how often one can be sent and how many
+// guesses it allows.
+type Policy struct {
+	CodeTTL         time.Duration
+	ResendCooldown  time.Duration
+	MaxAttempts     int
+	MaxSendsPerHour int
+}
+
+func NewPolicy(codeTTL time.Duration, resendCooldown time.Duration, maxAttempts int, maxSendsPerHour int) (Policy, error) {
+	if codeTTL <= 0 {
+		return Policy{}, fmt.Errorf("%w: code ttl %s", ErrorInvalidPolicy, codeTTL)
+	}
+	if resendCooldown < 0 {
+		return Policy{}, fmt.Errorf("%w: resend cooldown %s", ErrorInvalidPolicy, resendCooldown)
+	}
+	if maxAttempts <= 0 {
+		return Policy{}, fmt.Errorf("%w: max attempts %d", ErrorInvalidPolicy, maxAttempts)
+	}
+	if maxSendsPerHour <= 0 {
+		return Policy{}, fmt.Errorf("%w: max sends per hour %d", ErrorInvalidPolicy, maxSendsPerHour)
+	}
+
+	return Policy{
+		CodeTTL:         codeTTL,
+		ResendCooldown:  resendCooldown,
+		MaxAttempts:     maxAttempts,
+		MaxSendsPerHour: maxSendsPerHour,
+	}, nil
+}
+
+// CheckResend throttles sending: the latest code must be older than the
+// cooldown, and no more than MaxSendsPerHour codes go out in an hour.
+func (p Policy) CheckResend(latest *Challenge, sentLastHour int64, now time.Time) error {
+	if latest != nil && now.Before(p.ResendAfter(*latest)) {
+		return ErrorResendTooSoon
+	}
+	if sentLastHour >= int64(p.MaxSendsPerHour) {
+		return ErrorTooManyCodes
+	}
+	return nil
+}
+
+// ResendAfter is when another code may be sent after the challenge.
+func (p Policy) ResendAfter(challenge Challenge) time.Time {
+	return challenge.CreatedAt.Add(p.ResendCooldown)
+}
+
+func (p Policy) NewChallenge(userID identity.ID, channel string, destination string, code string, now time.Time) Challenge {
+	return Challenge{
+		UserID:      userID,
+		Channel:     channel,
+		Destination: destination,
+		CodeHash:    HashCode(code),
+		ExpiresAt:   now.Add(p.CodeTTL),
+		CreatedAt:   now,
+	}
+}
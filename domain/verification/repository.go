@@ -0,0 +1,23 @@
+package verification
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// CreateChallenge returns ErrorResendTooSoon when another challenge
+	// already replaced the one this challenge replaces.
+	CreateChallenge(ctx context.Context, tx interface{}, challenge Challenge) (Challenge, error)
+	GetChallengeByID(ctx context.Context, tx interface{}, id string) (Challenge, error)
+	// GetLatestChallenge returns the last challenge of the channel sent to
+	// the user, or ErrorChallengeNotFound.
+	GetLatestChallenge(ctx context.Context, tx interface{}, userID string, channel string) (Challenge, error)
+	CountChallengesSince(ctx context.Context, tx interface{}, userID string, channel string, since time.Time) (int64, error)
+	// RecordAttempt counts a guess, or returns ErrorTooManyAttempts if the
+	// challenge has none left. Concurrent guesses cannot exceed the limit.
+	RecordAttempt(ctx context.Context, tx interface{}, id string, maxAttempts int) error
+	// MarkVerified uses up the challenge and marks the user's email or phone
+	// number verified, as long as it is still the one the code was sent to.
+	MarkVerified(ctx context.Context, tx interface{}, challenge Challenge, at time.Time) error
+}
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/transaction"
+	"log"
+	"sync"
+)
+
+const DefaultEventHistorySize = 100
+
+type (
+	// LogPublisher writes every event to the log and keeps the most recent ones
+	// in memory.
+	LogPublisher interface {
+		port.EventPublisherPort
+		Events() []transaction.Event
+	}
+
+	logPublisher struct {
+		logger      *log.Logger
+		historySize int
+		mu          sync.Mutex
+		events      []transaction.Event
+	}
+)
+
+func NewLogPublisher(logger *log.Logger, historySize int) LogPublisher {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if historySize <= 0 {
+		historySize = DefaultEventHistorySize
+	}
+	return &logPublisher{
+		logger:      logger,
+		historySize: historySize,
+	}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event transaction.Event) error {
+	p.logger.Printf("[EVENT] %s: transaction %s %v", event.Type, event.TransactionID.String(), event.Data)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	if len(p.events) > p.historySize {
+		p.events = p.events[len(p.events)-p.historySize:]
+	}
+	return nil
+}
+
+func (p *logPublisher) Events() []transaction.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]transaction.Event, len(p.events))
+	copy(events, p.events)
+	return events
+}
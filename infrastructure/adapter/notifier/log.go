@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/sla"
+	"log"
+	"sync"
+	"time"
+)
+
+const DefaultAlertHistorySize = 100
+
+type (
+	// LogNotifier writes every escalation to the log and keeps the most recent
+	// ones in memory.
+	LogNotifier interface {
+		port.NotifierPort
+		Alerts() []sla.Breach
+	}
+
+	logNotifier struct {
+		logger      *log.Logger
+		historySize int
+		mu          sync.Mutex
+		alerts      []sla.Breach
+	}
+)
+
+func NewLogNotifier(logger *log.Logger, historySize int) LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if historySize <= 0 {
+		historySize = DefaultAlertHistorySize
+	}
+	return &logNotifier{
+		logger:      logger,
+		historySize: historySize,
+	}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, breach sla.Breach) error {
+	n.logger.Printf("[SLA] %s: transaction %s (%s) overdue by %s",
+		breach.Type.Type, breach.TransactionID.String(), breach.QueueCode, breach.Overdue().Round(time.Second))
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.alerts = append(n.alerts, breach)
+	if len(n.alerts) > n.historySize {
+		n.alerts = n.alerts[len(n.alerts)-n.historySize:]
+	}
+	return nil
+}
+
+func (n *logNotifier) Alerts() []sla.Breach {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	alerts := make([]sla.Breach, len(n.alerts))
+	copy(alerts, n.alerts)
+	return alerts
+}
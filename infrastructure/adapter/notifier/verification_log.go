@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/verification"
+	"log"
+	"sync"
+	"time"
+)
+
+const DefaultVerificationHistorySize = 100
+
+type (
+	// LogVerificationNotifier writes every verification code to the log
+	// instead of sending it, so accounts can be verified locally without an
+	// email or SMS provider. It keeps the most recent messages in memory.
+	LogVerificationNotifier interface {
+		port.VerificationNotifierPort
+		Messages() []verification.Message
+	}
+
+	logVerificationNotifier struct {
+		logger      *log.Logger
+		historySize int
+		mu          sync.Mutex
+		messages    []verification.Message
+	}
+)
+
+func NewLogVerificationNotifier(logger *log.Logger, historySize int) LogVerificationNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if historySize <= 0 {
+		historySize = DefaultVerificationHistorySize
+	}
+	return &logVerificationNotifier{
+		logger:      logger,
+		historySize: historySize,
+	}
+}
+
+func (n *logVerificationNotifier) SendVerification(ctx context.Context, message verification.Message) error {
+	if message.Link != "" {
+		n.logger.Printf("[VERIFICATION] %s to %s: code %s, link %s, expires %s",
+			message.Channel, message.Destination, message.Code, message.Link, message.ExpiresAt.Format(time.RFC3339))
+	} else {
+		n.logger.Printf("[VERIFICATION] %s to %s: code %s, expires %s",
+			message.Channel, message.Destination, message.Code, message.ExpiresAt.Format(time.RFC3339))
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, message)
+	if len(n.messages) > n.historySize {
+		n.messages = n.messages[len(n.messages)-n.historySize:]
+	}
+	return nil
+}
+
+func (n *logVerificationNotifier) Messages() []verification.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	messages := make([]verification.Message, len(n.messages))
+	copy(messages, n.messages)
+	return messages
+}
@@ -0,0 +1,72 @@
+package payment_gateway
+
+import (
+	"context"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/validation"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// counterAdapter handles transactions paid at the cashier. Nothing is sent to
+// a gateway: the transaction stays pending until a cashier confirms it, and
+// that confirmation is applied through HookPayment like any webhook.
+type counterAdapter struct {
+	db                       *gorm.DB
+	transactionDomainService transaction.Service
+	clock                    shared.Clock
+}
+
+func NewCounterAdapter(db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock) port.PaymentGatewayPort {
+	return &counterAdapter{
+		db:                       db,
+		transactionDomainService: transactionDomainService,
+		clock:                    clock,
+	}
+}
+
+func (c *counterAdapter) Provider() string {
+	return transaction.PaymentProviderCounter
+}
+
+func (c *counterAdapter) ProcessPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
+
+// ProcessSplitPayment is not supported: the cashier settles the whole
+// transaction in one go.
+func (c *counterAdapter) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, transaction.ErrorSplitNotSupported
+}
+
+func (c *counterAdapter) HookPayment(ctx context.Context, tx interface{}, transactionId uuid.UUID, datas map[string]interface{}) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = c.db
+	}
+
+	return applyPaymentNotification(ctx, db, c.transactionDomainService, c.clock, c.Provider(), transactionId, datas)
+}
+
+func (c *counterAdapter) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, port.ErrorPaymentNotFound
+}
+
+// CancelPayment does nothing: a counter order has no charge outside the shop.
+func (c *counterAdapter) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+
+// ParseNotification always fails: counter payments are confirmed by an
+// authenticated cashier, never by a webhook.
+func (c *counterAdapter) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return nil, port.ErrorInvalidSignature
+}
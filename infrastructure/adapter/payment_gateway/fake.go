@@ -0,0 +1,234 @@
+package payment_gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/validation"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultFakeServerKey = "fake-server-key"
+
+	fakeTokenPrefix = "fake-token-"
+)
+
+// fakeActions maps the buttons of the fake payment page to the Midtrans status
+// and status code a real notification would carry.
+var fakeActions = map[string]struct {
+	status     string
+	statusCode string
+}{
+	port.FakeActionPay:    {transaction.PaymentStatusSettlement, "200"},
+	port.FakeActionFail:   {transaction.PaymentStatusDeny, "202"},
+	port.FakeActionExpire: {transaction.PaymentStatusExpire, "407"},
+}
+
+// fakeAdapter derives tokens and payment links from the order ID, and
+// completing a payment posts a Midtrans-style notification to the webhook URL.
+type fakeAdapter struct {
+	db                       *gorm.DB
+	transactionDomainService transaction.Service
+	clock                    shared.Clock
+	baseURL                  string
+	webhookURL               string
+	serverKey                string
+	httpClient               *http.Client
+	mu                       sync.Mutex
+	payments                 map[string]port.FakePayment
+}
+
+func NewFakeAdapter(db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock, baseURL string, webhookURL string, serverKey string) port.FakePaymentGatewayPort {
+	if serverKey == "" {
+		serverKey = DefaultFakeServerKey
+	}
+	return &fakeAdapter{
+		db:                       db,
+		transactionDomainService: transactionDomainService,
+		clock:                    clock,
+		baseURL:                  strings.TrimRight(baseURL, "/"),
+		webhookURL:               webhookURL,
+		serverKey:                serverKey,
+		httpClient:               &http.Client{Timeout: 10 * time.Second},
+		payments:                 make(map[string]port.FakePayment),
+	}
+}
+
+func (f *fakeAdapter) ProcessPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (port.ProcessPaymentResponse, error) {
+	return f.createPayment(transactionEntity.ID.String(), transactionEntity.TotalPrice.Price), nil
+}
+
+func (f *fakeAdapter) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return f.createPayment(splitPayment.ID.String(), splitPayment.Amount.Price), nil
+}
+
+func (f *fakeAdapter) createPayment(orderID string, grossAmount decimal.Decimal) port.ProcessPaymentResponse {
+	token := fakeTokenPrefix + orderID
+
+	f.mu.Lock()
+	f.payments[token] = port.FakePayment{
+		Token:       token,
+		OrderID:     orderID,
+		Status:      transaction.PaymentStatusPending,
+		GrossAmount: grossAmount,
+	}
+	f.mu.Unlock()
+
+	return port.ProcessPaymentResponse{
+		Token:       token,
+		PaymentLink: f.baseURL + port.FakePaymentPath + "/" + token,
+	}
+}
+
+func (f *fakeAdapter) HookPayment(ctx context.Context, tx interface{}, transactionId uuid.UUID, datas map[string]interface{}) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = f.db
+	}
+
+	return applyPaymentNotification(ctx, db, f.transactionDomainService, f.clock, f.Provider(), transactionId, datas)
+}
+
+func (f *fakeAdapter) Provider() string {
+	return transaction.PaymentProviderFake
+}
+
+func (f *fakeAdapter) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	if err := verifyNotificationSignature(body, f.serverKey); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (f *fakeAdapter) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	payment, err := f.GetPayment(fakeTokenPrefix + transactionId.String())
+	if err != nil {
+		return port.PaymentStatusResponse{}, port.ErrorPaymentNotFound
+	}
+
+	return port.PaymentStatusResponse{
+		OrderID:       payment.OrderID,
+		TransactionID: payment.TransactionID,
+		Status:        payment.Status,
+		GrossAmount:   payment.GrossAmount,
+	}, nil
+}
+
+// CancelPayment expires the payment without sending a notification, as the
+// caller already knows.
+func (f *fakeAdapter) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	token := fakeTokenPrefix + transactionId.String()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[token]
+	if !ok {
+		return nil
+	}
+
+	cancellable, err := isCancellable(port.PaymentStatusResponse{Status: payment.Status}, nil)
+	if err != nil || !cancellable {
+		return err
+	}
+
+	payment.Status = transaction.PaymentStatusExpire
+	f.payments[token] = payment
+	return nil
+}
+
+func (f *fakeAdapter) GetPayment(token string) (port.FakePayment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[token]
+	if !ok {
+		return port.FakePayment{}, port.ErrorFakePaymentNotFound
+	}
+	return payment, nil
+}
+
+// CompletePayment settles, denies or expires the payment behind token and
+// delivers the matching notification to the webhook.
+func (f *fakeAdapter) CompletePayment(ctx context.Context, token string, action string) (port.FakePayment, error) {
+	result, ok := fakeActions[action]
+	if !ok {
+		return port.FakePayment{}, fmt.Errorf("%w: %s", port.ErrorFakeInvalidAction, action)
+	}
+
+	f.mu.Lock()
+	payment, ok := f.payments[token]
+	if !ok {
+		f.mu.Unlock()
+		return port.FakePayment{}, port.ErrorFakePaymentNotFound
+	}
+	if payment.Status != transaction.PaymentStatusPending {
+		f.mu.Unlock()
+		return port.FakePayment{}, fmt.Errorf("%w: %s", port.ErrorFakePaymentNotPending, payment.Status)
+	}
+	payment.Status = result.status
+	payment.TransactionID = "fake-" + payment.OrderID
+	f.payments[token] = payment
+	f.mu.Unlock()
+
+	grossAmount := payment.GrossAmount.StringFixed(2)
+	notification := map[string]interface{}{
+		"transaction_time":   f.clock.Now().Format("2006-01-02 15:04:05"),
+		"transaction_status": payment.Status,
+		"transaction_id":     payment.TransactionID,
+		"status_code":        result.statusCode,
+		"signature_key":      notificationSignature(payment.OrderID, result.statusCode, grossAmount, f.serverKey),
+		"payment_type":       "fake",
+		"order_id":           payment.OrderID,
+		"gross_amount":       grossAmount,
+		"currency":           "IDR",
+	}
+
+	if err := f.sendNotification(ctx, notification); err != nil {
+		return payment, err
+	}
+
+	return payment, nil
+}
+
+func (f *fakeAdapter) sendNotification(ctx context.Context, notification map[string]interface{}) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
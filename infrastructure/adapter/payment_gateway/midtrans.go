@@ -3,29 +3,60 @@ package payment_gateway
 import (
 	"context"
 	"fmt"
-	"fp-kpl/domain/identity"
 	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
 	"fp-kpl/domain/transaction"
 	"fp-kpl/infrastructure/database/schema"
 	"fp-kpl/infrastructure/database/validation"
-	"os"
+	"math"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
-type midtransAdapter struct {
-	db                       *gorm.DB
-	transactionDomainService transaction.Service
-}
+type (
+	MidtransConfig struct {
+		ServerKey  string
+		Production bool
+	}
+
+	midtransAdapter struct {
+		db                       *gorm.DB
+		transactionDomainService transaction.Service
+		clock                    shared.Clock
+		paymentTimeout           time.Duration
+		config                   MidtransConfig
+	}
+)
 
-func NewMidtransAdapter(db *gorm.DB, transactionDomainService transaction.Service) port.PaymentGatewayPort {
+func NewMidtransAdapter(db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock, paymentTimeout time.Duration, config MidtransConfig) port.PaymentGatewayPort {
+	if paymentTimeout <= 0 {
+		paymentTimeout = transaction.DefaultPaymentTimeout
+	}
 	return &midtransAdapter{
 		db:                       db,
 		transactionDomainService: transactionDomainService,
+		clock:                    clock,
+		paymentTimeout:           paymentTimeout,
+		config:                   config,
+	}
+}
+
+func (m midtransAdapter) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m midtransAdapter) environment() midtrans.EnvironmentType {
+	if m.config.Production {
+		return midtrans.Production
 	}
+	return midtrans.Sandbox
 }
 
 func (m midtransAdapter) ProcessPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (port.ProcessPaymentResponse, error) {
@@ -50,34 +81,100 @@ func (m midtransAdapter) ProcessPayment(ctx context.Context, tx interface{}, tra
 		return port.ProcessPaymentResponse{}, err
 	}
 
-	var s = snap.Client{}
-	s.New(os.Getenv("MIDTRANS_SERVER_KEY"), midtrans.Sandbox)
-
 	var itemDetails []midtrans.ItemDetails
 	for _, orderSchema := range transactionSchema.Orders {
 		menuSchema := orderSchema.Menu
 		itemDetails = append(itemDetails, midtrans.ItemDetails{
 			ID:    menuSchema.ID.String(),
 			Name:  menuSchema.Name,
-			Price: menuSchema.Price.IntPart(),
+			Price: schema.OrderUnitPrice(orderSchema).IntPart(),
 			Qty:   int32(orderSchema.Quantity),
 		})
 	}
+	itemDetails = append(itemDetails, pricingItemDetails(transactionSchema)...)
+
+	return m.createSnapTransaction(transactionSchema, transactionSchema.ID.String(), transactionSchema.TotalPrice.IntPart(), &itemDetails)
+}
+
+// pricingItemDetails sends the discount, redeemed loyalty points, packaging
+// fee, service charge, tax and rounding as item lines of their own, since
+// Midtrans rejects a gross amount that differs from the sum of the items. The
+// discounts go in as negative prices.
+func pricingItemDetails(transactionSchema schema.Transaction) []midtrans.ItemDetails {
+	lines := []struct {
+		id     string
+		name   string
+		amount decimal.Decimal
+	}{
+		{"discount", "Discount", transactionSchema.Discount.Neg()},
+		{"loyalty-points", "Loyalty Points", transactionSchema.LoyaltyDiscount.Neg()},
+		{"packaging-fee", "Packaging Fee", transactionSchema.PackagingFee},
+		{"service-charge", "Service Charge", transactionSchema.ServiceCharge},
+		{"tax", "PB1 Tax", transactionSchema.Tax},
+		{"rounding", "Rounding", transactionSchema.Rounding},
+	}
+
+	var itemDetails []midtrans.ItemDetails
+	for _, line := range lines {
+		if line.amount.IsZero() {
+			continue
+		}
+		itemDetails = append(itemDetails, midtrans.ItemDetails{
+			ID:    line.id,
+			Name:  line.name,
+			Price: line.amount.IntPart(),
+			Qty:   1,
+		})
+	}
+	return itemDetails
+}
+
+// ProcessSplitPayment opens a Snap transaction for one share. Midtrans
+// requires the items to add up to the gross amount, so the share is sent
+// without item details.
+func (m midtransAdapter) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return port.ProcessPaymentResponse{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = m.db
+	}
+
+	var transactionSchema schema.Transaction
+	err = db.WithContext(ctx).
+		Preload("User").
+		First(&transactionSchema, "id = ?", transactionEntity.ID.String()).Error
+	if err != nil {
+		return port.ProcessPaymentResponse{}, err
+	}
+
+	return m.createSnapTransaction(transactionSchema, splitPayment.ID.String(), splitPayment.Amount.Price.IntPart(), nil)
+}
+
+// createSnapTransaction charges grossAmount under orderID. The expiry always
+// starts at the transaction's creation so every charge for it lapses together.
+func (m midtransAdapter) createSnapTransaction(transactionSchema schema.Transaction, orderID string, grossAmount int64, itemDetails *[]midtrans.ItemDetails) (port.ProcessPaymentResponse, error) {
+	var s = snap.Client{}
+	s.New(m.config.ServerKey, m.environment())
 
 	req := &snap.Request{
 		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  transactionSchema.ID.String(),
-			GrossAmt: transactionSchema.TotalPrice.IntPart(),
+			OrderID:  orderID,
+			GrossAmt: grossAmount,
 		},
 		CreditCard: &snap.CreditCardDetails{
 			Secure: true,
 		},
-		CustomerDetail: &midtrans.CustomerDetails{
-			FName: transactionSchema.User.Name,
-			Email: transactionSchema.User.Email,
-			Phone: transactionSchema.User.PhoneNumber,
+		CustomerDetail: customerDetails(transactionSchema),
+		Items:          itemDetails,
+		Expiry: &snap.ExpiryDetails{
+			StartTime: transactionSchema.CreatedAt.Format("2006-01-02 15:04:05 -0700"),
+			Unit:      "minute",
+			Duration:  int64(math.Ceil(m.paymentTimeout.Minutes())),
 		},
-		Items: &itemDetails,
 	}
 
 	snapResp, snapErr := s.CreateTransaction(req)
@@ -90,6 +187,27 @@ func (m midtransAdapter) ProcessPayment(ctx context.Context, tx interface{}, tra
 	}, nil
 }
 
+// customerDetails falls back to the pickup contact a guest left, if any, when
+// the transaction has no user. A user's email and phone number are only sent
+// once they have been verified.
+func customerDetails(transactionSchema schema.Transaction) *midtrans.CustomerDetails {
+	if transactionSchema.User == nil {
+		return &midtrans.CustomerDetails{
+			FName: transactionSchema.GuestName,
+			Phone: transactionSchema.GuestPhone,
+		}
+	}
+
+	details := &midtrans.CustomerDetails{FName: transactionSchema.User.Name}
+	if transactionSchema.User.EmailVerifiedAt != nil {
+		details.Email = transactionSchema.User.Email
+	}
+	if transactionSchema.User.PhoneVerifiedAt != nil {
+		details.Phone = transactionSchema.User.PhoneNumber
+	}
+	return details
+}
+
 func (m midtransAdapter) HookPayment(ctx context.Context, tx interface{}, transactionId uuid.UUID, datas map[string]interface{}) error {
 	validatedTransaction, err := validation.ValidateTransaction(tx)
 	if err != nil {
@@ -101,52 +219,58 @@ func (m midtransAdapter) HookPayment(ctx context.Context, tx interface{}, transa
 		db = m.db
 	}
 
-	identityTransactionId := identity.NewIDFromSchema(transactionId)
+	return applyPaymentNotification(ctx, db, m.transactionDomainService, m.clock, m.Provider(), transactionId, datas)
+}
 
-	var transactionData schema.Transaction
-	err = db.WithContext(ctx).
-		Where("id = ?", identityTransactionId.String()).
-		First(&transactionData).Error
-	if err != nil {
+// CancelPayment expires the charge at Midtrans. A Snap charge the customer
+// has not picked a payment method for is unknown to Midtrans yet, so there
+// is nothing to expire.
+func (m midtransAdapter) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	cancellable, err := isCancellable(m.CheckPaymentStatus(ctx, transactionId))
+	if err != nil || !cancellable {
 		return err
 	}
 
-	status, ok := datas["transaction_status"].(string)
-	if !ok {
-		return fmt.Errorf("transaction_status is required in datas")
+	var c = coreapi.Client{}
+	c.New(m.config.ServerKey, m.environment())
+
+	if _, expireErr := c.ExpireTransaction(transactionId.String()); expireErr != nil {
+		return expireErr
 	}
+	return nil
+}
 
-	if !isValidPaymentStatus(status) {
-		return fmt.Errorf("invalid payment status: %s", status)
+func (m midtransAdapter) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	if err := verifyNotificationSignature(body, m.config.ServerKey); err != nil {
+		return nil, err
 	}
+	return body, nil
+}
 
-	transactionData.PaymentStatus = status
-	transactionData.PaymentCode = datas["transaction_id"].(string)
+func (m midtransAdapter) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	var c = coreapi.Client{}
+	c.New(m.config.ServerKey, m.environment())
 
-	queueCode, err := m.transactionDomainService.GenerateQueueCode(ctx, transactionData.ID.String())
-	if err != nil {
-		return fmt.Errorf("failed to generate queue code: %w", err)
+	statusResp, statusErr := c.CheckTransaction(transactionId.String())
+	if statusErr != nil {
+		if statusErr.StatusCode == http.StatusNotFound {
+			return port.PaymentStatusResponse{}, port.ErrorPaymentNotFound
+		}
+		return port.PaymentStatusResponse{}, statusErr
+	}
+	if statusResp.StatusCode == "404" {
+		return port.PaymentStatusResponse{}, port.ErrorPaymentNotFound
 	}
 
-	err = db.WithContext(ctx).
-		Model(&transactionData).
-		Updates(map[string]interface{}{
-			"payment_status": transactionData.PaymentStatus,
-			"payment_code":   transactionData.PaymentCode,
-			"queue_code":     queueCode,
-		}).Error
+	grossAmount, err := decimal.NewFromString(statusResp.GrossAmount)
 	if err != nil {
-		return err
+		return port.PaymentStatusResponse{}, fmt.Errorf("invalid gross amount %q: %w", statusResp.GrossAmount, err)
 	}
 
-	return nil
-}
-
-func isValidPaymentStatus(status string) bool {
-	for _, paymentStatus := range transaction.PaymentStatuses {
-		if paymentStatus == status {
-			return true
-		}
-	}
-	return false
+	return port.PaymentStatusResponse{
+		OrderID:       statusResp.OrderID,
+		TransactionID: statusResp.TransactionID,
+		Status:        statusResp.TransactionStatus,
+		GrossAmount:   grossAmount,
+	}, nil
 }
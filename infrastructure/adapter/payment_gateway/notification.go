@@ -0,0 +1,317 @@
+package payment_gateway
+
+import (
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	queueCodeSavePoint   = "queue_code"
+	maxQueueCodeAttempts = 3
+)
+
+// applyPaymentNotification writes a normalised notification to the
+// transaction. It is shared by every adapter; provider must be the one the
+// transaction was created with.
+func applyPaymentNotification(ctx context.Context, db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock, provider string, transactionId uuid.UUID, datas map[string]interface{}) error {
+	identityTransactionId := identity.NewIDFromSchema(transactionId)
+
+	var transactionData schema.Transaction
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", identityTransactionId.String()).
+		First(&transactionData).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Shares of a split bill are charged under their own ID.
+		return applySplitPaymentNotification(ctx, db, transactionDomainService, clock, provider, transactionId, datas)
+	}
+	if err != nil {
+		return err
+	}
+
+	if transactionData.PaymentProvider != "" && transactionData.PaymentProvider != provider {
+		return fmt.Errorf("%w: transaction uses %s, got %s", port.ErrorPaymentProviderMismatch, transactionData.PaymentProvider, provider)
+	}
+
+	status, err := notificationStatus(datas)
+	if err != nil {
+		return err
+	}
+
+	currentPayment := transaction.NewPaymentFromSchema(transactionData.PaymentCode, transactionData.PaymentStatus)
+	if currentPayment.Status == status {
+		// Midtrans retries notifications; a repeated status changes nothing.
+		return nil
+	}
+	if !currentPayment.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", transaction.ErrorPaymentAlreadyFinal, currentPayment.Status, status)
+	}
+
+	// The original charge is cancelled when the bill is split, but a Snap
+	// page the customer never opened cannot be, so it may still be paid.
+	var splitPaymentCount int64
+	err = db.WithContext(ctx).
+		Model(&schema.SplitPayment{}).
+		Where("transaction_id = ?", transactionData.ID).
+		Count(&splitPaymentCount).Error
+	if err != nil {
+		return err
+	}
+	if splitPaymentCount > 0 {
+		return transaction.CheckFullChargeAfterSplit(status)
+	}
+
+	transactionData.PaymentStatus = status
+	transactionData.PaymentCode = datas["transaction_id"].(string)
+
+	updates := map[string]interface{}{
+		"payment_status": transactionData.PaymentStatus,
+		"payment_code":   transactionData.PaymentCode,
+	}
+	if transaction.NewPaymentFromSchema(transactionData.PaymentCode, status).IsPaid() {
+		if transactionData.PaidAt == nil {
+			updates["paid_at"] = clock.Now()
+		}
+		if err = issueQueueCode(ctx, db, transactionDomainService, transactionData); err != nil {
+			return err
+		}
+	}
+
+	err = db.WithContext(ctx).
+		Model(&transactionData).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applySplitPaymentNotification writes a notification for one share of a
+// split bill. Once the paid shares cover the total, the transaction itself is
+// settled and gets its queue code, exactly as a single payment would. The
+// transaction row stays locked from before the share is updated until the
+// coverage is checked, so shares settled at the same time see each other.
+func applySplitPaymentNotification(ctx context.Context, db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock, provider string, splitPaymentId uuid.UUID, datas map[string]interface{}) error {
+	var splitPaymentData schema.SplitPayment
+	err := db.WithContext(ctx).
+		Select("transaction_id").
+		Where("id = ?", splitPaymentId.String()).
+		First(&splitPaymentData).Error
+	if err != nil {
+		return err
+	}
+
+	var transactionData schema.Transaction
+	err = db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", splitPaymentData.TransactionID).
+		First(&transactionData).Error
+	if err != nil {
+		return err
+	}
+
+	// Read under the lock, as a retried notification for the same share may
+	// have just changed it.
+	err = db.WithContext(ctx).
+		Where("id = ?", splitPaymentId.String()).
+		First(&splitPaymentData).Error
+	if err != nil {
+		return err
+	}
+
+	if splitPaymentData.Provider != provider {
+		return fmt.Errorf("%w: split payment uses %s, got %s", port.ErrorPaymentProviderMismatch, splitPaymentData.Provider, provider)
+	}
+
+	status, err := notificationStatus(datas)
+	if err != nil {
+		return err
+	}
+
+	currentPayment := transaction.NewPaymentFromSchema(splitPaymentData.PaymentCode, splitPaymentData.PaymentStatus)
+	if currentPayment.Status == status {
+		return nil
+	}
+	if !currentPayment.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", transaction.ErrorPaymentAlreadyFinal, currentPayment.Status, status)
+	}
+
+	paymentCode, _ := datas["transaction_id"].(string)
+	updatedPayment := transaction.NewPaymentFromSchema(paymentCode, status)
+	updates := map[string]interface{}{
+		"payment_status": status,
+		"payment_code":   paymentCode,
+	}
+	if updatedPayment.IsPaid() && splitPaymentData.PaidAt == nil {
+		updates["paid_at"] = clock.Now()
+	}
+
+	err = db.WithContext(ctx).
+		Model(&splitPaymentData).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+	if !updatedPayment.IsPaid() {
+		return nil
+	}
+
+	var splitPaymentSchemas []schema.SplitPayment
+	err = db.WithContext(ctx).
+		Where("transaction_id = ?", splitPaymentData.TransactionID).
+		Find(&splitPaymentSchemas).Error
+	if err != nil {
+		return err
+	}
+
+	splitPayments := make([]transaction.SplitPayment, 0, len(splitPaymentSchemas))
+	for _, splitPaymentSchema := range splitPaymentSchemas {
+		splitPayments = append(splitPayments, schema.SplitPaymentSchemaToEntity(splitPaymentSchema))
+	}
+	if !transaction.IsCovered(shared.NewPriceFromSchema(transactionData.TotalPrice), splitPayments) {
+		return nil
+	}
+
+	transactionPayment := transaction.NewPaymentFromSchema(transactionData.PaymentCode, transactionData.PaymentStatus)
+	if transactionPayment.IsPaid() {
+		// Already paid in full through the original charge.
+		return nil
+	}
+	if !transactionPayment.CanTransitionTo(transaction.PaymentStatusSettlement) {
+		return fmt.Errorf("%w: %s to %s", transaction.ErrorPaymentAlreadyFinal, transactionPayment.Status, transaction.PaymentStatusSettlement)
+	}
+
+	updates = map[string]interface{}{
+		"payment_status": transaction.PaymentStatusSettlement,
+		"payment_code":   paymentCode,
+		"paid_at":        clock.Now(),
+	}
+	if err = issueQueueCode(ctx, db, transactionDomainService, transactionData); err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).
+		Model(&transactionData).
+		Updates(updates).Error
+}
+
+// issueQueueCode writes a queue code to a transaction that has just been
+// paid. A transaction keeps the first code it was given, so a later status
+// such as capture to settlement does not move it in the queue. The code is
+// issued within db, the webhook's own transaction. Codes are unique per
+// business day, so a code taken by a concurrent payment is rolled back to a
+// savepoint and the next one is tried.
+func issueQueueCode(ctx context.Context, db *gorm.DB, transactionDomainService transaction.Service, transactionData schema.Transaction) error {
+	if transactionData.QueueCode != nil && *transactionData.QueueCode != "" {
+		return nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		queueCode, err := transactionDomainService.GenerateQueueCode(ctx, db_transaction.NewRepository(db), transactionData.ID.String())
+		if err != nil {
+			return fmt.Errorf("failed to generate queue code: %w", err)
+		}
+
+		if err = db.SavePoint(queueCodeSavePoint).Error; err != nil {
+			return err
+		}
+
+		err = db.WithContext(ctx).
+			Model(&schema.Transaction{}).
+			Where("id = ?", transactionData.ID).
+			Updates(map[string]interface{}{
+				"queue_code":   queueCode.Code,
+				"business_day": queueCode.BusinessDay,
+			}).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrDuplicatedKey) || attempt == maxQueueCodeAttempts {
+			return fmt.Errorf("failed to issue queue code: %w", err)
+		}
+
+		if err = db.RollbackTo(queueCodeSavePoint).Error; err != nil {
+			return err
+		}
+	}
+}
+
+func notificationStatus(datas map[string]interface{}) (string, error) {
+	status, ok := datas["transaction_status"].(string)
+	if !ok {
+		return "", fmt.Errorf("transaction_status is required in datas")
+	}
+
+	if !isValidPaymentStatus(status) {
+		return "", fmt.Errorf("invalid payment status: %s", status)
+	}
+
+	return status, nil
+}
+
+// notificationSignature follows Midtrans:
+// SHA512(order_id + status_code + gross_amount + server_key).
+func notificationSignature(orderID, statusCode, grossAmount, serverKey string) string {
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func verifyNotificationSignature(datas map[string]interface{}, serverKey string) error {
+	signatureKey, _ := datas["signature_key"].(string)
+	if signatureKey == "" || serverKey == "" {
+		return port.ErrorInvalidSignature
+	}
+
+	expected := notificationSignature(
+		fmt.Sprint(datas["order_id"]),
+		fmt.Sprint(datas["status_code"]),
+		fmt.Sprint(datas["gross_amount"]),
+		serverKey,
+	)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signatureKey)) != 1 {
+		return port.ErrorInvalidSignature
+	}
+
+	return nil
+}
+
+func isValidPaymentStatus(status string) bool {
+	for _, paymentStatus := range transaction.PaymentStatuses {
+		if paymentStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isCancellable reads the result of CheckPaymentStatus for CancelPayment. A
+// charge the gateway never saw has nothing to cancel, and a paid one cannot
+// be cancelled.
+func isCancellable(status port.PaymentStatusResponse, err error) (bool, error) {
+	if errors.Is(err, port.ErrorPaymentNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if transaction.NewPaymentFromSchema("", status.Status).IsPaid() {
+		return false, port.ErrorPaymentAlreadyPaid
+	}
+	return status.Status == transaction.PaymentStatusPending, nil
+}
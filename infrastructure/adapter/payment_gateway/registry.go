@@ -0,0 +1,41 @@
+package payment_gateway
+
+import (
+	"fmt"
+	"fp-kpl/domain/port"
+)
+
+type registry struct {
+	defaultProvider string
+	gateways        map[string]port.PaymentGatewayPort
+}
+
+// NewRegistry indexes gateways by their provider. defaultProvider must be one
+// of them; it handles transactions that do not ask for a provider.
+func NewRegistry(defaultProvider string, gateways ...port.PaymentGatewayPort) (port.PaymentGatewayRegistry, error) {
+	r := &registry{
+		defaultProvider: defaultProvider,
+		gateways:        make(map[string]port.PaymentGatewayPort, len(gateways)),
+	}
+	for _, gateway := range gateways {
+		r.gateways[gateway.Provider()] = gateway
+	}
+
+	if _, ok := r.gateways[defaultProvider]; !ok {
+		return nil, fmt.Errorf("%w: default %s is not enabled", port.ErrorPaymentProviderNotFound, defaultProvider)
+	}
+
+	return r, nil
+}
+
+func (r *registry) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	if provider == "" {
+		provider = r.defaultProvider
+	}
+
+	gateway, ok := r.gateways[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", port.ErrorPaymentProviderNotFound, provider)
+	}
+	return gateway, nil
+}
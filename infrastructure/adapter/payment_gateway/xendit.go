@@ -0,0 +1,231 @@
+package payment_gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/validation"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultXenditBaseURL = "https://api.xendit.co"
+
+	XenditCallbackTokenHeader = "X-Callback-Token"
+)
+
+// xenditStatuses maps invoice statuses to payment statuses. Xendit has no
+// separate test host; test and live mode are told apart by the secret key.
+var xenditStatuses = map[string]string{
+	"PENDING": transaction.PaymentStatusPending,
+	"PAID":    transaction.PaymentStatusSettlement,
+	"SETTLED": transaction.PaymentStatusSettlement,
+	"EXPIRED": transaction.PaymentStatusExpire,
+}
+
+type (
+	XenditConfig struct {
+		SecretKey     string
+		CallbackToken string
+		BaseURL       string
+	}
+
+	xenditAdapter struct {
+		db                       *gorm.DB
+		transactionDomainService transaction.Service
+		clock                    shared.Clock
+		paymentTimeout           time.Duration
+		config                   XenditConfig
+		httpClient               *http.Client
+	}
+
+	xenditInvoice struct {
+		ID         string          `json:"id"`
+		ExternalID string          `json:"external_id"`
+		Status     string          `json:"status"`
+		Amount     decimal.Decimal `json:"amount"`
+		PaidAmount decimal.Decimal `json:"paid_amount"`
+		InvoiceURL string          `json:"invoice_url"`
+	}
+)
+
+func NewXenditAdapter(db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock, paymentTimeout time.Duration, config XenditConfig) port.PaymentGatewayPort {
+	if paymentTimeout <= 0 {
+		paymentTimeout = transaction.DefaultPaymentTimeout
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultXenditBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	return &xenditAdapter{
+		db:                       db,
+		transactionDomainService: transactionDomainService,
+		clock:                    clock,
+		paymentTimeout:           paymentTimeout,
+		config:                   config,
+		httpClient:               &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (x *xenditAdapter) Provider() string {
+	return transaction.PaymentProviderXendit
+}
+
+func (x *xenditAdapter) ProcessPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (port.ProcessPaymentResponse, error) {
+	return x.createInvoice(ctx, transactionEntity.ID.String(), transactionEntity.TotalPrice.Price)
+}
+
+func (x *xenditAdapter) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return x.createInvoice(ctx, splitPayment.ID.String(), splitPayment.Amount.Price)
+}
+
+func (x *xenditAdapter) createInvoice(ctx context.Context, externalID string, amount decimal.Decimal) (port.ProcessPaymentResponse, error) {
+	var invoice xenditInvoice
+	err := x.call(ctx, http.MethodPost, "/v2/invoices", map[string]interface{}{
+		"external_id":      externalID,
+		"amount":           amount.IntPart(),
+		"description":      "Order " + externalID,
+		"invoice_duration": int64(x.paymentTimeout.Seconds()),
+		"currency":         "IDR",
+	}, &invoice)
+	if err != nil {
+		return port.ProcessPaymentResponse{}, err
+	}
+
+	return port.ProcessPaymentResponse{
+		Token:       invoice.ID,
+		PaymentLink: invoice.InvoiceURL,
+	}, nil
+}
+
+func (x *xenditAdapter) HookPayment(ctx context.Context, tx interface{}, transactionId uuid.UUID, datas map[string]interface{}) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = x.db
+	}
+
+	return applyPaymentNotification(ctx, db, x.transactionDomainService, x.clock, x.Provider(), transactionId, datas)
+}
+
+func (x *xenditAdapter) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	var invoices []xenditInvoice
+	err := x.call(ctx, http.MethodGet, "/v2/invoices?external_id="+url.QueryEscape(transactionId.String()), nil, &invoices)
+	if err != nil {
+		return port.PaymentStatusResponse{}, err
+	}
+	if len(invoices) == 0 {
+		return port.PaymentStatusResponse{}, port.ErrorPaymentNotFound
+	}
+
+	invoice := invoices[len(invoices)-1]
+	status, ok := xenditStatuses[invoice.Status]
+	if !ok {
+		return port.PaymentStatusResponse{}, fmt.Errorf("invalid xendit invoice status: %s", invoice.Status)
+	}
+
+	return port.PaymentStatusResponse{
+		OrderID:       invoice.ExternalID,
+		TransactionID: invoice.ID,
+		Status:        status,
+		GrossAmount:   invoice.grossAmount(),
+	}, nil
+}
+
+// CancelPayment expires the pending invoice of the transaction.
+func (x *xenditAdapter) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	status, err := x.CheckPaymentStatus(ctx, transactionId)
+	cancellable, err := isCancellable(status, err)
+	if err != nil || !cancellable {
+		return err
+	}
+
+	var invoice xenditInvoice
+	return x.call(ctx, http.MethodPost, "/invoices/"+url.PathEscape(status.TransactionID)+"/expire!", nil, &invoice)
+}
+
+// ParseNotification checks the callback token Xendit sends with every invoice
+// callback and converts the invoice to the normalised payload.
+func (x *xenditAdapter) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	callbackToken := headers[XenditCallbackTokenHeader]
+	if callbackToken == "" || x.config.CallbackToken == "" ||
+		subtle.ConstantTimeCompare([]byte(callbackToken), []byte(x.config.CallbackToken)) != 1 {
+		return nil, port.ErrorInvalidSignature
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var invoice xenditInvoice
+	if err := json.Unmarshal(raw, &invoice); err != nil {
+		return nil, fmt.Errorf("invalid xendit callback: %w", err)
+	}
+
+	status, ok := xenditStatuses[invoice.Status]
+	if !ok {
+		return nil, fmt.Errorf("invalid xendit invoice status: %s", invoice.Status)
+	}
+
+	return map[string]interface{}{
+		"order_id":           invoice.ExternalID,
+		"transaction_id":     invoice.ID,
+		"transaction_status": status,
+		"gross_amount":       invoice.grossAmount().StringFixed(2),
+	}, nil
+}
+
+func (i xenditInvoice) grossAmount() decimal.Decimal {
+	if i.PaidAmount.IsPositive() {
+		return i.PaidAmount
+	}
+	return i.Amount
+}
+
+func (x *xenditAdapter) call(ctx context.Context, method string, path string, payload interface{}, result interface{}) error {
+	var body bytes.Buffer
+	if payload != nil {
+		if err := json.NewEncoder(&body).Encode(payload); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, x.config.BaseURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(x.config.SecretKey, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := x.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call xendit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return port.ErrorPaymentNotFound
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("xendit responded with status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
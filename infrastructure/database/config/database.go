@@ -38,7 +38,8 @@ func SetUpDatabaseConnection() *gorm.DB {
 		DSN:                  dsn,
 		PreferSimpleProtocol: true,
 	}), &gorm.Config{
-		Logger: SetupLogger(),
+		Logger:         SetupLogger(),
+		TranslateError: true,
 	})
 	if err != nil {
 		panic(err)
@@ -0,0 +1,29 @@
+package data
+
+import "fp-kpl/infrastructure/database/schema"
+
+var Stations = []schema.Station{
+	{
+		Name: "Grill",
+	},
+	{
+		Name: "Fryer",
+	},
+	{
+		Name: "Drinks",
+	},
+}
+
+// CategoryStations maps category names to the station that prepares them.
+// Categories that are not listed stay on the general kitchen queue.
+var CategoryStations = map[string]string{
+	"Beef":    "Grill",
+	"Chicken": "Grill",
+	"Goat":    "Grill",
+	"Lamb":    "Grill",
+	"Pork":    "Grill",
+	"Seafood": "Fryer",
+	"Side":    "Fryer",
+	"Starter": "Fryer",
+	"Dessert": "Drinks",
+}
@@ -42,6 +42,13 @@ var Users = []schema.User{
 		PhoneNumber: "081234567890",
 		Role:        "waiter",
 	},
+	{
+		Name:        "Cashier",
+		Email:       "cashier@gmail.com",
+		Password:    getPassword("Cashier123!"),
+		PhoneNumber: "081234567890",
+		Role:        "cashier",
+	},
 }
 
 func getPassword(password string) string {
@@ -7,16 +7,199 @@ import (
 )
 
 func Migrate(db *gorm.DB) error {
+	if err := mergeDuplicateCartLines(db); err != nil {
+		return err
+	}
+
+	if err := abandonDuplicateOpenCarts(db); err != nil {
+		return err
+	}
+
+	if err := grandfatherVerifiedContacts(db); err != nil {
+		return err
+	}
+
+	if err := chainVerificationChallenges(db); err != nil {
+		return err
+	}
+
+	if err := renumberDuplicateQueueCodes(db); err != nil {
+		return err
+	}
+
 	if err := db.AutoMigrate(
+		&schema.Station{},
 		&schema.User{},
 		&schema.Table{},
 		&schema.Category{},
 		&schema.Menu{},
+		&schema.MenuSchedule{},
+		&schema.MenuPriceOverride{},
+		&schema.Device{},
 		&schema.Transaction{},
+		&schema.TransactionStatusChange{},
 		&schema.Order{},
+		&schema.Shift{},
+		&schema.CounterPayment{},
+		&schema.SplitPayment{},
+		&schema.SplitPaymentOrder{},
+		&schema.Refund{},
+		&schema.Promotion{},
+		&schema.PromotionRedemption{},
+		&schema.FavouriteMenu{},
+		&schema.Cart{},
+		&schema.CartLine{},
+		&schema.CartMember{},
+		&schema.Feedback{},
+		&schema.FeedbackMenuRating{},
+		&schema.LoyaltyEntry{},
+		&schema.VerificationChallenge{},
+		&schema.SLAAlert{},
 	); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// mergeDuplicateCartLines folds lines holding the same menu into the oldest
+// one, which carts created before cart lines were unique per menu can have.
+func mergeDuplicateCartLines(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&schema.CartLine{}) {
+		return nil
+	}
+
+	duplicates := `SELECT cart_id, menu_id, SUM(quantity) AS quantity,
+			(ARRAY_AGG(id ORDER BY created_at, id))[1] AS keep_id
+		FROM cart_lines GROUP BY cart_id, menu_id HAVING COUNT(*) > 1`
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE cart_lines SET quantity = duplicates.quantity
+			FROM (` + duplicates + `) AS duplicates
+			WHERE cart_lines.id = duplicates.keep_id`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`DELETE FROM cart_lines USING (` + duplicates + `) AS duplicates
+			WHERE cart_lines.cart_id = duplicates.cart_id
+			AND cart_lines.menu_id = duplicates.menu_id
+			AND cart_lines.id <> duplicates.keep_id`).Error
+	})
+}
+
+// abandonDuplicateOpenCarts gives up all but the newest open cart of each
+// table, which tables could have before their open cart was made unique.
+func abandonDuplicateOpenCarts(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&schema.Cart{}) {
+		return nil
+	}
+
+	if !db.Migrator().HasColumn(&schema.Cart{}, "AbandonedAt") {
+		if err := db.Migrator().AddColumn(&schema.Cart{}, "AbandonedAt"); err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(`UPDATE carts SET abandoned_at = NOW()
+		FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY table_id ORDER BY created_at DESC, id) AS position
+			FROM carts
+			WHERE table_id IS NOT NULL AND checked_out_at IS NULL AND abandoned_at IS NULL) AS open_carts
+		WHERE carts.id = open_carts.id AND open_carts.position > 1`).Error
+}
+
+// grandfatherVerifiedContacts counts the email and phone number of accounts
+// made before verification existed as verified, so they keep ordering and
+// sending their contact details to the payment gateway. It only runs when
+// the verification columns are first added.
+func grandfatherVerifiedContacts(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&schema.User{}) {
+		return nil
+	}
+
+	contacts := []struct {
+		field, column, contactColumn string
+	}{
+		{"EmailVerifiedAt", "email_verified_at", "email"},
+		{"PhoneVerifiedAt", "phone_verified_at", "phone_number"},
+	}
+
+	for _, contact := range contacts {
+		if db.Migrator().HasColumn(&schema.User{}, contact.field) {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&schema.User{}, contact.field); err != nil {
+				return err
+			}
+
+			return tx.Model(&schema.User{}).Unscoped().
+				Where(contact.contactColumn+" <> ''").
+				Update(contact.column, gorm.Expr("created_at")).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chainVerificationChallenges links each challenge to the one sent before it
+// to the same channel, which challenges sent before they were chained lack.
+func chainVerificationChallenges(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&schema.VerificationChallenge{}) ||
+		db.Migrator().HasColumn(&schema.VerificationChallenge{}, "ReplacesID") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&schema.VerificationChallenge{}, "ReplacesID"); err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE verification_challenges SET replaces_id = chained.previous_id
+			FROM (SELECT id, LAG(id) OVER (PARTITION BY user_id, channel ORDER BY created_at, id) AS previous_id
+				FROM verification_challenges) AS chained
+			WHERE verification_challenges.id = chained.id AND chained.previous_id IS NOT NULL`).Error
+	})
+}
+
+// renumberDuplicateQueueCodes moves every copy but the first of a queue code
+// issued twice on the same business day to the end of that day's queue, and
+// drops the index that allowed it so it is recreated as unique.
+func renumberDuplicateQueueCodes(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&schema.Transaction{}) {
+		return nil
+	}
+
+	var nonUnique int64
+	if err := db.Raw(`SELECT COUNT(*) FROM pg_index
+		JOIN pg_class ON pg_class.oid = pg_index.indexrelid
+		WHERE pg_class.relname = ? AND NOT pg_index.indisunique`, "idx_transactions_business_day_queue_code").
+		Scan(&nonUnique).Error; err != nil {
+		return err
+	}
+	if nonUnique == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE transactions SET queue_code = 'Q' || LPAD((COALESCE(latest.number, 0) + extra.nth)::text, 4, '0')
+			FROM (SELECT id, business_day, ROW_NUMBER() OVER (PARTITION BY business_day ORDER BY created_at, id) AS nth
+				FROM (SELECT id, business_day, created_at,
+						ROW_NUMBER() OVER (PARTITION BY business_day, queue_code ORDER BY created_at, id) AS copy
+					FROM transactions
+					WHERE business_day IS NOT NULL AND queue_code IS NOT NULL AND queue_code <> '') AS copies
+				WHERE copies.copy > 1) AS extra
+			LEFT JOIN (SELECT business_day, MAX(SUBSTRING(queue_code FROM 2)::int) AS number
+				FROM transactions
+				WHERE business_day IS NOT NULL AND queue_code ~ '^Q[0-9]+$'
+				GROUP BY business_day) AS latest ON latest.business_day = extra.business_day
+			WHERE transactions.id = extra.id`).Error; err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropIndex(&schema.Transaction{}, "idx_transactions_business_day_queue_code")
+	})
+}
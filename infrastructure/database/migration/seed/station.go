@@ -0,0 +1,39 @@
+package seed
+
+import (
+	"fp-kpl/infrastructure/database/migration/data"
+	"fp-kpl/infrastructure/database/schema"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Station(db *gorm.DB) error {
+	hasTable := db.Migrator().HasTable(&schema.Station{})
+	if !hasTable {
+		return db.AutoMigrate(&schema.Station{})
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).CreateInBatches(data.Stations, 100).Error
+}
+
+func CategoryStation(db *gorm.DB) error {
+	for categoryName, stationName := range data.CategoryStations {
+		var stationSchema schema.Station
+		if err := db.Where("name = ?", stationName).Take(&stationSchema).Error; err != nil {
+			return err
+		}
+
+		if err := db.Model(&schema.Category{}).
+			Where("name = ?", categoryName).
+			Where("station_id IS NULL").
+			Update("station_id", stationSchema.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -7,6 +7,10 @@ import (
 )
 
 func Seeder(db *gorm.DB) error {
+	if err := seed.Station(db); err != nil {
+		return err
+	}
+
 	if err := seed.User(db); err != nil {
 		return err
 	}
@@ -18,6 +22,10 @@ func Seeder(db *gorm.DB) error {
 		return err
 	}
 
+	if err := seed.CategoryStation(db); err != nil {
+		return err
+	}
+
 	if err := seed.Menu(db); err != nil {
 		return err
 	}
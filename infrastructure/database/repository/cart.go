@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/cart"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cartRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewCartRepository(db *db_transaction.Repository) cart.Repository {
+	return &cartRepository{db: db}
+}
+
+func preloadCart(db *gorm.DB) *gorm.DB {
+	return db.Preload("Members").Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("cart_lines.created_at ASC")
+	})
+}
+
+func (r *cartRepository) CreateCart(ctx context.Context, tx interface{}, cartEntity cart.Cart) (cart.Cart, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return cart.Cart{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	cartSchema := schema.CartEntityToSchema(cartEntity)
+	if cartSchema.TableID != nil {
+		// An expired cart would otherwise keep holding the table's open cart.
+		if err = db.WithContext(ctx).Model(&schema.Cart{}).
+			Where("table_id = ? AND checked_out_at IS NULL AND abandoned_at IS NULL AND expires_at <= ?",
+				cartSchema.TableID, cartSchema.CreatedAt).
+			Update("abandoned_at", cartSchema.CreatedAt).Error; err != nil {
+			return cart.Cart{}, err
+		}
+	}
+
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&cartSchema)
+	if result.Error != nil {
+		return cart.Cart{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return cart.Cart{}, cart.ErrorCartAlreadyOpen
+	}
+
+	return schema.CartSchemaToEntity(cartSchema), nil
+}
+
+func (r *cartRepository) GetCartByID(ctx context.Context, tx interface{}, id string) (cart.Cart, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return cart.Cart{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var cartSchema schema.Cart
+	if err = db.WithContext(ctx).Scopes(preloadCart).Where("id = ?", id).Take(&cartSchema).Error; err != nil {
+		return cart.Cart{}, err
+	}
+
+	return schema.CartSchemaToEntity(cartSchema), nil
+}
+
+func (r *cartRepository) GetOpenCart(ctx context.Context, tx interface{}, userID string, tableID string, at time.Time) (cart.Cart, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return cart.Cart{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	query := db.WithContext(ctx).Scopes(preloadCart).
+		Where("checked_out_at IS NULL AND expires_at > ?", at)
+	if tableID != "" {
+		query = query.Where("table_id = ?", tableID)
+	} else {
+		query = query.Where("user_id = ? AND table_id IS NULL", userID)
+	}
+
+	var cartSchema schema.Cart
+	if err = query.Order("created_at DESC").Take(&cartSchema).Error; err != nil {
+		return cart.Cart{}, err
+	}
+
+	return schema.CartSchemaToEntity(cartSchema), nil
+}
+
+func (r *cartRepository) JoinCart(ctx context.Context, tx interface{}, id string, userID string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	cartID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	memberID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&schema.CartMember{CartID: cartID, UserID: memberID}).Error
+}
+
+func (r *cartRepository) UpdateCart(ctx context.Context, tx interface{}, cartEntity cart.Cart) (cart.Cart, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return cart.Cart{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	// checked_out_at is left to CheckOutCart and ReopenCart, so a change
+	// made while another guest checks the cart out cannot reopen it.
+	cartSchema := schema.CartEntityToSchema(cartEntity)
+	result := db.WithContext(ctx).Model(&schema.Cart{}).
+		Where("id = ? AND checked_out_at IS NULL AND abandoned_at IS NULL", cartSchema.ID).
+		Select("order_type", "voucher_code", "expires_at", "updated_at").
+		Updates(&cartSchema)
+	if result.Error != nil {
+		return cart.Cart{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return cart.Cart{}, cart.ErrorCartCheckedOut
+	}
+
+	return cartEntity, nil
+}
+
+func (r *cartRepository) CheckOutCart(ctx context.Context, tx interface{}, id string, at time.Time) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).Model(&schema.Cart{}).
+		Where("id = ? AND checked_out_at IS NULL AND abandoned_at IS NULL", id).
+		Update("checked_out_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return cart.ErrorCartCheckedOut
+	}
+
+	return nil
+}
+
+func (r *cartRepository) ReopenCart(ctx context.Context, tx interface{}, id string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	// Fails on the open cart index when the table opened another cart while
+	// this one was being checked out.
+	return db.WithContext(ctx).Model(&schema.Cart{}).
+		Where("id = ? AND transaction_id IS NULL", id).
+		Update("checked_out_at", nil).Error
+}
+
+func (r *cartRepository) LinkCartTransaction(ctx context.Context, tx interface{}, id string, transactionID string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	return db.WithContext(ctx).Model(&schema.Cart{}).
+		Where("id = ?", id).
+		Update("transaction_id", transactionID).Error
+}
+
+func (r *cartRepository) AddCartLine(ctx context.Context, tx interface{}, line cart.Line) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	lineSchema := schema.CartLineEntityToSchema(line)
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "cart_id"}, {Name: "menu_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity": gorm.Expr("cart_lines.quantity + EXCLUDED.quantity"),
+		}),
+	}).Create(&lineSchema).Error
+}
+
+func (r *cartRepository) UpdateCartLineQuantity(ctx context.Context, tx interface{}, lineID string, quantity int) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).Model(&schema.CartLine{}).Where("id = ?", lineID).Update("quantity", quantity)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *cartRepository) DeleteCartLine(ctx context.Context, tx interface{}, lineID string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).Where("id = ?", lineID).Delete(&schema.CartLine{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fp-kpl/domain/menu/category"
+	"fp-kpl/domain/shared"
 	"fp-kpl/infrastructure/database/db_transaction"
 	"fp-kpl/infrastructure/database/schema"
 	"fp-kpl/infrastructure/database/validation"
@@ -55,10 +56,40 @@ func (r *categoryRepository) GetCategoryByID(ctx context.Context, tx interface{}
 
 	var categorySchema schema.Category
 
-	if err = db.WithContext(ctx).Where("id = ?", id).Take(&categorySchema).Error; err != nil {
+	if err = db.WithContext(ctx).Preload("Schedules").Where("id = ?", id).Take(&categorySchema).Error; err != nil {
 		return category.Category{}, err
 	}
 
 	categoryEntity := schema.CategorySchemaToEntity(categorySchema)
 	return categoryEntity, nil
 }
+
+func (r *categoryRepository) UpdateCategorySchedule(ctx context.Context, tx interface{}, id string, schedule []shared.TimeWindow) (category.Category, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return category.Category{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var categorySchema schema.Category
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&categorySchema).Error; err != nil {
+		return category.Category{}, err
+	}
+
+	if err = db.WithContext(ctx).Where("category_id = ?", id).Delete(&schema.MenuSchedule{}).Error; err != nil {
+		return category.Category{}, err
+	}
+
+	if len(schedule) > 0 {
+		scheduleSchemas := schema.TimeWindowsToMenuSchedules(nil, &categorySchema.ID, schedule)
+		if err = db.WithContext(ctx).Create(&scheduleSchemas).Error; err != nil {
+			return category.Category{}, err
+		}
+	}
+
+	return r.GetCategoryByID(ctx, tx, id)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/domain/device"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type deviceRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewDeviceRepository(db *db_transaction.Repository) device.Repository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) CreateDevice(ctx context.Context, tx interface{}, deviceEntity device.Device) (device.Device, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return device.Device{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	deviceSchema := schema.DeviceEntityToSchema(deviceEntity)
+	if err = db.WithContext(ctx).Create(&deviceSchema).Error; err != nil {
+		return device.Device{}, err
+	}
+
+	return schema.DeviceSchemaToEntity(deviceSchema), nil
+}
+
+func (r *deviceRepository) GetAllDevices(ctx context.Context, tx interface{}) ([]device.Device, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var deviceSchemas []schema.Device
+	if err = db.WithContext(ctx).Order("created_at ASC").Find(&deviceSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	devices := make([]device.Device, 0, len(deviceSchemas))
+	for _, deviceSchema := range deviceSchemas {
+		devices = append(devices, schema.DeviceSchemaToEntity(deviceSchema))
+	}
+
+	return devices, nil
+}
+
+func (r *deviceRepository) GetDeviceByID(ctx context.Context, tx interface{}, id string) (device.Device, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return device.Device{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var deviceSchema schema.Device
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&deviceSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return device.Device{}, device.ErrorDeviceNotFound
+		}
+		return device.Device{}, err
+	}
+
+	return schema.DeviceSchemaToEntity(deviceSchema), nil
+}
+
+func (r *deviceRepository) GetDeviceByKeyHash(ctx context.Context, tx interface{}, keyHash string) (device.Device, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return device.Device{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var deviceSchema schema.Device
+	if err = db.WithContext(ctx).Where("key_hash = ?", keyHash).Take(&deviceSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return device.Device{}, device.ErrorDeviceNotFound
+		}
+		return device.Device{}, err
+	}
+
+	return schema.DeviceSchemaToEntity(deviceSchema), nil
+}
+
+func (r *deviceRepository) RevokeDevice(ctx context.Context, tx interface{}, id string, revokedAt time.Time) (device.Device, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return device.Device{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var deviceSchema schema.Device
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&deviceSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return device.Device{}, device.ErrorDeviceNotFound
+		}
+		return device.Device{}, err
+	}
+
+	// Revoking twice keeps the first revocation time.
+	if deviceSchema.RevokedAt != nil {
+		return schema.DeviceSchemaToEntity(deviceSchema), nil
+	}
+
+	if err = db.WithContext(ctx).Model(&deviceSchema).Update("revoked_at", revokedAt).Error; err != nil {
+		return device.Device{}, err
+	}
+
+	deviceSchema.RevokedAt = &revokedAt
+	return schema.DeviceSchemaToEntity(deviceSchema), nil
+}
+
+func (r *deviceRepository) UpdateLastSeen(ctx context.Context, tx interface{}, id string, lastSeenAt time.Time) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	return db.WithContext(ctx).Model(&schema.Device{}).
+		Where("id = ?", id).
+		UpdateColumn("last_seen_at", lastSeenAt).Error
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/menu/favourite"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type favouriteRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewFavouriteRepository(db *db_transaction.Repository) favourite.Repository {
+	return &favouriteRepository{db: db}
+}
+
+// GetFavouritesByUserID lists the user's favourites, most recently added
+// first. Favourites of deleted menus are left out.
+func (r *favouriteRepository) GetFavouritesByUserID(ctx context.Context, tx interface{}, userID string) ([]favourite.Favourite, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var favouriteSchemas []schema.FavouriteMenu
+	if err = db.WithContext(ctx).
+		Joins("JOIN menus ON menus.id = favourite_menus.menu_id AND menus.deleted_at IS NULL").
+		Where("favourite_menus.user_id = ?", userID).
+		Order("favourite_menus.created_at DESC").
+		Find(&favouriteSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	favourites := make([]favourite.Favourite, 0, len(favouriteSchemas))
+	for _, favouriteSchema := range favouriteSchemas {
+		favourites = append(favourites, schema.FavouriteMenuSchemaToEntity(favouriteSchema))
+	}
+
+	return favourites, nil
+}
+
+func (r *favouriteRepository) AddFavourite(ctx context.Context, tx interface{}, favouriteEntity favourite.Favourite) (favourite.Favourite, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return favourite.Favourite{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	favouriteSchema := schema.FavouriteMenuEntityToSchema(favouriteEntity)
+	if err = db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&favouriteSchema).Error; err != nil {
+		return favourite.Favourite{}, err
+	}
+
+	return schema.FavouriteMenuSchemaToEntity(favouriteSchema), nil
+}
+
+func (r *favouriteRepository) RemoveFavourite(ctx context.Context, tx interface{}, userID string, menuID string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).
+		Where("user_id = ? AND menu_id = ?", userID, menuID).
+		Delete(&schema.FavouriteMenu{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
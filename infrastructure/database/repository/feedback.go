@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/identity"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+	"fp-kpl/platform/pagination"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type feedbackRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewFeedbackRepository(db *db_transaction.Repository) feedback.Repository {
+	return &feedbackRepository{db: db}
+}
+
+// CreateFeedback relies on the unique transaction_id so two submissions for
+// the same transaction cannot both be stored.
+func (r *feedbackRepository) CreateFeedback(ctx context.Context, tx interface{}, feedbackEntity feedback.Feedback) (feedback.Feedback, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return feedback.Feedback{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	feedbackSchema := schema.FeedbackEntityToSchema(feedbackEntity)
+	result := db.WithContext(ctx).Omit("MenuRatings").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "transaction_id"}}, DoNothing: true}).
+		Create(&feedbackSchema)
+	if result.Error != nil {
+		return feedback.Feedback{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return feedback.Feedback{}, feedback.ErrorFeedbackAlreadySubmitted
+	}
+
+	if len(feedbackSchema.MenuRatings) > 0 {
+		if err = db.WithContext(ctx).Create(&feedbackSchema.MenuRatings).Error; err != nil {
+			return feedback.Feedback{}, err
+		}
+	}
+
+	return schema.FeedbackSchemaToEntity(feedbackSchema), nil
+}
+
+func (r *feedbackRepository) GetAllFeedback(ctx context.Context, tx interface{}, filter feedback.Filter, req pagination.Request) (pagination.ResponseWithData, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return pagination.ResponseWithData{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var feedbackSchemas []schema.Feedback
+	var count int64
+
+	req.Default()
+
+	query := db.WithContext(ctx).Model(&feedbackSchemas)
+
+	if filter.MenuID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM feedback_menu_ratings WHERE feedback_menu_ratings.feedback_id = feedbacks.id AND feedback_menu_ratings.menu_id = ?)", filter.MenuID)
+	}
+	if filter.MinRating > 0 {
+		query = query.Where("feedbacks.rating >= ?", filter.MinRating)
+	}
+	if filter.MaxRating > 0 {
+		query = query.Where("feedbacks.rating <= ?", filter.MaxRating)
+	}
+	if filter.From != nil {
+		query = query.Where("feedbacks.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("feedbacks.created_at < ?", *filter.To)
+	}
+	if filter.HasComment != nil {
+		if *filter.HasComment {
+			query = query.Where("feedbacks.comment <> ''")
+		} else {
+			query = query.Where("feedbacks.comment = ''")
+		}
+	}
+	if req.Search != "" {
+		query = query.Where("feedbacks.comment ILIKE ?", "%"+req.Search+"%")
+	}
+
+	if err = query.Count(&count).Error; err != nil {
+		return pagination.ResponseWithData{}, err
+	}
+
+	if err = query.Scopes(pagination.Paginate(req)).
+		Preload("User").
+		Preload("Transaction").
+		Preload("MenuRatings").
+		Preload("MenuRatings.Menu", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Order("feedbacks.created_at DESC").
+		Find(&feedbackSchemas).Error; err != nil {
+		return pagination.ResponseWithData{}, err
+	}
+
+	data := make([]any, len(feedbackSchemas))
+	for i, feedbackSchema := range feedbackSchemas {
+		feedbackQuery := feedback.Query{
+			Feedback:  schema.FeedbackSchemaToEntity(feedbackSchema),
+			MenuNames: make(map[string]string, len(feedbackSchema.MenuRatings)),
+		}
+		if feedbackSchema.User != nil {
+			feedbackQuery.UserName = feedbackSchema.User.Name
+		}
+		if feedbackSchema.Transaction != nil && feedbackSchema.Transaction.QueueCode != nil {
+			feedbackQuery.QueueCode = *feedbackSchema.Transaction.QueueCode
+		}
+		for _, menuRating := range feedbackSchema.MenuRatings {
+			if menuRating.Menu != nil {
+				feedbackQuery.MenuNames[menuRating.MenuID.String()] = menuRating.Menu.Name
+			}
+		}
+		data[i] = feedbackQuery
+	}
+
+	return pagination.ResponseWithData{
+		Data: data,
+		Response: pagination.Response{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			MaxPage: pagination.TotalPage(count, int64(req.PerPage)),
+			Count:   count,
+		},
+	}, nil
+}
+
+func (r *feedbackRepository) GetMenuSummaries(ctx context.Context, tx interface{}, menuIDs []string) ([]feedback.MenuSummary, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	if len(menuIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []struct {
+		MenuID  uuid.UUID
+		Average decimal.Decimal
+		Count   int
+	}
+	if err = db.WithContext(ctx).Model(&schema.FeedbackMenuRating{}).
+		Select("menu_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("menu_id IN ?", menuIDs).
+		Group("menu_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	summaries := make([]feedback.MenuSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, feedback.MenuSummary{
+			MenuID:  identity.NewIDFromSchema(row.MenuID),
+			Average: row.Average,
+			Count:   row.Count,
+		})
+	}
+
+	return summaries, nil
+}
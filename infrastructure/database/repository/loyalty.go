@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/loyalty"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
+)
+
+type loyaltyRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewLoyaltyRepository(db *db_transaction.Repository) loyalty.Repository {
+	return &loyaltyRepository{db: db}
+}
+
+// LockAccount locks the customer's user row, which every change to their
+// ledger goes through. It only holds inside a database transaction.
+func (r *loyaltyRepository) LockAccount(ctx context.Context, tx interface{}, userID string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var userSchema schema.User
+	return db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", userID).
+		Take(&userSchema).Error
+}
+
+func (r *loyaltyRepository) GetAccount(ctx context.Context, tx interface{}, userID string) (loyalty.Account, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return loyalty.Account{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return loyalty.Account{}, err
+	}
+
+	var entrySchemas []schema.LoyaltyEntry
+	if err = db.WithContext(ctx).Where("user_id = ?", userUUID).
+		Order("sequence ASC").
+		Find(&entrySchemas).Error; err != nil {
+		return loyalty.Account{}, err
+	}
+
+	entries := make([]loyalty.Entry, 0, len(entrySchemas))
+	for _, entrySchema := range entrySchemas {
+		entries = append(entries, schema.LoyaltyEntrySchemaToEntity(entrySchema))
+	}
+
+	return loyalty.Account{
+		UserID:  identity.NewIDFromSchema(userUUID),
+		Entries: entries,
+	}, nil
+}
+
+func (r *loyaltyRepository) CreateEntries(ctx context.Context, tx interface{}, entries []loyalty.Entry) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	entrySchemas := make([]schema.LoyaltyEntry, 0, len(entries))
+	for _, entry := range entries {
+		entrySchemas = append(entrySchemas, schema.LoyaltyEntryEntityToSchema(entry))
+	}
+
+	return db.WithContext(ctx).Create(&entrySchemas).Error
+}
+
+func (r *loyaltyRepository) GetSettlements(ctx context.Context, tx interface{}, since time.Time) ([]loyalty.Settlement, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var rows []struct {
+		ID            uuid.UUID
+		UserID        uuid.UUID
+		PaymentCode   string
+		PaymentStatus string
+		TotalPrice    decimal.Decimal
+		Refunded      decimal.Decimal
+	}
+
+	if err = db.WithContext(ctx).Model(&schema.Transaction{}).
+		Select("transactions.id, transactions.user_id, transactions.payment_code, transactions.payment_status, transactions.total_price, "+
+			"COALESCE((SELECT SUM(refunds.amount) FROM refunds WHERE refunds.transaction_id = transactions.id), 0) AS refunded").
+		Where("transactions.user_id IS NOT NULL").
+		Where("transactions.updated_at >= ? OR EXISTS (SELECT 1 FROM refunds WHERE refunds.transaction_id = transactions.id AND refunds.created_at >= ?)", since, since).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	settlements := make([]loyalty.Settlement, 0, len(rows))
+	for _, row := range rows {
+		settlements = append(settlements, loyalty.Settlement{
+			TransactionID: identity.NewIDFromSchema(row.ID),
+			UserID:        identity.NewIDFromSchema(row.UserID),
+			Payment:       transaction.NewPaymentFromSchema(row.PaymentCode, row.PaymentStatus),
+			Total:         shared.NewPriceFromSchema(row.TotalPrice),
+			Refunded:      row.Refunded,
+		})
+	}
+	return settlements, nil
+}
+
+func (r *loyaltyRepository) GetExpiringUserIDs(ctx context.Context, tx interface{}, from time.Time, to time.Time) ([]string, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var userIDs []uuid.UUID
+	if err = db.WithContext(ctx).Model(&schema.LoyaltyEntry{}).
+		Distinct("user_id").
+		Where("points > 0 AND expires_at > ? AND expires_at <= ?", from, to).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.String())
+	}
+	return ids, nil
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/shared"
 	"fp-kpl/infrastructure/database/db_transaction"
 	"fp-kpl/infrastructure/database/schema"
 	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm"
 )
 
 type menuRepository struct {
@@ -30,6 +33,7 @@ func (r *menuRepository) GetAllMenus(ctx context.Context, tx interface{}) ([]men
 	var menuSchemas []schema.Menu
 
 	query := db.WithContext(ctx).Model(&schema.Menu{}).
+		Scopes(preloadSchedules).
 		Joins("JOIN categories ON menus.category_id = categories.id").
 		Order("categories.name ASC, menus.name ASC")
 
@@ -58,7 +62,7 @@ func (r *menuRepository) GetMenuByID(ctx context.Context, tx interface{}, id str
 
 	var menuSchema schema.Menu
 
-	if err = db.WithContext(ctx).Where("id = ?", id).Take(&menuSchema).Error; err != nil {
+	if err = db.WithContext(ctx).Scopes(preloadSchedules).Where("id = ?", id).Take(&menuSchema).Error; err != nil {
 		return menu.Menu{}, err
 	}
 
@@ -80,6 +84,7 @@ func (r *menuRepository) GetMenusByCategoryID(ctx context.Context, tx interface{
 	var menuSchemas []schema.Menu
 
 	query := db.WithContext(ctx).Model(&schema.Menu{}).
+		Scopes(preloadSchedules).
 		Joins("JOIN categories ON menus.category_id = categories.id").
 		Where("menus.category_id = ?", categoryID).
 		Order("categories.name ASC, menus.name ASC")
@@ -119,6 +124,71 @@ func (r *menuRepository) UpdateMenuAvailability(ctx context.Context, tx interfac
 		return menu.Menu{}, err
 	}
 
-	menuEntity := schema.MenuSchemaToEntity(menuSchema)
-	return menuEntity, nil
+	return r.GetMenuByID(ctx, tx, id)
+}
+
+func (r *menuRepository) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, schedule []shared.TimeWindow) (menu.Menu, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return menu.Menu{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var menuSchema schema.Menu
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&menuSchema).Error; err != nil {
+		return menu.Menu{}, err
+	}
+
+	if err = db.WithContext(ctx).Where("menu_id = ?", id).Delete(&schema.MenuSchedule{}).Error; err != nil {
+		return menu.Menu{}, err
+	}
+
+	if len(schedule) > 0 {
+		scheduleSchemas := schema.TimeWindowsToMenuSchedules(&menuSchema.ID, nil, schedule)
+		if err = db.WithContext(ctx).Create(&scheduleSchemas).Error; err != nil {
+			return menu.Menu{}, err
+		}
+	}
+
+	return r.GetMenuByID(ctx, tx, id)
+}
+
+func (r *menuRepository) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu.PriceOverride) (menu.Menu, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return menu.Menu{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var menuSchema schema.Menu
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&menuSchema).Error; err != nil {
+		return menu.Menu{}, err
+	}
+
+	if err = db.WithContext(ctx).Where("menu_id = ?", id).Delete(&schema.MenuPriceOverride{}).Error; err != nil {
+		return menu.Menu{}, err
+	}
+
+	if len(overrides) > 0 {
+		overrideSchemas := schema.PriceOverridesToSchemas(menuSchema.ID, overrides)
+		if err = db.WithContext(ctx).Create(&overrideSchemas).Error; err != nil {
+			return menu.Menu{}, err
+		}
+	}
+
+	return r.GetMenuByID(ctx, tx, id)
+}
+
+// preloadSchedules loads what it takes to tell a menu's availability and
+// price at a given time.
+func preloadSchedules(db *gorm.DB) *gorm.DB {
+	return db.Preload("Schedules").Preload("PriceOverrides").Preload("Category.Schedules")
 }
@@ -59,3 +59,39 @@ func (r *orderRepository) GetOrdersByTransactionID(ctx context.Context, tx inter
 
 	return orderEntities, nil
 }
+
+func (r *orderRepository) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	updates := map[string]interface{}{"cooking_status": status}
+	query := db.WithContext(ctx).Model(&schema.Order{}).Where("transaction_id = ?", transactionID)
+	if stationID != "" {
+		// Lines whose menu has no station go to the first station to get to them.
+		query = query.Where("(station_id = ? OR (station_id IS NULL AND cooking_status = ?))", stationID, order.CookingStatusPending)
+		updates["station_id"] = stationID
+	}
+
+	if err = query.Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	var orderSchemas []schema.Order
+	if err = db.WithContext(ctx).Where("transaction_id = ?", transactionID).Find(&orderSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	orderEntities := make([]order.Order, len(orderSchemas))
+	for i, orderSchema := range orderSchemas {
+		orderEntities[i] = schema.OrderSchemaToEntity(orderSchema)
+	}
+
+	return orderEntities, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type promotionRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewPromotionRepository(db *db_transaction.Repository) promotion.Repository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) CreatePromotion(ctx context.Context, tx interface{}, promotionEntity promotion.Promotion) (promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return promotion.Promotion{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	promotionSchema := schema.PromotionEntityToSchema(promotionEntity)
+	if err = db.WithContext(ctx).Create(&promotionSchema).Error; err != nil {
+		return promotion.Promotion{}, err
+	}
+
+	return schema.PromotionSchemaToEntity(promotionSchema), nil
+}
+
+func (r *promotionRepository) GetAllPromotions(ctx context.Context, tx interface{}) ([]promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var promotionSchemas []schema.Promotion
+	if err = db.WithContext(ctx).Order("created_at DESC").Find(&promotionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	promotions := make([]promotion.Promotion, 0, len(promotionSchemas))
+	for _, promotionSchema := range promotionSchemas {
+		promotions = append(promotions, schema.PromotionSchemaToEntity(promotionSchema))
+	}
+
+	return promotions, nil
+}
+
+func (r *promotionRepository) GetPromotionByID(ctx context.Context, tx interface{}, id string) (promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return promotion.Promotion{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var promotionSchema schema.Promotion
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&promotionSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return promotion.Promotion{}, promotion.ErrorPromotionNotFound
+		}
+		return promotion.Promotion{}, err
+	}
+
+	return schema.PromotionSchemaToEntity(promotionSchema), nil
+}
+
+func (r *promotionRepository) LockPromotion(ctx context.Context, tx interface{}, id string) (promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return promotion.Promotion{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var promotionSchema schema.Promotion
+	if err = db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Take(&promotionSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return promotion.Promotion{}, promotion.ErrorPromotionNotFound
+		}
+		return promotion.Promotion{}, err
+	}
+
+	return schema.PromotionSchemaToEntity(promotionSchema), nil
+}
+
+func (r *promotionRepository) GetActivePromotions(ctx context.Context, tx interface{}) ([]promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var promotionSchemas []schema.Promotion
+	if err = db.WithContext(ctx).
+		Where("is_active = ? AND voucher_code IS NULL", true).
+		Order("created_at ASC").
+		Find(&promotionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	promotions := make([]promotion.Promotion, 0, len(promotionSchemas))
+	for _, promotionSchema := range promotionSchemas {
+		promotions = append(promotions, schema.PromotionSchemaToEntity(promotionSchema))
+	}
+
+	return promotions, nil
+}
+
+func (r *promotionRepository) GetPromotionByVoucherCode(ctx context.Context, tx interface{}, code string) (promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return promotion.Promotion{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var promotionSchema schema.Promotion
+	if err = db.WithContext(ctx).Where("voucher_code = ?", code).Take(&promotionSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return promotion.Promotion{}, promotion.ErrorVoucherNotFound
+		}
+		return promotion.Promotion{}, err
+	}
+
+	return schema.PromotionSchemaToEntity(promotionSchema), nil
+}
+
+func (r *promotionRepository) UpdatePromotionStatus(ctx context.Context, tx interface{}, id string, isActive bool) (promotion.Promotion, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return promotion.Promotion{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).Model(&schema.Promotion{}).Where("id = ?", id).Update("is_active", isActive)
+	if result.Error != nil {
+		return promotion.Promotion{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return promotion.Promotion{}, promotion.ErrorPromotionNotFound
+	}
+
+	return r.GetPromotionByID(ctx, tx, id)
+}
+
+// GetUsage leaves out redemptions of transactions that were never paid and
+// can no longer be, so an abandoned checkout gives the use back.
+func (r *promotionRepository) GetUsage(ctx context.Context, tx interface{}, promotionID string, userID string) (promotion.Usage, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return promotion.Usage{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	query := db.WithContext(ctx).Model(&schema.PromotionRedemption{}).
+		Joins("JOIN transactions ON transactions.id = promotion_redemptions.transaction_id AND transactions.deleted_at IS NULL").
+		Where("promotion_redemptions.promotion_id = ?", promotionID).
+		Where("transactions.payment_status NOT IN ?", []string{
+			transaction.PaymentStatusCancel,
+			transaction.PaymentStatusDeny,
+			transaction.PaymentStatusExpire,
+		})
+
+	var total int64
+	if err = query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return promotion.Usage{}, err
+	}
+
+	var byUser int64
+	if userID != "" {
+		if err = query.Session(&gorm.Session{}).
+			Where("promotion_redemptions.user_id = ?", userID).
+			Count(&byUser).Error; err != nil {
+			return promotion.Usage{}, err
+		}
+	}
+
+	return promotion.Usage{
+		Total:  int(total),
+		ByUser: int(byUser),
+	}, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/shift"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+
+	"github.com/shopspring/decimal"
+)
+
+type shiftRepository struct {
+	db    *db_transaction.Repository
+	clock shared.Clock
+}
+
+func NewShiftRepository(db *db_transaction.Repository, clock shared.Clock) shift.Repository {
+	return &shiftRepository{db: db, clock: clock}
+}
+
+func (r *shiftRepository) OpenShift(ctx context.Context, tx interface{}, shiftEntity shift.Shift) (shift.Shift, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return shift.Shift{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	shiftSchema := schema.ShiftEntityToSchema(shiftEntity)
+	if err = db.WithContext(ctx).Create(&shiftSchema).Error; err != nil {
+		return shift.Shift{}, err
+	}
+
+	return schema.ShiftSchemaToEntity(shiftSchema), nil
+}
+
+func (r *shiftRepository) GetOpenShiftByCashierID(ctx context.Context, tx interface{}, cashierID string) (shift.Shift, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return shift.Shift{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var shiftSchema schema.Shift
+	if err = db.WithContext(ctx).
+		Where("cashier_id = ? AND closed_at IS NULL", cashierID).
+		Order("opened_at DESC").
+		Take(&shiftSchema).Error; err != nil {
+		return shift.Shift{}, err
+	}
+
+	return schema.ShiftSchemaToEntity(shiftSchema), nil
+}
+
+func (r *shiftRepository) CloseShift(ctx context.Context, tx interface{}, shiftID string, countedCash shared.Price) (shift.Shift, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return shift.Shift{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var shiftSchema schema.Shift
+	if err = db.WithContext(ctx).Where("id = ?", shiftID).Take(&shiftSchema).Error; err != nil {
+		return shift.Shift{}, err
+	}
+
+	closedAt := r.clock.Now()
+	if err = db.WithContext(ctx).Model(&shiftSchema).Updates(map[string]interface{}{
+		"closed_at":    closedAt,
+		"counted_cash": countedCash.Price,
+	}).Error; err != nil {
+		return shift.Shift{}, err
+	}
+
+	shiftSchema.ClosedAt = &closedAt
+	shiftSchema.CountedCash = &countedCash.Price
+	return schema.ShiftSchemaToEntity(shiftSchema), nil
+}
+
+func (r *shiftRepository) CreateCounterPayment(ctx context.Context, tx interface{}, counterPayment shift.CounterPayment) (shift.CounterPayment, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return shift.CounterPayment{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	counterPaymentSchema := schema.CounterPaymentEntityToSchema(counterPayment)
+	if err = db.WithContext(ctx).Create(&counterPaymentSchema).Error; err != nil {
+		return shift.CounterPayment{}, err
+	}
+
+	return schema.CounterPaymentSchemaToEntity(counterPaymentSchema), nil
+}
+
+func (r *shiftRepository) GetShiftTotals(ctx context.Context, tx interface{}, shiftID string) (shift.Totals, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return shift.Totals{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var rows []struct {
+		Method string
+		Total  decimal.Decimal
+		Count  int
+	}
+	if err = db.WithContext(ctx).Model(&schema.CounterPayment{}).
+		Select("method, COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count").
+		Where("shift_id = ?", shiftID).
+		Group("method").
+		Scan(&rows).Error; err != nil {
+		return shift.Totals{}, err
+	}
+
+	totals := shift.Totals{
+		Cash: shared.NewPriceFromSchema(decimal.Zero),
+		Card: shared.NewPriceFromSchema(decimal.Zero),
+	}
+	for _, row := range rows {
+		switch row.Method {
+		case shift.MethodCash:
+			totals.Cash = shared.NewPriceFromSchema(row.Total)
+		case shift.MethodCard:
+			totals.Card = shared.NewPriceFromSchema(row.Total)
+		}
+		totals.Transactions += row.Count
+	}
+
+	return totals, nil
+}
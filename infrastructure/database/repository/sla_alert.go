@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/sla"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm/clause"
+)
+
+type slaAlertRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewSLAAlertRepository(db *db_transaction.Repository) sla.Repository {
+	return &slaAlertRepository{db: db}
+}
+
+func (r *slaAlertRepository) RecordAlert(ctx context.Context, tx interface{}, breach sla.Breach) (bool, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return false, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	alertSchema := schema.SLAAlertEntityToSchema(breach)
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&alertSchema)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type splitPaymentRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewSplitPaymentRepository(db *db_transaction.Repository) transaction.SplitPaymentRepository {
+	return &splitPaymentRepository{db: db}
+}
+
+func (r *splitPaymentRepository) CreateSplitPayment(ctx context.Context, tx interface{}, splitPayment transaction.SplitPayment) (transaction.SplitPayment, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.SplitPayment{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	splitPaymentSchema := schema.SplitPaymentEntityToSchema(splitPayment)
+	if err = db.WithContext(ctx).Create(&splitPaymentSchema).Error; err != nil {
+		return transaction.SplitPayment{}, err
+	}
+
+	return schema.SplitPaymentSchemaToEntity(splitPaymentSchema), nil
+}
+
+func (r *splitPaymentRepository) GetSplitPaymentByID(ctx context.Context, tx interface{}, id string) (transaction.SplitPayment, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.SplitPayment{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var splitPaymentSchema schema.SplitPayment
+	if err = db.WithContext(ctx).
+		Preload("Orders").
+		Where("id = ?", id).
+		Take(&splitPaymentSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return transaction.SplitPayment{}, transaction.ErrorSplitPaymentNotFound
+		}
+		return transaction.SplitPayment{}, err
+	}
+
+	return schema.SplitPaymentSchemaToEntity(splitPaymentSchema), nil
+}
+
+func (r *splitPaymentRepository) LockSplitPayment(ctx context.Context, tx interface{}, id string) (transaction.SplitPayment, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.SplitPayment{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var splitPaymentSchema schema.SplitPayment
+	if err = db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Orders").
+		Where("id = ?", id).
+		Take(&splitPaymentSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return transaction.SplitPayment{}, transaction.ErrorSplitPaymentNotFound
+		}
+		return transaction.SplitPayment{}, err
+	}
+
+	return schema.SplitPaymentSchemaToEntity(splitPaymentSchema), nil
+}
+
+func (r *splitPaymentRepository) GetSplitPaymentsByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]transaction.SplitPayment, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var splitPaymentSchemas []schema.SplitPayment
+	if err = db.WithContext(ctx).
+		Preload("Orders").
+		Where("transaction_id = ?", transactionID).
+		Order("created_at ASC").
+		Find(&splitPaymentSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	splitPayments := make([]transaction.SplitPayment, 0, len(splitPaymentSchemas))
+	for _, splitPaymentSchema := range splitPaymentSchemas {
+		splitPayments = append(splitPayments, schema.SplitPaymentSchemaToEntity(splitPaymentSchema))
+	}
+
+	return splitPayments, nil
+}
+
+// CreateRefund stores the refund and adds it to the refunded amount of its
+// split payment.
+func (r *splitPaymentRepository) CreateRefund(ctx context.Context, tx interface{}, refund transaction.Refund) (transaction.Refund, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.Refund{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	refundSchema := schema.RefundEntityToSchema(refund)
+	if err = db.WithContext(ctx).Create(&refundSchema).Error; err != nil {
+		return transaction.Refund{}, err
+	}
+
+	if err = db.WithContext(ctx).Model(&schema.SplitPayment{}).
+		Where("id = ?", refundSchema.SplitPaymentID).
+		UpdateColumn("refunded_amount", gorm.Expr("refunded_amount + ?", refundSchema.Amount)).Error; err != nil {
+		return transaction.Refund{}, err
+	}
+
+	return schema.RefundSchemaToEntity(refundSchema), nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/station"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm"
+)
+
+type stationRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewStationRepository(db *db_transaction.Repository) station.Repository {
+	return &stationRepository{db: db}
+}
+
+func (r *stationRepository) GetAllStations(ctx context.Context, tx interface{}) ([]station.Station, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var stationSchemas []schema.Station
+
+	if err = db.WithContext(ctx).Model(&schema.Station{}).Order("name ASC").Find(&stationSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	stationEntities := make([]station.Station, len(stationSchemas))
+	for i, stationSchema := range stationSchemas {
+		stationEntities[i] = schema.StationSchemaToEntity(stationSchema)
+	}
+
+	return stationEntities, nil
+}
+
+func (r *stationRepository) GetStationByID(ctx context.Context, tx interface{}, id string) (station.Station, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return station.Station{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var stationSchema schema.Station
+
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&stationSchema).Error; err != nil {
+		return station.Station{}, err
+	}
+
+	stationEntity := schema.StationSchemaToEntity(stationSchema)
+	return stationEntity, nil
+}
+
+func (r *stationRepository) GetStationByMenuID(ctx context.Context, tx interface{}, menuID string) (station.Station, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return station.Station{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var menuSchema schema.Menu
+
+	if err = db.WithContext(ctx).Preload("Category").Where("id = ?", menuID).Take(&menuSchema).Error; err != nil {
+		return station.Station{}, err
+	}
+
+	// A station set on the menu itself overrides the one inherited from its category.
+	stationID := menuSchema.StationID
+	if stationID == nil && menuSchema.Category != nil {
+		stationID = menuSchema.Category.StationID
+	}
+
+	if stationID == nil {
+		return station.Station{}, station.ErrorStationNotFound
+	}
+
+	var stationSchema schema.Station
+
+	if err = db.WithContext(ctx).Where("id = ?", stationID.String()).Take(&stationSchema).Error; err != nil {
+		return station.Station{}, err
+	}
+
+	stationEntity := schema.StationSchemaToEntity(stationSchema)
+	return stationEntity, nil
+}
+
+func (r *stationRepository) UpdateMenuStation(ctx context.Context, tx interface{}, menuID string, stationID string) error {
+	return r.updateStationID(ctx, tx, &schema.Menu{}, menuID, stationID)
+}
+
+func (r *stationRepository) UpdateCategoryStation(ctx context.Context, tx interface{}, categoryID string, stationID string) error {
+	return r.updateStationID(ctx, tx, &schema.Category{}, categoryID, stationID)
+}
+
+func (r *stationRepository) UpdateUserStation(ctx context.Context, tx interface{}, userID string, stationID string) error {
+	return r.updateStationID(ctx, tx, &schema.User{}, userID, stationID)
+}
+
+func (r *stationRepository) updateStationID(ctx context.Context, tx interface{}, model interface{}, id string, stationID string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var value interface{}
+	if stationID != "" {
+		value = stationID
+	}
+
+	result := db.WithContext(ctx).Model(model).Where("id = ?", id).Update("station_id", value)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
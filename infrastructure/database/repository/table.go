@@ -62,3 +62,23 @@ func (r *tableRepository) GetTableByID(ctx context.Context, tx interface{}, id s
 	tableEntity := schema.TableSchemaToEntity(tableSchema)
 	return tableEntity, nil
 }
+
+func (r *tableRepository) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return table.Table{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var tableSchema schema.Table
+
+	if err = db.WithContext(ctx).Where("token = ?", token).Take(&tableSchema).Error; err != nil {
+		return table.Table{}, err
+	}
+
+	return schema.TableSchemaToEntity(tableSchema), nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"fp-kpl/application/response"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
 	"fp-kpl/domain/transaction"
 	"fp-kpl/infrastructure/database/db_transaction"
 	"fp-kpl/infrastructure/database/schema"
@@ -13,18 +15,44 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// transactionRepository reads "today" as the restaurant's current business
+// day, stored on each transaction when its queue code is issued, so the
+// queue does not reset at midnight in the database's timezone.
 type transactionRepository struct {
-	db *db_transaction.Repository
+	db    *db_transaction.Repository
+	clock shared.Clock
 }
 
-func NewTransactionRepository(db *db_transaction.Repository) transaction.Repository {
+func NewTransactionRepository(db *db_transaction.Repository, clock shared.Clock) transaction.Repository {
 	return &transactionRepository{
-		db: db,
+		db:    db,
+		clock: clock,
 	}
 }
 
+// currentOrInFlight keeps the transactions of the current business day plus
+// paid orders that have not been served yet, so an order paid before the
+// cutoff can still be found and cooked after it. Open orders older than
+// transaction.MaxInFlightAge are not carried over; a scheduled pickup order
+// is aged from its pickup time, since it may be paid days before.
+func (r *transactionRepository) currentOrInFlight(db *gorm.DB) *gorm.DB {
+	return db.Where("(business_day = ? OR (payment_status IN ? AND order_status IN ? AND COALESCE(pickup_at, created_at) >= ?))",
+		r.clock.Today().String(),
+		[]string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture},
+		transaction.OpenOrderStatuses,
+		r.clock.Now().Add(-transaction.MaxInFlightAge),
+	)
+}
+
+// dueInKitchen leaves out scheduled pickup orders that are not due to be
+// cooked yet.
+func (r *transactionRepository) dueInKitchen(db *gorm.DB) *gorm.DB {
+	return db.Where("(cook_after IS NULL OR cook_after <= ?)", r.clock.Now())
+}
+
 func (r *transactionRepository) CreateTransaction(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (transaction.Transaction, error) {
 	validatedTransaction, err := validation.ValidateTransaction(tx)
 	if err != nil {
@@ -75,6 +103,7 @@ func (r *transactionRepository) GetAllTransactionsWithPagination(ctx context.Con
 		Preload("Table").
 		Preload("Orders").
 		Preload("Orders.Menu").
+		Preload("Redemptions").
 		Find(&transactionSchemas).Error; err != nil {
 		return pagination.ResponseWithData{}, err
 	}
@@ -88,7 +117,9 @@ func (r *transactionRepository) GetAllTransactionsWithPagination(ctx context.Con
 			})
 			transactionQueries[i].Orders[j].Menu = schema.MenuSchemaToEntity(*orderSchema.Menu)
 		}
-		transactionQueries[i].Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+		if transactionSchema.Table != nil {
+			transactionQueries[i].Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+		}
 	}
 
 	totalPage := pagination.TotalPage(count, int64(req.PerPage))
@@ -128,7 +159,8 @@ func (r *transactionRepository) GetAllReadyToServeTransactionList(ctx context.Co
 	query := db.WithContext(ctx).Model(&transactionSchemas).
 		Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture}).
 		Where("served_at IS NULL").
-		Where("order_status = ?", transaction.OrderStatusReadyToServe)
+		Where("order_status = ?", transaction.OrderStatusReadyToServe).
+		Where("order_type = ?", transaction.OrderTypeDineIn)
 
 	if req.Search != "" {
 		query = query.Where("queue_code LIKE ?", "%"+req.Search+"%")
@@ -156,7 +188,9 @@ func (r *transactionRepository) GetAllReadyToServeTransactionList(ctx context.Co
 			})
 			transactionQueries[i].Orders[j].Menu = schema.MenuSchemaToEntity(*orderSchema.Menu)
 		}
-		transactionQueries[i].Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+		if transactionSchema.Table != nil {
+			transactionQueries[i].Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+		}
 	}
 
 	totalPage := pagination.TotalPage(count, int64(req.PerPage))
@@ -177,6 +211,24 @@ func (r *transactionRepository) GetAllReadyToServeTransactionList(ctx context.Co
 	}, nil
 }
 
+func (r *transactionRepository) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchema schema.Transaction
+	return db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", id).
+		Take(&transactionSchema).Error
+}
+
 func (r *transactionRepository) GetDetailedTransactionByID(ctx context.Context, tx interface{}, id string) (transaction.Query, error) {
 	validatedTransaction, err := validation.ValidateTransaction(tx)
 	if err != nil {
@@ -195,6 +247,7 @@ func (r *transactionRepository) GetDetailedTransactionByID(ctx context.Context,
 	if err = query.Preload("Table").
 		Preload("Orders").
 		Preload("Orders.Menu").
+		Preload("Redemptions").
 		Take(&transactionSchema).Error; err != nil {
 		return transaction.Query{}, err
 	}
@@ -207,12 +260,16 @@ func (r *transactionRepository) GetDetailedTransactionByID(ctx context.Context,
 		})
 		transactionQuery.Orders[i].Menu = schema.MenuSchemaToEntity(*orderSchema.Menu)
 	}
-	transactionQuery.Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+	if transactionSchema.Table != nil {
+		transactionQuery.Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+	}
 
 	return transactionQuery, nil
 }
 
-func (r *transactionRepository) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+// GetLatestQueueCode holds a lock on the business day's queue until tx ends,
+// so two payments settled at once cannot be given the same code.
+func (r *transactionRepository) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	validatedTransaction, err := validation.ValidateTransaction(tx)
 	if err != nil {
 		return "", err
@@ -223,10 +280,13 @@ func (r *transactionRepository) GetLatestQueueCode(ctx context.Context, tx inter
 		db = r.db.DB()
 	}
 
+	if err = db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "queue_code:"+businessDay).Error; err != nil {
+		return "", err
+	}
+
 	var transactionSchema schema.Transaction
-	today := time.Now().Format("2006-01-02")
 	if err = db.WithContext(ctx).
-		Where("DATE(created_at) = ?", today).
+		Where("business_day = ?", businessDay).
 		Where("queue_code IS NOT NULL").
 		Where("id != ?", id).
 		Order("queue_code DESC").
@@ -259,12 +319,10 @@ func (r *transactionRepository) GetNextOrder(ctx context.Context, tx interface{}
 	}
 
 	var transactionSchema schema.Transaction
-	today := time.Now().Format("2006-01-02")
-
 	query := db.WithContext(ctx).Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture})
 
 	if err = query.Where("order_status = ?", "pending").
-		Where("DATE(created_at) = ?", today).
+		Scopes(r.currentOrInFlight, r.dueInKitchen).
 		Preload("Table").
 		Preload("Orders").
 		Preload("Orders.Menu").
@@ -281,7 +339,56 @@ func (r *transactionRepository) GetNextOrder(ctx context.Context, tx interface{}
 			Menu: response.MenuForTransaction{
 				ID:    orderSchema.Menu.ID.String(),
 				Name:  orderSchema.Menu.Name,
-				Price: orderSchema.Menu.Price.String(),
+				Price: schema.OrderUnitPrice(orderSchema).String(),
+			},
+			Quantity: orderSchema.Quantity,
+		})
+	}
+
+	return response.NextOrder{
+		QueueCode: *transactionSchema.QueueCode,
+		Orders:    orderResponses,
+	}, nil
+}
+
+func (r *transactionRepository) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return response.NextOrder{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchema schema.Transaction
+	query := db.WithContext(ctx).Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture})
+
+	if err = query.Where("order_status IN ?", []string{transaction.OrderStatusPending, transaction.OrderStatusPreparing}).
+		Scopes(r.currentOrInFlight, r.dueInKitchen).
+		Where("EXISTS (SELECT 1 FROM orders WHERE orders.transaction_id = transactions.id AND (orders.station_id = ? OR orders.station_id IS NULL) AND orders.cooking_status = ? AND orders.deleted_at IS NULL)", stationID, order.CookingStatusPending).
+		Preload("Orders", "(station_id = ? OR station_id IS NULL)", stationID).
+		Preload("Orders.Menu").
+		Preload("Orders.Station").
+		Order("created_at ASC").First(&transactionSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NextOrder{}, nil
+		}
+		return response.NextOrder{}, err
+	}
+
+	var stationName string
+	var orderResponses []response.OrderForTransaction
+	for _, orderSchema := range transactionSchema.Orders {
+		if orderSchema.Station != nil {
+			stationName = orderSchema.Station.Name
+		}
+		orderResponses = append(orderResponses, response.OrderForTransaction{
+			Menu: response.MenuForTransaction{
+				ID:    orderSchema.Menu.ID.String(),
+				Name:  orderSchema.Menu.Name,
+				Price: schema.OrderUnitPrice(orderSchema).String(),
 			},
 			Quantity: orderSchema.Quantity,
 		})
@@ -289,10 +396,263 @@ func (r *transactionRepository) GetNextOrder(ctx context.Context, tx interface{}
 
 	return response.NextOrder{
 		QueueCode: *transactionSchema.QueueCode,
+		Station:   stationName,
 		Orders:    orderResponses,
 	}, nil
 }
 
+func (r *transactionRepository) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+	query := db.WithContext(ctx).Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture}).
+		Scopes(r.currentOrInFlight, r.dueInKitchen)
+
+	if stationID == "" {
+		query = query.Where("order_status = ?", transaction.OrderStatusPending).
+			Preload("Orders")
+	} else {
+		query = query.Where("order_status IN ?", []string{transaction.OrderStatusPending, transaction.OrderStatusPreparing}).
+			Where("EXISTS (SELECT 1 FROM orders WHERE orders.transaction_id = transactions.id AND (orders.station_id = ? OR orders.station_id IS NULL) AND orders.cooking_status = ? AND orders.deleted_at IS NULL)", stationID, order.CookingStatusPending).
+			Preload("Orders", "(station_id = ? OR station_id IS NULL)", stationID)
+	}
+
+	if err = query.Preload("Table").
+		Preload("Orders.Menu").
+		Order("created_at ASC").
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	return transactionSchemasToQueries(transactionSchemas), nil
+}
+
+func (r *transactionRepository) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+	if err = db.WithContext(ctx).Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture}).
+		Where("order_status IN ?", []string{transaction.OrderStatusPending, transaction.OrderStatusPreparing}).
+		Scopes(r.currentOrInFlight, r.dueInKitchen).
+		Preload("Orders").
+		Preload("Orders.Menu").
+		Order("created_at ASC").
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	return transactionSchemasToQueries(transactionSchemas), nil
+}
+
+func (r *transactionRepository) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+
+	if err = db.WithContext(ctx).Where("cooked_at IS NOT NULL").
+		Where("served_at IS NOT NULL").
+		Preload("Orders").
+		Preload("Orders.Menu").
+		Order("served_at DESC").
+		Limit(limit).
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	return transactionSchemasToQueries(transactionSchemas), nil
+}
+
+func (r *transactionRepository) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+
+	if err = db.WithContext(ctx).Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture}).
+		Where("order_status IN ?", []string{transaction.OrderStatusPending, transaction.OrderStatusPreparing, transaction.OrderStatusReadyToServe}).
+		Scopes(r.currentOrInFlight).
+		Preload("Table").
+		Preload("Orders").
+		Preload("Orders.Menu").
+		Order("created_at ASC").
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	return transactionSchemasToQueries(transactionSchemas), nil
+}
+
+func (r *transactionRepository) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+
+	if err = db.WithContext(ctx).Where("payment_status IN ?", []string{transaction.PaymentStatusSettlement, transaction.PaymentStatusCapture}).
+		Where("order_status IN ?", []string{transaction.OrderStatusPending, transaction.OrderStatusPreparing, transaction.OrderStatusReadyToServe}).
+		Where("queue_code IS NOT NULL AND queue_code <> ''").
+		Scopes(r.currentOrInFlight, r.dueInKitchen).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	// The newest rows are fetched so a flood of stale orders cannot push the
+	// current ones off the board; the board itself reads them oldest first.
+	transactions := make([]transaction.Transaction, 0, len(transactionSchemas))
+	for i := len(transactionSchemas) - 1; i >= 0; i-- {
+		transactions = append(transactions, schema.TransactionSchemaToEntity(transactionSchemas[i]))
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+
+	if err = db.WithContext(ctx).Where("payment_status = ?", transaction.PaymentStatusPending).
+		Where("created_at < ?", createdBefore).
+		Where("payment_provider NOT IN ?", transaction.UntimedPaymentProviders()).
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	var expiredTransactions []transaction.Transaction
+	for _, transactionSchema := range transactionSchemas {
+		if !schema.TransactionSchemaToEntity(transactionSchema).IsPaymentOverdue(createdBefore) {
+			continue
+		}
+
+		// Only flip rows that are still pending so a webhook that settled the
+		// payment in the meantime wins.
+		result := db.WithContext(ctx).Model(&schema.Transaction{}).
+			Where("id = ? AND payment_status = ?", transactionSchema.ID, transaction.PaymentStatusPending).
+			Update("payment_status", transaction.PaymentStatusExpire)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		// Unpaid shares of a split bill lapse with their transaction.
+		if err = db.WithContext(ctx).Model(&schema.SplitPayment{}).
+			Where("transaction_id = ? AND payment_status = ?", transactionSchema.ID, transaction.PaymentStatusPending).
+			Update("payment_status", transaction.PaymentStatusExpire).Error; err != nil {
+			return nil, err
+		}
+
+		transactionSchema.PaymentStatus = transaction.PaymentStatusExpire
+		expiredTransactions = append(expiredTransactions, schema.TransactionSchemaToEntity(transactionSchema))
+	}
+
+	return expiredTransactions, nil
+}
+
+// GetUnsettledTransactions returns the transactions created after createdAfter
+// whose payment is not settled yet, including the ones the expiry sweeper
+// gave up on, since the gateway may still have taken the money.
+func (r *transactionRepository) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchemas []schema.Transaction
+
+	if err = db.WithContext(ctx).
+		Where("payment_status IN ?", []string{
+			transaction.PaymentStatusPending,
+			transaction.PaymentStatusCapture,
+			transaction.PaymentStatusExpire,
+		}).
+		Where("created_at >= ?", createdAfter).
+		Order("created_at ASC").
+		Find(&transactionSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	transactions := make([]transaction.Transaction, 0, len(transactionSchemas))
+	for _, transactionSchema := range transactionSchemas {
+		transactions = append(transactions, schema.TransactionSchemaToEntity(transactionSchema))
+	}
+
+	return transactions, nil
+}
+
+func transactionSchemasToQueries(transactionSchemas []schema.Transaction) []transaction.Query {
+	var transactionQueries []transaction.Query
+	for _, transactionSchema := range transactionSchemas {
+		var transactionQuery transaction.Query
+		transactionQuery.Transaction = schema.TransactionSchemaToEntity(transactionSchema)
+		for i, orderSchema := range transactionSchema.Orders {
+			transactionQuery.Orders = append(transactionQuery.Orders, transaction.OrderQuery{
+				Order: schema.OrderSchemaToEntity(orderSchema),
+			})
+			transactionQuery.Orders[i].Menu = schema.MenuSchemaToEntity(*orderSchema.Menu)
+		}
+		if transactionSchema.Table != nil {
+			transactionQuery.Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+		}
+		transactionQueries = append(transactionQueries, transactionQuery)
+	}
+	return transactionQueries
+}
+
 func (r *transactionRepository) GetTransactionByQueueCode(ctx context.Context, tx interface{}, queueCode string) (transaction.Query, error) {
 	validatedTransaction, err := validation.ValidateTransaction(tx)
 	if err != nil {
@@ -305,13 +665,15 @@ func (r *transactionRepository) GetTransactionByQueueCode(ctx context.Context, t
 	}
 
 	var transactionSchema schema.Transaction
-	today := time.Now().Format("2006-01-02")
-
+	// A code left in flight from the previous business day can be issued
+	// again today; the newer transaction wins and the older one stays
+	// reachable by its ID.
 	if err = db.WithContext(ctx).Where("queue_code = ?", queueCode).
-		Where("DATE(created_at) = ?", today).
+		Scopes(r.currentOrInFlight).
 		Preload("Table").
 		Preload("Orders").
 		Preload("Orders.Menu").
+		Order("created_at DESC").
 		First(&transactionSchema).Error; err != nil {
 		return transaction.Query{}, err
 	}
@@ -324,7 +686,45 @@ func (r *transactionRepository) GetTransactionByQueueCode(ctx context.Context, t
 		})
 		transactionQuery.Orders[i].Menu = schema.MenuSchemaToEntity(*orderSchema.Menu)
 	}
-	transactionQuery.Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+	if transactionSchema.Table != nil {
+		transactionQuery.Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+	}
+
+	return transactionQuery, nil
+}
+
+func (r *transactionRepository) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.Query{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchema schema.Transaction
+	if err = db.WithContext(ctx).Where("guest_token_hash = ?", tokenHash).
+		Preload("Table").
+		Preload("Orders").
+		Preload("Orders.Menu").
+		Preload("Redemptions").
+		Take(&transactionSchema).Error; err != nil {
+		return transaction.Query{}, err
+	}
+
+	var transactionQuery transaction.Query
+	transactionQuery.Transaction = schema.TransactionSchemaToEntity(transactionSchema)
+	for i, orderSchema := range transactionSchema.Orders {
+		transactionQuery.Orders = append(transactionQuery.Orders, transaction.OrderQuery{
+			Order: schema.OrderSchemaToEntity(orderSchema),
+		})
+		transactionQuery.Orders[i].Menu = schema.MenuSchemaToEntity(*orderSchema.Menu)
+	}
+	if transactionSchema.Table != nil {
+		transactionQuery.Table = schema.TableSchemaToEntity(*transactionSchema.Table)
+	}
 
 	return transactionQuery, nil
 }
@@ -342,7 +742,7 @@ func (r *transactionRepository) UpdateCookedAt(ctx context.Context, tx interface
 
 	var transactionSchema schema.Transaction
 
-	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Update("cooked_at", time.Now()).Error; err != nil {
+	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Update("cooked_at", r.clock.Now()).Error; err != nil {
 		return transaction.Transaction{}, err
 	}
 
@@ -384,7 +784,10 @@ func (r *transactionRepository) UpdateTransactionCookingStatusFinish(ctx context
 
 	var transactionSchema schema.Transaction
 
-	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Update("order_status", transaction.OrderStatusReadyToServe).Error; err != nil {
+	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Updates(map[string]interface{}{
+		"order_status": transaction.OrderStatusReadyToServe,
+		"ready_at":     r.clock.Now(),
+	}).Error; err != nil {
 		return transaction.Transaction{}, err
 	}
 
@@ -447,7 +850,32 @@ func (r *transactionRepository) UpdateServedAt(ctx context.Context, tx interface
 
 	var transactionSchema schema.Transaction
 
-	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Update("served_at", time.Now()).Error; err != nil {
+	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Update("served_at", r.clock.Now()).Error; err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	transactionEntity := schema.TransactionSchemaToEntity(transactionSchema)
+	return transactionEntity, nil
+}
+
+func (r *transactionRepository) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var transactionSchema schema.Transaction
+
+	if err = db.WithContext(ctx).Model(&transactionSchema).Where("id = ?", transactionID).Update("priority", priority).Error; err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	if err = db.WithContext(ctx).Where("id = ?", transactionID).Take(&transactionSchema).Error; err != nil {
 		return transaction.Transaction{}, err
 	}
 
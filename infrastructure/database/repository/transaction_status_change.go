@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+)
+
+type statusChangeRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewStatusChangeRepository(db *db_transaction.Repository) transaction.StatusChangeRepository {
+	return &statusChangeRepository{db: db}
+}
+
+func (r *statusChangeRepository) CreateStatusChange(ctx context.Context, tx interface{}, statusChange transaction.StatusChange) (transaction.StatusChange, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return transaction.StatusChange{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	statusChangeSchema := schema.TransactionStatusChangeEntityToSchema(statusChange)
+	if err = db.WithContext(ctx).Create(&statusChangeSchema).Error; err != nil {
+		return transaction.StatusChange{}, err
+	}
+
+	return schema.TransactionStatusChangeSchemaToEntity(statusChangeSchema), nil
+}
+
+func (r *statusChangeRepository) GetStatusChangesByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]transaction.StatusChange, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var statusChangeSchemas []schema.TransactionStatusChange
+	if err = db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at ASC").
+		Find(&statusChangeSchemas).Error; err != nil {
+		return nil, err
+	}
+
+	statusChanges := make([]transaction.StatusChange, 0, len(statusChangeSchemas))
+	for _, statusChangeSchema := range statusChangeSchemas {
+		statusChanges = append(statusChanges, schema.TransactionStatusChangeSchemaToEntity(statusChangeSchema))
+	}
+
+	return statusChanges, nil
+}
@@ -6,6 +6,8 @@ import (
 	"fp-kpl/infrastructure/database/db_transaction"
 	"fp-kpl/infrastructure/database/schema"
 	"fp-kpl/infrastructure/database/validation"
+
+	"gorm.io/gorm"
 )
 
 type userRepository struct {
@@ -95,3 +97,73 @@ func (r *userRepository) CheckEmail(ctx context.Context, tx interface{}, email s
 	userEntity := schema.UserSchemaToEntity(userSchema)
 	return userEntity, true, nil
 }
+
+func (r *userRepository) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).Model(&schema.User{}).Where("id = ?", userEntity.ID.ID).Updates(map[string]interface{}{
+		"name":              userEntity.Name,
+		"email":             userEntity.Email,
+		"email_verified_at": userEntity.EmailVerifiedAt,
+		"phone_number":      userEntity.PhoneNumber,
+		"phone_verified_at": userEntity.PhoneVerifiedAt,
+	})
+	if result.Error != nil {
+		return user.User{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.User{}, gorm.ErrRecordNotFound
+	}
+
+	return r.GetUserByID(ctx, tx, userEntity.ID.String())
+}
+
+func (r *userRepository) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	userSchema := schema.UserEntityToSchema(anonymised)
+	return db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&schema.User{}).Where("id = ?", userSchema.ID).Updates(map[string]interface{}{
+			"name":              userSchema.Name,
+			"email":             userSchema.Email,
+			"email_verified_at": nil,
+			"phone_number":      "",
+			"phone_verified_at": nil,
+			"password":          "",
+			"deleted_at":        userSchema.DeletedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := db.Model(&schema.Feedback{}).Where("user_id = ?", userSchema.ID).Update("comment", "").Error; err != nil {
+			return err
+		}
+		if err := db.Model(&schema.CartLine{}).Where("added_by = ?", userSchema.ID).Update("added_by", nil).Error; err != nil {
+			return err
+		}
+		if err := db.Where("user_id = ?", userSchema.ID).Delete(&schema.Cart{}).Error; err != nil {
+			return err
+		}
+		return db.Where("user_id = ?", userSchema.ID).Delete(&schema.FavouriteMenu{}).Error
+	})
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/domain/verification"
+	"fp-kpl/infrastructure/database/db_transaction"
+	"fp-kpl/infrastructure/database/schema"
+	"fp-kpl/infrastructure/database/validation"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type verificationRepository struct {
+	db *db_transaction.Repository
+}
+
+func NewVerificationRepository(db *db_transaction.Repository) verification.Repository {
+	return &verificationRepository{db: db}
+}
+
+func (r *verificationRepository) CreateChallenge(ctx context.Context, tx interface{}, challenge verification.Challenge) (verification.Challenge, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return verification.Challenge{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	challengeSchema := schema.VerificationChallengeEntityToSchema(challenge)
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&challengeSchema)
+	if result.Error != nil {
+		return verification.Challenge{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return verification.Challenge{}, verification.ErrorResendTooSoon
+	}
+
+	return schema.VerificationChallengeSchemaToEntity(challengeSchema), nil
+}
+
+func (r *verificationRepository) GetChallengeByID(ctx context.Context, tx interface{}, id string) (verification.Challenge, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return verification.Challenge{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var challengeSchema schema.VerificationChallenge
+	if err = db.WithContext(ctx).Where("id = ?", id).Take(&challengeSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return verification.Challenge{}, verification.ErrorChallengeNotFound
+		}
+		return verification.Challenge{}, err
+	}
+
+	return schema.VerificationChallengeSchemaToEntity(challengeSchema), nil
+}
+
+func (r *verificationRepository) GetLatestChallenge(ctx context.Context, tx interface{}, userID string, channel string) (verification.Challenge, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return verification.Challenge{}, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var challengeSchema schema.VerificationChallenge
+	if err = db.WithContext(ctx).
+		Where("user_id = ? AND channel = ?", userID, channel).
+		Order("created_at DESC").
+		Take(&challengeSchema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return verification.Challenge{}, verification.ErrorChallengeNotFound
+		}
+		return verification.Challenge{}, err
+	}
+
+	return schema.VerificationChallengeSchemaToEntity(challengeSchema), nil
+}
+
+func (r *verificationRepository) CountChallengesSince(ctx context.Context, tx interface{}, userID string, channel string, since time.Time) (int64, error) {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	var count int64
+	if err = db.WithContext(ctx).Model(&schema.VerificationChallenge{}).
+		Where("user_id = ? AND channel = ? AND created_at >= ?", userID, channel, since).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *verificationRepository) RecordAttempt(ctx context.Context, tx interface{}, id string, maxAttempts int) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	result := db.WithContext(ctx).Model(&schema.VerificationChallenge{}).
+		Where("id = ? AND verified_at IS NULL AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return verification.ErrorTooManyAttempts
+	}
+	return nil
+}
+
+func (r *verificationRepository) MarkVerified(ctx context.Context, tx interface{}, challenge verification.Challenge, at time.Time) error {
+	validatedTransaction, err := validation.ValidateTransaction(tx)
+	if err != nil {
+		return err
+	}
+
+	db := validatedTransaction.DB()
+	if db == nil {
+		db = r.db.DB()
+	}
+
+	destinationColumn, verifiedColumn := "email", "email_verified_at"
+	if challenge.Channel == verification.ChannelPhone {
+		destinationColumn, verifiedColumn = "phone_number", "phone_verified_at"
+	}
+
+	return db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&schema.VerificationChallenge{}).
+			Where("id = ? AND verified_at IS NULL", challenge.ID.ID).
+			Update("verified_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return verification.ErrorAlreadyVerified
+		}
+
+		result = db.Model(&schema.User{}).
+			Where("id = ? AND "+destinationColumn+" = ?", challenge.UserID.ID, challenge.Destination).
+			Update(verifiedColumn, at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return verification.ErrorCodeExpired
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"fp-kpl/domain/cart"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Cart struct {
+		ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		UserID        *uuid.UUID `gorm:"type:uuid;index;column:user_id"`
+		TableID       *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_carts_open_table,where:checked_out_at IS NULL AND abandoned_at IS NULL;column:table_id"`
+		OrderType     string     `gorm:"type:varchar(20);not null;default:'dine_in';column:order_type"`
+		VoucherCode   string     `gorm:"type:varchar(50);not null;default:'';column:voucher_code"`
+		ExpiresAt     time.Time  `gorm:"type:timestamp with time zone;index;not null;column:expires_at"`
+		CheckedOutAt  *time.Time `gorm:"type:timestamp with time zone;column:checked_out_at"`
+		TransactionID *uuid.UUID `gorm:"type:uuid;column:transaction_id"`
+		// AbandonedAt is set on an expired table cart when the table opens a
+		// new one, so it no longer counts as the table's open cart.
+		AbandonedAt *time.Time `gorm:"type:timestamp with time zone;column:abandoned_at"`
+		CreatedAt   time.Time  `gorm:"type:timestamp with time zone;column:created_at"`
+		UpdatedAt   time.Time  `gorm:"type:timestamp with time zone;column:updated_at"`
+
+		User    *User        `gorm:"foreignKey:UserID"`
+		Table   *Table       `gorm:"foreignKey:TableID"`
+		Lines   []CartLine   `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+		Members []CartMember `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	}
+
+	CartLine struct {
+		ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		CartID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_cart_lines_cart_menu;column:cart_id"`
+		MenuID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_cart_lines_cart_menu;column:menu_id"`
+		Quantity  int        `gorm:"type:int;not null;column:quantity"`
+		AddedBy   *uuid.UUID `gorm:"type:uuid;column:added_by"`
+		CreatedAt time.Time  `gorm:"type:timestamp with time zone;column:created_at"`
+	}
+
+	CartMember struct {
+		CartID    uuid.UUID `gorm:"type:uuid;primaryKey;column:cart_id"`
+		UserID    uuid.UUID `gorm:"type:uuid;primaryKey;column:user_id"`
+		CreatedAt time.Time `gorm:"type:timestamp with time zone;column:created_at"`
+	}
+)
+
+func CartEntityToSchema(entity cart.Cart) Cart {
+	return Cart{
+		ID:            entity.ID.ID,
+		UserID:        nullableID(entity.UserID),
+		TableID:       nullableID(entity.TableID),
+		OrderType:     entity.OrderType.Type,
+		VoucherCode:   entity.VoucherCode,
+		ExpiresAt:     entity.ExpiresAt,
+		CheckedOutAt:  entity.CheckedOutAt,
+		TransactionID: nullableID(entity.TransactionID),
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+}
+
+func CartSchemaToEntity(schema Cart) cart.Cart {
+	lines := make([]cart.Line, 0, len(schema.Lines))
+	for _, line := range schema.Lines {
+		lines = append(lines, CartLineSchemaToEntity(line))
+	}
+
+	memberIDs := make([]identity.ID, 0, len(schema.Members))
+	for _, member := range schema.Members {
+		memberIDs = append(memberIDs, identity.NewIDFromSchema(member.UserID))
+	}
+
+	return cart.Cart{
+		ID:            identity.NewIDFromSchema(schema.ID),
+		UserID:        idFromNullable(schema.UserID),
+		TableID:       idFromNullable(schema.TableID),
+		OrderType:     transaction.NewOrderTypeFromSchema(schema.OrderType),
+		VoucherCode:   schema.VoucherCode,
+		Lines:         lines,
+		MemberIDs:     memberIDs,
+		ExpiresAt:     schema.ExpiresAt,
+		CheckedOutAt:  schema.CheckedOutAt,
+		TransactionID: idFromNullable(schema.TransactionID),
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+		},
+	}
+}
+
+func CartLineEntityToSchema(entity cart.Line) CartLine {
+	return CartLine{
+		ID:       entity.ID.ID,
+		CartID:   entity.CartID.ID,
+		MenuID:   entity.MenuID.ID,
+		Quantity: entity.Quantity,
+		AddedBy:  nullableID(entity.AddedBy),
+	}
+}
+
+func CartLineSchemaToEntity(schema CartLine) cart.Line {
+	return cart.Line{
+		ID:       identity.NewIDFromSchema(schema.ID),
+		CartID:   identity.NewIDFromSchema(schema.CartID),
+		MenuID:   identity.NewIDFromSchema(schema.MenuID),
+		Quantity: schema.Quantity,
+		AddedBy:  idFromNullable(schema.AddedBy),
+	}
+}
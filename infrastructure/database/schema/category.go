@@ -13,11 +13,14 @@ import (
 type Category struct {
 	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
 	Name      string         `gorm:"type:varchar(255);unique;not null;column:name"`
+	StationID *uuid.UUID     `gorm:"type:uuid;column:station_id"`
 	CreatedAt time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
 	UpdatedAt time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
 
-	Menus []Menu `gorm:"foreignKey:CategoryID"`
+	Station   *Station       `gorm:"foreignKey:StationID"`
+	Menus     []Menu         `gorm:"foreignKey:CategoryID"`
+	Schedules []MenuSchedule `gorm:"foreignKey:CategoryID"`
 }
 
 func CategoryEntityToSchema(entity category.Category) Category {
@@ -30,6 +33,7 @@ func CategoryEntityToSchema(entity category.Category) Category {
 	return Category{
 		ID:        entity.ID.ID,
 		Name:      entity.Name,
+		StationID: nullableID(entity.StationID),
 		CreatedAt: entity.Timestamp.CreatedAt,
 		UpdatedAt: entity.Timestamp.UpdatedAt,
 		DeletedAt: gorm.DeletedAt{
@@ -41,8 +45,10 @@ func CategoryEntityToSchema(entity category.Category) Category {
 
 func CategorySchemaToEntity(schema Category) category.Category {
 	return category.Category{
-		ID:   identity.NewIDFromSchema(schema.ID),
-		Name: schema.Name,
+		ID:        identity.NewIDFromSchema(schema.ID),
+		Name:      schema.Name,
+		StationID: idFromNullable(schema.StationID),
+		Schedule:  MenuSchedulesToTimeWindows(schema.Schedules),
 		Timestamp: shared.Timestamp{
 			CreatedAt: schema.CreatedAt,
 			UpdatedAt: schema.UpdatedAt,
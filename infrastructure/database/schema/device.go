@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"fp-kpl/domain/device"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/user"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Device struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	Name       string         `gorm:"type:varchar(100);not null;column:name"`
+	Role       string         `gorm:"type:varchar(50);not null;column:role"`
+	StationID  *uuid.UUID     `gorm:"type:uuid;column:station_id"`
+	TableID    *uuid.UUID     `gorm:"type:uuid;column:table_id"`
+	KeyPrefix  string         `gorm:"type:varchar(20);not null;column:key_prefix"`
+	KeyHash    string         `gorm:"type:varchar(64);uniqueIndex;not null;column:key_hash"`
+	LastSeenAt *time.Time     `gorm:"type:timestamp with time zone;column:last_seen_at"`
+	RevokedAt  *time.Time     `gorm:"type:timestamp with time zone;column:revoked_at"`
+	CreatedAt  time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
+	UpdatedAt  time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
+
+	Station *Station `gorm:"foreignKey:StationID"`
+	Table   *Table   `gorm:"foreignKey:TableID"`
+}
+
+func DeviceEntityToSchema(entity device.Device) Device {
+	return Device{
+		ID:         entity.ID.ID,
+		Name:       entity.Name,
+		Role:       entity.Role.Name,
+		StationID:  nullableID(entity.StationID),
+		TableID:    nullableID(entity.TableID),
+		KeyPrefix:  entity.KeyPrefix,
+		KeyHash:    entity.KeyHash,
+		LastSeenAt: entity.LastSeenAt,
+		RevokedAt:  entity.RevokedAt,
+		CreatedAt:  entity.CreatedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}
+}
+
+func DeviceSchemaToEntity(schema Device) device.Device {
+	return device.Device{
+		ID:         identity.NewIDFromSchema(schema.ID),
+		Name:       schema.Name,
+		Role:       user.NewRoleFromSchema(schema.Role),
+		StationID:  idFromNullable(schema.StationID),
+		TableID:    idFromNullable(schema.TableID),
+		KeyPrefix:  schema.KeyPrefix,
+		KeyHash:    schema.KeyHash,
+		LastSeenAt: schema.LastSeenAt,
+		RevokedAt:  schema.RevokedAt,
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+			DeletedAt: &schema.DeletedAt.Time,
+		},
+	}
+}
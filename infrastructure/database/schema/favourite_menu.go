@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/menu/favourite"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FavouriteMenu struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;column:user_id"`
+	MenuID    uuid.UUID `gorm:"type:uuid;primaryKey;index;column:menu_id"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;column:created_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+	Menu *Menu `gorm:"foreignKey:MenuID"`
+}
+
+func FavouriteMenuEntityToSchema(entity favourite.Favourite) FavouriteMenu {
+	return FavouriteMenu{
+		UserID:    entity.UserID.ID,
+		MenuID:    entity.MenuID.ID,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+func FavouriteMenuSchemaToEntity(schema FavouriteMenu) favourite.Favourite {
+	return favourite.Favourite{
+		UserID:    identity.NewIDFromSchema(schema.UserID),
+		MenuID:    identity.NewIDFromSchema(schema.MenuID),
+		CreatedAt: schema.CreatedAt,
+	}
+}
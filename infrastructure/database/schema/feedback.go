@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Feedback struct {
+		ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		TransactionID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null;column:transaction_id"`
+		UserID        uuid.UUID `gorm:"type:uuid;index;not null;column:user_id"`
+		Rating        int       `gorm:"type:smallint;index;not null;column:rating"`
+		Comment       string    `gorm:"type:text;not null;default:'';column:comment"`
+		CreatedAt     time.Time `gorm:"type:timestamp with time zone;index;column:created_at"`
+		UpdatedAt     time.Time `gorm:"type:timestamp with time zone;column:updated_at"`
+
+		Transaction *Transaction         `gorm:"foreignKey:TransactionID"`
+		User        *User                `gorm:"foreignKey:UserID"`
+		MenuRatings []FeedbackMenuRating `gorm:"foreignKey:FeedbackID;constraint:OnDelete:CASCADE"`
+	}
+
+	FeedbackMenuRating struct {
+		FeedbackID uuid.UUID `gorm:"type:uuid;primaryKey;column:feedback_id"`
+		MenuID     uuid.UUID `gorm:"type:uuid;primaryKey;index;column:menu_id"`
+		Rating     int       `gorm:"type:smallint;not null;column:rating"`
+
+		Menu *Menu `gorm:"foreignKey:MenuID"`
+	}
+)
+
+func FeedbackEntityToSchema(entity feedback.Feedback) Feedback {
+	menuRatings := make([]FeedbackMenuRating, 0, len(entity.MenuRatings))
+	for _, menuRating := range entity.MenuRatings {
+		menuRatings = append(menuRatings, FeedbackMenuRating{
+			FeedbackID: entity.ID.ID,
+			MenuID:     menuRating.MenuID.ID,
+			Rating:     menuRating.Rating.Value,
+		})
+	}
+
+	return Feedback{
+		ID:            entity.ID.ID,
+		TransactionID: entity.TransactionID.ID,
+		UserID:        entity.UserID.ID,
+		Rating:        entity.Rating.Value,
+		Comment:       entity.Comment,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+		MenuRatings:   menuRatings,
+	}
+}
+
+func FeedbackSchemaToEntity(schema Feedback) feedback.Feedback {
+	menuRatings := make([]feedback.MenuRating, 0, len(schema.MenuRatings))
+	for _, menuRating := range schema.MenuRatings {
+		menuRatings = append(menuRatings, feedback.MenuRating{
+			MenuID: identity.NewIDFromSchema(menuRating.MenuID),
+			Rating: feedback.NewRatingFromSchema(menuRating.Rating),
+		})
+	}
+
+	return feedback.Feedback{
+		ID:            identity.NewIDFromSchema(schema.ID),
+		TransactionID: identity.NewIDFromSchema(schema.TransactionID),
+		UserID:        identity.NewIDFromSchema(schema.UserID),
+		Rating:        feedback.NewRatingFromSchema(schema.Rating),
+		Comment:       schema.Comment,
+		MenuRatings:   menuRatings,
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+		},
+	}
+}
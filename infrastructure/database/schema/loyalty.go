@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/loyalty"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoyaltyEntry is one line of a points ledger. Sequence keeps the order the
+// entries were written in, which timestamps alone cannot when a sync writes
+// several at once. The partial unique index lets a transaction earn and
+// redeem at most once.
+type LoyaltyEntry struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	Sequence       int64      `gorm:"type:bigserial;autoIncrement;index;column:sequence"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index;column:user_id"`
+	TransactionID  *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_loyalty_entries_transaction_type,where:type IN ('earn','redeem');column:transaction_id"`
+	Type           string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_loyalty_entries_transaction_type;column:type"`
+	Points         int64      `gorm:"type:bigint;not null;column:points"`
+	ExpiresAt      *time.Time `gorm:"type:timestamp with time zone;index;column:expires_at"`
+	RelatedEntryID *uuid.UUID `gorm:"type:uuid;index;column:related_entry_id"`
+	CreatedAt      time.Time  `gorm:"type:timestamp with time zone;column:created_at"`
+
+	User        *User        `gorm:"foreignKey:UserID"`
+	Transaction *Transaction `gorm:"foreignKey:TransactionID"`
+}
+
+func LoyaltyEntryEntityToSchema(entity loyalty.Entry) LoyaltyEntry {
+	return LoyaltyEntry{
+		ID:             entity.ID.ID,
+		UserID:         entity.UserID.ID,
+		TransactionID:  nullableID(entity.TransactionID),
+		Type:           entity.Type,
+		Points:         entity.Points,
+		ExpiresAt:      entity.ExpiresAt,
+		RelatedEntryID: nullableID(entity.RelatedEntryID),
+		CreatedAt:      entity.CreatedAt,
+	}
+}
+
+func LoyaltyEntrySchemaToEntity(schema LoyaltyEntry) loyalty.Entry {
+	return loyalty.Entry{
+		ID:             identity.NewIDFromSchema(schema.ID),
+		UserID:         identity.NewIDFromSchema(schema.UserID),
+		TransactionID:  idFromNullable(schema.TransactionID),
+		Type:           schema.Type,
+		Points:         schema.Points,
+		ExpiresAt:      schema.ExpiresAt,
+		RelatedEntryID: idFromNullable(schema.RelatedEntryID),
+		CreatedAt:      schema.CreatedAt,
+	}
+}
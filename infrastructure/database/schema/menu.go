@@ -119,6 +119,7 @@ func (d *Duration) parseInterval(interval string) error {
 type Menu struct {
 	ID          uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
 	CategoryID  uuid.UUID       `gorm:"type:uuid;not null;column:category_id"`
+	StationID   *uuid.UUID      `gorm:"type:uuid;column:station_id"`
 	Name        string          `gorm:"type:varchar(255);uniqueIndex;not null;column:name"`
 	ImageURL    string          `gorm:"type:varchar(255);not null;column:image_url"`
 	Price       decimal.Decimal `gorm:"type:decimal(10,2);not null;column:price"`
@@ -129,8 +130,11 @@ type Menu struct {
 	UpdatedAt   time.Time       `gorm:"type:timestamp with time zone;not null;column:updated_at"`
 	DeletedAt   gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
 
-	Category *Category `gorm:"foreignKey:CategoryID"`
-	Orders   []Order   `gorm:"foreignKey:MenuID"`
+	Category       *Category           `gorm:"foreignKey:CategoryID"`
+	Station        *Station            `gorm:"foreignKey:StationID"`
+	Orders         []Order             `gorm:"foreignKey:MenuID"`
+	Schedules      []MenuSchedule      `gorm:"foreignKey:MenuID"`
+	PriceOverrides []MenuPriceOverride `gorm:"foreignKey:MenuID"`
 }
 
 func MenuEntityToSchema(entity menu.Menu) Menu {
@@ -156,19 +160,31 @@ func MenuEntityToSchema(entity menu.Menu) Menu {
 			Valid: entity.DeletedAt != nil,
 		},
 		CategoryID: entity.CategoryID.ID,
+		StationID:  nullableID(entity.StationID),
 	}
 }
 
+// MenuSchemaToEntity also maps the schedules and price overrides when they
+// were preloaded, the category's schedule included.
 func MenuSchemaToEntity(schema Menu) menu.Menu {
+	var categorySchedule []shared.TimeWindow
+	if schema.Category != nil {
+		categorySchedule = MenuSchedulesToTimeWindows(schema.Category.Schedules)
+	}
+
 	return menu.Menu{
-		ID:          identity.NewIDFromSchema(schema.ID),
-		CategoryID:  identity.NewIDFromSchema(schema.CategoryID),
-		Name:        schema.Name,
-		ImageURL:    shared.NewURLFromSchema(schema.ImageURL),
-		Price:       shared.NewPriceFromSchema(schema.Price),
-		IsAvailable: schema.IsAvailable,
-		CookingTime: schema.CookingTime.Duration,
-		Description: schema.Description,
+		ID:               identity.NewIDFromSchema(schema.ID),
+		CategoryID:       identity.NewIDFromSchema(schema.CategoryID),
+		StationID:        idFromNullable(schema.StationID),
+		Name:             schema.Name,
+		ImageURL:         shared.NewURLFromSchema(schema.ImageURL),
+		Price:            shared.NewPriceFromSchema(schema.Price),
+		IsAvailable:      schema.IsAvailable,
+		CookingTime:      schema.CookingTime.Duration,
+		Description:      schema.Description,
+		Schedule:         MenuSchedulesToTimeWindows(schema.Schedules),
+		CategorySchedule: categorySchedule,
+		PriceOverrides:   PriceOverrideSchemasToEntities(schema.PriceOverrides),
 		Timestamp: shared.Timestamp{
 			CreatedAt: schema.CreatedAt,
 			UpdatedAt: schema.UpdatedAt,
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/shared"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	// MenuSchedule is one availability window of either a menu or a
+	// category.
+	MenuSchedule struct {
+		ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		MenuID      *uuid.UUID `gorm:"type:uuid;index;column:menu_id"`
+		CategoryID  *uuid.UUID `gorm:"type:uuid;index;column:category_id"`
+		Days        string     `gorm:"type:varchar(20);not null;default:'';column:days"`
+		StartMinute int        `gorm:"type:int;not null;default:0;column:start_minute"`
+		EndMinute   int        `gorm:"type:int;not null;default:0;column:end_minute"`
+	}
+
+	MenuPriceOverride struct {
+		ID          uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		MenuID      uuid.UUID       `gorm:"type:uuid;not null;index;column:menu_id"`
+		Name        string          `gorm:"type:varchar(255);not null;column:name"`
+		Price       decimal.Decimal `gorm:"type:decimal(10,2);not null;column:price"`
+		Days        string          `gorm:"type:varchar(20);not null;default:'';column:days"`
+		StartMinute int             `gorm:"type:int;not null;default:0;column:start_minute"`
+		EndMinute   int             `gorm:"type:int;not null;default:0;column:end_minute"`
+	}
+)
+
+func TimeWindowsToMenuSchedules(menuID *uuid.UUID, categoryID *uuid.UUID, windows []shared.TimeWindow) []MenuSchedule {
+	schedules := make([]MenuSchedule, 0, len(windows))
+	for _, window := range windows {
+		schedules = append(schedules, MenuSchedule{
+			MenuID:      menuID,
+			CategoryID:  categoryID,
+			Days:        encodeDays(window.Days),
+			StartMinute: window.StartMinute,
+			EndMinute:   window.EndMinute,
+		})
+	}
+	return schedules
+}
+
+func MenuSchedulesToTimeWindows(schedules []MenuSchedule) []shared.TimeWindow {
+	windows := make([]shared.TimeWindow, 0, len(schedules))
+	for _, schedule := range schedules {
+		windows = append(windows, shared.TimeWindow{
+			Days:        decodeDays(schedule.Days),
+			StartMinute: schedule.StartMinute,
+			EndMinute:   schedule.EndMinute,
+		})
+	}
+	return windows
+}
+
+func PriceOverridesToSchemas(menuID uuid.UUID, overrides []menu.PriceOverride) []MenuPriceOverride {
+	schemas := make([]MenuPriceOverride, 0, len(overrides))
+	for _, override := range overrides {
+		schemas = append(schemas, MenuPriceOverride{
+			MenuID:      menuID,
+			Name:        override.Name,
+			Price:       override.Price.Price,
+			Days:        encodeDays(override.Window.Days),
+			StartMinute: override.Window.StartMinute,
+			EndMinute:   override.Window.EndMinute,
+		})
+	}
+	return schemas
+}
+
+func PriceOverrideSchemasToEntities(schemas []MenuPriceOverride) []menu.PriceOverride {
+	overrides := make([]menu.PriceOverride, 0, len(schemas))
+	for _, override := range schemas {
+		overrides = append(overrides, menu.PriceOverride{
+			Name:  override.Name,
+			Price: shared.NewPriceFromSchema(override.Price),
+			Window: shared.TimeWindow{
+				Days:        decodeDays(override.Days),
+				StartMinute: override.StartMinute,
+				EndMinute:   override.EndMinute,
+			},
+		})
+	}
+	return overrides
+}
+
+// encodeDays stores weekdays as a comma separated list, Sunday being 0.
+func encodeDays(days []time.Weekday) string {
+	encoded := make([]string, 0, len(days))
+	for _, day := range days {
+		encoded = append(encoded, strconv.Itoa(int(day)))
+	}
+	return strings.Join(encoded, ",")
+}
+
+func decodeDays(encoded string) []time.Weekday {
+	var days []time.Weekday
+	for _, day := range strings.Split(encoded, ",") {
+		if weekday, err := strconv.Atoi(day); err == nil {
+			days = append(days, time.Weekday(weekday))
+		}
+	}
+	return days
+}
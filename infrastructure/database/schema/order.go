@@ -7,20 +7,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
 type Order struct {
-	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
-	TransactionID uuid.UUID      `gorm:"type:uuid;not null;column:transaction_id"`
-	MenuID        uuid.UUID      `gorm:"type:uuid;not null;column:menu_id"`
-	Quantity      int            `gorm:"type:int;not null;column:quantity"`
-	CreatedAt     time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
-	UpdatedAt     time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	TransactionID uuid.UUID       `gorm:"type:uuid;not null;column:transaction_id"`
+	MenuID        uuid.UUID       `gorm:"type:uuid;not null;column:menu_id"`
+	StationID     *uuid.UUID      `gorm:"type:uuid;column:station_id"`
+	Quantity      int             `gorm:"type:int;not null;column:quantity"`
+	Price         decimal.Decimal `gorm:"type:decimal(10,2);not null;default:0;column:price"`
+	CookingStatus string          `gorm:"type:varchar(255);not null;default:'pending';column:cooking_status"`
+	CreatedAt     time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+	UpdatedAt     time.Time       `gorm:"type:timestamp with time zone;column:updated_at"`
+	DeletedAt     gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
 
 	Transaction *Transaction `gorm:"foreignKey:TransactionID"`
 	Menu        *Menu        `gorm:"foreignKey:MenuID"`
+	Station     *Station     `gorm:"foreignKey:StationID"`
 }
 
 func OrderEntityToSchema(entity order.Order) Order {
@@ -35,7 +40,10 @@ func OrderEntityToSchema(entity order.Order) Order {
 		ID:            entity.ID.ID,
 		TransactionID: entity.TransactionID.ID,
 		MenuID:        entity.MenuID.ID,
+		StationID:     nullableID(entity.StationID),
 		Quantity:      entity.Quantity,
+		Price:         entity.Price.Price,
+		CookingStatus: entity.CookingStatus.Status,
 		CreatedAt:     entity.Timestamp.CreatedAt,
 		UpdatedAt:     entity.Timestamp.UpdatedAt,
 		DeletedAt: gorm.DeletedAt{
@@ -50,7 +58,10 @@ func OrderSchemaToEntity(schema Order) order.Order {
 		ID:            identity.NewIDFromSchema(schema.ID),
 		TransactionID: identity.NewIDFromSchema(schema.TransactionID),
 		MenuID:        identity.NewIDFromSchema(schema.MenuID),
+		StationID:     idFromNullable(schema.StationID),
 		Quantity:      schema.Quantity,
+		Price:         shared.NewPriceFromSchema(schema.Price),
+		CookingStatus: order.NewCookingStatusFromSchema(schema.CookingStatus),
 		Timestamp: shared.Timestamp{
 			CreatedAt: schema.CreatedAt,
 			UpdatedAt: schema.UpdatedAt,
@@ -58,3 +69,12 @@ func OrderSchemaToEntity(schema Order) order.Order {
 		},
 	}
 }
+
+// OrderUnitPrice is the price the line was charged at. Lines ordered before
+// prices were stored on the order fall back to the menu price.
+func OrderUnitPrice(schema Order) decimal.Decimal {
+	if schema.Price.IsPositive() || schema.Menu == nil {
+		return schema.Price
+	}
+	return schema.Menu.Price
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/shared"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type (
+	Promotion struct {
+		ID           uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		Name         string          `gorm:"type:varchar(255);not null;column:name"`
+		Type         string          `gorm:"type:varchar(50);not null;column:type"`
+		Value        decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:value"`
+		BuyQuantity  int             `gorm:"type:int;not null;default:0;column:buy_quantity"`
+		FreeQuantity int             `gorm:"type:int;not null;default:0;column:free_quantity"`
+		CategoryID   *uuid.UUID      `gorm:"type:uuid;column:category_id"`
+		MenuID       *uuid.UUID      `gorm:"type:uuid;column:menu_id"`
+		MinimumSpend decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:minimum_spend"`
+		StartsAt     *time.Time      `gorm:"type:timestamp with time zone;column:starts_at"`
+		EndsAt       *time.Time      `gorm:"type:timestamp with time zone;column:ends_at"`
+		Days         string          `gorm:"type:varchar(20);not null;default:'';column:days"`
+		StartMinute  int             `gorm:"type:int;not null;default:0;column:start_minute"`
+		EndMinute    int             `gorm:"type:int;not null;default:0;column:end_minute"`
+		VoucherCode  *string         `gorm:"type:varchar(50);uniqueIndex;column:voucher_code"`
+		UsageLimit   int             `gorm:"type:int;not null;default:0;column:usage_limit"`
+		PerUserLimit int             `gorm:"type:int;not null;default:0;column:per_user_limit"`
+		IsActive     bool            `gorm:"type:boolean;not null;default:true;column:is_active"`
+		CreatedAt    time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+		UpdatedAt    time.Time       `gorm:"type:timestamp with time zone;column:updated_at"`
+		DeletedAt    gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
+
+		Category *Category `gorm:"foreignKey:CategoryID"`
+		Menu     *Menu     `gorm:"foreignKey:MenuID"`
+	}
+
+	// PromotionRedemption is a discount applied to a transaction. It keeps
+	// the promotion's name and code as they were, and counts towards the
+	// promotion's usage limits.
+	PromotionRedemption struct {
+		ID            uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		PromotionID   uuid.UUID       `gorm:"type:uuid;not null;index;column:promotion_id"`
+		TransactionID uuid.UUID       `gorm:"type:uuid;not null;index;column:transaction_id"`
+		UserID        *uuid.UUID      `gorm:"type:uuid;index;column:user_id"`
+		Name          string          `gorm:"type:varchar(255);not null;column:name"`
+		VoucherCode   string          `gorm:"type:varchar(50);not null;default:'';column:voucher_code"`
+		Amount        decimal.Decimal `gorm:"type:decimal(12,2);not null;column:amount"`
+		CreatedAt     time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+
+		Promotion   *Promotion   `gorm:"foreignKey:PromotionID"`
+		Transaction *Transaction `gorm:"foreignKey:TransactionID"`
+	}
+)
+
+func PromotionEntityToSchema(entity promotion.Promotion) Promotion {
+	var voucherCode *string
+	if entity.VoucherCode != "" {
+		voucherCode = &entity.VoucherCode
+	}
+
+	return Promotion{
+		ID:           entity.ID.ID,
+		Name:         entity.Name,
+		Type:         entity.Type.Type,
+		Value:        entity.Value,
+		BuyQuantity:  entity.BuyQuantity,
+		FreeQuantity: entity.FreeQuantity,
+		CategoryID:   nullableID(entity.CategoryID),
+		MenuID:       nullableID(entity.MenuID),
+		MinimumSpend: entity.MinimumSpend.Price,
+		StartsAt:     entity.Schedule.StartsAt,
+		EndsAt:       entity.Schedule.EndsAt,
+		Days:         encodeDays(entity.Schedule.Window.Days),
+		StartMinute:  entity.Schedule.Window.StartMinute,
+		EndMinute:    entity.Schedule.Window.EndMinute,
+		VoucherCode:  voucherCode,
+		UsageLimit:   entity.UsageLimit,
+		PerUserLimit: entity.PerUserLimit,
+		IsActive:     entity.IsActive,
+		CreatedAt:    entity.CreatedAt,
+		UpdatedAt:    entity.UpdatedAt,
+	}
+}
+
+func PromotionSchemaToEntity(schema Promotion) promotion.Promotion {
+	var voucherCode string
+	if schema.VoucherCode != nil {
+		voucherCode = *schema.VoucherCode
+	}
+
+	return promotion.Promotion{
+		ID:           identity.NewIDFromSchema(schema.ID),
+		Name:         schema.Name,
+		Type:         promotion.NewTypeFromSchema(schema.Type),
+		Value:        schema.Value,
+		BuyQuantity:  schema.BuyQuantity,
+		FreeQuantity: schema.FreeQuantity,
+		CategoryID:   idFromNullable(schema.CategoryID),
+		MenuID:       idFromNullable(schema.MenuID),
+		MinimumSpend: shared.NewPriceFromSchema(schema.MinimumSpend),
+		Schedule: promotion.Schedule{
+			StartsAt: schema.StartsAt,
+			EndsAt:   schema.EndsAt,
+			Window: shared.TimeWindow{
+				Days:        decodeDays(schema.Days),
+				StartMinute: schema.StartMinute,
+				EndMinute:   schema.EndMinute,
+			},
+		},
+		VoucherCode:  voucherCode,
+		UsageLimit:   schema.UsageLimit,
+		PerUserLimit: schema.PerUserLimit,
+		IsActive:     schema.IsActive,
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+			DeletedAt: &schema.DeletedAt.Time,
+		},
+	}
+}
+
+// DiscountsToRedemptionSchemas records the discounts of a transaction
+// against the user who placed it, or no one for guest transactions.
+func DiscountsToRedemptionSchemas(userID *uuid.UUID, discounts []order.Discount) []PromotionRedemption {
+	redemptions := make([]PromotionRedemption, 0, len(discounts))
+	for _, discount := range discounts {
+		redemptions = append(redemptions, PromotionRedemption{
+			PromotionID: discount.PromotionID.ID,
+			UserID:      userID,
+			Name:        discount.Name,
+			VoucherCode: discount.VoucherCode,
+			Amount:      discount.Amount.Price,
+		})
+	}
+	return redemptions
+}
+
+func RedemptionSchemasToDiscounts(schemas []PromotionRedemption) []order.Discount {
+	discounts := make([]order.Discount, 0, len(schemas))
+	for _, redemption := range schemas {
+		discounts = append(discounts, order.Discount{
+			PromotionID: identity.NewIDFromSchema(redemption.PromotionID),
+			Name:        redemption.Name,
+			VoucherCode: redemption.VoucherCode,
+			Amount:      shared.NewPriceFromSchema(redemption.Amount),
+		})
+	}
+	return discounts
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/shift"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type (
+	Shift struct {
+		ID           uuid.UUID        `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		CashierID    uuid.UUID        `gorm:"type:uuid;not null;index;column:cashier_id"`
+		OpeningFloat decimal.Decimal  `gorm:"type:decimal(12,2);not null;default:0;column:opening_float"`
+		CountedCash  *decimal.Decimal `gorm:"type:decimal(12,2);column:counted_cash"`
+		OpenedAt     time.Time        `gorm:"type:timestamp with time zone;not null;column:opened_at"`
+		ClosedAt     *time.Time       `gorm:"type:timestamp with time zone;column:closed_at"`
+		CreatedAt    time.Time        `gorm:"type:timestamp with time zone;column:created_at"`
+		UpdatedAt    time.Time        `gorm:"type:timestamp with time zone;column:updated_at"`
+		DeletedAt    gorm.DeletedAt   `gorm:"type:timestamp with time zone;column:deleted_at"`
+
+		Cashier         *User            `gorm:"foreignKey:CashierID"`
+		CounterPayments []CounterPayment `gorm:"foreignKey:ShiftID"`
+	}
+
+	CounterPayment struct {
+		ID             uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		TransactionID  uuid.UUID       `gorm:"type:uuid;not null;index;column:transaction_id"`
+		ShiftID        uuid.UUID       `gorm:"type:uuid;not null;index;column:shift_id"`
+		CashierID      uuid.UUID       `gorm:"type:uuid;not null;column:cashier_id"`
+		Method         string          `gorm:"type:varchar(50);not null;column:method"`
+		Amount         decimal.Decimal `gorm:"type:decimal(12,2);not null;column:amount"`
+		AmountTendered decimal.Decimal `gorm:"type:decimal(12,2);not null;column:amount_tendered"`
+		Change         decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:change"`
+		CreatedAt      time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+		UpdatedAt      time.Time       `gorm:"type:timestamp with time zone;column:updated_at"`
+		DeletedAt      gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
+
+		Transaction *Transaction `gorm:"foreignKey:TransactionID"`
+	}
+)
+
+func ShiftEntityToSchema(entity shift.Shift) Shift {
+	var countedCash *decimal.Decimal
+	if entity.CountedCash != nil {
+		countedCash = &entity.CountedCash.Price
+	}
+	return Shift{
+		ID:           entity.ID.ID,
+		CashierID:    entity.CashierID.ID,
+		OpeningFloat: entity.OpeningFloat.Price,
+		CountedCash:  countedCash,
+		OpenedAt:     entity.OpenedAt,
+		ClosedAt:     entity.ClosedAt,
+		CreatedAt:    entity.CreatedAt,
+		UpdatedAt:    entity.UpdatedAt,
+	}
+}
+
+func ShiftSchemaToEntity(schema Shift) shift.Shift {
+	var countedCash *shared.Price
+	if schema.CountedCash != nil {
+		price := shared.NewPriceFromSchema(*schema.CountedCash)
+		countedCash = &price
+	}
+	return shift.Shift{
+		ID:           identity.NewIDFromSchema(schema.ID),
+		CashierID:    identity.NewIDFromSchema(schema.CashierID),
+		OpeningFloat: shared.NewPriceFromSchema(schema.OpeningFloat),
+		CountedCash:  countedCash,
+		OpenedAt:     schema.OpenedAt,
+		ClosedAt:     schema.ClosedAt,
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+			DeletedAt: &schema.DeletedAt.Time,
+		},
+	}
+}
+
+func CounterPaymentEntityToSchema(entity shift.CounterPayment) CounterPayment {
+	return CounterPayment{
+		ID:             entity.ID.ID,
+		TransactionID:  entity.TransactionID.ID,
+		ShiftID:        entity.ShiftID.ID,
+		CashierID:      entity.CashierID.ID,
+		Method:         entity.Method.Method,
+		Amount:         entity.Amount.Price,
+		AmountTendered: entity.AmountTendered.Price,
+		Change:         entity.Change.Price,
+		CreatedAt:      entity.CreatedAt,
+		UpdatedAt:      entity.UpdatedAt,
+	}
+}
+
+func CounterPaymentSchemaToEntity(schema CounterPayment) shift.CounterPayment {
+	return shift.CounterPayment{
+		ID:             identity.NewIDFromSchema(schema.ID),
+		TransactionID:  identity.NewIDFromSchema(schema.TransactionID),
+		ShiftID:        identity.NewIDFromSchema(schema.ShiftID),
+		CashierID:      identity.NewIDFromSchema(schema.CashierID),
+		Method:         shift.NewMethodFromSchema(schema.Method),
+		Amount:         shared.NewPriceFromSchema(schema.Amount),
+		AmountTendered: shared.NewPriceFromSchema(schema.AmountTendered),
+		Change:         shared.NewPriceFromSchema(schema.Change),
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+			DeletedAt: &schema.DeletedAt.Time,
+		},
+	}
+}
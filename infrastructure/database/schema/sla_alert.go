@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"fp-kpl/domain/sla"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLAAlert records a breach that has been escalated. A transaction is alerted
+// at most once per breach type.
+type SLAAlert struct {
+	TransactionID uuid.UUID `gorm:"type:uuid;primaryKey;column:transaction_id"`
+	Type          string    `gorm:"type:varchar(50);primaryKey;column:type"`
+	Deadline      time.Time `gorm:"type:timestamp with time zone;column:deadline"`
+	AlertedAt     time.Time `gorm:"type:timestamp with time zone;column:alerted_at"`
+
+	Transaction *Transaction `gorm:"foreignKey:TransactionID"`
+}
+
+func SLAAlertEntityToSchema(breach sla.Breach) SLAAlert {
+	return SLAAlert{
+		TransactionID: breach.TransactionID.ID,
+		Type:          breach.Type.Type,
+		Deadline:      breach.Deadline,
+		AlertedAt:     breach.DetectedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type (
+	SplitPayment struct {
+		ID             uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		TransactionID  uuid.UUID       `gorm:"type:uuid;not null;index;column:transaction_id"`
+		Provider       string          `gorm:"type:varchar(50);not null;column:provider"`
+		Amount         decimal.Decimal `gorm:"type:decimal(12,2);not null;column:amount"`
+		PaymentCode    string          `gorm:"type:varchar(255);not null;column:payment_code"`
+		PaymentStatus  string          `gorm:"type:varchar(255);not null;column:payment_status"`
+		RefundedAmount decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:refunded_amount"`
+		PaidAt         *time.Time      `gorm:"type:timestamp with time zone;column:paid_at"`
+		CreatedAt      time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+		UpdatedAt      time.Time       `gorm:"type:timestamp with time zone;column:updated_at"`
+		DeletedAt      gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
+
+		Transaction *Transaction        `gorm:"foreignKey:TransactionID"`
+		Orders      []SplitPaymentOrder `gorm:"foreignKey:SplitPaymentID"`
+	}
+
+	// SplitPaymentOrder links a share to the order lines it pays for when
+	// the bill was split by lines.
+	SplitPaymentOrder struct {
+		SplitPaymentID uuid.UUID `gorm:"type:uuid;primaryKey;column:split_payment_id"`
+		OrderID        uuid.UUID `gorm:"type:uuid;primaryKey;column:order_id"`
+	}
+
+	Refund struct {
+		ID             uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+		SplitPaymentID uuid.UUID       `gorm:"type:uuid;not null;index;column:split_payment_id"`
+		TransactionID  uuid.UUID       `gorm:"type:uuid;not null;index;column:transaction_id"`
+		Amount         decimal.Decimal `gorm:"type:decimal(12,2);not null;column:amount"`
+		Reason         string          `gorm:"type:text;column:reason"`
+		CreatedAt      time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+
+		SplitPayment *SplitPayment `gorm:"foreignKey:SplitPaymentID"`
+	}
+)
+
+func SplitPaymentEntityToSchema(entity transaction.SplitPayment) SplitPayment {
+	orders := make([]SplitPaymentOrder, 0, len(entity.OrderIDs))
+	for _, orderID := range entity.OrderIDs {
+		orders = append(orders, SplitPaymentOrder{
+			SplitPaymentID: entity.ID.ID,
+			OrderID:        orderID.ID,
+		})
+	}
+	return SplitPayment{
+		ID:             entity.ID.ID,
+		TransactionID:  entity.TransactionID.ID,
+		Provider:       entity.Provider,
+		Amount:         entity.Amount.Price,
+		PaymentCode:    entity.Payment.Code,
+		PaymentStatus:  entity.Payment.Status,
+		RefundedAmount: entity.RefundedAmount.Price,
+		PaidAt:         entity.PaidAt,
+		CreatedAt:      entity.CreatedAt,
+		UpdatedAt:      entity.UpdatedAt,
+		Orders:         orders,
+	}
+}
+
+func SplitPaymentSchemaToEntity(schema SplitPayment) transaction.SplitPayment {
+	orderIDs := make([]identity.ID, 0, len(schema.Orders))
+	for _, orderSchema := range schema.Orders {
+		orderIDs = append(orderIDs, identity.NewIDFromSchema(orderSchema.OrderID))
+	}
+	return transaction.SplitPayment{
+		ID:             identity.NewIDFromSchema(schema.ID),
+		TransactionID:  identity.NewIDFromSchema(schema.TransactionID),
+		Provider:       schema.Provider,
+		Amount:         shared.NewPriceFromSchema(schema.Amount),
+		OrderIDs:       orderIDs,
+		Payment:        transaction.NewPaymentFromSchema(schema.PaymentCode, schema.PaymentStatus),
+		RefundedAmount: shared.NewPriceFromSchema(schema.RefundedAmount),
+		PaidAt:         schema.PaidAt,
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+			DeletedAt: &schema.DeletedAt.Time,
+		},
+	}
+}
+
+func RefundEntityToSchema(entity transaction.Refund) Refund {
+	return Refund{
+		ID:             entity.ID.ID,
+		SplitPaymentID: entity.SplitPaymentID.ID,
+		TransactionID:  entity.TransactionID.ID,
+		Amount:         entity.Amount.Price,
+		Reason:         entity.Reason,
+		CreatedAt:      entity.CreatedAt,
+	}
+}
+
+func RefundSchemaToEntity(schema Refund) transaction.Refund {
+	return transaction.Refund{
+		ID:             identity.NewIDFromSchema(schema.ID),
+		SplitPaymentID: identity.NewIDFromSchema(schema.SplitPaymentID),
+		TransactionID:  identity.NewIDFromSchema(schema.TransactionID),
+		Amount:         shared.NewPriceFromSchema(schema.Amount),
+		Reason:         schema.Reason,
+		CreatedAt:      schema.CreatedAt,
+	}
+}
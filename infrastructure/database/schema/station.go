@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/station"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Station struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	Name      string         `gorm:"type:varchar(255);unique;not null;column:name"`
+	CreatedAt time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
+	UpdatedAt time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
+
+	Categories []Category `gorm:"foreignKey:StationID"`
+	Menus      []Menu     `gorm:"foreignKey:StationID"`
+}
+
+func StationEntityToSchema(entity station.Station) Station {
+	var deletedAtTime time.Time
+	if entity.DeletedAt != nil {
+		deletedAtTime = *entity.DeletedAt
+	} else {
+		deletedAtTime = time.Time{}
+	}
+	return Station{
+		ID:        entity.ID.ID,
+		Name:      entity.Name,
+		CreatedAt: entity.Timestamp.CreatedAt,
+		UpdatedAt: entity.Timestamp.UpdatedAt,
+		DeletedAt: gorm.DeletedAt{
+			Time:  deletedAtTime,
+			Valid: entity.DeletedAt != nil,
+		},
+	}
+}
+
+func StationSchemaToEntity(schema Station) station.Station {
+	return station.Station{
+		ID:   identity.NewIDFromSchema(schema.ID),
+		Name: schema.Name,
+		Timestamp: shared.Timestamp{
+			CreatedAt: schema.CreatedAt,
+			UpdatedAt: schema.UpdatedAt,
+			DeletedAt: &schema.DeletedAt.Time,
+		},
+	}
+}
+
+func nullableID(id identity.ID) *uuid.UUID {
+	if id.IsEmpty() {
+		return nil
+	}
+	return &id.ID
+}
+
+func idFromNullable(id *uuid.UUID) identity.ID {
+	if id == nil {
+		return identity.ID{}
+	}
+	return identity.NewIDFromSchema(*id)
+}
@@ -13,6 +13,7 @@ import (
 type Table struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
 	TableNumber string         `gorm:"type:varchar(255);unique;not null;column:table_number"`
+	Token       uuid.UUID      `gorm:"type:uuid;uniqueIndex;not null;default:uuid_generate_v4();column:token"`
 	CreatedAt   time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
 	UpdatedAt   time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
@@ -30,6 +31,7 @@ func TableEntityToSchema(entity table.Table) Table {
 	return Table{
 		ID:          entity.ID.ID,
 		TableNumber: entity.TableNumber,
+		Token:       entity.Token.ID,
 		CreatedAt:   entity.Timestamp.CreatedAt,
 		UpdatedAt:   entity.Timestamp.UpdatedAt,
 		DeletedAt: gorm.DeletedAt{
@@ -43,6 +45,7 @@ func TableSchemaToEntity(schema Table) table.Table {
 	return table.Table{
 		ID:          identity.NewIDFromSchema(schema.ID),
 		TableNumber: schema.TableNumber,
+		Token:       identity.NewIDFromSchema(schema.Token),
 		Timestamp: shared.Timestamp{
 			CreatedAt: schema.CreatedAt,
 			UpdatedAt: schema.UpdatedAt,
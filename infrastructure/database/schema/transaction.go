@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fp-kpl/domain/identity"
+	"fp-kpl/domain/order"
 	"fp-kpl/domain/shared"
 	"fp-kpl/domain/transaction"
 	"time"
@@ -12,23 +13,45 @@ import (
 )
 
 type Transaction struct {
-	ID            uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
-	UserID        uuid.UUID       `gorm:"type:uuid;not null;column:user_id"`
-	TableID       uuid.UUID       `gorm:"type:uuid;not null;column:table_id"`
-	PaymentCode   string          `gorm:"type:varchar(255);not null;column:payment_code"`
-	PaymentStatus string          `gorm:"type:varchar(255);not null;column:payment_status"`
-	OrderStatus   string          `gorm:"type:varchar(255);not null;column:order_status"`
-	CookedAt      *time.Time      `gorm:"type:timestamp with time zone;column:cooked_at"`
-	ServedAt      *time.Time      `gorm:"type:timestamp with time zone;column:served_at"`
-	QueueCode     *string         `gorm:"type:varchar(255);column:queue_code"`
-	TotalPrice    decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:total_price"`
-	CreatedAt     time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
-	UpdatedAt     time.Time       `gorm:"type:timestamp with time zone;column:updated_at"`
-	DeletedAt     gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
+	ID                uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	UserID            *uuid.UUID      `gorm:"type:uuid;column:user_id"`
+	GuestName         string          `gorm:"type:varchar(100);not null;default:'';column:guest_name"`
+	GuestPhone        string          `gorm:"type:varchar(20);not null;default:'';column:guest_phone"`
+	GuestTokenHash    *string         `gorm:"type:varchar(64);uniqueIndex;column:guest_token_hash"`
+	TableID           *uuid.UUID      `gorm:"type:uuid;column:table_id"`
+	OrderType         string          `gorm:"type:varchar(20);not null;default:'dine_in';column:order_type"`
+	PickupAt          *time.Time      `gorm:"type:timestamp with time zone;column:pickup_at"`
+	CookAfter         *time.Time      `gorm:"type:timestamp with time zone;index;column:cook_after"`
+	PaymentCode       string          `gorm:"type:varchar(255);not null;column:payment_code"`
+	PaymentStatus     string          `gorm:"type:varchar(255);not null;column:payment_status"`
+	PaymentProvider   string          `gorm:"type:varchar(50);not null;default:'midtrans';column:payment_provider"`
+	OrderStatus       string          `gorm:"type:varchar(255);not null;column:order_status"`
+	PaidAt            *time.Time      `gorm:"type:timestamp with time zone;column:paid_at"`
+	CookedAt          *time.Time      `gorm:"type:timestamp with time zone;column:cooked_at"`
+	ReadyAt           *time.Time      `gorm:"type:timestamp with time zone;column:ready_at"`
+	ServedAt          *time.Time      `gorm:"type:timestamp with time zone;column:served_at"`
+	QueueCode         *string         `gorm:"type:varchar(255);uniqueIndex:idx_transactions_business_day_queue_code,priority:2;column:queue_code"`
+	BusinessDay       *string         `gorm:"type:varchar(10);uniqueIndex:idx_transactions_business_day_queue_code,priority:1;column:business_day"`
+	Priority          int             `gorm:"type:int;not null;default:0;column:priority"`
+	TotalPrice        decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:total_price"`
+	Subtotal          decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:subtotal"`
+	Discount          decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:discount"`
+	LoyaltyPoints     int64           `gorm:"type:bigint;not null;default:0;column:loyalty_points"`
+	LoyaltyDiscount   decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:loyalty_discount"`
+	PackagingFee      decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:packaging_fee"`
+	ServiceCharge     decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:service_charge"`
+	ServiceChargeRate decimal.Decimal `gorm:"type:decimal(5,2);not null;default:0;column:service_charge_rate"`
+	Tax               decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:tax"`
+	TaxRate           decimal.Decimal `gorm:"type:decimal(5,2);not null;default:0;column:tax_rate"`
+	Rounding          decimal.Decimal `gorm:"type:decimal(12,2);not null;default:0;column:rounding"`
+	CreatedAt         time.Time       `gorm:"type:timestamp with time zone;column:created_at"`
+	UpdatedAt         time.Time       `gorm:"type:timestamp with time zone;column:updated_at"`
+	DeletedAt         gorm.DeletedAt  `gorm:"type:timestamp with time zone;column:deleted_at"`
 
-	User   *User   `gorm:"foreignKey:UserID"`
-	Table  *Table  `gorm:"foreignKey:TableID"`
-	Orders []Order `gorm:"foreignKey:TransactionID"`
+	User        *User                 `gorm:"foreignKey:UserID"`
+	Table       *Table                `gorm:"foreignKey:TableID"`
+	Orders      []Order               `gorm:"foreignKey:TransactionID"`
+	Redemptions []PromotionRedemption `gorm:"foreignKey:TransactionID"`
 }
 
 func TransactionEntityToSchema(entity transaction.Transaction) Transaction {
@@ -39,18 +62,40 @@ func TransactionEntityToSchema(entity transaction.Transaction) Transaction {
 		deletedAtTime = time.Time{}
 	}
 	return Transaction{
-		ID:            entity.ID.ID,
-		UserID:        entity.UserID.ID,
-		TableID:       entity.TableID.ID,
-		PaymentCode:   entity.Payment.Code,
-		PaymentStatus: entity.Payment.Status,
-		OrderStatus:   entity.OrderStatus.Status,
-		ServedAt:      entity.ServedAt,
-		CookedAt:      entity.CookedAt,
-		QueueCode:     &entity.QueueCode.Code,
-		TotalPrice:    entity.TotalPrice.Price,
-		CreatedAt:     entity.CreatedAt,
-		UpdatedAt:     entity.UpdatedAt,
+		ID:                entity.ID.ID,
+		UserID:            nullableID(entity.UserID),
+		GuestName:         entity.Guest.Name,
+		GuestPhone:        entity.Guest.Phone,
+		GuestTokenHash:    nullableString(entity.Guest.TokenHash),
+		TableID:           nullableID(entity.TableID),
+		OrderType:         entity.OrderType.Type,
+		PickupAt:          entity.PickupAt,
+		CookAfter:         entity.CookAfter,
+		PaymentCode:       entity.Payment.Code,
+		PaymentStatus:     entity.Payment.Status,
+		PaymentProvider:   entity.PaymentProvider,
+		OrderStatus:       entity.OrderStatus.Status,
+		PaidAt:            entity.PaidAt,
+		ServedAt:          entity.ServedAt,
+		CookedAt:          entity.CookedAt,
+		ReadyAt:           entity.ReadyAt,
+		QueueCode:         &entity.QueueCode.Code,
+		BusinessDay:       nullableString(entity.QueueCode.BusinessDay),
+		Priority:          entity.Priority,
+		TotalPrice:        entity.TotalPrice.Price,
+		Subtotal:          entity.Pricing.Subtotal.Price,
+		Discount:          entity.Pricing.Discount.Price,
+		LoyaltyPoints:     entity.Pricing.LoyaltyPoints,
+		LoyaltyDiscount:   entity.Pricing.LoyaltyDiscount.Price,
+		PackagingFee:      entity.Pricing.PackagingFee.Price,
+		ServiceCharge:     entity.Pricing.ServiceCharge.Price,
+		ServiceChargeRate: entity.Pricing.ServiceChargeRate,
+		Tax:               entity.Pricing.Tax.Price,
+		TaxRate:           entity.Pricing.TaxRate,
+		Rounding:          entity.Pricing.Rounding.Price,
+		Redemptions:       DiscountsToRedemptionSchemas(nullableID(entity.UserID), entity.Pricing.Discounts),
+		CreatedAt:         entity.CreatedAt,
+		UpdatedAt:         entity.UpdatedAt,
 		DeletedAt: gorm.DeletedAt{
 			Time:  deletedAtTime,
 			Valid: entity.DeletedAt != nil,
@@ -62,22 +107,58 @@ func TransactionSchemaToEntity(schema Transaction) transaction.Transaction {
 	var queueCode transaction.QueueCode
 	if schema.QueueCode != nil {
 		queueCode = transaction.NewQueueCodeFromSchema(*schema.QueueCode, true)
+		if schema.BusinessDay != nil {
+			queueCode.BusinessDay = *schema.BusinessDay
+		}
 	} else {
 		queueCode = transaction.QueueCode{
 			Code:  "Q0000",
 			Valid: false,
 		}
 	}
+	// Transactions created before the pricing breakdown was stored only have
+	// a total, which was the plain subtotal back then.
+	subtotal := schema.Subtotal
+	if subtotal.IsZero() && schema.Discount.IsZero() && schema.ServiceCharge.IsZero() && schema.Tax.IsZero() && schema.Rounding.IsZero() && schema.PackagingFee.IsZero() {
+		subtotal = schema.TotalPrice
+	}
+
 	return transaction.Transaction{
-		ID:          identity.NewIDFromSchema(schema.ID),
-		UserID:      identity.NewIDFromSchema(schema.UserID),
-		TableID:     identity.NewIDFromSchema(schema.TableID),
-		Payment:     transaction.NewPaymentFromSchema(schema.PaymentCode, schema.PaymentStatus),
-		OrderStatus: transaction.NewOrderStatusFromSchema(schema.OrderStatus),
-		ServedAt:    schema.ServedAt,
-		CookedAt:    schema.CookedAt,
-		QueueCode:   queueCode,
-		TotalPrice:  shared.NewPriceFromSchema(schema.TotalPrice),
+		ID:     identity.NewIDFromSchema(schema.ID),
+		UserID: idFromNullable(schema.UserID),
+		Guest: transaction.Guest{
+			Name:      schema.GuestName,
+			Phone:     schema.GuestPhone,
+			TokenHash: stringFromNullable(schema.GuestTokenHash),
+		},
+		TableID:         idFromNullable(schema.TableID),
+		OrderType:       transaction.NewOrderTypeFromSchema(schema.OrderType),
+		PickupAt:        schema.PickupAt,
+		CookAfter:       schema.CookAfter,
+		Payment:         transaction.NewPaymentFromSchema(schema.PaymentCode, schema.PaymentStatus),
+		PaymentProvider: schema.PaymentProvider,
+		OrderStatus:     transaction.NewOrderStatusFromSchema(schema.OrderStatus),
+		PaidAt:          schema.PaidAt,
+		ServedAt:        schema.ServedAt,
+		CookedAt:        schema.CookedAt,
+		ReadyAt:         schema.ReadyAt,
+		QueueCode:       queueCode,
+		Priority:        schema.Priority,
+		TotalPrice:      shared.NewPriceFromSchema(schema.TotalPrice),
+		Pricing: order.PriceBreakdown{
+			Subtotal:          shared.NewPriceFromSchema(subtotal),
+			Discounts:         RedemptionSchemasToDiscounts(schema.Redemptions),
+			Discount:          shared.NewPriceFromSchema(schema.Discount),
+			LoyaltyPoints:     schema.LoyaltyPoints,
+			LoyaltyDiscount:   shared.NewPriceFromSchema(schema.LoyaltyDiscount),
+			PackagingFee:      shared.NewPriceFromSchema(schema.PackagingFee),
+			ServiceChargeRate: schema.ServiceChargeRate,
+			ServiceCharge:     shared.NewPriceFromSchema(schema.ServiceCharge),
+			TaxRate:           schema.TaxRate,
+			Tax:               shared.NewPriceFromSchema(schema.Tax),
+			Rounding:          shared.NewPriceFromSchema(schema.Rounding),
+			Total:             shared.NewPriceFromSchema(schema.TotalPrice),
+		},
 		Timestamp: shared.Timestamp{
 			CreatedAt: schema.CreatedAt,
 			UpdatedAt: schema.UpdatedAt,
@@ -85,3 +166,17 @@ func TransactionSchemaToEntity(schema Transaction) transaction.Transaction {
 		},
 	}
 }
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringFromNullable(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
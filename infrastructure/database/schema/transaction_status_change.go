@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/transaction"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransactionStatusChange struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	TransactionID uuid.UUID  `gorm:"type:uuid;not null;index;column:transaction_id"`
+	Status        string     `gorm:"type:varchar(50);not null;column:status"`
+	UserID        *uuid.UUID `gorm:"type:uuid;column:user_id"`
+	DeviceID      *uuid.UUID `gorm:"type:uuid;index;column:device_id"`
+	CreatedAt     time.Time  `gorm:"type:timestamp with time zone;column:created_at"`
+
+	Transaction *Transaction `gorm:"foreignKey:TransactionID"`
+	User        *User        `gorm:"foreignKey:UserID"`
+	Device      *Device      `gorm:"foreignKey:DeviceID"`
+}
+
+func TransactionStatusChangeEntityToSchema(entity transaction.StatusChange) TransactionStatusChange {
+	return TransactionStatusChange{
+		ID:            entity.ID.ID,
+		TransactionID: entity.TransactionID.ID,
+		Status:        entity.Status.Status,
+		UserID:        nullableID(entity.UserID),
+		DeviceID:      nullableID(entity.DeviceID),
+		CreatedAt:     entity.CreatedAt,
+	}
+}
+
+func TransactionStatusChangeSchemaToEntity(schema TransactionStatusChange) transaction.StatusChange {
+	return transaction.StatusChange{
+		ID:            identity.NewIDFromSchema(schema.ID),
+		TransactionID: identity.NewIDFromSchema(schema.TransactionID),
+		Status:        transaction.NewOrderStatusFromSchema(schema.Status),
+		UserID:        idFromNullable(schema.UserID),
+		DeviceID:      idFromNullable(schema.DeviceID),
+		CreatedAt:     schema.CreatedAt,
+	}
+}
@@ -11,16 +11,20 @@ import (
 )
 
 type User struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
-	Name        string         `gorm:"type:varchar(100);not null;column:name"`
-	Email       string         `gorm:"type:varchar(255);uniqueIndex;not null;column:email"`
-	PhoneNumber string         `gorm:"type:varchar(20);index;column:phone_number"`
-	Password    string         `gorm:"type:varchar(255);not null;column:password"`
-	Role        string         `gorm:"type:varchar(50);not null;default:'user';column:role"`
-	CreatedAt   time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
-	UpdatedAt   time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	Name            string         `gorm:"type:varchar(100);not null;column:name"`
+	Email           string         `gorm:"type:varchar(255);uniqueIndex;not null;column:email"`
+	PhoneNumber     string         `gorm:"type:varchar(20);index;column:phone_number"`
+	Password        string         `gorm:"type:varchar(255);not null;column:password"`
+	Role            string         `gorm:"type:varchar(50);not null;default:'user';column:role"`
+	StationID       *uuid.UUID     `gorm:"type:uuid;column:station_id"`
+	EmailVerifiedAt *time.Time     `gorm:"type:timestamp with time zone;column:email_verified_at"`
+	PhoneVerifiedAt *time.Time     `gorm:"type:timestamp with time zone;column:phone_verified_at"`
+	CreatedAt       time.Time      `gorm:"type:timestamp with time zone;column:created_at"`
+	UpdatedAt       time.Time      `gorm:"type:timestamp with time zone;column:updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"type:timestamp with time zone;column:deleted_at"`
 
+	Station      *Station      `gorm:"foreignKey:StationID"`
 	Transactions []Transaction `gorm:"foreignKey:UserID"`
 }
 
@@ -32,14 +36,17 @@ func UserEntityToSchema(entity user.User) User {
 		deletedAtTime = time.Time{}
 	}
 	return User{
-		ID:          entity.ID.ID,
-		Email:       entity.Email,
-		Password:    entity.Password.Password,
-		Name:        entity.Name,
-		PhoneNumber: entity.PhoneNumber,
-		Role:        entity.Role.Name,
-		CreatedAt:   entity.Timestamp.CreatedAt,
-		UpdatedAt:   entity.Timestamp.UpdatedAt,
+		ID:              entity.ID.ID,
+		Email:           entity.Email,
+		Password:        entity.Password.Password,
+		Name:            entity.Name,
+		PhoneNumber:     entity.PhoneNumber,
+		Role:            entity.Role.Name,
+		StationID:       nullableID(entity.StationID),
+		EmailVerifiedAt: entity.EmailVerifiedAt,
+		PhoneVerifiedAt: entity.PhoneVerifiedAt,
+		CreatedAt:       entity.Timestamp.CreatedAt,
+		UpdatedAt:       entity.Timestamp.UpdatedAt,
 		DeletedAt: gorm.DeletedAt{
 			Time:  deletedAtTime,
 			Valid: entity.DeletedAt != nil,
@@ -49,12 +56,15 @@ func UserEntityToSchema(entity user.User) User {
 
 func UserSchemaToEntity(schema User) user.User {
 	return user.User{
-		ID:          identity.NewIDFromSchema(schema.ID),
-		Email:       schema.Email,
-		Password:    user.NewPasswordFromSchema(schema.Password),
-		Name:        schema.Name,
-		PhoneNumber: schema.PhoneNumber,
-		Role:        user.NewRoleFromSchema(schema.Role),
+		ID:              identity.NewIDFromSchema(schema.ID),
+		Email:           schema.Email,
+		Password:        user.NewPasswordFromSchema(schema.Password),
+		Name:            schema.Name,
+		PhoneNumber:     schema.PhoneNumber,
+		Role:            user.NewRoleFromSchema(schema.Role),
+		StationID:       idFromNullable(schema.StationID),
+		EmailVerifiedAt: schema.EmailVerifiedAt,
+		PhoneVerifiedAt: schema.PhoneVerifiedAt,
 		Timestamp: shared.Timestamp{
 			CreatedAt: schema.CreatedAt,
 			UpdatedAt: schema.UpdatedAt,
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/verification"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationChallenge is a code sent to a user's email or phone number.
+// Only the hash of the code is stored.
+type VerificationChallenge struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();column:id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_verification_challenges_user_channel;uniqueIndex:idx_verification_challenges_first,where:replaces_id IS NULL;column:user_id"`
+	Channel     string     `gorm:"type:varchar(10);not null;index:idx_verification_challenges_user_channel;uniqueIndex:idx_verification_challenges_first,where:replaces_id IS NULL;column:channel"`
+	Destination string     `gorm:"type:varchar(255);not null;column:destination"`
+	CodeHash    string     `gorm:"type:varchar(64);not null;column:code_hash"`
+	Attempts    int        `gorm:"type:int;not null;default:0;column:attempts"`
+	ExpiresAt   time.Time  `gorm:"type:timestamp with time zone;not null;column:expires_at"`
+	VerifiedAt  *time.Time `gorm:"type:timestamp with time zone;column:verified_at"`
+	ReplacesID  *uuid.UUID `gorm:"type:uuid;uniqueIndex;column:replaces_id"`
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone;index:idx_verification_challenges_user_channel;column:created_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
+
+func VerificationChallengeEntityToSchema(entity verification.Challenge) VerificationChallenge {
+	return VerificationChallenge{
+		ID:          entity.ID.ID,
+		UserID:      entity.UserID.ID,
+		Channel:     entity.Channel,
+		Destination: entity.Destination,
+		CodeHash:    entity.CodeHash,
+		Attempts:    entity.Attempts,
+		ExpiresAt:   entity.ExpiresAt,
+		VerifiedAt:  entity.VerifiedAt,
+		ReplacesID:  nullableID(entity.ReplacesID),
+		CreatedAt:   entity.CreatedAt,
+	}
+}
+
+func VerificationChallengeSchemaToEntity(schema VerificationChallenge) verification.Challenge {
+	return verification.Challenge{
+		ID:          identity.NewIDFromSchema(schema.ID),
+		UserID:      identity.NewIDFromSchema(schema.UserID),
+		Channel:     schema.Channel,
+		Destination: schema.Destination,
+		CodeHash:    schema.CodeHash,
+		Attempts:    schema.Attempts,
+		ExpiresAt:   schema.ExpiresAt,
+		VerifiedAt:  schema.VerifiedAt,
+		ReplacesID:  idFromNullable(schema.ReplacesID),
+		CreatedAt:   schema.CreatedAt,
+	}
+}
@@ -1,10 +1,20 @@
 package main
 
 import (
+	"context"
 	"fp-kpl/application/service"
 	"fp-kpl/command"
+	"fp-kpl/domain/cart"
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/loyalty"
 	"fp-kpl/domain/order"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/sla"
 	"fp-kpl/domain/transaction"
+	"fp-kpl/domain/verification"
+	"fp-kpl/infrastructure/adapter/event"
+	"fp-kpl/infrastructure/adapter/notifier"
 	"fp-kpl/infrastructure/adapter/payment_gateway"
 	"fp-kpl/infrastructure/database/config"
 	"fp-kpl/infrastructure/database/db_transaction"
@@ -14,14 +24,18 @@ import (
 	"fp-kpl/presentation/route"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
-func args(db *gorm.DB) bool {
+func args(db *gorm.DB, reconciliationService service.ReconciliationService) bool {
 	if len(os.Args) > 1 {
-		flag := command.Commands(db)
+		flag := command.Commands(db, reconciliationService)
 		return flag
 	}
 
@@ -52,53 +66,369 @@ func run(server *gin.Engine) {
 	}
 }
 
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+
+	return duration
+}
+
+func stationCapacity() int {
+	value := os.Getenv("KITCHEN_STATION_CAPACITY")
+	if value == "" {
+		return transaction.DefaultStationCapacity
+	}
+
+	capacity, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("invalid KITCHEN_STATION_CAPACITY: %v", err)
+	}
+
+	return capacity
+}
+
+// restaurantClock tells the time in RESTAURANT_TIMEZONE. The business day
+// starts at BUSINESS_DAY_CUTOFF, so orders taken after midnight keep the
+// evening's queue.
+func restaurantClock() shared.Clock {
+	cutoff := durationEnv("BUSINESS_DAY_CUTOFF", shared.DefaultBusinessDayCutoff)
+	if err := shared.ValidateBusinessDayCutoff(cutoff); err != nil {
+		log.Fatalf("invalid BUSINESS_DAY_CUTOFF: %v", err)
+	}
+
+	return shared.NewSystemClock(restaurantLocation(), cutoff)
+}
+
+// restaurantLocation is the timezone menu schedules, happy hours and business
+// days are evaluated in.
+func restaurantLocation() *time.Location {
+	value := os.Getenv("RESTAURANT_TIMEZONE")
+	if value == "" {
+		value = "Asia/Jakarta"
+	}
+
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		log.Fatalf("invalid RESTAURANT_TIMEZONE: %v", err)
+	}
+
+	return location
+}
+
+// pricingPolicy reads the service charge, PB1 tax, rounding and packaging fee
+// rules. Without any of them set the total is the plain subtotal.
+func pricingPolicy() order.PricingPolicy {
+	rate := func(key string) decimal.Decimal {
+		value := os.Getenv(key)
+		if value == "" {
+			return decimal.Zero
+		}
+
+		parsed, err := decimal.NewFromString(value)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		return parsed
+	}
+
+	var roundingUnit int64
+	if value := os.Getenv("PRICING_ROUNDING_UNIT"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid PRICING_ROUNDING_UNIT: %v", err)
+		}
+		roundingUnit = parsed
+	}
+
+	policy, err := order.NewPricingPolicy(
+		rate("PRICING_SERVICE_CHARGE_PERCENT"),
+		rate("PRICING_TAX_PERCENT"),
+		os.Getenv("PRICING_TAX_ORDER"),
+		roundingUnit,
+		rate("PRICING_PACKAGING_FEE"),
+	)
+	if err != nil {
+		log.Fatalf("invalid pricing policy: %v", err)
+	}
+
+	return policy
+}
+
+// loyaltyPolicy reads how many IDR earn a point, what a point is worth and
+// after how many months points lapse.
+func loyaltyPolicy() loyalty.Policy {
+	amount := func(key string, fallback int64) decimal.Decimal {
+		value := os.Getenv(key)
+		if value == "" {
+			return decimal.NewFromInt(fallback)
+		}
+
+		parsed, err := decimal.NewFromString(value)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		return parsed
+	}
+
+	expiryMonths := loyalty.DefaultExpiryMonths
+	if value := os.Getenv("LOYALTY_EXPIRY_MONTHS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid LOYALTY_EXPIRY_MONTHS: %v", err)
+		}
+		expiryMonths = parsed
+	}
+
+	policy, err := loyalty.NewPolicy(
+		amount("LOYALTY_EARN_UNIT", loyalty.DefaultEarnUnit),
+		amount("LOYALTY_POINT_VALUE", loyalty.DefaultPointValue),
+		expiryMonths,
+	)
+	if err != nil {
+		log.Fatalf("invalid loyalty policy: %v", err)
+	}
+
+	return policy
+}
+
+// verificationPolicy reads how long verification codes last, how often they
+// can be sent and how many guesses each allows.
+func verificationPolicy() verification.Policy {
+	count := func(key string, fallback int) int {
+		value := os.Getenv(key)
+		if value == "" {
+			return fallback
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		return parsed
+	}
+
+	policy, err := verification.NewPolicy(
+		durationEnv("VERIFICATION_CODE_TTL", verification.DefaultCodeTTL),
+		durationEnv("VERIFICATION_RESEND_COOLDOWN", verification.DefaultResendCooldown),
+		count("VERIFICATION_MAX_ATTEMPTS", verification.DefaultMaxAttempts),
+		count("VERIFICATION_MAX_SENDS_PER_HOUR", verification.DefaultMaxSendsPerHour),
+	)
+	if err != nil {
+		log.Fatalf("invalid verification policy: %v", err)
+	}
+
+	return policy
+}
+
+// verificationNotifier writes verification codes to VERIFICATION_OUTBOX_FILE,
+// or to the log when it is not set.
+func verificationNotifier() notifier.LogVerificationNotifier {
+	logger := log.Default()
+	if path := os.Getenv("VERIFICATION_OUTBOX_FILE"); path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			log.Fatalf("error opening VERIFICATION_OUTBOX_FILE: %v", err)
+		}
+		logger = log.New(file, "", log.LstdFlags)
+	}
+
+	return notifier.NewLogVerificationNotifier(logger, notifier.DefaultVerificationHistorySize)
+}
+
+// appURL is where the app is reached from outside, for links sent to
+// customers and payment gateways.
+func appURL() string {
+	if value := os.Getenv("APP_URL"); value != "" {
+		return value
+	}
+	return "http://localhost:" + os.Getenv("GOLANG_PORT")
+}
+
+// paymentGateways builds every provider listed in PAYMENT_PROVIDERS, with
+// PAYMENT_GATEWAY as the default one. The fake adapter is returned separately
+// so its payment page can be routed.
+func paymentGateways(db *gorm.DB, transactionDomainService transaction.Service, clock shared.Clock, paymentTimeout time.Duration) (port.PaymentGatewayRegistry, port.FakePaymentGatewayPort) {
+	defaultProvider := os.Getenv("PAYMENT_GATEWAY")
+	if defaultProvider == "" {
+		defaultProvider = transaction.PaymentProviderMidtrans
+	}
+
+	providers := []string{defaultProvider}
+	if value := os.Getenv("PAYMENT_PROVIDERS"); value != "" {
+		providers = strings.Split(value, ",")
+	}
+
+	var gateways []port.PaymentGatewayPort
+	var fakeAdapter port.FakePaymentGatewayPort
+	for _, provider := range providers {
+		switch provider = strings.TrimSpace(provider); provider {
+		case transaction.PaymentProviderMidtrans:
+			gateways = append(gateways, payment_gateway.NewMidtransAdapter(db, transactionDomainService, clock, paymentTimeout, payment_gateway.MidtransConfig{
+				ServerKey:  os.Getenv("MIDTRANS_SERVER_KEY"),
+				Production: os.Getenv("MIDTRANS_ENVIRONMENT") == "production",
+			}))
+		case transaction.PaymentProviderXendit:
+			gateways = append(gateways, payment_gateway.NewXenditAdapter(db, transactionDomainService, clock, paymentTimeout, payment_gateway.XenditConfig{
+				SecretKey:     os.Getenv("XENDIT_SECRET_KEY"),
+				CallbackToken: os.Getenv("XENDIT_CALLBACK_TOKEN"),
+				BaseURL:       os.Getenv("XENDIT_BASE_URL"),
+			}))
+		case transaction.PaymentProviderCounter:
+			continue
+		case transaction.PaymentProviderFake:
+			fakeAdapter = payment_gateway.NewFakeAdapter(db, transactionDomainService, clock, appURL(), appURL()+"/api/transaction/hook/"+transaction.PaymentProviderFake, os.Getenv("FAKE_GATEWAY_SERVER_KEY"))
+			gateways = append(gateways, fakeAdapter)
+		default:
+			log.Fatalf("invalid payment provider: %s", provider)
+		}
+	}
+
+	// Paying at the counter needs no configuration and is always available.
+	gateways = append(gateways, payment_gateway.NewCounterAdapter(db, transactionDomainService, clock))
+
+	registry, err := payment_gateway.NewRegistry(defaultProvider, gateways...)
+	if err != nil {
+		log.Fatalf("error setting up payment gateways: %v", err)
+	}
+
+	return registry, fakeAdapter
+}
+
+func schedulingStrategy(transactionDomainService transaction.Service) transaction.SchedulingStrategy {
+	agingRate := transaction.DefaultAgingRate
+	if value := os.Getenv("KITCHEN_SCHEDULING_AGING_RATE"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Fatalf("invalid KITCHEN_SCHEDULING_AGING_RATE: %v", err)
+		}
+		agingRate = parsed
+	}
+
+	strategy, err := transaction.NewSchedulingStrategy(os.Getenv("KITCHEN_SCHEDULING_STRATEGY"), transactionDomainService.CalculateMaxCookingTime, agingRate)
+	if err != nil {
+		log.Fatalf("error setting up kitchen scheduling: %v", err)
+	}
+
+	return strategy
+}
+
 func main() {
 	db := config.SetUpDatabaseConnection()
 
 	jwtService := service.NewJWTService()
 	dbTransactionRepository := db_transaction.NewRepository(db)
+	clock := restaurantClock()
 
 	userRepository := repository.NewUserRepository(dbTransactionRepository)
 	tableRepository := repository.NewTableRepository(dbTransactionRepository)
 	categoryRepository := repository.NewCategoryRepository(dbTransactionRepository)
 	menuRepository := repository.NewMenuRepository(dbTransactionRepository)
 	orderRepository := repository.NewOrderRepository(dbTransactionRepository)
-	transactionRepository := repository.NewTransactionRepository(dbTransactionRepository)
+	transactionRepository := repository.NewTransactionRepository(dbTransactionRepository, clock)
+	stationRepository := repository.NewStationRepository(dbTransactionRepository)
+	shiftRepository := repository.NewShiftRepository(dbTransactionRepository, clock)
+	splitPaymentRepository := repository.NewSplitPaymentRepository(dbTransactionRepository)
+	promotionRepository := repository.NewPromotionRepository(dbTransactionRepository)
+	deviceRepository := repository.NewDeviceRepository(dbTransactionRepository)
+	statusChangeRepository := repository.NewStatusChangeRepository(dbTransactionRepository)
+	favouriteRepository := repository.NewFavouriteRepository(dbTransactionRepository)
+	cartRepository := repository.NewCartRepository(dbTransactionRepository)
+	feedbackRepository := repository.NewFeedbackRepository(dbTransactionRepository)
+	loyaltyRepository := repository.NewLoyaltyRepository(dbTransactionRepository)
+	verificationRepository := repository.NewVerificationRepository(dbTransactionRepository)
+	slaAlertRepository := repository.NewSLAAlertRepository(dbTransactionRepository)
 
-	transactionDomainService := transaction.NewService(transactionRepository)
-	orderDomainService := order.NewService()
+	transactionDomainService := transaction.NewService(transactionRepository, stationCapacity(), clock)
+	orderDomainService := order.NewService(pricingPolicy())
+	slaDomainService := sla.NewService(sla.NewPolicy(
+		durationEnv("SLA_PICKUP_TIMEOUT", sla.DefaultPickupTimeout),
+		durationEnv("SLA_PENDING_TIMEOUT", sla.DefaultPendingTimeout),
+	), transactionDomainService)
 
-	paymentGateway := payment_gateway.NewMidtransAdapter(db, transactionDomainService)
+	paymentTimeout := durationEnv("PAYMENT_TIMEOUT", transaction.DefaultPaymentTimeout)
+	paymentGatewayRegistry, fakePaymentGateway := paymentGateways(db, transactionDomainService, clock, paymentTimeout)
+	eventPublisher := event.NewLogPublisher(log.Default(), event.DefaultEventHistorySize)
+	slaNotifier := notifier.NewLogNotifier(log.Default(), notifier.DefaultAlertHistorySize)
 
-	userService := service.NewUserService(userRepository, jwtService, dbTransactionRepository)
+	loyaltyService := service.NewLoyaltyService(loyaltyRepository, orderDomainService, loyaltyPolicy(), clock, dbTransactionRepository, durationEnv("LOYALTY_SYNC_LOOKBACK", loyalty.DefaultSyncLookback))
+	verificationService := service.NewVerificationService(verificationRepository, userRepository, verificationNotifier(), verificationPolicy(), clock, appURL(), os.Getenv("VERIFICATION_REQUIRED_TO_ORDER") == "true")
+	userService := service.NewUserService(userRepository, loyaltyService, verificationService, jwtService, clock, dbTransactionRepository)
 	tableService := service.NewTableService(tableRepository)
-	categoryService := service.NewCategoryService(categoryRepository)
-	menuService := service.NewMenuService(menuRepository, categoryRepository)
-	orderService := service.NewOrderService(orderRepository, menuRepository, orderDomainService)
-	transactionService := service.NewTransactionService(transactionRepository, userRepository, tableRepository, orderRepository, menuRepository, transactionDomainService, paymentGateway, dbTransactionRepository, orderService)
+	categoryService := service.NewCategoryService(categoryRepository, dbTransactionRepository)
+	menuService := service.NewMenuService(menuRepository, categoryRepository, favouriteRepository, feedbackRepository, clock, dbTransactionRepository)
+	stationService := service.NewStationService(stationRepository)
+	orderService := service.NewOrderService(orderRepository, menuRepository, orderDomainService, promotionRepository, clock)
+	transactionService := service.NewTransactionService(transactionRepository, userRepository, tableRepository, orderRepository, menuRepository, transactionDomainService, paymentGatewayRegistry, dbTransactionRepository, orderService, stationRepository, schedulingStrategy(transactionDomainService), eventPublisher, clock, statusChangeRepository, loyaltyService)
+	cashierService := service.NewCashierService(shiftRepository, transactionRepository, paymentGatewayRegistry, clock, dbTransactionRepository)
+	splitPaymentService := service.NewSplitPaymentService(splitPaymentRepository, transactionRepository, userRepository, paymentGatewayRegistry, dbTransactionRepository)
+	promotionService := service.NewPromotionService(promotionRepository)
+	paymentExpiryService := service.NewPaymentExpiryService(transactionRepository, eventPublisher, clock, paymentTimeout)
+	reconciliationService := service.NewReconciliationService(transactionRepository, paymentGatewayRegistry, transactionService, clock, durationEnv("RECONCILIATION_LOOKBACK", transaction.DefaultReconciliationLookback))
+	slaService := service.NewSLAService(transactionRepository, slaAlertRepository, transactionDomainService, slaDomainService, slaNotifier)
+	deviceService := service.NewDeviceService(deviceRepository, stationRepository, tableRepository, clock)
+	cartService := service.NewCartService(cartRepository, menuRepository, tableRepository, orderService, transactionService, clock, durationEnv("CART_IDLE_TIMEOUT", cart.DefaultIdleTimeout))
+	feedbackService := service.NewFeedbackService(feedbackRepository, transactionRepository, clock, dbTransactionRepository, durationEnv("FEEDBACK_WINDOW", feedback.DefaultSubmissionWindow))
+	displayService := service.NewDisplayService(transactionRepository, clock, durationEnv("DISPLAY_HIGHLIGHT_DURATION", transaction.DefaultDisplayHighlightDuration))
 
 	userController := controller.NewUserController(userService)
+	verificationController := controller.NewVerificationController(verificationService)
 	tableController := controller.NewTableController(tableService)
 	categoryController := controller.NewCategoryController(categoryService)
 	menuController := controller.NewMenuController(menuService)
+	stationController := controller.NewStationController(stationService)
 	transactionController := controller.NewTransactionController(transactionService)
 	orderController := controller.NewOrderController(orderService)
+	slaController := controller.NewSLAController(slaService)
+	cashierController := controller.NewCashierController(cashierService)
+	splitPaymentController := controller.NewSplitPaymentController(splitPaymentService)
+	promotionController := controller.NewPromotionController(promotionService)
+	deviceController := controller.NewDeviceController(deviceService)
+	cartController := controller.NewCartController(cartService)
+	feedbackController := controller.NewFeedbackController(feedbackService)
+	displayController := controller.NewDisplayController(displayService, durationEnv("DISPLAY_STREAM_INTERVAL", controller.DefaultDisplayStreamInterval))
 
 	defer config.CloseDatabaseConnection(db)
 
-	if !args(db) {
+	if !args(db, reconciliationService) {
 		return
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go slaService.Start(ctx, durationEnv("SLA_SCAN_INTERVAL", service.DefaultSLAScanInterval))
+	go paymentExpiryService.Start(ctx, durationEnv("PAYMENT_EXPIRY_SWEEP_INTERVAL", service.DefaultPaymentExpirySweepInterval))
+	go reconciliationService.Start(ctx, durationEnv("RECONCILIATION_INTERVAL", service.DefaultReconciliationInterval))
+	go loyaltyService.Start(ctx, durationEnv("LOYALTY_SYNC_INTERVAL", service.DefaultLoyaltySyncInterval))
+
 	server := gin.Default()
 	server.Use(middleware.CORSMiddleware())
 
 	route.UserRoute(server, userController, jwtService)
-	route.TableRoute(server, tableController, jwtService)
-	route.CategoryRoute(server, categoryController, jwtService)
-	route.MenuRoute(server, menuController, jwtService, userService)
-	route.TransactionRoute(server, transactionController, jwtService, userService)
+	route.VerificationRoute(server, verificationController, jwtService)
+	route.TableRoute(server, tableController, jwtService, deviceService, userService)
+	route.CategoryRoute(server, categoryController, jwtService, deviceService, userService)
+	route.MenuRoute(server, menuController, jwtService, deviceService, userService)
+	route.StationRoute(server, stationController, jwtService, deviceService, userService)
+	route.TransactionRoute(server, transactionController, jwtService, deviceService, userService, verificationService)
 	route.OrderRoute(server, orderController, jwtService)
+	route.SLARoute(server, slaController, jwtService, userService)
+	route.CashierRoute(server, cashierController, jwtService, userService)
+	route.SplitPaymentRoute(server, splitPaymentController, jwtService, userService)
+	route.PromotionRoute(server, promotionController, jwtService, userService)
+	route.DeviceRoute(server, deviceController, jwtService, userService)
+	route.CartRoute(server, cartController, jwtService, deviceService, userService, verificationService)
+	route.FeedbackRoute(server, feedbackController, jwtService, userService)
+	route.DisplayRoute(server, displayController, os.Getenv("DISPLAY_API_KEY"), deviceService)
+	if fakePaymentGateway != nil {
+		route.FakeGatewayRoute(server, controller.NewFakeGatewayController(service.NewFakeGatewayService(fakePaymentGateway)))
+	}
 
 	run(server)
 }
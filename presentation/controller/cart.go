@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/cart"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/table"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	CartController interface {
+		OpenCart(ctx *gin.Context)
+		GetCart(ctx *gin.Context)
+		UpdateCart(ctx *gin.Context)
+		AddCartLine(ctx *gin.Context)
+		UpdateCartLine(ctx *gin.Context)
+		RemoveCartLine(ctx *gin.Context)
+		ApplyCartVoucher(ctx *gin.Context)
+		RemoveCartVoucher(ctx *gin.Context)
+		CheckoutCart(ctx *gin.Context)
+	}
+
+	cartController struct {
+		cartService service.CartService
+	}
+)
+
+func NewCartController(cartService service.CartService) CartController {
+	return &cartController{cartService: cartService}
+}
+
+// The cart handlers read user_id without requiring it, since kiosks share
+// the cart of their table without a user.
+
+func (c *cartController) OpenCart(ctx *gin.Context) {
+	var req request.OpenCart
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.cartService.OpenCart(ctx.Request.Context(), ctx.GetString("user_id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedOpenCart, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessOpenCart, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) GetCart(ctx *gin.Context) {
+	result, err := c.cartService.GetCart(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"))
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetCart, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetCart, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) UpdateCart(ctx *gin.Context) {
+	var req request.UpdateCart
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.cartService.UpdateCart(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedUpdateCart, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdateCart, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) AddCartLine(ctx *gin.Context) {
+	var req request.AddCartLine
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.cartService.AddCartLine(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedAddCartLine, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessAddCartLine, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) UpdateCartLine(ctx *gin.Context) {
+	var req request.UpdateCartLine
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.cartService.UpdateCartLine(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"), ctx.Param("line_id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedUpdateCartLine, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdateCartLine, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) RemoveCartLine(ctx *gin.Context) {
+	result, err := c.cartService.RemoveCartLine(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"), ctx.Param("line_id"))
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedRemoveCartLine, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessRemoveCartLine, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) ApplyCartVoucher(ctx *gin.Context) {
+	var req request.ApplyCartVoucher
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.cartService.ApplyCartVoucher(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedApplyCartVoucher, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessApplyCartVoucher, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) RemoveCartVoucher(ctx *gin.Context) {
+	result, err := c.cartService.RemoveCartVoucher(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"))
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedRemoveCartVoucher, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessRemoveCartVoucher, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cartController) CheckoutCart(ctx *gin.Context) {
+	var req request.CheckoutCart
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.cartService.CheckoutCart(ctx.Request.Context(), ctx.GetString("user_id"), ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedCheckoutCart, err.Error(), nil)
+		ctx.AbortWithStatusJSON(cartErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessCheckoutCart, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func cartErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, cart.ErrorCartNotFound),
+		errors.Is(err, cart.ErrorCartLineNotFound),
+		errors.Is(err, menu.ErrorMenuNotFound),
+		errors.Is(err, table.ErrorTableNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, cart.ErrorNotCartMember):
+		return http.StatusForbidden
+	case errors.Is(err, cart.ErrorCartExpired):
+		return http.StatusGone
+	case errors.Is(err, cart.ErrorCartCheckedOut):
+		return http.StatusConflict
+	case errors.Is(err, cart.ErrorCartEmpty),
+		errors.Is(err, cart.ErrorCartHasUnavailableItems),
+		errors.Is(err, cart.ErrorInvalidQuantity),
+		errors.Is(err, menu.ErrorMenuUnavailable),
+		errors.Is(err, table.ErrorTableRequired),
+		errors.Is(err, port.ErrorPaymentProviderNotFound):
+		return http.StatusBadRequest
+	default:
+		return orderErrorStatus(err)
+	}
+}
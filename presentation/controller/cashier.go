@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/shift"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	CashierController interface {
+		OpenShift(ctx *gin.Context)
+		GetCurrentShift(ctx *gin.Context)
+		CloseShift(ctx *gin.Context)
+		ConfirmPayment(ctx *gin.Context)
+	}
+
+	cashierController struct {
+		cashierService service.CashierService
+	}
+)
+
+func NewCashierController(cashierService service.CashierService) CashierController {
+	return &cashierController{cashierService: cashierService}
+}
+
+func (c *cashierController) OpenShift(ctx *gin.Context) {
+	var req request.OpenShift
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.cashierService.OpenShift(ctx.Request.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, shift.ErrorShiftAlreadyOpen) {
+			res := presentation.BuildResponseFailed(message.FailedOpenShift, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedOpenShift, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessOpenShift, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *cashierController) GetCurrentShift(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.cashierService.GetCurrentShift(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, shift.ErrorShiftNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetCurrentShift, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetCurrentShift, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetCurrentShift, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cashierController) CloseShift(ctx *gin.Context) {
+	var req request.CloseShift
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.cashierService.CloseShift(ctx.Request.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, shift.ErrorShiftNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedCloseShift, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedCloseShift, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessCloseShift, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *cashierController) ConfirmPayment(ctx *gin.Context) {
+	var req request.ConfirmPayment
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.cashierService.ConfirmPayment(ctx.Request.Context(), userID, ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedConfirmPayment, err.Error(), nil)
+		switch {
+		case errors.Is(err, transaction.ErrorTransactionNotFound):
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+		case errors.Is(err, shift.ErrorShiftNotFound),
+			errors.Is(err, transaction.ErrorPaymentAlreadyFinal):
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+		case errors.Is(err, transaction.ErrorNotCounterPayment),
+			errors.Is(err, shift.ErrorInvalidPaymentMethod),
+			errors.Is(err, shift.ErrorInsufficientAmount):
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		}
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessConfirmPayment, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -2,8 +2,10 @@ package controller
 
 import (
 	"errors"
+	"fp-kpl/application/request"
 	"fp-kpl/application/service"
 	"fp-kpl/domain/menu/category"
+	"fp-kpl/domain/shared"
 	"fp-kpl/presentation"
 	"fp-kpl/presentation/message"
 	"net/http"
@@ -15,6 +17,7 @@ type (
 	CategoryController interface {
 		GetAllCategories(ctx *gin.Context)
 		GetCategoryByID(ctx *gin.Context)
+		UpdateCategorySchedule(ctx *gin.Context)
 	}
 
 	categoryController struct {
@@ -56,3 +59,32 @@ func (c *categoryController) GetCategoryByID(ctx *gin.Context) {
 	res := presentation.BuildResponseSuccess(message.SuccessGetCategory, responseCategory)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *categoryController) UpdateCategorySchedule(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var req request.UpdateSchedule
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	responseCategory, err := c.categoryService.UpdateCategorySchedule(ctx.Request.Context(), id, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, category.ErrorCategoryNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, shared.ErrorInvalidTimeWindow):
+			status = http.StatusBadRequest
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedUpdateCategorySchedule, err.Error(), nil)
+		ctx.AbortWithStatusJSON(status, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdateCategorySchedule, responseCategory)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/device"
+	"fp-kpl/domain/station"
+	"fp-kpl/domain/table"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	DeviceController interface {
+		CreateDevice(ctx *gin.Context)
+		GetAllDevices(ctx *gin.Context)
+		RevokeDevice(ctx *gin.Context)
+	}
+
+	deviceController struct {
+		deviceService service.DeviceService
+	}
+)
+
+func NewDeviceController(deviceService service.DeviceService) DeviceController {
+	return &deviceController{deviceService: deviceService}
+}
+
+func (c *deviceController) CreateDevice(ctx *gin.Context) {
+	var req request.CreateDevice
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.deviceService.CreateDevice(ctx.Request.Context(), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedCreateDevice, err.Error(), nil)
+		ctx.AbortWithStatusJSON(deviceErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessCreateDevice, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *deviceController) GetAllDevices(ctx *gin.Context) {
+	result, err := c.deviceService.GetAllDevices(ctx.Request.Context())
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetAllDevices, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetAllDevices, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *deviceController) RevokeDevice(ctx *gin.Context) {
+	result, err := c.deviceService.RevokeDevice(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedRevokeDevice, err.Error(), nil)
+		ctx.AbortWithStatusJSON(deviceErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessRevokeDevice, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func deviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, device.ErrorInvalidDeviceRole):
+		return http.StatusBadRequest
+	case errors.Is(err, device.ErrorDeviceNotFound),
+		errors.Is(err, station.ErrorStationNotFound),
+		errors.Is(err, table.ErrorTableNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"encoding/json"
+	"fp-kpl/application/service"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const DefaultDisplayStreamInterval = 5 * time.Second
+
+type (
+	DisplayController interface {
+		GetBoard(ctx *gin.Context)
+		StreamBoard(ctx *gin.Context)
+	}
+
+	displayController struct {
+		displayService service.DisplayService
+		streamInterval time.Duration
+	}
+)
+
+func NewDisplayController(displayService service.DisplayService, streamInterval time.Duration) DisplayController {
+	if streamInterval <= 0 {
+		streamInterval = DefaultDisplayStreamInterval
+	}
+	return &displayController{
+		displayService: displayService,
+		streamInterval: streamInterval,
+	}
+}
+
+func (c *displayController) GetBoard(ctx *gin.Context) {
+	board, err := c.displayService.GetBoard(ctx.Request.Context())
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDisplayBoard, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetDisplayBoard, board)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// StreamBoard pushes a "board" server-sent event whenever the queue codes or
+// their highlights change, and a comment line in between to keep the
+// connection open.
+func (c *displayController) StreamBoard(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(c.streamInterval)
+	defer ticker.Stop()
+
+	var lastBoard []byte
+	first := true
+	ctx.Stream(func(w io.Writer) bool {
+		if !first {
+			select {
+			case <-ctx.Request.Context().Done():
+				return false
+			case <-ticker.C:
+			}
+		}
+		first = false
+
+		board, err := c.displayService.GetBoard(ctx.Request.Context())
+		if err != nil {
+			ctx.SSEvent("error", message.FailedGetDisplayBoard)
+			return true
+		}
+
+		// The timestamp changes on every tick, so compare the codes only.
+		updatedAt := board.UpdatedAt
+		board.UpdatedAt = time.Time{}
+		encoded, err := json.Marshal(board)
+		if err != nil {
+			ctx.SSEvent("error", message.FailedGetDisplayBoard)
+			return true
+		}
+		if string(encoded) == string(lastBoard) {
+			_, err = io.WriteString(w, ":\n\n")
+			return err == nil
+		}
+
+		lastBoard = encoded
+		board.UpdatedAt = updatedAt
+		ctx.SSEvent("board", board)
+		return true
+	})
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fp-kpl/application/response"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/port"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var fakePaymentPage = template.Must(template.New("fake_payment").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Fake Payment {{.Payment.OrderID}}</title>
+</head>
+<body>
+    <h1>Fake Payment Gateway</h1>
+    <p>Order: {{.Payment.OrderID}}</p>
+    <p>Amount: {{.Payment.GrossAmount}}</p>
+    <p>Status: <strong>{{.Payment.Status}}</strong></p>
+    {{if .Error}}<p style="color: red;">{{.Error}}</p>{{end}}
+    {{range .Actions}}
+    <form method="POST" action="{{$.Path}}/{{$.Payment.Token}}/{{.}}" style="display: inline;">
+        <button type="submit">{{.}}</button>
+    </form>
+    {{end}}
+</body>
+</html>
+`))
+
+type (
+	FakeGatewayController interface {
+		GetPaymentPage(ctx *gin.Context)
+		CompletePayment(ctx *gin.Context)
+	}
+
+	fakeGatewayController struct {
+		fakeGatewayService service.FakeGatewayService
+	}
+)
+
+func NewFakeGatewayController(fakeGatewayService service.FakeGatewayService) FakeGatewayController {
+	return &fakeGatewayController{fakeGatewayService: fakeGatewayService}
+}
+
+func (c *fakeGatewayController) GetPaymentPage(ctx *gin.Context) {
+	payment, err := c.fakeGatewayService.GetPayment(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetFakePayment, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+		return
+	}
+
+	c.render(ctx, http.StatusOK, payment, nil)
+}
+
+func (c *fakeGatewayController) CompletePayment(ctx *gin.Context) {
+	payment, err := c.fakeGatewayService.CompletePayment(ctx.Request.Context(), ctx.Param("token"), ctx.Param("action"))
+	if err != nil {
+		if errors.Is(err, port.ErrorFakePaymentNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedCompleteFakePayment, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		if errors.Is(err, port.ErrorFakeInvalidAction) {
+			res := presentation.BuildResponseFailed(message.FailedCompleteFakePayment, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
+		if errors.Is(err, port.ErrorFakePaymentNotPending) {
+			res := presentation.BuildResponseFailed(message.FailedCompleteFakePayment, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		}
+
+		c.render(ctx, http.StatusBadGateway, payment, err)
+		return
+	}
+
+	c.render(ctx, http.StatusOK, payment, nil)
+}
+
+func (c *fakeGatewayController) render(ctx *gin.Context, status int, payment response.FakePayment, err error) {
+	data := map[string]interface{}{
+		"Payment": payment,
+		"Path":    port.FakePaymentPath,
+		"Actions": port.FakeActions,
+	}
+	if err != nil {
+		data["Error"] = err.Error()
+	}
+
+	var page bytes.Buffer
+	if err := fakePaymentPage.Execute(&page, data); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetFakePayment, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	ctx.Data(status, "text/html; charset=utf-8", page.Bytes())
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	FeedbackController interface {
+		SubmitFeedback(ctx *gin.Context)
+		GetAllFeedback(ctx *gin.Context)
+	}
+
+	feedbackController struct {
+		feedbackService service.FeedbackService
+	}
+)
+
+func NewFeedbackController(feedbackService service.FeedbackService) FeedbackController {
+	return &feedbackController{feedbackService: feedbackService}
+}
+
+func (c *feedbackController) SubmitFeedback(ctx *gin.Context) {
+	var req request.SubmitFeedback
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.feedbackService.SubmitFeedback(ctx.Request.Context(), ctx.GetString("user_id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedSubmitFeedback, err.Error(), nil)
+		ctx.AbortWithStatusJSON(feedbackErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessSubmitFeedback, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *feedbackController) GetAllFeedback(ctx *gin.Context) {
+	var req request.GetAllFeedback
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromQuery, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.feedbackService.GetAllFeedback(ctx.Request.Context(), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetAllFeedback, err.Error(), nil)
+		ctx.AbortWithStatusJSON(feedbackErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetAllFeedback, result.Data, result.Response)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func feedbackErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transaction.ErrorTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, transaction.ErrorNotTransactionOwner):
+		return http.StatusForbidden
+	case errors.Is(err, feedback.ErrorFeedbackAlreadySubmitted):
+		return http.StatusConflict
+	case errors.Is(err, feedback.ErrorSubmissionClosed):
+		return http.StatusGone
+	case errors.Is(err, feedback.ErrorNotServed),
+		errors.Is(err, feedback.ErrorInvalidRating),
+		errors.Is(err, feedback.ErrorInvalidMenuRating):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fp-kpl/application/request"
 	"fp-kpl/application/response"
 	"fp-kpl/application/service"
+	"fp-kpl/domain/menu/favourite"
 	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/shared"
 	"fp-kpl/presentation"
 	"fp-kpl/presentation/message"
 	"net/http"
@@ -18,6 +20,11 @@ type (
 		GetAllMenus(ctx *gin.Context)
 		GetMenuByID(ctx *gin.Context)
 		UpdateMenuAvailability(ctx *gin.Context)
+		UpdateMenuSchedule(ctx *gin.Context)
+		UpdateMenuPriceOverrides(ctx *gin.Context)
+		GetFavouriteMenus(ctx *gin.Context)
+		AddFavouriteMenu(ctx *gin.Context)
+		RemoveFavouriteMenu(ctx *gin.Context)
 	}
 
 	menuController struct {
@@ -107,3 +114,110 @@ func (c *menuController) UpdateMenuAvailability(ctx *gin.Context) {
 	res := presentation.BuildResponseSuccess(message.SuccessUpdateMenuAvailability, responseMenu)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *menuController) UpdateMenuSchedule(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var req request.UpdateSchedule
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	responseMenu, err := c.menuService.UpdateMenuSchedule(ctx.Request.Context(), id, req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedUpdateMenuSchedule, err.Error(), nil)
+		ctx.AbortWithStatusJSON(menuScheduleErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdateMenuSchedule, responseMenu)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *menuController) UpdateMenuPriceOverrides(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var req request.UpdatePriceOverrides
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	responseMenu, err := c.menuService.UpdateMenuPriceOverrides(ctx.Request.Context(), id, req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedUpdateMenuPriceOverrides, err.Error(), nil)
+		ctx.AbortWithStatusJSON(menuScheduleErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdateMenuPriceOverrides, responseMenu)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *menuController) GetFavouriteMenus(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	favouriteMenus, err := c.menuService.GetFavouriteMenus(ctx.Request.Context(), userID)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetFavouriteMenus, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetFavouriteMenus, favouriteMenus)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *menuController) AddFavouriteMenu(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	responseMenu, err := c.menuService.AddFavouriteMenu(ctx.Request.Context(), userID, id)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedAddFavouriteMenu, err.Error(), nil)
+		ctx.AbortWithStatusJSON(favouriteErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessAddFavouriteMenu, responseMenu)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *menuController) RemoveFavouriteMenu(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	if err := c.menuService.RemoveFavouriteMenu(ctx.Request.Context(), userID, id); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedRemoveFavouriteMenu, err.Error(), nil)
+		ctx.AbortWithStatusJSON(favouriteErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessRemoveFavouriteMenu, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func favouriteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, menu.ErrorMenuNotFound),
+		errors.Is(err, favourite.ErrorFavouriteNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func menuScheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, menu.ErrorMenuNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, shared.ErrorInvalidTimeWindow),
+		errors.Is(err, menu.ErrorInvalidPriceOverride):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"fp-kpl/application/request"
 	"fp-kpl/application/response"
 	"fp-kpl/application/service"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/transaction"
 	"fp-kpl/presentation"
 	"fp-kpl/presentation/message"
 	"github.com/gin-gonic/gin"
@@ -34,17 +38,63 @@ func (c *orderController) CalculateTotalPrice(ctx *gin.Context) {
 		return
 	}
 
-	totalPrice, err := c.orderService.CalculateTotalPrice(ctx.Request.Context(), req.Orders)
+	userID := ctx.MustGet("user_id").(string)
+	pricing, err := c.orderService.CalculatePriceBreakdown(ctx.Request.Context(), userID, req.Orders, req.VoucherCode, req.OrderType, req.PickupAt)
 	if err != nil {
 		res := presentation.BuildResponseFailed(message.FailedCalculateTotalPrice, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		ctx.AbortWithStatusJSON(orderErrorStatus(err), res)
 		return
 	}
 
+	discounts := make([]response.Discount, 0, len(pricing.Discounts))
+	for _, discount := range pricing.Discounts {
+		discounts = append(discounts, response.Discount{
+			PromotionID: discount.PromotionID.String(),
+			Name:        discount.Name,
+			VoucherCode: discount.VoucherCode,
+			Amount:      discount.Amount.Price.String(),
+		})
+	}
+
 	result := response.CalculateTotalPrice{
-		TotalPrice: totalPrice.Price.String(),
+		TotalPrice: pricing.Total.Price.String(),
+		Pricing: response.PriceBreakdown{
+			Subtotal:          pricing.Subtotal.Price.String(),
+			Discounts:         discounts,
+			Discount:          pricing.Discount.Price.String(),
+			LoyaltyPoints:     pricing.LoyaltyPoints,
+			LoyaltyDiscount:   pricing.LoyaltyDiscount.Price.String(),
+			PackagingFee:      pricing.PackagingFee.Price.String(),
+			ServiceChargeRate: pricing.ServiceChargeRate.String(),
+			ServiceCharge:     pricing.ServiceCharge.Price.String(),
+			TaxRate:           pricing.TaxRate.String(),
+			Tax:               pricing.Tax.Price.String(),
+			Rounding:          pricing.Rounding.Price.String(),
+			Total:             pricing.Total.Price.String(),
+		},
 	}
 
 	res := presentation.BuildResponseSuccess(message.SuccessCalculateTotalPrice, result)
 	ctx.JSON(http.StatusOK, res)
 }
+
+// orderErrorStatus tells the customer when their voucher, an item out of its
+// schedule or the order type and pickup time was the problem.
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, promotion.ErrorVoucherNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, promotion.ErrorVoucherNotApplicable),
+		errors.Is(err, promotion.ErrorUsageLimitReached),
+		errors.Is(err, promotion.ErrorUserLimitReached),
+		errors.Is(err, promotion.ErrorAccountRequired),
+		errors.Is(err, menu.ErrorMenuOutOfSchedule),
+		errors.Is(err, transaction.ErrorInvalidOrderType),
+		errors.Is(err, transaction.ErrorPickupTimeRequired),
+		errors.Is(err, transaction.ErrorInvalidPickupTime),
+		errors.Is(err, transaction.ErrorPickupTooFarAhead):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
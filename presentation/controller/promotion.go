@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	PromotionController interface {
+		CreatePromotion(ctx *gin.Context)
+		GetAllPromotions(ctx *gin.Context)
+		UpdatePromotionStatus(ctx *gin.Context)
+	}
+
+	promotionController struct {
+		promotionService service.PromotionService
+	}
+)
+
+func NewPromotionController(promotionService service.PromotionService) PromotionController {
+	return &promotionController{promotionService: promotionService}
+}
+
+func (c *promotionController) CreatePromotion(ctx *gin.Context) {
+	var req request.CreatePromotion
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.promotionService.CreatePromotion(ctx.Request.Context(), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedCreatePromotion, err.Error(), nil)
+		ctx.AbortWithStatusJSON(promotionErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessCreatePromotion, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *promotionController) GetAllPromotions(ctx *gin.Context) {
+	result, err := c.promotionService.GetAllPromotions(ctx.Request.Context())
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetAllPromotions, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetAllPromotions, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *promotionController) UpdatePromotionStatus(ctx *gin.Context) {
+	var req request.UpdatePromotionStatus
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.promotionService.UpdatePromotionStatus(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedUpdatePromotionStatus, err.Error(), nil)
+		ctx.AbortWithStatusJSON(promotionErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdatePromotionStatus, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, promotion.ErrorPromotionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, promotion.ErrorVoucherAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, promotion.ErrorInvalidPromotion),
+		errors.Is(err, promotion.ErrorInvalidPromotionType):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SLAController interface {
+		GetCurrentBreaches(ctx *gin.Context)
+	}
+
+	slaController struct {
+		slaService service.SLAService
+	}
+)
+
+func NewSLAController(slaService service.SLAService) SLAController {
+	return &slaController{slaService: slaService}
+}
+
+func (c *slaController) GetCurrentBreaches(ctx *gin.Context) {
+	breaches := c.slaService.GetCurrentBreaches(ctx.Request.Context())
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetSLABreaches, breaches)
+	ctx.JSON(http.StatusOK, res)
+}
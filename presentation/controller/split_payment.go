@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SplitPaymentController interface {
+		SplitBill(ctx *gin.Context)
+		GetPayments(ctx *gin.Context)
+		RefundPayment(ctx *gin.Context)
+	}
+
+	splitPaymentController struct {
+		splitPaymentService service.SplitPaymentService
+	}
+)
+
+func NewSplitPaymentController(splitPaymentService service.SplitPaymentService) SplitPaymentController {
+	return &splitPaymentController{splitPaymentService: splitPaymentService}
+}
+
+func (c *splitPaymentController) SplitBill(ctx *gin.Context) {
+	var req request.SplitBill
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.splitPaymentService.SplitBill(ctx.Request.Context(), userID, ctx.Param("id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedSplitBill, err.Error(), nil)
+		ctx.AbortWithStatusJSON(splitPaymentErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessSplitBill, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *splitPaymentController) GetPayments(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.splitPaymentService.GetPayments(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetPayments, err.Error(), nil)
+		ctx.AbortWithStatusJSON(splitPaymentErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetPayments, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *splitPaymentController) RefundPayment(ctx *gin.Context) {
+	var req request.RefundPayment
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.splitPaymentService.RefundPayment(ctx.Request.Context(), ctx.Param("id"), ctx.Param("payment_id"), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedRefundPayment, err.Error(), nil)
+		ctx.AbortWithStatusJSON(splitPaymentErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessRefundPayment, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func splitPaymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transaction.ErrorTransactionNotFound),
+		errors.Is(err, transaction.ErrorSplitPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, transaction.ErrorNotTransactionOwner):
+		return http.StatusForbidden
+	case errors.Is(err, transaction.ErrorPaymentAlreadyFinal),
+		errors.Is(err, transaction.ErrorPaymentNotPaid):
+		return http.StatusConflict
+	case errors.Is(err, transaction.ErrorInvalidSplit),
+		errors.Is(err, transaction.ErrorSplitExceedsTotal),
+		errors.Is(err, transaction.ErrorSplitNotSupported),
+		errors.Is(err, transaction.ErrorInvalidRefund),
+		errors.Is(err, port.ErrorPaymentProviderNotFound):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/station"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	StationController interface {
+		GetAllStations(ctx *gin.Context)
+		GetStationByID(ctx *gin.Context)
+		AssignMenuStation(ctx *gin.Context)
+		AssignCategoryStation(ctx *gin.Context)
+		AssignUserStation(ctx *gin.Context)
+	}
+
+	stationController struct {
+		stationService service.StationService
+	}
+)
+
+func NewStationController(stationService service.StationService) StationController {
+	return &stationController{stationService: stationService}
+}
+
+func (c *stationController) GetAllStations(ctx *gin.Context) {
+	stations, err := c.stationService.GetAllStations(ctx.Request.Context())
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetAllStations, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetAllStations, stations)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *stationController) GetStationByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	responseStation, err := c.stationService.GetStationByID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, station.ErrorStationNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetStation, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetStation, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetStation, responseStation)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *stationController) AssignMenuStation(ctx *gin.Context) {
+	c.assign(ctx, "menu_id", c.stationService.AssignMenuStation)
+}
+
+func (c *stationController) AssignCategoryStation(ctx *gin.Context) {
+	c.assign(ctx, "category_id", c.stationService.AssignCategoryStation)
+}
+
+func (c *stationController) AssignUserStation(ctx *gin.Context) {
+	c.assign(ctx, "user_id", c.stationService.AssignUserStation)
+}
+
+func (c *stationController) assign(
+	ctx *gin.Context,
+	param string,
+	assignFunc func(ctx context.Context, id string, req request.AssignStation) (response.StationAssignment, error),
+) {
+	var req request.AssignStation
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := assignFunc(ctx.Request.Context(), ctx.Param(param), req)
+	if err != nil {
+		if errors.Is(err, station.ErrorStationNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedAssignStation, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedAssignStation, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessAssignStation, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -15,6 +15,7 @@ type (
 	TableController interface {
 		GetAllTables(ctx *gin.Context)
 		GetTableByID(ctx *gin.Context)
+		GetTableToken(ctx *gin.Context)
 	}
 
 	tableController struct {
@@ -56,3 +57,22 @@ func (c *tableController) GetTableByID(ctx *gin.Context) {
 	res := presentation.BuildResponseSuccess(message.SuccessGetTable, responseTable)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *tableController) GetTableToken(ctx *gin.Context) {
+	id := ctx.Param("id")
+	tableToken, err := c.tableService.GetTableToken(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, table.ErrorTableNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetTableToken, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetTableToken, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetTableToken, tableToken)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -4,6 +4,10 @@ import (
 	"errors"
 	"fp-kpl/application/request"
 	"fp-kpl/application/service"
+	"fp-kpl/domain/loyalty"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/station"
+	"fp-kpl/domain/table"
 	"fp-kpl/domain/transaction"
 	"fp-kpl/platform/pagination"
 	"fp-kpl/presentation"
@@ -20,11 +24,18 @@ type (
 		GetAllTransactionsWithPagination(ctx *gin.Context)
 		GetAllReadyToServeTransactionList(ctx *gin.Context)
 		GetTransactionByID(ctx *gin.Context)
+		LookupTransaction(ctx *gin.Context)
+		GetGuestTransaction(ctx *gin.Context)
+		Reorder(ctx *gin.Context)
 		GetNextOrder(ctx *gin.Context)
+		GetStationNextOrder(ctx *gin.Context)
 		StartCooking(ctx *gin.Context)
 		FinishCooking(ctx *gin.Context)
 		StartDelivering(ctx *gin.Context)
 		FinishDelivering(ctx *gin.Context)
+		CollectOrder(ctx *gin.Context)
+		UpdatePriority(ctx *gin.Context)
+		GetStatusChanges(ctx *gin.Context)
 	}
 
 	transactionController struct {
@@ -46,11 +57,18 @@ func (t transactionController) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
-	userID := ctx.MustGet("user_id").(string)
+	// Kiosk devices order on behalf of guests and carry no user.
+	userID := ctx.GetString("user_id")
 	result, err := t.transactionService.CreateTransaction(ctx.Request.Context(), userID, req)
 	if err != nil {
+		if errors.Is(err, port.ErrorPaymentProviderNotFound) || errors.Is(err, table.ErrorTableRequired) ||
+			errors.Is(err, loyalty.ErrorInsufficientPoints) || errors.Is(err, loyalty.ErrorAccountRequired) {
+			res := presentation.BuildResponseFailed(message.FailedCreateTransaction, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
 		res := presentation.BuildResponseFailed(message.FailedCreateTransaction, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		ctx.AbortWithStatusJSON(orderErrorStatus(err), res)
 		return
 	}
 
@@ -66,8 +84,34 @@ func (t transactionController) HookTransaction(ctx *gin.Context) {
 		return
 	}
 
-	err := t.transactionService.HookTransaction(ctx.Request.Context(), datas)
+	// The original webhook route has no provider and stays with Midtrans.
+	provider := ctx.Param("provider")
+	if provider == "" {
+		provider = transaction.PaymentProviderMidtrans
+	}
+
+	headers := make(map[string]string, len(ctx.Request.Header))
+	for key := range ctx.Request.Header {
+		headers[key] = ctx.Request.Header.Get(key)
+	}
+
+	err := t.transactionService.HookTransaction(ctx.Request.Context(), provider, headers, datas)
 	if err != nil {
+		if errors.Is(err, port.ErrorInvalidSignature) {
+			res := presentation.BuildResponseFailed(message.FailedHookTransaction, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		}
+		if errors.Is(err, port.ErrorPaymentProviderNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedHookTransaction, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		if errors.Is(err, port.ErrorPaymentProviderMismatch) {
+			res := presentation.BuildResponseFailed(message.FailedHookTransaction, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
 		res := presentation.BuildResponseFailed(message.FailedHookTransaction, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
 		return
@@ -132,8 +176,95 @@ func (t transactionController) GetTransactionByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+func (t transactionController) Reorder(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("id")
+
+	result, err := t.transactionService.Reorder(ctx.Request.Context(), userID, id)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedReorder, err.Error(), nil)
+		ctx.AbortWithStatusJSON(reorderErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessReorder, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func reorderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transaction.ErrorTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, transaction.ErrorNotTransactionOwner):
+		return http.StatusForbidden
+	default:
+		return orderErrorStatus(err)
+	}
+}
+
+func (t transactionController) LookupTransaction(ctx *gin.Context) {
+	reference := ctx.Param("reference")
+
+	result, err := t.transactionService.LookupTransaction(ctx.Request.Context(), reference)
+	if err != nil {
+		if errors.Is(err, transaction.ErrorTransactionNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetTransaction, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetTransaction, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetTransaction, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (t transactionController) GetGuestTransaction(ctx *gin.Context) {
+	result, err := t.transactionService.GetGuestTransaction(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		if errors.Is(err, transaction.ErrorTransactionNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetTransaction, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetTransaction, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetTransaction, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
 func (t transactionController) GetNextOrder(ctx *gin.Context) {
-	result, err := t.transactionService.GetNextOrder(ctx.Request.Context())
+	// Device keys carry no user; the service uses the device's station.
+	userID := ctx.GetString("user_id")
+	result, err := t.transactionService.GetNextOrder(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, transaction.ErrorNextOrderNotFound) {
+			res := presentation.BuildResponseSuccess(message.SuccessGetNextOrder, nil)
+			ctx.JSON(http.StatusOK, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetNextOrder, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetNextOrder, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (t transactionController) GetStationNextOrder(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	stationID := ctx.Param("station_id")
+
+	result, err := t.transactionService.GetStationNextOrder(ctx.Request.Context(), userID, stationID)
 	if err != nil {
 		if errors.Is(err, transaction.ErrorNextOrderNotFound) {
 			res := presentation.BuildResponseSuccess(message.SuccessGetNextOrder, nil)
@@ -141,6 +272,18 @@ func (t transactionController) GetNextOrder(ctx *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, station.ErrorStationNotAllowed) {
+			res := presentation.BuildResponseFailed(message.FailedGetNextOrder, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, res)
+			return
+		}
+
+		if errors.Is(err, station.ErrorStationNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetNextOrder, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
 		res := presentation.BuildResponseFailed(message.FailedGetNextOrder, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
 		return
@@ -158,7 +301,8 @@ func (t transactionController) StartCooking(ctx *gin.Context) {
 		return
 	}
 
-	result, err := t.transactionService.StartCooking(ctx.Request.Context(), req)
+	userID := ctx.GetString("user_id")
+	result, err := t.transactionService.StartCooking(ctx.Request.Context(), userID, req)
 	if err != nil {
 		res := presentation.BuildResponseFailed(message.FailedStartCooking, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
@@ -177,7 +321,8 @@ func (t transactionController) FinishCooking(ctx *gin.Context) {
 		return
 	}
 
-	result, err := t.transactionService.FinishCooking(ctx.Request.Context(), req)
+	userID := ctx.GetString("user_id")
+	result, err := t.transactionService.FinishCooking(ctx.Request.Context(), userID, req)
 	if err != nil {
 		res := presentation.BuildResponseFailed(message.FailedFinishCooking, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
@@ -199,7 +344,7 @@ func (t transactionController) StartDelivering(ctx *gin.Context) {
 	result, err := t.transactionService.StartDelivering(ctx.Request.Context(), req)
 	if err != nil {
 		res := presentation.BuildResponseFailed(message.FailedStartDelivering, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		ctx.AbortWithStatusJSON(handOverErrorStatus(err), res)
 		return
 	}
 
@@ -218,10 +363,87 @@ func (t transactionController) FinishDelivering(ctx *gin.Context) {
 	result, err := t.transactionService.FinishDelivering(ctx.Request.Context(), req)
 	if err != nil {
 		res := presentation.BuildResponseFailed(message.FailedFinishDelivering, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		ctx.AbortWithStatusJSON(handOverErrorStatus(err), res)
 		return
 	}
 
 	res := presentation.BuildResponseSuccess(message.SuccessFinishDelivering, result)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (t transactionController) CollectOrder(ctx *gin.Context) {
+	var req request.CollectOrder
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := t.transactionService.CollectOrder(ctx.Request.Context(), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedCollectOrder, err.Error(), nil)
+		ctx.AbortWithStatusJSON(handOverErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessCollectOrder, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// handOverErrorStatus tells waiters and the counter when an order is handed
+// over the wrong way or is not ready for it.
+func handOverErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transaction.ErrorTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, transaction.ErrorNotDineIn),
+		errors.Is(err, transaction.ErrorNotPackaged),
+		errors.Is(err, transaction.ErrorInvalidOrderStatus):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (t transactionController) UpdatePriority(ctx *gin.Context) {
+	var req request.UpdatePriority
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := t.transactionService.UpdatePriority(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		if errors.Is(err, transaction.ErrorTransactionNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedUpdatePriority, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedUpdatePriority, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdatePriority, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (t transactionController) GetStatusChanges(ctx *gin.Context) {
+	result, err := t.transactionService.GetStatusChanges(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, transaction.ErrorTransactionNotFound) {
+			res := presentation.BuildResponseFailed(message.FailedGetStatusChanges, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+
+		res := presentation.BuildResponseFailed(message.FailedGetStatusChanges, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetStatusChanges, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"errors"
 	"fp-kpl/application/request"
 	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
 	"fp-kpl/presentation"
 	"fp-kpl/presentation/message"
 	"github.com/gin-gonic/gin"
@@ -14,6 +16,8 @@ type (
 		Register(ctx *gin.Context)
 		Login(ctx *gin.Context)
 		Me(ctx *gin.Context)
+		UpdateMe(ctx *gin.Context)
+		DeleteMe(ctx *gin.Context)
 	}
 
 	userController struct {
@@ -67,7 +71,7 @@ func (c *userController) Login(ctx *gin.Context) {
 func (c *userController) Me(ctx *gin.Context) {
 	userID := ctx.MustGet("user_id").(string)
 
-	result, err := c.userService.GetUserByID(ctx.Request.Context(), userID)
+	result, err := c.userService.Me(ctx.Request.Context(), userID)
 	if err != nil {
 		res := presentation.BuildResponseFailed(message.FailedGetUser, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
@@ -77,3 +81,59 @@ func (c *userController) Me(ctx *gin.Context) {
 	res := presentation.BuildResponseSuccess(message.SuccessGetUser, result)
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *userController) UpdateMe(ctx *gin.Context) {
+	var req request.UserUpdateProfile
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.userService.UpdateProfile(ctx.Request.Context(), userID, req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedUpdateUser, err.Error(), nil)
+		ctx.AbortWithStatusJSON(userErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessUpdateUser, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *userController) DeleteMe(ctx *gin.Context) {
+	var req request.UserDelete
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	if err := c.userService.DeleteAccount(ctx.Request.Context(), userID, req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedDeleteUser, err.Error(), nil)
+		ctx.AbortWithStatusJSON(userErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessDeleteUser, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, user.ErrorGetUserById):
+		return http.StatusNotFound
+	case errors.Is(err, user.ErrorPasswordRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, user.ErrorInvalidPassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, user.ErrorDeletionNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, user.ErrorEmailAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
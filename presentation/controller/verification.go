@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"errors"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/domain/verification"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	VerificationController interface {
+		GetStatus(ctx *gin.Context)
+		SendCode(ctx *gin.Context)
+		Verify(ctx *gin.Context)
+		VerifyEmailLink(ctx *gin.Context)
+	}
+
+	verificationController struct {
+		verificationService service.VerificationService
+	}
+)
+
+func NewVerificationController(verificationService service.VerificationService) VerificationController {
+	return &verificationController{
+		verificationService: verificationService,
+	}
+}
+
+func (c *verificationController) GetStatus(ctx *gin.Context) {
+	userID := ctx.MustGet("user_id").(string)
+
+	result, err := c.verificationService.GetStatus(ctx.Request.Context(), userID)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetVerification, err.Error(), nil)
+		ctx.AbortWithStatusJSON(verificationErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessGetVerification, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *verificationController) SendCode(ctx *gin.Context) {
+	var req request.VerificationSend
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.verificationService.SendCode(ctx.Request.Context(), userID, req.Channel)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedSendVerification, err.Error(), nil)
+		ctx.AbortWithStatusJSON(verificationErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessSendVerification, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *verificationController) Verify(ctx *gin.Context) {
+	var req request.VerificationVerify
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userID := ctx.MustGet("user_id").(string)
+	result, err := c.verificationService.Verify(ctx.Request.Context(), userID, req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedVerify, err.Error(), nil)
+		ctx.AbortWithStatusJSON(verificationErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessVerify, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// VerifyEmailLink is opened from the email, so it takes the challenge and code
+// from the query string and needs no token.
+func (c *verificationController) VerifyEmailLink(ctx *gin.Context) {
+	var req request.VerificationLink
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := presentation.BuildResponseFailed(message.FailedGetDataFromBody, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.verificationService.VerifyLink(ctx.Request.Context(), req)
+	if err != nil {
+		res := presentation.BuildResponseFailed(message.FailedVerify, err.Error(), nil)
+		ctx.AbortWithStatusJSON(verificationErrorStatus(err), res)
+		return
+	}
+
+	res := presentation.BuildResponseSuccess(message.SuccessVerify, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func verificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, user.ErrorGetUserById),
+		errors.Is(err, verification.ErrorChallengeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, verification.ErrorInvalidChannel),
+		errors.Is(err, verification.ErrorNoDestination),
+		errors.Is(err, verification.ErrorInvalidCode),
+		errors.Is(err, verification.ErrorCodeExpired):
+		return http.StatusBadRequest
+	case errors.Is(err, verification.ErrorAlreadyVerified):
+		return http.StatusConflict
+	case errors.Is(err, verification.ErrorTooManyAttempts),
+		errors.Is(err, verification.ErrorResendTooSoon),
+		errors.Is(err, verification.ErrorTooManyCodes):
+		return http.StatusTooManyRequests
+	case errors.Is(err, verification.ErrorSendCode):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,23 @@
+package message
+
+const (
+	FailedOpenCart          = "Failed to open cart"
+	FailedGetCart           = "Failed to get cart"
+	FailedUpdateCart        = "Failed to update cart"
+	FailedAddCartLine       = "Failed to add cart line"
+	FailedUpdateCartLine    = "Failed to update cart line"
+	FailedRemoveCartLine    = "Failed to remove cart line"
+	FailedApplyCartVoucher  = "Failed to apply voucher"
+	FailedRemoveCartVoucher = "Failed to remove voucher"
+	FailedCheckoutCart      = "Failed to check out cart"
+
+	SuccessOpenCart          = "Successfully opened cart"
+	SuccessGetCart           = "Successfully retrieved cart"
+	SuccessUpdateCart        = "Successfully updated cart"
+	SuccessAddCartLine       = "Successfully added cart line"
+	SuccessUpdateCartLine    = "Successfully updated cart line"
+	SuccessRemoveCartLine    = "Successfully removed cart line"
+	SuccessApplyCartVoucher  = "Successfully applied voucher"
+	SuccessRemoveCartVoucher = "Successfully removed voucher"
+	SuccessCheckoutCart      = "Successfully checked out cart"
+)
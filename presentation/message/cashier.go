@@ -0,0 +1,13 @@
+package message
+
+const (
+	FailedOpenShift       = "failed open shift"
+	FailedGetCurrentShift = "failed get current shift"
+	FailedCloseShift      = "failed close shift"
+	FailedConfirmPayment  = "failed confirm payment"
+
+	SuccessOpenShift       = "success open shift"
+	SuccessGetCurrentShift = "success get current shift"
+	SuccessCloseShift      = "success close shift"
+	SuccessConfirmPayment  = "success confirm payment"
+)
@@ -1,9 +1,11 @@
 package message
 
 const (
-	FailedGetCategory      = "Failed to get category"
-	FailedGetAllCategories = "Failed to get all categories"
+	FailedGetCategory            = "Failed to get category"
+	FailedGetAllCategories       = "Failed to get all categories"
+	FailedUpdateCategorySchedule = "Failed to update category schedule"
 
-	SuccessGetCategory      = "Successfully retrieved category"
-	SuccessGetAllCategories = "Successfully retrieved all categories"
+	SuccessGetCategory            = "Successfully retrieved category"
+	SuccessGetAllCategories       = "Successfully retrieved all categories"
+	SuccessUpdateCategorySchedule = "Successfully updated category schedule"
 )
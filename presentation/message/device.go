@@ -0,0 +1,12 @@
+package message
+
+const (
+	FailedCreateDevice      = "Failed to create device"
+	FailedGetAllDevices     = "Failed to get all devices"
+	FailedRevokeDevice      = "Failed to revoke device"
+	FailedDeviceKeyNotValid = "Device key not valid"
+
+	SuccessCreateDevice  = "Successfully created device"
+	SuccessGetAllDevices = "Successfully retrieved all devices"
+	SuccessRevokeDevice  = "Successfully revoked device"
+)
@@ -0,0 +1,8 @@
+package message
+
+const (
+	FailedGetDisplayBoard    = "Failed to get display board"
+	FailedDisplayKeyNotValid = "Display key not valid"
+
+	SuccessGetDisplayBoard = "Successfully retrieved display board"
+)
@@ -0,0 +1,6 @@
+package message
+
+const (
+	FailedGetFakePayment      = "failed get fake payment"
+	FailedCompleteFakePayment = "failed complete fake payment"
+)
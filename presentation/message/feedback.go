@@ -0,0 +1,9 @@
+package message
+
+const (
+	FailedSubmitFeedback = "Failed to submit feedback"
+	FailedGetAllFeedback = "Failed to get all feedback"
+
+	SuccessSubmitFeedback = "Successfully submitted feedback"
+	SuccessGetAllFeedback = "Successfully retrieved all feedback"
+)
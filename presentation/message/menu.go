@@ -1,13 +1,23 @@
 package message
 
 const (
-	FailedGetMenu                = "Failed to get menu"
-	FailedGetAllMenus            = "Failed to get all menus"
-	FailedGetMenusByCategory     = "Failed to get menus by category"
-	FailedUpdateMenuAvailability = "Failed to update menu availability"
+	FailedGetMenu                  = "Failed to get menu"
+	FailedGetAllMenus              = "Failed to get all menus"
+	FailedGetMenusByCategory       = "Failed to get menus by category"
+	FailedUpdateMenuAvailability   = "Failed to update menu availability"
+	FailedUpdateMenuSchedule       = "Failed to update menu schedule"
+	FailedUpdateMenuPriceOverrides = "Failed to update menu price overrides"
+	FailedGetFavouriteMenus        = "Failed to get favourite menus"
+	FailedAddFavouriteMenu         = "Failed to add favourite menu"
+	FailedRemoveFavouriteMenu      = "Failed to remove favourite menu"
 
-	SuccessGetMenu                = "Successfully retrieved menu"
-	SuccessGetAllMenus            = "Successfully retrieved all menus"
-	SuccessGetMenusByCategory     = "Successfully retrieved menus by category"
-	SuccessUpdateMenuAvailability = "Successfully updated menu availability"
+	SuccessGetMenu                  = "Successfully retrieved menu"
+	SuccessGetAllMenus              = "Successfully retrieved all menus"
+	SuccessGetMenusByCategory       = "Successfully retrieved menus by category"
+	SuccessUpdateMenuAvailability   = "Successfully updated menu availability"
+	SuccessUpdateMenuSchedule       = "Successfully updated menu schedule"
+	SuccessUpdateMenuPriceOverrides = "Successfully updated menu price overrides"
+	SuccessGetFavouriteMenus        = "Successfully retrieved favourite menus"
+	SuccessAddFavouriteMenu         = "Successfully added favourite menu"
+	SuccessRemoveFavouriteMenu      = "Successfully removed favourite menu"
 )
@@ -0,0 +1,11 @@
+package message
+
+const (
+	FailedCreatePromotion       = "Failed to create promotion"
+	FailedGetAllPromotions      = "Failed to get all promotions"
+	FailedUpdatePromotionStatus = "Failed to update promotion status"
+
+	SuccessCreatePromotion       = "Successfully created promotion"
+	SuccessGetAllPromotions      = "Successfully retrieved all promotions"
+	SuccessUpdatePromotionStatus = "Successfully updated promotion status"
+)
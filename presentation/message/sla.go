@@ -0,0 +1,5 @@
+package message
+
+const (
+	SuccessGetSLABreaches = "success get sla breaches"
+)
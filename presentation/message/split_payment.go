@@ -0,0 +1,11 @@
+package message
+
+const (
+	FailedSplitBill     = "failed split bill"
+	FailedGetPayments   = "failed get payments"
+	FailedRefundPayment = "failed refund payment"
+
+	SuccessSplitBill     = "success split bill"
+	SuccessGetPayments   = "success get payments"
+	SuccessRefundPayment = "success refund payment"
+)
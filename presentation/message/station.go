@@ -0,0 +1,11 @@
+package message
+
+const (
+	FailedGetStation     = "Failed to get station"
+	FailedGetAllStations = "Failed to get all stations"
+	FailedAssignStation  = "Failed to assign station"
+
+	SuccessGetStation     = "Successfully retrieved station"
+	SuccessGetAllStations = "Successfully retrieved all stations"
+	SuccessAssignStation  = "Successfully assigned station"
+)
@@ -1,9 +1,11 @@
 package message
 
 const (
-	FailedGetTable     = "Failed to get table"
-	FailedGetAllTables = "Failed to get all tables"
+	FailedGetTable      = "Failed to get table"
+	FailedGetAllTables  = "Failed to get all tables"
+	FailedGetTableToken = "Failed to get table token"
 
-	SuccessGetTable     = "Successfully retrieved table"
-	SuccessGetAllTables = "Successfully retrieved all tables"
+	SuccessGetTable      = "Successfully retrieved table"
+	SuccessGetAllTables  = "Successfully retrieved all tables"
+	SuccessGetTableToken = "Successfully retrieved table token"
 )
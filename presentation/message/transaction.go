@@ -11,6 +11,10 @@ const (
 	FailedFinishCooking                  = "failed finish cooking"
 	FailedStartDelivering                = "failed start delivering"
 	FailedFinishDelivering               = "failed finish delivering"
+	FailedCollectOrder                   = "failed collect order"
+	FailedUpdatePriority                 = "failed update priority"
+	FailedGetStatusChanges               = "failed get status changes"
+	FailedReorder                        = "failed reorder"
 
 	SuccessCreateTransaction              = "success create transaction"
 	SuccessHookTransaction                = "success hook transaction"
@@ -22,4 +26,8 @@ const (
 	SuccessFinishCooking                  = "success finish cooking"
 	SuccessStartDelivering                = "success start delivering"
 	SuccessFinishDelivering               = "success finish delivering"
+	SuccessCollectOrder                   = "success collect order"
+	SuccessUpdatePriority                 = "success update priority"
+	SuccessGetStatusChanges               = "success get status changes"
+	SuccessReorder                        = "success reorder"
 )
@@ -0,0 +1,12 @@
+package message
+
+const (
+	FailedSendVerification  = "Failed to send verification code"
+	FailedVerify            = "Failed to verify"
+	FailedGetVerification   = "Failed to get verification status"
+	FailedUnverifiedAccount = "Verify your account before placing an order"
+
+	SuccessSendVerification = "Successfully sent verification code"
+	SuccessVerify           = "Successfully verified"
+	SuccessGetVerification  = "Successfully retrieved verification status"
+)
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"fp-kpl/application"
 	"fp-kpl/application/service"
+	"fp-kpl/domain/device"
 	"fp-kpl/presentation"
 	"fp-kpl/presentation/message"
 	"github.com/gin-gonic/gin"
@@ -9,9 +11,37 @@ import (
 	"strings"
 )
 
-func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
+// Authenticate admits users with a JWT and, when deviceService is set, devices
+// with an API key sent in X-Device-Key or as the bearer token. Routes that
+// need a signed-in user pass a nil deviceService.
+func Authenticate(jwtService service.JWTService, deviceService service.DeviceService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
+		deviceKey := ctx.GetHeader("X-Device-Key")
+		if deviceKey == "" && device.IsKey(strings.TrimPrefix(authHeader, "Bearer ")) {
+			deviceKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+
+		if deviceKey != "" {
+			if deviceService == nil {
+				response := presentation.BuildResponseFailed(message.FailedProcessRequest, message.FailedDeniedAccess, nil)
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+				return
+			}
+
+			actor, err := deviceService.AuthenticateDevice(ctx.Request.Context(), deviceKey)
+			if err != nil {
+				response := presentation.BuildResponseFailed(message.FailedProcessRequest, message.FailedDeviceKeyNotValid, nil)
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+				return
+			}
+
+			ctx.Set("device_id", actor.DeviceID)
+			ctx.Set("device_role", actor.Role)
+			ctx.Request = ctx.Request.WithContext(application.WithActor(ctx.Request.Context(), actor))
+			ctx.Next()
+			return
+		}
 
 		if authHeader == "" {
 			response := presentation.BuildResponseFailed(message.FailedProcessRequest, message.FailedTokenNotFound, nil)
@@ -48,6 +78,7 @@ func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
 
 		ctx.Set("token", authHeader)
 		ctx.Set("user_id", userId)
+		ctx.Request = ctx.Request.WithContext(application.WithActor(ctx.Request.Context(), application.Actor{UserID: userId}))
 		ctx.Next()
 	}
 }
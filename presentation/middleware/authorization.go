@@ -11,6 +11,18 @@ import (
 
 func Authorize(userService service.UserService, allowedRoles []user.Role) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		// Devices carry their role on the key and have no user to look up.
+		if deviceRole, ok := ctx.Get("device_role"); ok {
+			if !hasRole(allowedRoles, deviceRole.(string)) {
+				response := presentation.BuildResponseFailed(message.FailedProcessRequest, message.FailedDeniedAccess, nil)
+				ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+				return
+			}
+
+			ctx.Next()
+			return
+		}
+
 		userID := ctx.MustGet("user_id").(string)
 		thisUser, err := userService.GetUserByID(ctx.Request.Context(), userID)
 		if err != nil {
@@ -19,15 +31,7 @@ func Authorize(userService service.UserService, allowedRoles []user.Role) gin.Ha
 			return
 		}
 
-		authorized := false
-		for _, role := range allowedRoles {
-			if thisUser.Role == role.Name {
-				authorized = true
-				break
-			}
-		}
-
-		if !authorized {
+		if !hasRole(allowedRoles, thisUser.Role) {
 			response := presentation.BuildResponseFailed(message.FailedProcessRequest, message.FailedDeniedAccess, nil)
 			ctx.AbortWithStatusJSON(http.StatusForbidden, response)
 			return
@@ -36,3 +40,12 @@ func Authorize(userService service.UserService, allowedRoles []user.Role) gin.Ha
 		ctx.Next()
 	}
 }
+
+func hasRole(allowedRoles []user.Role, role string) bool {
+	for _, allowedRole := range allowedRoles {
+		if allowedRole.Name == role {
+			return true
+		}
+	}
+	return false
+}
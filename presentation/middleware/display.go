@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/device"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthenticateDisplay admits display boards holding the shared API key or a
+// display device key in the X-Display-Key header. The key is never read from
+// the query string, where it would end up in access logs and browser history.
+func AuthenticateDisplay(apiKey string, deviceService service.DeviceService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader("X-Display-Key")
+
+		if key != "" && apiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			ctx.Next()
+			return
+		}
+
+		if device.IsKey(key) {
+			actor, err := deviceService.AuthenticateDevice(ctx.Request.Context(), key)
+			if err == nil && actor.Role == device.RoleDisplay {
+				ctx.Next()
+				return
+			}
+		}
+
+		response := presentation.BuildResponseFailed(message.FailedProcessRequest, message.FailedDisplayKeyNotValid, nil)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+	}
+}
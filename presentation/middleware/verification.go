@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation"
+	"fp-kpl/presentation/message"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerified stops customers whose account is not verified from placing
+// orders, when the configuration asks for it. Devices have no user to check
+// and pass.
+func RequireVerified(verificationService service.VerificationService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, ok := ctx.Get("user_id")
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		if err := verificationService.CheckCanOrder(ctx.Request.Context(), userID.(string)); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, user.ErrorUnverified) {
+				status = http.StatusForbidden
+			}
+			response := presentation.BuildResponseFailed(message.FailedUnverifiedAccount, err.Error(), nil)
+			ctx.AbortWithStatusJSON(status, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
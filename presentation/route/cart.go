@@ -0,0 +1,82 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/device"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CartRoute(route *gin.Engine, cartController controller.CartController, jwtService service.JWTService, deviceService service.DeviceService, userService service.UserService, verificationService service.VerificationService) {
+	cartGroup := route.Group("/api/cart")
+	{
+		// Customer & Kiosk
+		cartGroup.POST("/",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.OpenCart)
+		cartGroup.GET("/:id",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.GetCart)
+		cartGroup.PATCH("/:id",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.UpdateCart)
+		cartGroup.POST("/:id/lines",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.AddCartLine)
+		cartGroup.PATCH("/:id/lines/:line_id",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.UpdateCartLine)
+		cartGroup.DELETE("/:id/lines/:line_id",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.RemoveCartLine)
+		cartGroup.PUT("/:id/voucher",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.ApplyCartVoucher)
+		cartGroup.DELETE("/:id/voucher",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			cartController.RemoveCartVoucher)
+		cartGroup.POST("/:id/checkout",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
+			}),
+			middleware.RequireVerified(verificationService),
+			cartController.CheckoutCart)
+	}
+}
@@ -0,0 +1,41 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CashierRoute(route *gin.Engine, cashierController controller.CashierController, jwtService service.JWTService, userService service.UserService) {
+	cashierRoles := []user.Role{
+		{Name: user.RoleCashier},
+		{Name: user.RoleSuperAdmin},
+	}
+
+	shiftGroup := route.Group("/api/shift")
+	{
+		shiftGroup.POST("/open",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, cashierRoles),
+			cashierController.OpenShift)
+		shiftGroup.GET("/current",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, cashierRoles),
+			cashierController.GetCurrentShift)
+		shiftGroup.POST("/close",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, cashierRoles),
+			cashierController.CloseShift)
+	}
+
+	transactionGroup := route.Group("/api/transaction")
+	{
+		transactionGroup.POST("/:id/confirm-payment",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, cashierRoles),
+			cashierController.ConfirmPayment)
+	}
+}
@@ -2,16 +2,25 @@ package route
 
 import (
 	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
 	"fp-kpl/presentation/controller"
 	"fp-kpl/presentation/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CategoryRoute(route *gin.Engine, categoryController controller.CategoryController, jwtService service.JWTService) {
+func CategoryRoute(route *gin.Engine, categoryController controller.CategoryController, jwtService service.JWTService, deviceService service.DeviceService, userService service.UserService) {
 	categoryGroup := route.Group("/api/category")
 	{
-		categoryGroup.GET("/", middleware.Authenticate(jwtService), categoryController.GetAllCategories)
-		categoryGroup.GET("/:id", middleware.Authenticate(jwtService), categoryController.GetCategoryByID)
+		categoryGroup.GET("/", middleware.Authenticate(jwtService, deviceService), categoryController.GetAllCategories)
+		categoryGroup.GET("/:id", middleware.Authenticate(jwtService, deviceService), categoryController.GetCategoryByID)
+
+		// Superadmin
+		categoryGroup.PUT("/:id/schedule",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			categoryController.UpdateCategorySchedule)
 	}
 }
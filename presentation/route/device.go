@@ -0,0 +1,35 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DeviceRoute(route *gin.Engine, deviceController controller.DeviceController, jwtService service.JWTService, userService service.UserService) {
+	deviceGroup := route.Group("/api/device")
+	{
+		// Superadmin
+		deviceGroup.POST("/",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			deviceController.CreateDevice)
+		deviceGroup.GET("/",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			deviceController.GetAllDevices)
+		deviceGroup.POST("/:id/revoke",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			deviceController.RevokeDevice)
+	}
+}
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DisplayRoute(route *gin.Engine, displayController controller.DisplayController, displayAPIKey string, deviceService service.DeviceService) {
+	displayGroup := route.Group("/api/display", middleware.AuthenticateDisplay(displayAPIKey, deviceService))
+	{
+		displayGroup.GET("/board", displayController.GetBoard)
+		displayGroup.GET("/board/stream", displayController.StreamBoard)
+	}
+}
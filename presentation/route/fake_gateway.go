@@ -0,0 +1,16 @@
+package route
+
+import (
+	"fp-kpl/domain/port"
+	"fp-kpl/presentation/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+func FakeGatewayRoute(route *gin.Engine, fakeGatewayController controller.FakeGatewayController) {
+	fakeGatewayGroup := route.Group(port.FakePaymentPath)
+	{
+		fakeGatewayGroup.GET("/:token", fakeGatewayController.GetPaymentPage)
+		fakeGatewayGroup.POST("/:token/:action", fakeGatewayController.CompletePayment)
+	}
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func FeedbackRoute(route *gin.Engine, feedbackController controller.FeedbackController, jwtService service.JWTService, userService service.UserService) {
+	feedbackGroup := route.Group("/api/feedback")
+	{
+		// Customer
+		feedbackGroup.POST("/",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+			}),
+			feedbackController.SubmitFeedback)
+
+		// Superadmin
+		feedbackGroup.GET("/",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			feedbackController.GetAllFeedback)
+	}
+}
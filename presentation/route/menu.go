@@ -9,17 +9,52 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func MenuRoute(route *gin.Engine, menuController controller.MenuController, jwtService service.JWTService, userService service.UserService) {
+func MenuRoute(route *gin.Engine, menuController controller.MenuController, jwtService service.JWTService, deviceService service.DeviceService, userService service.UserService) {
 	menuGroup := route.Group("/api/menu")
 	{
-		menuGroup.GET("/", middleware.Authenticate(jwtService), menuController.GetAllMenus)
-		menuGroup.GET("/:id", middleware.Authenticate(jwtService), menuController.GetMenuByID)
+		menuGroup.GET("/", middleware.Authenticate(jwtService, deviceService), menuController.GetAllMenus)
+
+		// Customer
+		menuGroup.GET("/favourites",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+			}),
+			menuController.GetFavouriteMenus)
+		menuGroup.POST("/:id/favourite",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+			}),
+			menuController.AddFavouriteMenu)
+		menuGroup.DELETE("/:id/favourite",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+			}),
+			menuController.RemoveFavouriteMenu)
+
+		menuGroup.GET("/:id", middleware.Authenticate(jwtService, deviceService), menuController.GetMenuByID)
 		menuGroup.PATCH("/:id/availability",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleKitchen},
 				{Name: user.RoleSuperAdmin},
 			}),
 			menuController.UpdateMenuAvailability)
+
+		// Superadmin
+		menuGroup.PUT("/:id/schedule",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			menuController.UpdateMenuSchedule)
+		menuGroup.PUT("/:id/price-overrides",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			menuController.UpdateMenuPriceOverrides)
 	}
 }
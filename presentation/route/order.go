@@ -10,6 +10,6 @@ import (
 func OrderRoute(route *gin.Engine, orderController controller.OrderController, jwtService service.JWTService) {
 	orderGroup := route.Group("/api/order")
 	{
-		orderGroup.POST("/calculate-total-price", middleware.Authenticate(jwtService), orderController.CalculateTotalPrice)
+		orderGroup.POST("/calculate-total-price", middleware.Authenticate(jwtService, nil), orderController.CalculateTotalPrice)
 	}
 }
@@ -0,0 +1,35 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PromotionRoute(route *gin.Engine, promotionController controller.PromotionController, jwtService service.JWTService, userService service.UserService) {
+	promotionGroup := route.Group("/api/promotion")
+	{
+		// Superadmin
+		promotionGroup.POST("/",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			promotionController.CreatePromotion)
+		promotionGroup.GET("/",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			promotionController.GetAllPromotions)
+		promotionGroup.PATCH("/:id/status",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			promotionController.UpdatePromotionStatus)
+	}
+}
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SLARoute(route *gin.Engine, slaController controller.SLAController, jwtService service.JWTService, userService service.UserService) {
+	slaGroup := route.Group("/api/sla")
+	{
+		// Superadmin
+		slaGroup.GET("/breaches",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			slaController.GetCurrentBreaches)
+	}
+}
@@ -0,0 +1,41 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SplitPaymentRoute(route *gin.Engine, splitPaymentController controller.SplitPaymentController, jwtService service.JWTService, userService service.UserService) {
+	transactionGroup := route.Group("/api/transaction")
+	{
+		transactionGroup.POST("/:id/split",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: user.RoleWaiter},
+				{Name: user.RoleCashier},
+				{Name: user.RoleSuperAdmin},
+			}),
+			splitPaymentController.SplitBill)
+		transactionGroup.GET("/:id/payments",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: user.RoleWaiter},
+				{Name: user.RoleCashier},
+				{Name: user.RoleSuperAdmin},
+			}),
+			splitPaymentController.GetPayments)
+		transactionGroup.POST("/:id/payments/:payment_id/refund",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCashier},
+				{Name: user.RoleSuperAdmin},
+			}),
+			splitPaymentController.RefundPayment)
+	}
+}
@@ -0,0 +1,38 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StationRoute(route *gin.Engine, stationController controller.StationController, jwtService service.JWTService, deviceService service.DeviceService, userService service.UserService) {
+	stationGroup := route.Group("/api/station")
+	{
+		stationGroup.GET("/", middleware.Authenticate(jwtService, deviceService), stationController.GetAllStations)
+		stationGroup.GET("/:id", middleware.Authenticate(jwtService, deviceService), stationController.GetStationByID)
+
+		// Superadmin
+		stationGroup.PATCH("/menu/:menu_id",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			stationController.AssignMenuStation)
+		stationGroup.PATCH("/category/:category_id",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			stationController.AssignCategoryStation)
+		stationGroup.PATCH("/user/:user_id",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			stationController.AssignUserStation)
+	}
+}
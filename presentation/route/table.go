@@ -2,16 +2,25 @@ package route
 
 import (
 	"fp-kpl/application/service"
+	"fp-kpl/domain/user"
 	"fp-kpl/presentation/controller"
 	"fp-kpl/presentation/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func TableRoute(route *gin.Engine, tableController controller.TableController, jwtService service.JWTService) {
+func TableRoute(route *gin.Engine, tableController controller.TableController, jwtService service.JWTService, deviceService service.DeviceService, userService service.UserService) {
 	tableGroup := route.Group("/api/table")
 	{
-		tableGroup.GET("/", middleware.Authenticate(jwtService), tableController.GetAllTables)
-		tableGroup.GET("/:id", middleware.Authenticate(jwtService), tableController.GetTableByID)
+		tableGroup.GET("/", middleware.Authenticate(jwtService, deviceService), tableController.GetAllTables)
+		tableGroup.GET("/:id", middleware.Authenticate(jwtService, deviceService), tableController.GetTableByID)
+
+		// Superadmin
+		tableGroup.GET("/:id/token",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			tableController.GetTableToken)
 	}
 }
@@ -2,6 +2,7 @@ package route
 
 import (
 	"fp-kpl/application/service"
+	"fp-kpl/domain/device"
 	"fp-kpl/domain/user"
 	"fp-kpl/presentation/controller"
 	"fp-kpl/presentation/middleware"
@@ -9,37 +10,77 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func TransactionRoute(route *gin.Engine, transactionController controller.TransactionController, jwtService service.JWTService, userService service.UserService) {
+func TransactionRoute(route *gin.Engine, transactionController controller.TransactionController, jwtService service.JWTService, deviceService service.DeviceService, userService service.UserService, verificationService service.VerificationService) {
 	transactionGroup := route.Group("/api/transaction")
 	{
 		transactionGroup.POST("/",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleCustomer},
+				{Name: device.RoleKiosk},
 				{Name: user.RoleSuperAdmin},
 			}),
+			middleware.RequireVerified(verificationService),
 			transactionController.CreateTransaction)
-		transactionGroup.GET("/", middleware.Authenticate(jwtService), transactionController.GetAllTransactionsWithPagination)
-		transactionGroup.GET("/:id", middleware.Authenticate(jwtService), transactionController.GetTransactionByID)
+		transactionGroup.GET("/", middleware.Authenticate(jwtService, nil), transactionController.GetAllTransactionsWithPagination)
+		transactionGroup.GET("/:id", middleware.Authenticate(jwtService, nil), transactionController.GetTransactionByID)
+		transactionGroup.GET("/guest/:token", transactionController.GetGuestTransaction)
+		transactionGroup.POST("/:id/reorder",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCustomer},
+				{Name: user.RoleSuperAdmin},
+			}),
+			transactionController.Reorder)
 		transactionGroup.POST("/hook", transactionController.HookTransaction)
+		transactionGroup.POST("/hook/:provider", transactionController.HookTransaction)
+		transactionGroup.PATCH("/:id/priority",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			transactionController.UpdatePriority)
+		transactionGroup.GET("/:id/status-changes",
+			middleware.Authenticate(jwtService, nil),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleSuperAdmin},
+			}),
+			transactionController.GetStatusChanges)
+
+		// Kitchen & Waiter
+		transactionGroup.GET("/lookup/:reference",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleKitchen},
+				{Name: user.RoleWaiter},
+				{Name: user.RoleSuperAdmin},
+			}),
+			transactionController.LookupTransaction)
 
 		// Kitchen
 		transactionGroup.GET("/next-order",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleKitchen},
 				{Name: user.RoleSuperAdmin},
 			}),
 			transactionController.GetNextOrder)
+		transactionGroup.GET("/station/:station_id/next-order",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleKitchen},
+				{Name: user.RoleSuperAdmin},
+			}),
+			transactionController.GetStationNextOrder)
 		transactionGroup.POST("/start-cooking",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleKitchen},
 				{Name: user.RoleSuperAdmin},
 			}),
 			transactionController.StartCooking)
 		transactionGroup.POST("/finish-cooking",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleKitchen},
 				{Name: user.RoleSuperAdmin},
@@ -48,25 +89,35 @@ func TransactionRoute(route *gin.Engine, transactionController controller.Transa
 
 		// Waiter
 		transactionGroup.GET("/ready-to-serve",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleWaiter},
 				{Name: user.RoleSuperAdmin},
 			}),
 			transactionController.GetAllReadyToServeTransactionList)
 		transactionGroup.POST("/start-delivering",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleWaiter},
 				{Name: user.RoleSuperAdmin},
 			}),
 			transactionController.StartDelivering)
 		transactionGroup.POST("/finish-delivering",
-			middleware.Authenticate(jwtService),
+			middleware.Authenticate(jwtService, deviceService),
 			middleware.Authorize(userService, []user.Role{
 				{Name: user.RoleWaiter},
 				{Name: user.RoleSuperAdmin},
 			}),
 			transactionController.FinishDelivering)
+
+		// Counter
+		transactionGroup.POST("/collect",
+			middleware.Authenticate(jwtService, deviceService),
+			middleware.Authorize(userService, []user.Role{
+				{Name: user.RoleCashier},
+				{Name: user.RoleWaiter},
+				{Name: user.RoleSuperAdmin},
+			}),
+			transactionController.CollectOrder)
 	}
 }
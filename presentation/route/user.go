@@ -12,6 +12,8 @@ func UserRoute(route *gin.Engine, userController controller.UserController, jwtS
 	{
 		userGroup.POST("/register", userController.Register)
 		userGroup.POST("/login", userController.Login)
-		userGroup.GET("/me", middleware.Authenticate(jwtService), userController.Me)
+		userGroup.GET("/me", middleware.Authenticate(jwtService, nil), userController.Me)
+		userGroup.PATCH("/me", middleware.Authenticate(jwtService, nil), userController.UpdateMe)
+		userGroup.DELETE("/me", middleware.Authenticate(jwtService, nil), userController.DeleteMe)
 	}
 }
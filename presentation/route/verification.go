@@ -0,0 +1,19 @@
+package route
+
+import (
+	"fp-kpl/application/service"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func VerificationRoute(route *gin.Engine, verificationController controller.VerificationController, jwtService service.JWTService) {
+	verificationGroup := route.Group("/api/user/verification")
+	{
+		verificationGroup.GET("/", middleware.Authenticate(jwtService, nil), verificationController.GetStatus)
+		verificationGroup.POST("/send", middleware.Authenticate(jwtService, nil), verificationController.SendCode)
+		verificationGroup.POST("/verify", middleware.Authenticate(jwtService, nil), verificationController.Verify)
+		verificationGroup.GET("/email", verificationController.VerifyEmailLink)
+	}
+}
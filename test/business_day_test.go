@@ -0,0 +1,47 @@
+package test
+
+import (
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// realClock runs on the wall clock for tests that do not pin the time.
+var realClock = shared.NewSystemClock(time.Local, 0)
+
+func TestBusinessDay_RollsOverAtCutoff(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	lateNight := time.Date(2024, 5, 16, 0, 5, 0, 0, jakarta)
+
+	day := shared.NewBusinessDay(lateNight, 4*time.Hour)
+
+	assert.Equal(t, "2024-05-15", day.String())
+	assert.Equal(t, time.Date(2024, 5, 15, 4, 0, 0, 0, jakarta), day.Start)
+	assert.Equal(t, time.Date(2024, 5, 16, 4, 0, 0, 0, jakarta), day.End)
+	assert.True(t, day.Contains(time.Date(2024, 5, 15, 23, 55, 0, 0, jakarta)))
+	assert.True(t, day.Contains(lateNight))
+	assert.False(t, day.Contains(day.End))
+
+	assert.Equal(t, "2024-05-16", shared.NewBusinessDay(day.End, 4*time.Hour).String())
+	assert.Equal(t, "2024-05-16", shared.NewBusinessDay(lateNight, 0).String())
+}
+
+func TestBusinessDay_ValidateCutoff(t *testing.T) {
+	assert.NoError(t, shared.ValidateBusinessDayCutoff(0))
+	assert.NoError(t, shared.ValidateBusinessDayCutoff(shared.DefaultBusinessDayCutoff))
+	assert.Error(t, shared.ValidateBusinessDayCutoff(24*time.Hour))
+	assert.Error(t, shared.ValidateBusinessDayCutoff(-time.Hour))
+}
+
+func TestGetOrderDelayStatus_UsesClock(t *testing.T) {
+	cookedAt := lunchTime.Add(-20 * time.Minute)
+
+	onTime := transaction.NewService(nil, transaction.DefaultStationCapacity, fixedClock{now: lunchTime})
+	assert.False(t, onTime.GetOrderDelayStatus(30*time.Minute, &cookedAt, nil))
+
+	late := transaction.NewService(nil, transaction.DefaultStationCapacity, fixedClock{now: lunchTime.Add(15 * time.Minute)})
+	assert.True(t, late.GetOrderDelayStatus(30*time.Minute, &cookedAt, nil))
+}
@@ -31,6 +31,10 @@ func (m *MockOrderRepositoryForCalculatePrice) GetOrdersByTransactionID(ctx cont
 	return args.Get(0).([]order.Order), args.Error(1)
 }
 
+func (m *MockOrderRepositoryForCalculatePrice) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
+
 type MockMenuRepositoryForCalculatePrice struct {
 	mock.Mock
 }
@@ -45,6 +49,16 @@ func (m *MockOrderDomainService) CalculatePrice(ctx context.Context, price share
 	return args.Get(0).(shared.Price), args.Error(1)
 }
 
+// CalculateBreakdown applies an empty pricing policy, so the total stays the
+// plain subtotal these tests expect.
+func (m *MockOrderDomainService) CalculateBreakdown(ctx context.Context, subtotal shared.Price, discounts []order.Discount, packaged bool) (order.PriceBreakdown, error) {
+	return order.PricingPolicy{}.ApplyDiscounts(subtotal, discounts), nil
+}
+
+func (m *MockOrderDomainService) ApplyLoyalty(ctx context.Context, breakdown order.PriceBreakdown, points int64, amount shared.Price) (order.PriceBreakdown, error) {
+	return order.PricingPolicy{}.ApplyLoyalty(breakdown, points, amount), nil
+}
+
 func (m *MockMenuRepositoryForCalculatePrice) GetAllMenus(ctx context.Context, tx interface{}) ([]menu.Menu, error) {
 	args := m.Called(ctx, tx)
 	return args.Get(0).([]menu.Menu), args.Error(1)
@@ -65,13 +79,23 @@ func (m *MockMenuRepositoryForCalculatePrice) UpdateMenuAvailability(ctx context
 	return args.Get(0).(menu.Menu), args.Error(1)
 }
 
+func (m *MockMenuRepositoryForCalculatePrice) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepositoryForCalculatePrice) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu.PriceOverride) (menu.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu.Menu), args.Error(1)
+}
+
 func TestCalculateTotalPrice_Success(t *testing.T) {
 	// Arrange
 	mockMenuRepo := new(MockMenuRepositoryForCalculatePrice)
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -125,7 +149,7 @@ func TestCalculateTotalPrice_SingleItem(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -165,7 +189,7 @@ func TestCalculateTotalPrice_ZeroQuantity(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -204,7 +228,7 @@ func TestCalculateTotalPrice_NegativeQuantity(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -243,7 +267,7 @@ func TestCalculateTotalPrice_MenuNotFound(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -272,7 +296,7 @@ func TestCalculateTotalPrice_EmptyOrders(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -298,7 +322,7 @@ func TestCalculateTotalPrice_DecimalPrices(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -351,7 +375,7 @@ func TestCalculateTotalPrice_LargeQuantities(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -391,7 +415,7 @@ func TestCalculateTotalPrice_MixedValidAndInvalid(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepositoryForCalculatePrice)
 	mockOrderDomainService := new(MockOrderDomainService)
 
-	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService)
+	orderService := service.NewOrderService(mockOrderRepo, mockMenuRepo, mockOrderDomainService, noPromotions(), fixedClock{now: lunchTime})
 
 	ctx := context.Background()
 
@@ -0,0 +1,412 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/cart"
+	"fp-kpl/domain/device"
+	"fp-kpl/domain/identity"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/table"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockCartRepository struct{ mock.Mock }
+
+// CreateCart and UpdateCart return what they are given, since the cart is
+// only known inside the service.
+func (m *MockCartRepository) CreateCart(ctx context.Context, tx interface{}, cartEntity cart.Cart) (cart.Cart, error) {
+	args := m.Called(ctx, tx, cartEntity)
+	return cartEntity, args.Error(0)
+}
+func (m *MockCartRepository) GetCartByID(ctx context.Context, tx interface{}, id string) (cart.Cart, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(cart.Cart), args.Error(1)
+}
+func (m *MockCartRepository) GetOpenCart(ctx context.Context, tx interface{}, userID string, tableID string, at time.Time) (cart.Cart, error) {
+	args := m.Called(ctx, tx, userID, tableID, at)
+	return args.Get(0).(cart.Cart), args.Error(1)
+}
+func (m *MockCartRepository) JoinCart(ctx context.Context, tx interface{}, id string, userID string) error {
+	args := m.Called(ctx, tx, id, userID)
+	return args.Error(0)
+}
+func (m *MockCartRepository) UpdateCart(ctx context.Context, tx interface{}, cartEntity cart.Cart) (cart.Cart, error) {
+	args := m.Called(ctx, tx, cartEntity)
+	return cartEntity, args.Error(0)
+}
+func (m *MockCartRepository) CheckOutCart(ctx context.Context, tx interface{}, id string, at time.Time) error {
+	args := m.Called(ctx, tx, id, at)
+	return args.Error(0)
+}
+func (m *MockCartRepository) ReopenCart(ctx context.Context, tx interface{}, id string) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+func (m *MockCartRepository) LinkCartTransaction(ctx context.Context, tx interface{}, id string, transactionID string) error {
+	args := m.Called(ctx, tx, id, transactionID)
+	return args.Error(0)
+}
+func (m *MockCartRepository) AddCartLine(ctx context.Context, tx interface{}, line cart.Line) error {
+	args := m.Called(ctx, tx, line)
+	return args.Error(0)
+}
+func (m *MockCartRepository) UpdateCartLineQuantity(ctx context.Context, tx interface{}, lineID string, quantity int) error {
+	args := m.Called(ctx, tx, lineID, quantity)
+	return args.Error(0)
+}
+func (m *MockCartRepository) DeleteCartLine(ctx context.Context, tx interface{}, lineID string) error {
+	args := m.Called(ctx, tx, lineID)
+	return args.Error(0)
+}
+
+// MockTransactionServiceForCart only checks out carts; any other call
+// panics on the nil TransactionService it embeds.
+type MockTransactionServiceForCart struct {
+	service.TransactionService
+	mock.Mock
+}
+
+func (m *MockTransactionServiceForCart) CreateTransaction(ctx context.Context, userID string, req request.TransactionCreate) (response.TransactionCreate, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(response.TransactionCreate), args.Error(1)
+}
+
+type cartFixture struct {
+	cartRepo       *MockCartRepository
+	menuRepo       *MockMenuRepositoryForTransaction
+	tableRepo      *MockTableRepositoryForTransaction
+	promotionRepo  *MockPromotionRepository
+	transactionSvc *MockTransactionServiceForCart
+	cartService    service.CartService
+	nasiGoreng     menu.Menu
+	esTeh          menu.Menu
+}
+
+func newCartFixture(t *testing.T) cartFixture {
+	policy, err := order.NewPricingPolicy(decimal.Zero, decimal.Zero, "", 0, decimal.Zero)
+	assert.NoError(t, err)
+
+	f := cartFixture{
+		cartRepo:       new(MockCartRepository),
+		menuRepo:       new(MockMenuRepositoryForTransaction),
+		tableRepo:      new(MockTableRepositoryForTransaction),
+		promotionRepo:  noPromotions(),
+		transactionSvc: new(MockTransactionServiceForCart),
+		nasiGoreng:     menu.Menu{ID: identity.NewID(uuid.New()), Name: "Nasi Goreng", Price: price(30000), IsAvailable: true},
+		esTeh:          menu.Menu{ID: identity.NewID(uuid.New()), Name: "Es Teh", Price: price(5000), IsAvailable: true},
+	}
+	orderService := service.NewOrderService(nil, f.menuRepo, order.NewService(policy), f.promotionRepo, fixedClock{now: lunchTime})
+	f.cartService = service.NewCartService(f.cartRepo, f.menuRepo, f.tableRepo, orderService, f.transactionSvc, fixedClock{now: lunchTime}, 0)
+
+	f.menuRepo.On("GetMenuByID", mock.Anything, nil, f.nasiGoreng.ID.String()).Return(f.nasiGoreng, nil)
+	f.menuRepo.On("GetMenuByID", mock.Anything, nil, f.esTeh.ID.String()).Return(f.esTeh, nil)
+	return f
+}
+
+func openCart(userID identity.ID, lines ...cart.Line) cart.Cart {
+	cartID := identity.NewID(uuid.New())
+	for i := range lines {
+		lines[i].ID = identity.NewID(uuid.New())
+		lines[i].CartID = cartID
+	}
+	return cart.Cart{
+		ID:        cartID,
+		UserID:    userID,
+		OrderType: transaction.OrderType{Type: transaction.OrderTypeDineIn},
+		Lines:     lines,
+		ExpiresAt: lunchTime.Add(time.Hour),
+	}
+}
+
+func TestCart_CanAccessAndCheckOpen(t *testing.T) {
+	owner := identity.NewID(uuid.New())
+	tableID := identity.NewID(uuid.New())
+
+	personal := cart.Cart{UserID: owner, ExpiresAt: lunchTime.Add(time.Minute)}
+	assert.True(t, personal.CanAccess(owner.String(), ""))
+	assert.False(t, personal.CanAccess(uuid.NewString(), ""))
+	assert.False(t, personal.CanAccess("", tableID.String()))
+
+	guest := identity.NewID(uuid.New())
+	shared := cart.Cart{TableID: tableID, MemberIDs: []identity.ID{guest}, ExpiresAt: lunchTime.Add(time.Minute)}
+	assert.True(t, shared.CanAccess(guest.String(), ""))
+	assert.False(t, shared.CanAccess(uuid.NewString(), ""))
+	assert.False(t, shared.CanAccess("", ""))
+	assert.True(t, shared.CanAccess("", tableID.String()))
+	assert.False(t, shared.CanAccess("", uuid.NewString()))
+
+	assert.NoError(t, personal.CheckOpen(lunchTime))
+	assert.ErrorIs(t, personal.CheckOpen(lunchTime.Add(time.Minute)), cart.ErrorCartExpired)
+	personal.CheckedOutAt = &lunchTime
+	assert.ErrorIs(t, personal.CheckOpen(lunchTime), cart.ErrorCartCheckedOut)
+}
+
+func TestOpenCart_KioskJoinsTableCart(t *testing.T) {
+	f := newCartFixture(t)
+	tableEntity := table.Table{ID: identity.NewID(uuid.New())}
+	ctx := application.WithActor(context.Background(), application.Actor{
+		DeviceID: uuid.NewString(),
+		Role:     device.RoleKiosk,
+		TableID:  tableEntity.ID.String(),
+	})
+	tableCart := openCart(identity.ID{}, cart.Line{MenuID: f.esTeh.ID, Quantity: 3})
+	tableCart.TableID = tableEntity.ID
+
+	f.tableRepo.On("GetTableByID", ctx, nil, tableEntity.ID.String()).Return(tableEntity, nil)
+	f.cartRepo.On("GetOpenCart", ctx, nil, "", tableEntity.ID.String(), lunchTime).Return(tableCart, nil)
+
+	result, err := f.cartService.OpenCart(ctx, "", request.OpenCart{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, tableCart.ID.String(), result.ID)
+	assert.True(t, result.IsShared)
+	assert.Equal(t, "15000", result.Pricing.Total)
+	assert.True(t, result.CanCheckout)
+	f.cartRepo.AssertNotCalled(t, "CreateCart", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOpenCart_StartsPersonalCart(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	f.cartRepo.On("GetOpenCart", ctx, nil, userID, "", lunchTime).Return(cart.Cart{}, gorm.ErrRecordNotFound)
+	f.cartRepo.On("CreateCart", ctx, nil, mock.MatchedBy(func(cartEntity cart.Cart) bool {
+		return cartEntity.UserID.String() == userID &&
+			cartEntity.TableID.IsEmpty() &&
+			cartEntity.ExpiresAt.Equal(lunchTime.Add(cart.DefaultIdleTimeout))
+	})).Return(nil)
+
+	result, err := f.cartService.OpenCart(ctx, userID, request.OpenCart{OrderType: transaction.OrderTypeTakeaway})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsShared)
+	assert.Equal(t, transaction.OrderTypeTakeaway, result.OrderType)
+	assert.Empty(t, result.Lines)
+	assert.Nil(t, result.Pricing)
+	assert.False(t, result.CanCheckout)
+	f.cartRepo.AssertExpectations(t)
+}
+
+func TestOpenCart_UnknownTableToken(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	token := uuid.NewString()
+
+	f.tableRepo.On("GetTableByToken", ctx, nil, token).Return(table.Table{}, gorm.ErrRecordNotFound)
+
+	_, err := f.cartService.OpenCart(ctx, uuid.NewString(), request.OpenCart{TableToken: token})
+
+	assert.ErrorIs(t, err, table.ErrorTableNotFound)
+	f.cartRepo.AssertNotCalled(t, "GetOpenCart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOpenCart_JoinsCartOpenedMeanwhile(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	userID := uuid.NewString()
+	tableEntity := table.Table{ID: identity.NewID(uuid.New()), Token: identity.NewID(uuid.New())}
+	tableCart := openCart(identity.ID{}, cart.Line{MenuID: f.esTeh.ID, Quantity: 1})
+	tableCart.TableID = tableEntity.ID
+
+	f.tableRepo.On("GetTableByToken", ctx, nil, tableEntity.Token.String()).Return(tableEntity, nil)
+	f.cartRepo.On("GetOpenCart", ctx, nil, userID, tableEntity.ID.String(), lunchTime).Return(cart.Cart{}, gorm.ErrRecordNotFound).Once()
+	f.cartRepo.On("CreateCart", ctx, nil, mock.Anything).Return(cart.ErrorCartAlreadyOpen)
+	f.cartRepo.On("GetOpenCart", ctx, nil, userID, tableEntity.ID.String(), lunchTime).Return(tableCart, nil).Once()
+	f.cartRepo.On("JoinCart", ctx, nil, tableCart.ID.String(), userID).Return(nil)
+
+	result, err := f.cartService.OpenCart(ctx, userID, request.OpenCart{TableToken: tableEntity.Token.String()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, tableCart.ID.String(), result.ID)
+	assert.True(t, result.IsShared)
+	f.cartRepo.AssertExpectations(t)
+}
+
+func TestAddCartLine_AddsToQuantityOfSameMenu(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	userID := identity.NewID(uuid.New())
+	before := openCart(userID, cart.Line{MenuID: f.nasiGoreng.ID, Quantity: 1})
+	after := before
+	after.Lines = []cart.Line{before.Lines[0]}
+	after.Lines[0].Quantity = 3
+
+	f.cartRepo.On("GetCartByID", ctx, nil, before.ID.String()).Return(before, nil).Once()
+	f.cartRepo.On("AddCartLine", ctx, nil, mock.MatchedBy(func(line cart.Line) bool {
+		return line.CartID == before.ID && line.MenuID == f.nasiGoreng.ID && line.Quantity == 2 && line.AddedBy == userID
+	})).Return(nil)
+	f.cartRepo.On("GetCartByID", ctx, nil, before.ID.String()).Return(after, nil).Once()
+	f.cartRepo.On("UpdateCart", ctx, nil, mock.MatchedBy(func(cartEntity cart.Cart) bool {
+		return cartEntity.ExpiresAt.Equal(lunchTime.Add(cart.DefaultIdleTimeout))
+	})).Return(nil)
+
+	result, err := f.cartService.AddCartLine(ctx, userID.String(), before.ID.String(), request.AddCartLine{MenuID: f.nasiGoreng.ID.String(), Quantity: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Lines, 1)
+	assert.Equal(t, 3, result.Lines[0].Quantity)
+	assert.Equal(t, "90000", result.Pricing.Total)
+	f.cartRepo.AssertExpectations(t)
+}
+
+func TestAddCartLine_Rejected(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	userID := identity.NewID(uuid.New())
+	soldOut := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Sate Ayam", Price: price(25000)}
+	f.menuRepo.On("GetMenuByID", ctx, nil, soldOut.ID.String()).Return(soldOut, nil)
+
+	t.Run("menu switched off", func(t *testing.T) {
+		cartEntity := openCart(userID)
+		f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+
+		_, err := f.cartService.AddCartLine(ctx, userID.String(), cartEntity.ID.String(), request.AddCartLine{MenuID: soldOut.ID.String(), Quantity: 1})
+
+		assert.ErrorIs(t, err, menu.ErrorMenuUnavailable)
+	})
+
+	t.Run("someone else's cart", func(t *testing.T) {
+		cartEntity := openCart(identity.NewID(uuid.New()))
+		f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+
+		_, err := f.cartService.AddCartLine(ctx, userID.String(), cartEntity.ID.String(), request.AddCartLine{MenuID: f.esTeh.ID.String(), Quantity: 1})
+
+		assert.ErrorIs(t, err, cart.ErrorNotCartMember)
+	})
+
+	t.Run("expired cart", func(t *testing.T) {
+		cartEntity := openCart(userID)
+		cartEntity.ExpiresAt = lunchTime.Add(-time.Minute)
+		f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+
+		_, err := f.cartService.AddCartLine(ctx, userID.String(), cartEntity.ID.String(), request.AddCartLine{MenuID: f.esTeh.ID.String(), Quantity: 1})
+
+		assert.ErrorIs(t, err, cart.ErrorCartExpired)
+	})
+}
+
+func TestGetCart_RevalidatesLinesAndVoucher(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	userID := identity.NewID(uuid.New())
+	switchedOff := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Sate Ayam", Price: price(25000)}
+	f.menuRepo.On("GetMenuByID", ctx, nil, switchedOff.ID.String()).Return(switchedOff, nil)
+
+	voucher := newPromotion(promotion.TypeFixedAmount, 15000)
+	voucher.VoucherCode = "BIGSPENDER"
+	voucher.MinimumSpend = price(100000)
+	f.promotionRepo.On("GetPromotionByVoucherCode", mock.Anything, nil, "BIGSPENDER").Return(voucher, nil)
+
+	cartEntity := openCart(userID,
+		cart.Line{MenuID: f.nasiGoreng.ID, Quantity: 1},
+		cart.Line{MenuID: switchedOff.ID, Quantity: 2},
+	)
+	cartEntity.VoucherCode = "BIGSPENDER"
+	f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+
+	result, err := f.cartService.GetCart(ctx, userID.String(), cartEntity.ID.String())
+
+	assert.NoError(t, err)
+	assert.True(t, result.Lines[0].IsAvailable)
+	assert.False(t, result.Lines[1].IsAvailable)
+	assert.Equal(t, menu.ErrorMenuUnavailable.Error(), result.Lines[1].Reason)
+	assert.Equal(t, promotion.ErrorVoucherNotApplicable.Error(), result.VoucherError)
+	assert.Equal(t, "30000", result.Pricing.Total)
+	assert.False(t, result.CanCheckout)
+}
+
+func TestRemoveCartVoucher_CheckedOutMeanwhile(t *testing.T) {
+	f := newCartFixture(t)
+	ctx := context.Background()
+	userID := identity.NewID(uuid.New())
+	cartEntity := openCart(userID, cart.Line{MenuID: f.esTeh.ID, Quantity: 1})
+	cartEntity.VoucherCode = "BIGSPENDER"
+
+	f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+	f.cartRepo.On("UpdateCart", ctx, nil, mock.Anything).Return(cart.ErrorCartCheckedOut)
+
+	_, err := f.cartService.RemoveCartVoucher(ctx, userID.String(), cartEntity.ID.String())
+
+	assert.ErrorIs(t, err, cart.ErrorCartCheckedOut)
+	f.cartRepo.AssertExpectations(t)
+}
+
+func TestCheckoutCart(t *testing.T) {
+	t.Run("shared cart is ordered for its table", func(t *testing.T) {
+		f := newCartFixture(t)
+		ctx := context.Background()
+		userID := uuid.NewString()
+		tableCart := openCart(identity.ID{}, cart.Line{MenuID: f.nasiGoreng.ID, Quantity: 2})
+		tableCart.TableID = identity.NewID(uuid.New())
+		tableCart.MemberIDs = []identity.ID{identity.NewID(uuid.MustParse(userID))}
+		transactionID := uuid.NewString()
+
+		f.cartRepo.On("GetCartByID", ctx, nil, tableCart.ID.String()).Return(tableCart, nil)
+		f.cartRepo.On("CheckOutCart", ctx, nil, tableCart.ID.String(), lunchTime).Return(nil)
+		f.transactionSvc.On("CreateTransaction", ctx, userID, request.TransactionCreate{
+			OrderType:       transaction.OrderTypeDineIn,
+			TableID:         tableCart.TableID.String(),
+			Orders:          []request.Order{{MenuID: f.nasiGoreng.ID.String(), Quantity: 2}},
+			PaymentProvider: transaction.PaymentProviderCounter,
+		}).Return(response.TransactionCreate{TransactionID: transactionID}, nil)
+		f.cartRepo.On("LinkCartTransaction", ctx, nil, tableCart.ID.String(), transactionID).Return(nil)
+
+		result, err := f.cartService.CheckoutCart(ctx, userID, tableCart.ID.String(), request.CheckoutCart{PaymentProvider: transaction.PaymentProviderCounter})
+
+		assert.NoError(t, err)
+		assert.Equal(t, transactionID, result.TransactionID)
+		f.cartRepo.AssertExpectations(t)
+	})
+
+	t.Run("cart is reopened when the transaction fails", func(t *testing.T) {
+		f := newCartFixture(t)
+		ctx := context.Background()
+		userID := identity.NewID(uuid.New())
+		cartEntity := openCart(userID, cart.Line{MenuID: f.esTeh.ID, Quantity: 1})
+		failure := errors.New("payment gateway down")
+
+		f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+		f.cartRepo.On("CheckOutCart", ctx, nil, cartEntity.ID.String(), lunchTime).Return(nil)
+		f.transactionSvc.On("CreateTransaction", ctx, userID.String(), mock.Anything).Return(response.TransactionCreate{}, failure)
+		f.cartRepo.On("ReopenCart", ctx, nil, cartEntity.ID.String()).Return(nil)
+
+		_, err := f.cartService.CheckoutCart(ctx, userID.String(), cartEntity.ID.String(), request.CheckoutCart{})
+
+		assert.ErrorIs(t, err, failure)
+		f.cartRepo.AssertExpectations(t)
+	})
+
+	t.Run("cart with an unavailable line", func(t *testing.T) {
+		f := newCartFixture(t)
+		ctx := context.Background()
+		userID := identity.NewID(uuid.New())
+		deletedMenuID := identity.NewID(uuid.New())
+		f.menuRepo.On("GetMenuByID", ctx, nil, deletedMenuID.String()).Return(menu.Menu{}, gorm.ErrRecordNotFound)
+		cartEntity := openCart(userID,
+			cart.Line{MenuID: f.esTeh.ID, Quantity: 1},
+			cart.Line{MenuID: deletedMenuID, Quantity: 1},
+		)
+		f.cartRepo.On("GetCartByID", ctx, nil, cartEntity.ID.String()).Return(cartEntity, nil)
+
+		_, err := f.cartService.CheckoutCart(ctx, userID.String(), cartEntity.ID.String(), request.CheckoutCart{})
+
+		assert.ErrorIs(t, err, cart.ErrorCartHasUnavailableItems)
+		f.cartRepo.AssertNotCalled(t, "CheckOutCart", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,209 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/shift"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/adapter/payment_gateway"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockShiftRepository struct {
+	mock.Mock
+}
+
+func (m *MockShiftRepository) OpenShift(ctx context.Context, tx interface{}, shiftEntity shift.Shift) (shift.Shift, error) {
+	args := m.Called(ctx, tx, shiftEntity)
+	return args.Get(0).(shift.Shift), args.Error(1)
+}
+
+func (m *MockShiftRepository) GetOpenShiftByCashierID(ctx context.Context, tx interface{}, cashierID string) (shift.Shift, error) {
+	args := m.Called(ctx, tx, cashierID)
+	return args.Get(0).(shift.Shift), args.Error(1)
+}
+
+func (m *MockShiftRepository) CloseShift(ctx context.Context, tx interface{}, shiftID string, countedCash shared.Price) (shift.Shift, error) {
+	args := m.Called(ctx, tx, shiftID, countedCash)
+	return args.Get(0).(shift.Shift), args.Error(1)
+}
+
+func (m *MockShiftRepository) CreateCounterPayment(ctx context.Context, tx interface{}, counterPayment shift.CounterPayment) (shift.CounterPayment, error) {
+	args := m.Called(ctx, tx, counterPayment)
+	return args.Get(0).(shift.CounterPayment), args.Error(1)
+}
+
+func (m *MockShiftRepository) GetShiftTotals(ctx context.Context, tx interface{}, shiftID string) (shift.Totals, error) {
+	args := m.Called(ctx, tx, shiftID)
+	return args.Get(0).(shift.Totals), args.Error(1)
+}
+
+func price(value int64) shared.Price {
+	return shared.NewPriceFromSchema(decimal.NewFromInt(value))
+}
+
+func TestMethod_Settle(t *testing.T) {
+	cash, _ := shift.NewMethod(shift.MethodCash)
+	card, _ := shift.NewMethod(shift.MethodCard)
+
+	tendered, change, err := cash.Settle(price(45000), decimal.NewFromInt(50000))
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(50000).Equal(tendered.Price))
+	assert.True(t, decimal.NewFromInt(5000).Equal(change.Price))
+
+	tendered, change, err = cash.Settle(price(45000), decimal.NewFromInt(45000))
+	assert.NoError(t, err)
+	assert.True(t, change.Price.IsZero())
+
+	_, _, err = cash.Settle(price(45000), decimal.NewFromInt(40000))
+	assert.ErrorIs(t, err, shift.ErrorInsufficientAmount)
+
+	tendered, change, err = card.Settle(price(45000), decimal.Zero)
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(45000).Equal(tendered.Price))
+	assert.True(t, change.Price.IsZero())
+
+	_, err = shift.NewMethod("cheque")
+	assert.ErrorIs(t, err, shift.ErrorInvalidPaymentMethod)
+}
+
+func TestOpenShift_Success(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepository)
+	cashierService := service.NewCashierService(mockShiftRepo, nil, nil, fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	cashierID := uuid.New()
+	mockShiftRepo.On("GetOpenShiftByCashierID", ctx, nil, cashierID.String()).Return(shift.Shift{}, gorm.ErrRecordNotFound)
+	mockShiftRepo.On("OpenShift", ctx, nil, mock.MatchedBy(func(shiftEntity shift.Shift) bool {
+		return shiftEntity.CashierID.ID == cashierID && shiftEntity.OpeningFloat.Price.Equal(decimal.NewFromInt(200000))
+	})).Return(shift.Shift{
+		ID:           identity.NewID(uuid.New()),
+		CashierID:    identity.NewID(cashierID),
+		OpeningFloat: price(200000),
+		OpenedAt:     time.Now(),
+	}, nil)
+
+	result, err := cashierService.OpenShift(ctx, cashierID.String(), request.OpenShift{OpeningFloat: "200000"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, cashierID.String(), result.CashierID)
+	assert.Equal(t, "200000", result.OpeningFloat)
+	assert.Equal(t, "200000", result.ExpectedCash)
+	assert.Nil(t, result.ClosedAt)
+	mockShiftRepo.AssertExpectations(t)
+}
+
+func TestOpenShift_AlreadyOpen(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepository)
+	cashierService := service.NewCashierService(mockShiftRepo, nil, nil, fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	cashierID := uuid.NewString()
+	mockShiftRepo.On("GetOpenShiftByCashierID", ctx, nil, cashierID).Return(shift.Shift{ID: identity.NewID(uuid.New())}, nil)
+
+	_, err := cashierService.OpenShift(ctx, cashierID, request.OpenShift{})
+
+	assert.ErrorIs(t, err, shift.ErrorShiftAlreadyOpen)
+	mockShiftRepo.AssertNotCalled(t, "OpenShift", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetCurrentShift_Totals(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepository)
+	cashierService := service.NewCashierService(mockShiftRepo, nil, nil, fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	cashierID := uuid.NewString()
+	shiftID := identity.NewID(uuid.New())
+	mockShiftRepo.On("GetOpenShiftByCashierID", ctx, nil, cashierID).Return(shift.Shift{ID: shiftID, OpeningFloat: price(100000)}, nil)
+	mockShiftRepo.On("GetShiftTotals", ctx, nil, shiftID.String()).Return(shift.Totals{
+		Cash:         price(90000),
+		Card:         price(45000),
+		Transactions: 3,
+	}, nil)
+
+	result, err := cashierService.GetCurrentShift(ctx, cashierID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "90000", result.CashTotal)
+	assert.Equal(t, "45000", result.CardTotal)
+	assert.Equal(t, 3, result.Transactions)
+	assert.Equal(t, "190000", result.ExpectedCash)
+	assert.Empty(t, result.Variance)
+}
+
+func TestGetCurrentShift_NoOpenShift(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepository)
+	cashierService := service.NewCashierService(mockShiftRepo, nil, nil, fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	cashierID := uuid.NewString()
+	mockShiftRepo.On("GetOpenShiftByCashierID", ctx, nil, cashierID).Return(shift.Shift{}, gorm.ErrRecordNotFound)
+
+	_, err := cashierService.GetCurrentShift(ctx, cashierID)
+
+	assert.ErrorIs(t, err, shift.ErrorShiftNotFound)
+}
+
+func TestCloseShift_ReportsVariance(t *testing.T) {
+	mockShiftRepo := new(MockShiftRepository)
+	cashierService := service.NewCashierService(mockShiftRepo, nil, nil, fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	cashierID := uuid.NewString()
+	openShift := shift.Shift{ID: identity.NewID(uuid.New()), OpeningFloat: price(100000)}
+	closedAt := time.Now()
+	counted := price(185000)
+	closedShift := openShift
+	closedShift.ClosedAt = &closedAt
+	closedShift.CountedCash = &counted
+
+	mockShiftRepo.On("GetOpenShiftByCashierID", ctx, nil, cashierID).Return(openShift, nil)
+	mockShiftRepo.On("GetShiftTotals", ctx, nil, openShift.ID.String()).Return(shift.Totals{Cash: price(90000), Card: price(0)}, nil)
+	mockShiftRepo.On("CloseShift", ctx, nil, openShift.ID.String(), mock.MatchedBy(func(countedCash shared.Price) bool {
+		return countedCash.Price.Equal(decimal.NewFromInt(185000))
+	})).Return(closedShift, nil)
+
+	result, err := cashierService.CloseShift(ctx, cashierID, request.CloseShift{CountedCash: "185000"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "190000", result.ExpectedCash)
+	assert.Equal(t, "185000", result.CountedCash)
+	assert.Equal(t, "-5000", result.Variance)
+	assert.NotNil(t, result.ClosedAt)
+}
+
+func TestConfirmPayment_InvalidMethod(t *testing.T) {
+	cashierService := service.NewCashierService(new(MockShiftRepository), nil, nil, fixedClock{now: lunchTime}, nil)
+
+	_, err := cashierService.ConfirmPayment(context.Background(), uuid.NewString(), uuid.NewString(), request.ConfirmPayment{Method: "cheque"})
+
+	assert.ErrorIs(t, err, shift.ErrorInvalidPaymentMethod)
+}
+
+func TestCounterAdapter(t *testing.T) {
+	counterAdapter := payment_gateway.NewCounterAdapter(nil, nil, fixedClock{now: lunchTime})
+	transactionEntity := fakeGatewayTransaction(45000)
+
+	payment, err := counterAdapter.ProcessPayment(context.Background(), nil, transactionEntity)
+	assert.NoError(t, err)
+	assert.Empty(t, payment.Token)
+	assert.Empty(t, payment.PaymentLink)
+	assert.Equal(t, transaction.PaymentProviderCounter, counterAdapter.Provider())
+
+	_, err = counterAdapter.CheckPaymentStatus(context.Background(), transactionEntity.ID.ID)
+	assert.ErrorIs(t, err, port.ErrorPaymentNotFound)
+
+	_, err = counterAdapter.ParseNotification(nil, map[string]interface{}{"order_id": transactionEntity.ID.String()})
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+}
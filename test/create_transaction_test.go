@@ -14,6 +14,7 @@ import (
 	"fp-kpl/domain/user"
 	"fp-kpl/platform/pagination"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func (m *MockTransactionRepositoryForCreateTransaction) GetTransactionByID(ctx c
 func (m *MockTransactionRepositoryForCreateTransaction) GetNextOrder(ctx context.Context, tx interface{}) (response.NextOrder, error) {
 	return response.NextOrder{}, nil
 }
-func (m *MockTransactionRepositoryForCreateTransaction) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+func (m *MockTransactionRepositoryForCreateTransaction) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	return "", nil
 }
 func (m *MockTransactionRepositoryForCreateTransaction) UpdateCookedAt(ctx context.Context, tx interface{}, transactionID string) (transaction.Transaction, error) {
@@ -70,6 +71,50 @@ func (m *MockTransactionRepositoryForCreateTransaction) GetDetailedTransactionBy
 	return transaction.Query{}, nil
 }
 
+func (m *MockTransactionRepositoryForCreateTransaction) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForCreateTransaction) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 type MockTransactionInterfaceForCreateTransaction struct {
 	mock.Mock
 }
@@ -102,6 +147,14 @@ func (m *MockMenuRepositoryForCreateTransaction) GetMenusByCategoryID(ctx contex
 func (m *MockMenuRepositoryForCreateTransaction) UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (menu_item.Menu, error) {
 	return menu_item.Menu{}, nil
 }
+func (m *MockMenuRepositoryForCreateTransaction) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+func (m *MockMenuRepositoryForCreateTransaction) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
 
 type MockUserRepositoryForCreateTransaction struct{ mock.Mock }
 
@@ -117,6 +170,12 @@ func (m *MockUserRepositoryForCreateTransaction) GetUserByEmail(ctx context.Cont
 func (m *MockUserRepositoryForCreateTransaction) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
 	return user.User{}, false, nil
 }
+func (m *MockUserRepositoryForCreateTransaction) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForCreateTransaction) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForCreateTransaction struct{ mock.Mock }
 
@@ -127,6 +186,10 @@ func (m *MockTableRepositoryForCreateTransaction) GetTableByID(ctx context.Conte
 	return table.Table{}, nil
 }
 
+func (m *MockTableRepositoryForCreateTransaction) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	return table.Table{}, nil
+}
+
 type MockOrderRepositoryForCreateTransaction struct{ mock.Mock }
 
 func (m *MockOrderRepositoryForCreateTransaction) CreateOrder(ctx context.Context, tx interface{}, orderEntity order.Order) (order.Order, error) {
@@ -135,6 +198,9 @@ func (m *MockOrderRepositoryForCreateTransaction) CreateOrder(ctx context.Contex
 func (m *MockOrderRepositoryForCreateTransaction) GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]order.Order, error) {
 	return nil, nil
 }
+func (m *MockOrderRepositoryForCreateTransaction) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
 
 type MockPaymentGatewayPortForCreateTransaction struct{ mock.Mock }
 
@@ -148,12 +214,46 @@ func (m *MockPaymentGatewayPortForCreateTransaction) HookPayment(ctx context.Con
 	return args.Error(0)
 }
 
+func (m *MockPaymentGatewayPortForCreateTransaction) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForCreateTransaction) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockPaymentGatewayPortForCreateTransaction) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForCreateTransaction) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForCreateTransaction) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForCreateTransaction) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
+
 type MockOrderServiceForCreateTransaction struct{ mock.Mock }
 
 func (m *MockOrderServiceForCreateTransaction) CalculateTotalPrice(ctx context.Context, orders []request.Order) (shared.Price, error) {
 	args := m.Called(ctx, orders)
 	return args.Get(0).(shared.Price), args.Error(1)
 }
+func (m *MockOrderServiceForCreateTransaction) CalculatePriceBreakdown(ctx context.Context, userID string, orders []request.Order, voucherCode string, orderType string, pickupAt *time.Time) (order.PriceBreakdown, error) {
+	var breakdown order.PriceBreakdown
+	for _, orderItem := range orders {
+		breakdown.Lines = append(breakdown.Lines, order.Line{Quantity: orderItem.Quantity})
+	}
+	return breakdown, nil
+}
+func (m *MockOrderServiceForCreateTransaction) ClaimPromotions(ctx context.Context, tx interface{}, userID string, breakdown order.PriceBreakdown) error {
+	return nil
+}
 
 // Example test using mocks
 func TestCreateTransaction_Success(t *testing.T) {
@@ -176,6 +276,12 @@ func TestCreateTransaction_Success(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	userID := uuid.New()
@@ -0,0 +1,202 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/device"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/domain/user"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceRepository struct{ mock.Mock }
+
+// CreateDevice stores what it is given, since the key is only known inside
+// the service.
+func (m *MockDeviceRepository) CreateDevice(ctx context.Context, tx interface{}, deviceEntity device.Device) (device.Device, error) {
+	args := m.Called(ctx, tx, deviceEntity)
+	return deviceEntity, args.Error(0)
+}
+func (m *MockDeviceRepository) GetAllDevices(ctx context.Context, tx interface{}) ([]device.Device, error) {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]device.Device), args.Error(1)
+}
+func (m *MockDeviceRepository) GetDeviceByID(ctx context.Context, tx interface{}, id string) (device.Device, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(device.Device), args.Error(1)
+}
+func (m *MockDeviceRepository) GetDeviceByKeyHash(ctx context.Context, tx interface{}, keyHash string) (device.Device, error) {
+	args := m.Called(ctx, tx, keyHash)
+	return args.Get(0).(device.Device), args.Error(1)
+}
+func (m *MockDeviceRepository) RevokeDevice(ctx context.Context, tx interface{}, id string, revokedAt time.Time) (device.Device, error) {
+	args := m.Called(ctx, tx, id, revokedAt)
+	return args.Get(0).(device.Device), args.Error(1)
+}
+func (m *MockDeviceRepository) UpdateLastSeen(ctx context.Context, tx interface{}, id string, lastSeenAt time.Time) error {
+	args := m.Called(ctx, tx, id, lastSeenAt)
+	return args.Error(0)
+}
+
+type MockStatusChangeRepository struct{ mock.Mock }
+
+func (m *MockStatusChangeRepository) CreateStatusChange(ctx context.Context, tx interface{}, statusChange transaction.StatusChange) (transaction.StatusChange, error) {
+	args := m.Called(ctx, tx, statusChange)
+	return args.Get(0).(transaction.StatusChange), args.Error(1)
+}
+func (m *MockStatusChangeRepository) GetStatusChangesByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]transaction.StatusChange, error) {
+	args := m.Called(ctx, tx, transactionID)
+	return args.Get(0).([]transaction.StatusChange), args.Error(1)
+}
+
+func TestCreateDevice_StoresOnlyKeyHash(t *testing.T) {
+	mockDeviceRepo := new(MockDeviceRepository)
+	deviceService := service.NewDeviceService(mockDeviceRepo, nil, nil, fixedClock{now: lunchTime})
+
+	var stored device.Device
+	mockDeviceRepo.On("CreateDevice", mock.Anything, nil, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(2).(device.Device) }).
+		Return(nil)
+
+	result, err := deviceService.CreateDevice(context.Background(), request.CreateDevice{Name: "Grill KDS", Role: user.RoleKitchen})
+
+	assert.NoError(t, err)
+	assert.True(t, device.IsKey(result.Key))
+	assert.True(t, strings.HasPrefix(result.Key, result.KeyPrefix))
+	assert.Equal(t, device.HashKey(result.Key), stored.KeyHash)
+	assert.Equal(t, user.RoleKitchen, result.Role)
+}
+
+func TestCreateDevice_RejectsUserOnlyRole(t *testing.T) {
+	deviceService := service.NewDeviceService(new(MockDeviceRepository), nil, nil, fixedClock{now: lunchTime})
+
+	_, err := deviceService.CreateDevice(context.Background(), request.CreateDevice{Name: "Back office", Role: user.RoleSuperAdmin})
+
+	assert.ErrorIs(t, err, device.ErrorInvalidDeviceRole)
+}
+
+func TestAuthenticateDevice(t *testing.T) {
+	key, _, err := device.GenerateKey()
+	assert.NoError(t, err)
+
+	stationID := identity.NewID(uuid.New())
+	recentlySeen := lunchTime.Add(-10 * time.Second)
+	kds := device.Device{
+		ID:         identity.NewID(uuid.New()),
+		Role:       user.Role{Name: user.RoleKitchen},
+		StationID:  stationID,
+		LastSeenAt: &recentlySeen,
+	}
+
+	t.Run("active key", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		deviceService := service.NewDeviceService(mockDeviceRepo, nil, nil, fixedClock{now: lunchTime})
+		mockDeviceRepo.On("GetDeviceByKeyHash", mock.Anything, nil, device.HashKey(key)).Return(kds, nil)
+
+		actor, err := deviceService.AuthenticateDevice(context.Background(), key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, kds.ID.String(), actor.DeviceID)
+		assert.Equal(t, user.RoleKitchen, actor.Role)
+		assert.Equal(t, stationID.String(), actor.StationID)
+		mockDeviceRepo.AssertNotCalled(t, "UpdateLastSeen", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale last seen is refreshed", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		deviceService := service.NewDeviceService(mockDeviceRepo, nil, nil, fixedClock{now: lunchTime.Add(time.Hour)})
+		mockDeviceRepo.On("GetDeviceByKeyHash", mock.Anything, nil, device.HashKey(key)).Return(kds, nil)
+		mockDeviceRepo.On("UpdateLastSeen", mock.Anything, nil, kds.ID.String(), lunchTime.Add(time.Hour)).Return(nil)
+
+		_, err := deviceService.AuthenticateDevice(context.Background(), key)
+
+		assert.NoError(t, err)
+		mockDeviceRepo.AssertExpectations(t)
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		deviceService := service.NewDeviceService(mockDeviceRepo, nil, nil, fixedClock{now: lunchTime})
+		revoked := kds
+		revoked.RevokedAt = &recentlySeen
+		mockDeviceRepo.On("GetDeviceByKeyHash", mock.Anything, nil, device.HashKey(key)).Return(revoked, nil)
+
+		_, err := deviceService.AuthenticateDevice(context.Background(), key)
+
+		assert.ErrorIs(t, err, device.ErrorDeviceRevoked)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		deviceService := service.NewDeviceService(mockDeviceRepo, nil, nil, fixedClock{now: lunchTime})
+		mockDeviceRepo.On("GetDeviceByKeyHash", mock.Anything, nil, device.HashKey(key)).Return(device.Device{}, device.ErrorDeviceNotFound)
+
+		_, err := deviceService.AuthenticateDevice(context.Background(), key)
+		assert.ErrorIs(t, err, device.ErrorInvalidDeviceKey)
+
+		_, err = deviceService.AuthenticateDevice(context.Background(), "not-a-key")
+		assert.ErrorIs(t, err, device.ErrorInvalidDeviceKey)
+	})
+}
+
+func TestStartDelivering_DeviceIsRecorded(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForStartDelivering)
+	mockStatusChangeRepo := new(MockStatusChangeRepository)
+
+	transactionService := service.NewTransactionService(
+		mockTransactionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		fixedClock{now: lunchTime},
+		mockStatusChangeRepo,
+		nil,
+	)
+
+	deviceID := uuid.NewString()
+	ctx := application.WithActor(context.Background(), application.Actor{
+		DeviceID: deviceID,
+		Role:     user.RoleWaiter,
+	})
+	transactionQuery := transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.New()),
+			OrderType:   transaction.OrderType{Type: transaction.OrderTypeDineIn},
+			OrderStatus: transaction.OrderStatus{Status: transaction.OrderStatusReadyToServe},
+			QueueCode:   transaction.QueueCode{Code: "Q0001"},
+		},
+	}
+	transactionID := transactionQuery.Transaction.ID.String()
+
+	mockTransactionRepo.On("GetTransactionByQueueCode", ctx, nil, "Q0001").Return(transactionQuery, nil)
+	mockTransactionRepo.On("UpdateTransactionDeliveringStatusStart", ctx, nil, transactionID).Return(transaction.Transaction{}, nil)
+	mockStatusChangeRepo.On("CreateStatusChange", ctx, nil, mock.MatchedBy(func(statusChange transaction.StatusChange) bool {
+		return statusChange.TransactionID.String() == transactionID &&
+			statusChange.Status.Status == transaction.OrderStatusDelivering &&
+			statusChange.DeviceID.String() == deviceID &&
+			statusChange.UserID.IsEmpty()
+	})).Return(transaction.StatusChange{}, nil)
+
+	_, err := transactionService.StartDelivering(ctx, request.StartDelivering{QueueCode: "Q0001"})
+
+	assert.NoError(t, err)
+	mockTransactionRepo.AssertExpectations(t)
+	mockStatusChangeRepo.AssertExpectations(t)
+}
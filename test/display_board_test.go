@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/presentation/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func displayTransaction(queueCode string, status string, readyAt *time.Time) transaction.Transaction {
+	return transaction.Transaction{
+		QueueCode:   transaction.QueueCode{Code: queueCode},
+		OrderStatus: transaction.OrderStatus{Status: status},
+		ReadyAt:     readyAt,
+	}
+}
+
+func TestNewDisplayBoard_GroupsAndHighlights(t *testing.T) {
+	justCalled := lunchTime.Add(-30 * time.Second)
+	calledEarlier := lunchTime.Add(-10 * time.Minute)
+
+	board := transaction.NewDisplayBoard([]transaction.Transaction{
+		displayTransaction("Q0001", transaction.OrderStatusReadyToServe, &calledEarlier),
+		displayTransaction("Q0002", transaction.OrderStatusPreparing, nil),
+		displayTransaction("Q0003", transaction.OrderStatusReadyToServe, &justCalled),
+		displayTransaction("Q0004", transaction.OrderStatusPending, nil),
+		displayTransaction("", transaction.OrderStatusPending, nil),
+	}, lunchTime, 2*time.Minute)
+
+	assert.Equal(t, []transaction.DisplayEntry{{QueueCode: "Q0002"}, {QueueCode: "Q0004"}}, board.Preparing)
+	assert.Equal(t, []transaction.DisplayEntry{
+		{QueueCode: "Q0003", Highlighted: true},
+		{QueueCode: "Q0001"},
+	}, board.Ready)
+}
+
+func TestDisplayService_GetBoard(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForSLA)
+	displayService := service.NewDisplayService(mockTransactionRepo, fixedClock{now: lunchTime}, 0)
+
+	justCalled := lunchTime.Add(-time.Minute)
+	mockTransactionRepo.On("GetDisplayBoardTransactions", mock.Anything, nil, transaction.DisplayBoardLimit).Return([]transaction.Transaction{
+		displayTransaction("Q0005", transaction.OrderStatusReadyToServe, &justCalled),
+	}, nil)
+
+	board, err := displayService.GetBoard(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, board.Preparing)
+	assert.NotNil(t, board.Preparing)
+	assert.Len(t, board.Ready, 1)
+	assert.Equal(t, "Q0005", board.Ready[0].QueueCode)
+	assert.True(t, board.Ready[0].Highlighted)
+	assert.Equal(t, lunchTime, board.UpdatedAt)
+}
+
+func TestAuthenticateDisplay_HeaderOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/board", middleware.AuthenticateDisplay("display-secret", nil), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	fromHeader := httptest.NewRequest(http.MethodGet, "/board", nil)
+	fromHeader.Header.Set("X-Display-Key", "display-secret")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, fromHeader)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/board?key=display-secret", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,239 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/adapter/payment_gateway"
+	"fp-kpl/presentation/controller"
+	"fp-kpl/presentation/route"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// webhookRecorder stands in for /api/transaction/hook and keeps every
+// notification it receives.
+type webhookRecorder struct {
+	server        *httptest.Server
+	notifications []map[string]interface{}
+}
+
+func newWebhookRecorder(t *testing.T) *webhookRecorder {
+	recorder := &webhookRecorder{}
+	recorder.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		recorder.notifications = append(recorder.notifications, notification)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(recorder.server.Close)
+	return recorder
+}
+
+func fakeGatewayTransaction(totalPrice int64) transaction.Transaction {
+	return transaction.Transaction{
+		ID:         identity.NewID(uuid.New()),
+		Payment:    transaction.Payment{Status: transaction.PaymentStatusPending},
+		TotalPrice: shared.Price{Price: decimal.NewFromInt(totalPrice)},
+	}
+}
+
+func TestFakeGateway_ProcessPaymentIsDeterministic(t *testing.T) {
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost:8888/", "http://localhost:8888/api/transaction/hook", "")
+	transactionEntity := fakeGatewayTransaction(30000)
+
+	first, err := fakeAdapter.ProcessPayment(context.Background(), nil, transactionEntity)
+	assert.NoError(t, err)
+	second, err := fakeAdapter.ProcessPayment(context.Background(), nil, transactionEntity)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, "fake-token-"+transactionEntity.ID.String(), first.Token)
+	assert.Equal(t, "http://localhost:8888/fake-gateway/pay/"+first.Token, first.PaymentLink)
+
+	status, err := fakeAdapter.CheckPaymentStatus(context.Background(), transactionEntity.ID.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.PaymentStatusPending, status.Status)
+	assert.True(t, decimal.NewFromInt(30000).Equal(status.GrossAmount))
+
+	_, err = fakeAdapter.CheckPaymentStatus(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, port.ErrorPaymentNotFound)
+}
+
+func TestFakeGateway_CompletePaymentSendsSignedWebhook(t *testing.T) {
+	tests := []struct {
+		action     string
+		status     string
+		statusCode string
+	}{
+		{port.FakeActionPay, transaction.PaymentStatusSettlement, "200"},
+		{port.FakeActionFail, transaction.PaymentStatusDeny, "202"},
+		{port.FakeActionExpire, transaction.PaymentStatusExpire, "407"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			webhook := newWebhookRecorder(t)
+			fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", webhook.server.URL, "secret")
+			transactionEntity := fakeGatewayTransaction(45000)
+
+			payment, err := fakeAdapter.ProcessPayment(context.Background(), nil, transactionEntity)
+			assert.NoError(t, err)
+
+			completed, err := fakeAdapter.CompletePayment(context.Background(), payment.Token, tt.action)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, completed.Status)
+
+			assert.Len(t, webhook.notifications, 1)
+			notification := webhook.notifications[0]
+			assert.Equal(t, transactionEntity.ID.String(), notification["order_id"])
+			assert.Equal(t, tt.status, notification["transaction_status"])
+			assert.Equal(t, tt.statusCode, notification["status_code"])
+			assert.Equal(t, "45000.00", notification["gross_amount"])
+			assert.Equal(t, "2024-05-15 12:30:00", notification["transaction_time"])
+			parsed, err := fakeAdapter.ParseNotification(nil, notification)
+			assert.NoError(t, err)
+			assert.Equal(t, notification, parsed)
+
+			status, err := fakeAdapter.CheckPaymentStatus(context.Background(), transactionEntity.ID.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, status.Status)
+			assert.Equal(t, notification["transaction_id"], status.TransactionID)
+		})
+	}
+}
+
+func TestFakeGateway_ParseNotificationRejectsTampering(t *testing.T) {
+	webhook := newWebhookRecorder(t)
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", webhook.server.URL, "secret")
+	payment, _ := fakeAdapter.ProcessPayment(context.Background(), nil, fakeGatewayTransaction(45000))
+	_, err := fakeAdapter.CompletePayment(context.Background(), payment.Token, port.FakeActionPay)
+	assert.NoError(t, err)
+
+	tampered := make(map[string]interface{})
+	for key, value := range webhook.notifications[0] {
+		tampered[key] = value
+	}
+	tampered["gross_amount"] = "1.00"
+	_, err = fakeAdapter.ParseNotification(nil, tampered)
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+
+	otherKey := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", webhook.server.URL, "other")
+	_, err = otherKey.ParseNotification(nil, webhook.notifications[0])
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+
+	delete(tampered, "signature_key")
+	_, err = fakeAdapter.ParseNotification(nil, tampered)
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+}
+
+func TestFakeGateway_CompletePaymentErrors(t *testing.T) {
+	webhook := newWebhookRecorder(t)
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", webhook.server.URL, "secret")
+	payment, _ := fakeAdapter.ProcessPayment(context.Background(), nil, fakeGatewayTransaction(45000))
+
+	_, err := fakeAdapter.CompletePayment(context.Background(), payment.Token, "refund")
+	assert.ErrorIs(t, err, port.ErrorFakeInvalidAction)
+
+	_, err = fakeAdapter.CompletePayment(context.Background(), "unknown", port.FakeActionPay)
+	assert.ErrorIs(t, err, port.ErrorFakePaymentNotFound)
+
+	assert.Empty(t, webhook.notifications)
+}
+
+func TestFakeGateway_CancelPayment(t *testing.T) {
+	webhook := newWebhookRecorder(t)
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", webhook.server.URL, "secret")
+
+	pending := fakeGatewayTransaction(45000)
+	payment, _ := fakeAdapter.ProcessPayment(context.Background(), nil, pending)
+	assert.NoError(t, fakeAdapter.CancelPayment(context.Background(), pending.ID.ID))
+
+	status, err := fakeAdapter.CheckPaymentStatus(context.Background(), pending.ID.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.PaymentStatusExpire, status.Status)
+
+	_, err = fakeAdapter.CompletePayment(context.Background(), payment.Token, port.FakeActionPay)
+	assert.ErrorIs(t, err, port.ErrorFakePaymentNotPending)
+	assert.Empty(t, webhook.notifications)
+
+	paid := fakeGatewayTransaction(45000)
+	payment, _ = fakeAdapter.ProcessPayment(context.Background(), nil, paid)
+	_, err = fakeAdapter.CompletePayment(context.Background(), payment.Token, port.FakeActionPay)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, fakeAdapter.CancelPayment(context.Background(), paid.ID.ID), port.ErrorPaymentAlreadyPaid)
+
+	assert.NoError(t, fakeAdapter.CancelPayment(context.Background(), uuid.New()))
+}
+
+func TestHookTransaction_RejectsUnsignedNotification(t *testing.T) {
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", "http://localhost/api/transaction/hook/fake", "secret")
+	registry, err := payment_gateway.NewRegistry(transaction.PaymentProviderFake, fakeAdapter)
+	assert.NoError(t, err)
+
+	transactionService := service.NewTransactionService(
+		new(MockTransactionRepositoryForNextOrder),
+		new(MockUserRepository),
+		new(MockTableRepository),
+		new(MockOrderRepository),
+		new(MockMenuRepository),
+		nil,
+		registry,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
+	)
+
+	err = transactionService.HookTransaction(context.Background(), transaction.PaymentProviderFake, nil, map[string]interface{}{
+		"order_id":           uuid.NewString(),
+		"transaction_status": transaction.PaymentStatusSettlement,
+		"status_code":        "200",
+		"gross_amount":       "45000.00",
+		"signature_key":      "forged",
+	})
+
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+}
+
+func TestFakeGatewayRoute_PaymentPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	webhook := newWebhookRecorder(t)
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", webhook.server.URL, "secret")
+	transactionEntity := fakeGatewayTransaction(45000)
+	payment, _ := fakeAdapter.ProcessPayment(context.Background(), nil, transactionEntity)
+
+	server := gin.New()
+	route.FakeGatewayRoute(server, controller.NewFakeGatewayController(service.NewFakeGatewayService(fakeAdapter)))
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, strings.TrimPrefix(payment.PaymentLink, "http://localhost"), nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), transactionEntity.ID.String())
+	assert.Contains(t, recorder.Body.String(), "45000.00")
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/fake-gateway/pay/"+payment.Token+"/pay", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), transaction.PaymentStatusSettlement)
+	assert.Len(t, webhook.notifications, 1)
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fake-gateway/pay/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
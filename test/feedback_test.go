@@ -0,0 +1,215 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/feedback"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/menu/category"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/platform/pagination"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockFeedbackRepository struct{ mock.Mock }
+
+func (m *MockFeedbackRepository) CreateFeedback(ctx context.Context, tx interface{}, feedbackEntity feedback.Feedback) (feedback.Feedback, error) {
+	args := m.Called(ctx, tx, feedbackEntity)
+	return feedbackEntity, args.Error(0)
+}
+func (m *MockFeedbackRepository) GetAllFeedback(ctx context.Context, tx interface{}, filter feedback.Filter, req pagination.Request) (pagination.ResponseWithData, error) {
+	args := m.Called(ctx, tx, filter, req)
+	return args.Get(0).(pagination.ResponseWithData), args.Error(1)
+}
+func (m *MockFeedbackRepository) GetMenuSummaries(ctx context.Context, tx interface{}, menuIDs []string) ([]feedback.MenuSummary, error) {
+	args := m.Called(ctx, tx, menuIDs)
+	return args.Get(0).([]feedback.MenuSummary), args.Error(1)
+}
+
+func noRatings() *MockFeedbackRepository {
+	mockFeedbackRepo := new(MockFeedbackRepository)
+	mockFeedbackRepo.On("GetMenuSummaries", mock.Anything, nil, mock.Anything).Return([]feedback.MenuSummary{}, nil)
+	return mockFeedbackRepo
+}
+
+func servedTransaction(customerID identity.ID, servedAt time.Time, menus ...menu.Menu) transaction.Query {
+	orders := make([]transaction.OrderQuery, 0, len(menus))
+	for _, menuEntity := range menus {
+		orders = append(orders, transaction.OrderQuery{
+			Order: order.Order{MenuID: menuEntity.ID, Price: menuEntity.Price, Quantity: 1},
+			Menu:  menuEntity,
+		})
+	}
+
+	return transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.New()),
+			UserID:      customerID,
+			OrderStatus: transaction.NewOrderStatusFromSchema(transaction.OrderStatusServed),
+			ServedAt:    &servedAt,
+			QueueCode:   transaction.QueueCode{Code: "A012"},
+		},
+		Orders: orders,
+	}
+}
+
+func TestFeedback_CheckSubmittable(t *testing.T) {
+	customerID := identity.NewID(uuid.New())
+	served := servedTransaction(customerID, lunchTime.Add(-time.Hour)).Transaction
+	window := feedback.DefaultSubmissionWindow
+
+	assert.NoError(t, feedback.CheckSubmittable(served, customerID.String(), lunchTime, window))
+	assert.ErrorIs(t, feedback.CheckSubmittable(served, uuid.NewString(), lunchTime, window), transaction.ErrorNotTransactionOwner)
+	assert.ErrorIs(t, feedback.CheckSubmittable(served, customerID.String(), lunchTime.Add(window), window), feedback.ErrorSubmissionClosed)
+
+	delivering := served
+	delivering.OrderStatus = transaction.NewOrderStatusFromSchema(transaction.OrderStatusDelivering)
+	delivering.ServedAt = nil
+	assert.ErrorIs(t, feedback.CheckSubmittable(delivering, customerID.String(), lunchTime, window), feedback.ErrorNotServed)
+
+	guest := served
+	guest.UserID = identity.ID{}
+	assert.ErrorIs(t, feedback.CheckSubmittable(guest, "", lunchTime, window), transaction.ErrorNotTransactionOwner)
+}
+
+func TestSubmitFeedback(t *testing.T) {
+	ctx := context.Background()
+	customerID := identity.NewID(uuid.New())
+	nasiGoreng := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Nasi Goreng", Price: price(30000)}
+	esTeh := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Es Teh", Price: price(5000)}
+
+	t.Run("stores the ratings in its own transaction", func(t *testing.T) {
+		mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+		mockFeedbackRepo := new(MockFeedbackRepository)
+		feedbackService := service.NewFeedbackService(mockFeedbackRepo, mockTransactionRepo, fixedClock{now: lunchTime}, new(MockTransactionInterfaceForFinishCooking), 0)
+
+		served := servedTransaction(customerID, lunchTime.Add(-2*time.Hour), nasiGoreng, esTeh)
+		mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, served.Transaction.ID.String()).Return(served, nil)
+
+		_, err := feedbackService.SubmitFeedback(ctx, customerID.String(), request.SubmitFeedback{
+			TransactionID: served.Transaction.ID.String(),
+			Rating:        4,
+			MenuRatings:   []request.MenuRating{{MenuID: nasiGoreng.ID.String(), Rating: 5}},
+			Comment:       "Nasi gorengnya enak",
+		})
+
+		// The validation passes and the feedback is only written within a
+		// transaction, which the mock cannot begin.
+		assert.Error(t, err)
+		assert.Equal(t, "invalid transaction", err.Error())
+		mockFeedbackRepo.AssertNotCalled(t, "CreateFeedback", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects ratings of menus that were not ordered", func(t *testing.T) {
+		mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+		mockFeedbackRepo := new(MockFeedbackRepository)
+		feedbackService := service.NewFeedbackService(mockFeedbackRepo, mockTransactionRepo, fixedClock{now: lunchTime}, nil, 0)
+
+		served := servedTransaction(customerID, lunchTime.Add(-time.Hour), esTeh)
+		mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, served.Transaction.ID.String()).Return(served, nil)
+
+		_, err := feedbackService.SubmitFeedback(ctx, customerID.String(), request.SubmitFeedback{
+			TransactionID: served.Transaction.ID.String(),
+			Rating:        3,
+			MenuRatings:   []request.MenuRating{{MenuID: nasiGoreng.ID.String(), Rating: 1}},
+		})
+
+		assert.ErrorIs(t, err, feedback.ErrorInvalidMenuRating)
+		mockFeedbackRepo.AssertNotCalled(t, "CreateFeedback", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("after the window", func(t *testing.T) {
+		mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+		feedbackService := service.NewFeedbackService(new(MockFeedbackRepository), mockTransactionRepo, fixedClock{now: lunchTime}, nil, time.Hour)
+
+		served := servedTransaction(customerID, lunchTime.Add(-2*time.Hour), esTeh)
+		mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, served.Transaction.ID.String()).Return(served, nil)
+
+		_, err := feedbackService.SubmitFeedback(ctx, customerID.String(), request.SubmitFeedback{
+			TransactionID: served.Transaction.ID.String(),
+			Rating:        5,
+		})
+
+		assert.ErrorIs(t, err, feedback.ErrorSubmissionClosed)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+		feedbackService := service.NewFeedbackService(new(MockFeedbackRepository), mockTransactionRepo, fixedClock{now: lunchTime}, nil, 0)
+
+		transactionID := uuid.NewString()
+		mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transaction.Query{}, gorm.ErrRecordNotFound)
+
+		_, err := feedbackService.SubmitFeedback(ctx, customerID.String(), request.SubmitFeedback{TransactionID: transactionID, Rating: 5})
+
+		assert.ErrorIs(t, err, transaction.ErrorTransactionNotFound)
+	})
+}
+
+func TestGetAllFeedback_PassesFilters(t *testing.T) {
+	ctx := context.Background()
+	mockFeedbackRepo := new(MockFeedbackRepository)
+	feedbackService := service.NewFeedbackService(mockFeedbackRepo, nil, fixedClock{now: lunchTime}, nil, 0)
+
+	menuID := uuid.NewString()
+	hasComment := true
+	feedbackEntity := feedback.Feedback{
+		ID:            identity.NewID(uuid.New()),
+		TransactionID: identity.NewID(uuid.New()),
+		UserID:        identity.NewID(uuid.New()),
+		Rating:        feedback.NewRatingFromSchema(2),
+		Comment:       "Es tehnya kemanisan",
+		MenuRatings:   []feedback.MenuRating{{MenuID: identity.NewID(uuid.MustParse(menuID)), Rating: feedback.NewRatingFromSchema(1)}},
+	}
+	req := request.GetAllFeedback{MenuID: menuID, MaxRating: 2, HasComment: &hasComment}
+
+	mockFeedbackRepo.On("GetAllFeedback", ctx, nil, feedback.Filter{MenuID: menuID, MaxRating: 2, HasComment: &hasComment}, req.Request).Return(pagination.ResponseWithData{
+		Data:     []any{feedback.Query{Feedback: feedbackEntity, UserName: "Budi", MenuNames: map[string]string{menuID: "Es Teh"}}},
+		Response: pagination.Response{Page: 1, PerPage: 10, MaxPage: 1, Count: 1},
+	}, nil)
+
+	result, err := feedbackService.GetAllFeedback(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Count)
+	assert.Len(t, result.Data, 1)
+
+	_, err = feedbackService.GetAllFeedback(ctx, request.GetAllFeedback{MinRating: 4, MaxRating: 2})
+	assert.ErrorIs(t, err, feedback.ErrorInvalidRating)
+}
+
+func TestGetAllMenus_IncludesRatings(t *testing.T) {
+	ctx := context.Background()
+	mockMenuRepo := new(MockMenuRepositoryForAvailability)
+	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
+	mockFeedbackRepo := new(MockFeedbackRepository)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, mockFeedbackRepo, fixedClock{now: lunchTime}, nil)
+
+	categoryEntity := category.Category{ID: identity.NewID(uuid.New()), Name: "Makanan"}
+	rated := menu.Menu{ID: identity.NewID(uuid.New()), CategoryID: categoryEntity.ID, Name: "Nasi Goreng", Price: price(30000), IsAvailable: true}
+	unrated := menu.Menu{ID: identity.NewID(uuid.New()), CategoryID: categoryEntity.ID, Name: "Mie Goreng", Price: price(28000), IsAvailable: true}
+
+	mockMenuRepo.On("GetAllMenus", ctx, nil).Return([]menu.Menu{rated, unrated}, nil)
+	mockCategoryRepo.On("GetCategoryByID", ctx, nil, categoryEntity.ID.String()).Return(categoryEntity, nil)
+	mockFeedbackRepo.On("GetMenuSummaries", ctx, nil, []string{rated.ID.String(), unrated.ID.String()}).Return([]feedback.MenuSummary{
+		{MenuID: rated.ID, Average: decimal.RequireFromString("4.3333"), Count: 3},
+	}, nil)
+
+	result, err := menuService.GetAllMenus(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "4.3", result[0].Rating.Average.String())
+	assert.Equal(t, 3, result[0].Rating.Count)
+	assert.Nil(t, result[1].Rating)
+}
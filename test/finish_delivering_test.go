@@ -51,8 +51,8 @@ func (m *MockTransactionRepositoryForFinishDelivering) GetTransactionByID(ctx co
 	return args.Get(0), args.Error(1)
 }
 
-func (m *MockTransactionRepositoryForFinishDelivering) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
-	args := m.Called(ctx, tx, id)
+func (m *MockTransactionRepositoryForFinishDelivering) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
+	args := m.Called(ctx, tx, id, businessDay)
 	return args.Get(0).(string), args.Error(1)
 }
 
@@ -96,6 +96,50 @@ func (m *MockTransactionRepositoryForFinishDelivering) GetTransactionByQueueCode
 	return args.Get(0).(transaction.Query), args.Error(1)
 }
 
+func (m *MockTransactionRepositoryForFinishDelivering) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForFinishDelivering) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 // Mock other repositories
 type MockUserRepositoryForFinishDelivering struct {
 	mock.Mock
@@ -120,6 +164,12 @@ func (m *MockUserRepositoryForFinishDelivering) CheckEmail(ctx context.Context,
 	args := m.Called(ctx, tx, email)
 	return args.Get(0).(user.User), args.Get(1).(bool), args.Error(2)
 }
+func (m *MockUserRepositoryForFinishDelivering) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForFinishDelivering) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForFinishDelivering struct {
 	mock.Mock
@@ -135,6 +185,11 @@ func (m *MockTableRepositoryForFinishDelivering) GetTableByID(ctx context.Contex
 	return args.Get(0).(table.Table), args.Error(1)
 }
 
+func (m *MockTableRepositoryForFinishDelivering) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	args := m.Called(ctx, tx, token)
+	return args.Get(0).(table.Table), args.Error(1)
+}
+
 type MockOrderRepositoryForFinishDelivering struct {
 	mock.Mock
 }
@@ -149,6 +204,10 @@ func (m *MockOrderRepositoryForFinishDelivering) GetOrdersByTransactionID(ctx co
 	return args.Get(0).([]order.Order), args.Error(1)
 }
 
+func (m *MockOrderRepositoryForFinishDelivering) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
+
 type MockMenuRepositoryForFinishDelivering struct {
 	mock.Mock
 }
@@ -173,6 +232,16 @@ func (m *MockMenuRepositoryForFinishDelivering) UpdateMenuAvailability(ctx conte
 	return args.Get(0).(menu_item.Menu), args.Error(1)
 }
 
+func (m *MockMenuRepositoryForFinishDelivering) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepositoryForFinishDelivering) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+
 type MockPaymentGatewayPortForFinishDelivering struct {
 	mock.Mock
 }
@@ -187,14 +256,38 @@ func (m *MockPaymentGatewayPortForFinishDelivering) HookPayment(ctx context.Cont
 	return args.Error(0)
 }
 
+func (m *MockPaymentGatewayPortForFinishDelivering) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForFinishDelivering) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockPaymentGatewayPortForFinishDelivering) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForFinishDelivering) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForFinishDelivering) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForFinishDelivering) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
+
 // Mock transaction domain service
 type MockTransactionDomainServiceForFinishDelivering struct {
 	mock.Mock
 }
 
-func (m *MockTransactionDomainServiceForFinishDelivering) GenerateQueueCode(ctx context.Context, transactionID string) (string, error) {
+func (m *MockTransactionDomainServiceForFinishDelivering) GenerateQueueCode(ctx context.Context, tx interface{}, transactionID string) (transaction.QueueCode, error) {
 	args := m.Called(ctx, transactionID)
-	return args.Get(0).(string), args.Error(1)
+	return args.Get(0).(transaction.QueueCode), args.Error(1)
 }
 
 func (m *MockTransactionDomainServiceForFinishDelivering) CalculateMaxCookingTime(orders []transaction.OrderQuery) time.Duration {
@@ -207,6 +300,21 @@ func (m *MockTransactionDomainServiceForFinishDelivering) GetOrderDelayStatus(ma
 	return args.Get(0).(bool)
 }
 
+func (m *MockTransactionDomainServiceForFinishDelivering) GetKitchenSnapshot(ctx context.Context) (transaction.KitchenSnapshot, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(transaction.KitchenSnapshot), args.Error(1)
+}
+
+func (m *MockTransactionDomainServiceForFinishDelivering) GetThroughput(ctx context.Context) (transaction.Throughput, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(transaction.Throughput), args.Error(1)
+}
+
+func (m *MockTransactionDomainServiceForFinishDelivering) EstimateWaitTime(snapshot transaction.KitchenSnapshot, target transaction.Query) transaction.WaitEstimate {
+	args := m.Called(snapshot, target)
+	return args.Get(0).(transaction.WaitEstimate)
+}
+
 // Mock order service
 type MockOrderServiceForFinishDelivering struct {
 	mock.Mock
@@ -217,6 +325,13 @@ func (m *MockOrderServiceForFinishDelivering) CalculateTotalPrice(ctx context.Co
 	return args.Get(0).(shared.Price), args.Error(1)
 }
 
+func (m *MockOrderServiceForFinishDelivering) CalculatePriceBreakdown(ctx context.Context, userID string, orders []request.Order, voucherCode string, orderType string, pickupAt *time.Time) (order.PriceBreakdown, error) {
+	return order.PriceBreakdown{}, nil
+}
+func (m *MockOrderServiceForFinishDelivering) ClaimPromotions(ctx context.Context, tx interface{}, userID string, breakdown order.PriceBreakdown) error {
+	return nil
+}
+
 // Mock transaction interface that can be validated
 type MockTransactionInterfaceForFinishDelivering struct {
 	mock.Mock
@@ -258,6 +373,12 @@ func TestFinishDelivering_Success(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -296,6 +417,12 @@ func TestFinishDelivering_TransactionNotFound(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -333,6 +460,12 @@ func TestFinishDelivering_InvalidTransactionType(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -370,6 +503,12 @@ func TestFinishDelivering_InvalidOrderStatus(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -407,6 +546,12 @@ func TestFinishDelivering_UpdateStatusError(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -444,6 +589,12 @@ func TestFinishDelivering_UpdateServedAtError(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -481,6 +632,12 @@ func TestFinishDelivering_TransactionBeginError(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -518,6 +675,12 @@ func TestFinishDelivering_WithMultipleOrders(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
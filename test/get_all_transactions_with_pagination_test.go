@@ -54,7 +54,7 @@ func (m *MockTransactionRepositoryForPagination) GetTransactionByID(ctx context.
 func (m *MockTransactionRepositoryForPagination) GetNextOrder(ctx context.Context, tx interface{}) (response.NextOrder, error) {
 	return response.NextOrder{}, nil
 }
-func (m *MockTransactionRepositoryForPagination) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+func (m *MockTransactionRepositoryForPagination) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	return "", nil
 }
 func (m *MockTransactionRepositoryForPagination) UpdateCookedAt(ctx context.Context, tx interface{}, transactionID string) (transaction.Transaction, error) {
@@ -83,14 +83,58 @@ func (m *MockTransactionRepositoryForPagination) GetTransactionByQueueCode(ctx c
 	return transaction.Query{}, nil
 }
 
+func (m *MockTransactionRepositoryForPagination) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForPagination) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 // Mock transaction domain service
 type MockTransactionDomainServiceForPagination struct {
 	mock.Mock
 }
 
-func (m *MockTransactionDomainServiceForPagination) GenerateQueueCode(ctx context.Context, transactionID string) (string, error) {
+func (m *MockTransactionDomainServiceForPagination) GenerateQueueCode(ctx context.Context, tx interface{}, transactionID string) (transaction.QueueCode, error) {
 	args := m.Called(ctx, transactionID)
-	return args.Get(0).(string), args.Error(1)
+	return args.Get(0).(transaction.QueueCode), args.Error(1)
 }
 
 func (m *MockTransactionDomainServiceForPagination) CalculateMaxCookingTime(orders []transaction.OrderQuery) time.Duration {
@@ -103,6 +147,21 @@ func (m *MockTransactionDomainServiceForPagination) GetOrderDelayStatus(maxCooki
 	return args.Get(0).(bool)
 }
 
+func (m *MockTransactionDomainServiceForPagination) GetKitchenSnapshot(ctx context.Context) (transaction.KitchenSnapshot, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(transaction.KitchenSnapshot), args.Error(1)
+}
+
+func (m *MockTransactionDomainServiceForPagination) GetThroughput(ctx context.Context) (transaction.Throughput, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(transaction.Throughput), args.Error(1)
+}
+
+func (m *MockTransactionDomainServiceForPagination) EstimateWaitTime(snapshot transaction.KitchenSnapshot, target transaction.Query) transaction.WaitEstimate {
+	args := m.Called(snapshot, target)
+	return args.Get(0).(transaction.WaitEstimate)
+}
+
 // Mock order service
 type MockOrderServiceForPagination struct {
 	mock.Mock
@@ -113,6 +172,13 @@ func (m *MockOrderServiceForPagination) CalculateTotalPrice(ctx context.Context,
 	return args.Get(0).(shared.Price), args.Error(1)
 }
 
+func (m *MockOrderServiceForPagination) CalculatePriceBreakdown(ctx context.Context, userID string, orders []request.Order, voucherCode string, orderType string, pickupAt *time.Time) (order.PriceBreakdown, error) {
+	return order.PriceBreakdown{}, nil
+}
+func (m *MockOrderServiceForPagination) ClaimPromotions(ctx context.Context, tx interface{}, userID string, breakdown order.PriceBreakdown) error {
+	return nil
+}
+
 // Minimal mocks for other repositories
 type MockUserRepositoryForPagination struct{ mock.Mock }
 
@@ -128,6 +194,12 @@ func (m *MockUserRepositoryForPagination) GetUserByEmail(ctx context.Context, tx
 func (m *MockUserRepositoryForPagination) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
 	return user.User{}, false, nil
 }
+func (m *MockUserRepositoryForPagination) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForPagination) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForPagination struct{ mock.Mock }
 
@@ -138,6 +210,10 @@ func (m *MockTableRepositoryForPagination) GetTableByID(ctx context.Context, tx
 	return table.Table{}, nil
 }
 
+func (m *MockTableRepositoryForPagination) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	return table.Table{}, nil
+}
+
 type MockOrderRepositoryForPagination struct{ mock.Mock }
 
 func (m *MockOrderRepositoryForPagination) CreateOrder(ctx context.Context, tx interface{}, orderEntity order.Order) (order.Order, error) {
@@ -146,6 +222,9 @@ func (m *MockOrderRepositoryForPagination) CreateOrder(ctx context.Context, tx i
 func (m *MockOrderRepositoryForPagination) GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]order.Order, error) {
 	return nil, nil
 }
+func (m *MockOrderRepositoryForPagination) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
 
 type MockMenuRepositoryForPagination struct{ mock.Mock }
 
@@ -161,6 +240,14 @@ func (m *MockMenuRepositoryForPagination) GetMenusByCategoryID(ctx context.Conte
 func (m *MockMenuRepositoryForPagination) UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (menu_item.Menu, error) {
 	return menu_item.Menu{}, nil
 }
+func (m *MockMenuRepositoryForPagination) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+func (m *MockMenuRepositoryForPagination) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
 
 type MockPaymentGatewayPortForPagination struct{ mock.Mock }
 
@@ -170,6 +257,28 @@ func (m *MockPaymentGatewayPortForPagination) ProcessPayment(ctx context.Context
 func (m *MockPaymentGatewayPortForPagination) HookPayment(ctx context.Context, tx interface{}, transactionID uuid.UUID, datas map[string]interface{}) error {
 	return nil
 }
+func (m *MockPaymentGatewayPortForPagination) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForPagination) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+func (m *MockPaymentGatewayPortForPagination) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForPagination) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForPagination) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForPagination) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
 
 func TestGetAllTransactionsWithPagination_Success(t *testing.T) {
 	mockTransactionRepo := new(MockTransactionRepositoryForPagination)
@@ -191,6 +300,12 @@ func TestGetAllTransactionsWithPagination_Success(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -233,8 +348,9 @@ func TestGetAllTransactionsWithPagination_Success(t *testing.T) {
 	req := pagination.Request{Page: 1, PerPage: 10}
 	mockTransactionRepo.On("GetAllTransactionsWithPagination", ctx, nil, userID, req).Return(paginated, nil)
 
-	// Mock the CalculateMaxCookingTime method that the service calls
-	mockTransactionDomainService.On("CalculateMaxCookingTime", query.Orders).Return(30 * time.Minute)
+	// Mock the kitchen snapshot and estimate the service calls
+	mockTransactionDomainService.On("GetKitchenSnapshot", ctx).Return(transaction.KitchenSnapshot{}, nil)
+	mockTransactionDomainService.On("EstimateWaitTime", transaction.KitchenSnapshot{}, query).Return(transaction.WaitEstimate{CookingTime: 30 * time.Minute})
 
 	// Mock the GetOrderDelayStatus method that the service calls
 	mockTransactionDomainService.On("GetOrderDelayStatus", 30*time.Minute, (*time.Time)(nil), (*time.Time)(nil)).Return(false)
@@ -275,6 +391,12 @@ func TestGetAllTransactionsWithPagination_Empty(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -286,6 +408,7 @@ func TestGetAllTransactionsWithPagination_Empty(t *testing.T) {
 	}
 
 	mockTransactionRepo.On("GetAllTransactionsWithPagination", ctx, nil, userID, req).Return(paginated, nil)
+	mockTransactionDomainService.On("GetThroughput", ctx).Return(transaction.Throughput{}, nil)
 
 	result, err := transactionService.GetAllTransactionsWithPagination(ctx, userID, req)
 
@@ -314,6 +437,12 @@ func TestGetAllTransactionsWithPagination_InvalidType(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -353,6 +482,12 @@ func TestGetAllTransactionsWithPagination_RepoError(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
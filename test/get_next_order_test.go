@@ -7,11 +7,13 @@ import (
 	menu_item "fp-kpl/domain/menu/menu_item"
 	"fp-kpl/domain/order"
 	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
 	"fp-kpl/domain/table"
 	"fp-kpl/domain/transaction"
 	"fp-kpl/domain/user"
 	"fp-kpl/platform/pagination"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -42,7 +44,7 @@ func (m *MockTransactionRepositoryForNextOrder) GetAllReadyToServeTransactionLis
 func (m *MockTransactionRepositoryForNextOrder) GetTransactionByID(ctx context.Context, tx interface{}, userID string, id string) (interface{}, error) {
 	return nil, nil
 }
-func (m *MockTransactionRepositoryForNextOrder) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+func (m *MockTransactionRepositoryForNextOrder) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	return "", nil
 }
 func (m *MockTransactionRepositoryForNextOrder) UpdateCookedAt(ctx context.Context, tx interface{}, transactionID string) (transaction.Transaction, error) {
@@ -70,6 +72,50 @@ func (m *MockTransactionRepositoryForNextOrder) GetDetailedTransactionByID(ctx c
 	return transaction.Query{}, nil
 }
 
+func (m *MockTransactionRepositoryForNextOrder) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForNextOrder) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 // Mocks for other repositories (minimal, not used in these tests)
 type MockUserRepository struct{ mock.Mock }
 
@@ -85,6 +131,12 @@ func (m *MockUserRepository) GetUserByEmail(ctx context.Context, tx interface{},
 func (m *MockUserRepository) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
 	return user.User{}, false, nil
 }
+func (m *MockUserRepository) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepository) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepository struct{ mock.Mock }
 
@@ -95,6 +147,10 @@ func (m *MockTableRepository) GetTableByID(ctx context.Context, tx interface{},
 	return table.Table{}, nil
 }
 
+func (m *MockTableRepository) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	return table.Table{}, nil
+}
+
 type MockOrderRepository struct{ mock.Mock }
 
 func (m *MockOrderRepository) CreateOrder(ctx context.Context, tx interface{}, orderEntity order.Order) (order.Order, error) {
@@ -103,6 +159,9 @@ func (m *MockOrderRepository) CreateOrder(ctx context.Context, tx interface{}, o
 func (m *MockOrderRepository) GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]order.Order, error) {
 	return nil, nil
 }
+func (m *MockOrderRepository) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
 
 type MockMenuRepository struct{ mock.Mock }
 
@@ -118,6 +177,14 @@ func (m *MockMenuRepository) GetMenusByCategoryID(ctx context.Context, tx interf
 func (m *MockMenuRepository) UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (menu_item.Menu, error) {
 	return menu_item.Menu{}, nil
 }
+func (m *MockMenuRepository) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+func (m *MockMenuRepository) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
 
 type MockPaymentGatewayPort struct{ mock.Mock }
 
@@ -127,6 +194,28 @@ func (m *MockPaymentGatewayPort) ProcessPayment(ctx context.Context, tx interfac
 func (m *MockPaymentGatewayPort) HookPayment(ctx context.Context, tx interface{}, transactionID uuid.UUID, datas map[string]interface{}) error {
 	return nil
 }
+func (m *MockPaymentGatewayPort) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPort) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+func (m *MockPaymentGatewayPort) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPort) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPort) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPort) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
 
 func TestGetNextOrder_Success(t *testing.T) {
 	mockTransactionRepo := new(MockTransactionRepositoryForNextOrder)
@@ -146,6 +235,12 @@ func TestGetNextOrder_Success(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -165,7 +260,7 @@ func TestGetNextOrder_Success(t *testing.T) {
 		}},
 	}, nil)
 
-	result, err := transactionService.GetNextOrder(ctx)
+	result, err := transactionService.GetNextOrder(ctx, uuid.NewString())
 
 	assert.NoError(t, err)
 	assert.Equal(t, queueCode, result.QueueCode)
@@ -195,13 +290,19 @@ func TestGetNextOrder_NoOrder(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 
 	mockTransactionRepo.On("GetNextOrder", ctx, nil).Return(response.NextOrder{}, transaction.ErrorNextOrderNotFound)
 
-	result, err := transactionService.GetNextOrder(ctx)
+	result, err := transactionService.GetNextOrder(ctx, uuid.NewString())
 
 	assert.ErrorIs(t, err, transaction.ErrorNextOrderNotFound)
 	assert.Equal(t, response.NextOrder{}, result)
@@ -226,13 +327,19 @@ func TestGetNextOrder_InvalidType(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 
 	mockTransactionRepo.On("GetNextOrder", ctx, nil).Return(response.NextOrder{}, assert.AnError)
 
-	result, err := transactionService.GetNextOrder(ctx)
+	result, err := transactionService.GetNextOrder(ctx, uuid.NewString())
 
 	assert.ErrorIs(t, err, assert.AnError)
 	assert.Equal(t, response.NextOrder{}, result)
@@ -257,6 +364,12 @@ func TestGetNextOrder_RepoError(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -264,7 +377,7 @@ func TestGetNextOrder_RepoError(t *testing.T) {
 	repoErr := assert.AnError
 	mockTransactionRepo.On("GetNextOrder", ctx, nil).Return(response.NextOrder{}, repoErr)
 
-	result, err := transactionService.GetNextOrder(ctx)
+	result, err := transactionService.GetNextOrder(ctx, uuid.NewString())
 
 	assert.Error(t, err)
 	assert.Equal(t, response.NextOrder{}, result)
@@ -14,6 +14,7 @@ import (
 	"fp-kpl/domain/user"
 	"fp-kpl/platform/pagination"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -43,7 +44,7 @@ func (m *MockTransactionRepositoryForReadyToServe) GetTransactionByID(ctx contex
 func (m *MockTransactionRepositoryForReadyToServe) GetNextOrder(ctx context.Context, tx interface{}) (response.NextOrder, error) {
 	return response.NextOrder{}, nil
 }
-func (m *MockTransactionRepositoryForReadyToServe) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+func (m *MockTransactionRepositoryForReadyToServe) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	return "", nil
 }
 func (m *MockTransactionRepositoryForReadyToServe) UpdateCookedAt(ctx context.Context, tx interface{}, transactionID string) (transaction.Transaction, error) {
@@ -71,6 +72,50 @@ func (m *MockTransactionRepositoryForReadyToServe) GetDetailedTransactionByID(ct
 	return transaction.Query{}, nil
 }
 
+func (m *MockTransactionRepositoryForReadyToServe) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForReadyToServe) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 // Minimal mocks for other repositories
 type MockUserRepositoryForReadyToServe struct{ mock.Mock }
 
@@ -86,6 +131,12 @@ func (m *MockUserRepositoryForReadyToServe) GetUserByEmail(ctx context.Context,
 func (m *MockUserRepositoryForReadyToServe) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
 	return user.User{}, false, nil
 }
+func (m *MockUserRepositoryForReadyToServe) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForReadyToServe) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForReadyToServe struct{ mock.Mock }
 
@@ -96,6 +147,10 @@ func (m *MockTableRepositoryForReadyToServe) GetTableByID(ctx context.Context, t
 	return table.Table{}, nil
 }
 
+func (m *MockTableRepositoryForReadyToServe) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	return table.Table{}, nil
+}
+
 type MockOrderRepositoryForReadyToServe struct{ mock.Mock }
 
 func (m *MockOrderRepositoryForReadyToServe) CreateOrder(ctx context.Context, tx interface{}, orderEntity order.Order) (order.Order, error) {
@@ -104,6 +159,9 @@ func (m *MockOrderRepositoryForReadyToServe) CreateOrder(ctx context.Context, tx
 func (m *MockOrderRepositoryForReadyToServe) GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]order.Order, error) {
 	return nil, nil
 }
+func (m *MockOrderRepositoryForReadyToServe) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
 
 type MockMenuRepositoryForReadyToServe struct{ mock.Mock }
 
@@ -119,6 +177,14 @@ func (m *MockMenuRepositoryForReadyToServe) GetMenusByCategoryID(ctx context.Con
 func (m *MockMenuRepositoryForReadyToServe) UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (menu_item.Menu, error) {
 	return menu_item.Menu{}, nil
 }
+func (m *MockMenuRepositoryForReadyToServe) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+func (m *MockMenuRepositoryForReadyToServe) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
 
 type MockPaymentGatewayPortForReadyToServe struct{ mock.Mock }
 
@@ -128,6 +194,28 @@ func (m *MockPaymentGatewayPortForReadyToServe) ProcessPayment(ctx context.Conte
 func (m *MockPaymentGatewayPortForReadyToServe) HookPayment(ctx context.Context, tx interface{}, transactionID uuid.UUID, datas map[string]interface{}) error {
 	return nil
 }
+func (m *MockPaymentGatewayPortForReadyToServe) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForReadyToServe) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+func (m *MockPaymentGatewayPortForReadyToServe) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForReadyToServe) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForReadyToServe) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForReadyToServe) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
 
 func TestGetAllReadyToServeTransactionList_Success(t *testing.T) {
 	mockTransactionRepo := new(MockTransactionRepositoryForReadyToServe)
@@ -147,6 +235,12 @@ func TestGetAllReadyToServeTransactionList_Success(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -222,6 +316,12 @@ func TestGetAllReadyToServeTransactionList_Empty(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -258,6 +358,12 @@ func TestGetAllReadyToServeTransactionList_InvalidType(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
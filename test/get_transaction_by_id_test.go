@@ -48,8 +48,8 @@ func (m *MockTransactionRepositoryForGetByID) GetTransactionByID(ctx context.Con
 	return args.Get(0), args.Error(1)
 }
 
-func (m *MockTransactionRepositoryForGetByID) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
-	args := m.Called(ctx, tx, id)
+func (m *MockTransactionRepositoryForGetByID) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
+	args := m.Called(ctx, tx, id, businessDay)
 	return args.Get(0).(string), args.Error(1)
 }
 
@@ -97,6 +97,52 @@ func (m *MockTransactionRepositoryForGetByID) GetDetailedTransactionByID(ctx con
 	return args.Get(0).(transaction.Query), args.Error(1)
 }
 
+func (m *MockTransactionRepositoryForGetByID) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForGetByID) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	args := m.Called(ctx, tx, tokenHash)
+	return args.Get(0).(transaction.Query), args.Error(1)
+}
+
+func (m *MockTransactionRepositoryForGetByID) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	args := m.Called(ctx, tx, id)
+	return args.Error(0)
+}
+
 // Mock other repositories
 type MockUserRepositoryForTransaction struct {
 	mock.Mock
@@ -126,6 +172,12 @@ func (m *MockUserRepositoryForTransaction) CheckEmail(ctx context.Context, tx in
 	args := m.Called(ctx, tx, email)
 	return args.Get(0).(user.User), args.Get(1).(bool), args.Error(2)
 }
+func (m *MockUserRepositoryForTransaction) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForTransaction) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForTransaction struct {
 	mock.Mock
@@ -141,6 +193,11 @@ func (m *MockTableRepositoryForTransaction) GetTableByID(ctx context.Context, tx
 	return args.Get(0).(table.Table), args.Error(1)
 }
 
+func (m *MockTableRepositoryForTransaction) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	args := m.Called(ctx, tx, token)
+	return args.Get(0).(table.Table), args.Error(1)
+}
+
 type MockOrderRepositoryForTransaction struct {
 	mock.Mock
 }
@@ -155,6 +212,10 @@ func (m *MockOrderRepositoryForTransaction) GetOrdersByTransactionID(ctx context
 	return args.Get(0).([]order.Order), args.Error(1)
 }
 
+func (m *MockOrderRepositoryForTransaction) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
+
 type MockMenuRepositoryForTransaction struct {
 	mock.Mock
 }
@@ -179,6 +240,16 @@ func (m *MockMenuRepositoryForTransaction) UpdateMenuAvailability(ctx context.Co
 	return args.Get(0).(menu.Menu), args.Error(1)
 }
 
+func (m *MockMenuRepositoryForTransaction) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepositoryForTransaction) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu.PriceOverride) (menu.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu.Menu), args.Error(1)
+}
+
 type MockPaymentGatewayPortForGetByID struct {
 	mock.Mock
 }
@@ -193,18 +264,48 @@ func (m *MockPaymentGatewayPortForGetByID) HookPayment(ctx context.Context, tx i
 	return args.Error(0)
 }
 
+func (m *MockPaymentGatewayPortForGetByID) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForGetByID) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockPaymentGatewayPortForGetByID) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForGetByID) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForGetByID) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForGetByID) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
+
 type MockOrderServiceForGetByID struct{ mock.Mock }
 
 func (m *MockOrderServiceForGetByID) CalculateTotalPrice(ctx context.Context, orders []request.Order) (shared.Price, error) {
 	args := m.Called(ctx, orders)
 	return args.Get(0).(shared.Price), args.Error(1)
 }
+func (m *MockOrderServiceForGetByID) CalculatePriceBreakdown(ctx context.Context, userID string, orders []request.Order, voucherCode string, orderType string, pickupAt *time.Time) (order.PriceBreakdown, error) {
+	return order.PriceBreakdown{}, nil
+}
+func (m *MockOrderServiceForGetByID) ClaimPromotions(ctx context.Context, tx interface{}, userID string, breakdown order.PriceBreakdown) error {
+	return nil
+}
 
 type MockTransactionDomainServiceForGetByID struct{ mock.Mock }
 
-func (m *MockTransactionDomainServiceForGetByID) GenerateQueueCode(ctx context.Context, transactionID string) (string, error) {
+func (m *MockTransactionDomainServiceForGetByID) GenerateQueueCode(ctx context.Context, tx interface{}, transactionID string) (transaction.QueueCode, error) {
 	args := m.Called(ctx, transactionID)
-	return args.Get(0).(string), args.Error(1)
+	return args.Get(0).(transaction.QueueCode), args.Error(1)
 }
 
 func (m *MockTransactionDomainServiceForGetByID) CalculateMaxCookingTime(orders []transaction.OrderQuery) time.Duration {
@@ -217,6 +318,21 @@ func (m *MockTransactionDomainServiceForGetByID) GetOrderDelayStatus(maxCookingT
 	return args.Get(0).(bool)
 }
 
+func (m *MockTransactionDomainServiceForGetByID) GetKitchenSnapshot(ctx context.Context) (transaction.KitchenSnapshot, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(transaction.KitchenSnapshot), args.Error(1)
+}
+
+func (m *MockTransactionDomainServiceForGetByID) GetThroughput(ctx context.Context) (transaction.Throughput, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(transaction.Throughput), args.Error(1)
+}
+
+func (m *MockTransactionDomainServiceForGetByID) EstimateWaitTime(snapshot transaction.KitchenSnapshot, target transaction.Query) transaction.WaitEstimate {
+	args := m.Called(snapshot, target)
+	return args.Get(0).(transaction.WaitEstimate)
+}
+
 func TestGetTransactionByID_Success(t *testing.T) {
 	// Arrange
 	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
@@ -238,6 +354,12 @@ func TestGetTransactionByID_Success(t *testing.T) {
 		mockPaymentGateway,
 		nil, // interface{} - using nil for now
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -290,7 +412,8 @@ func TestGetTransactionByID_Success(t *testing.T) {
 
 	// Set up expectations
 	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transactionQuery, nil)
-	mockTransactionDomainService.On("CalculateMaxCookingTime", transactionQuery.Orders).Return(45 * time.Minute)
+	mockTransactionDomainService.On("GetKitchenSnapshot", ctx).Return(transaction.KitchenSnapshot{}, nil)
+	mockTransactionDomainService.On("EstimateWaitTime", transaction.KitchenSnapshot{}, transactionQuery).Return(transaction.WaitEstimate{CookingTime: 45 * time.Minute})
 	mockTransactionDomainService.On("GetOrderDelayStatus", 45*time.Minute, transactionQuery.Transaction.CookedAt, transactionQuery.Transaction.ServedAt).Return(true)
 
 	// Act
@@ -346,6 +469,12 @@ func TestGetTransactionByID_Success_NotDelayed(t *testing.T) {
 		mockPaymentGateway,
 		nil, // interface{} - using nil for now
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -390,7 +519,8 @@ func TestGetTransactionByID_Success_NotDelayed(t *testing.T) {
 
 	// Set up expectations
 	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transactionQuery, nil)
-	mockTransactionDomainService.On("CalculateMaxCookingTime", transactionQuery.Orders).Return(45 * time.Minute)
+	mockTransactionDomainService.On("GetThroughput", ctx).Return(transaction.Throughput{}, nil)
+	mockTransactionDomainService.On("EstimateWaitTime", mock.AnythingOfType("transaction.KitchenSnapshot"), transactionQuery).Return(transaction.WaitEstimate{CookingTime: 45 * time.Minute})
 	mockTransactionDomainService.On("GetOrderDelayStatus", 45*time.Minute, &cookedAt, &servedAt).Return(false)
 
 	// Act
@@ -437,6 +567,12 @@ func TestGetTransactionByID_TransactionNotFound(t *testing.T) {
 		mockPaymentGateway,
 		nil, // interface{} - using nil for now
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -476,6 +612,12 @@ func TestGetTransactionByID_InvalidTransactionType(t *testing.T) {
 		mockPaymentGateway,
 		nil, // interface{} - using nil for now
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -515,6 +657,12 @@ func TestGetTransactionByID_EmptyOrders(t *testing.T) {
 		mockPaymentGateway,
 		nil, // interface{} - using nil for now
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -543,7 +691,8 @@ func TestGetTransactionByID_EmptyOrders(t *testing.T) {
 
 	// Set up expectations
 	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transactionQuery, nil)
-	mockTransactionDomainService.On("CalculateMaxCookingTime", transactionQuery.Orders).Return(0 * time.Minute)
+	mockTransactionDomainService.On("GetThroughput", ctx).Return(transaction.Throughput{}, nil)
+	mockTransactionDomainService.On("EstimateWaitTime", mock.AnythingOfType("transaction.KitchenSnapshot"), transactionQuery).Return(transaction.WaitEstimate{CookingTime: 0 * time.Minute})
 	mockTransactionDomainService.On("GetOrderDelayStatus", 0*time.Minute, (*time.Time)(nil), (*time.Time)(nil)).Return(false)
 
 	// Act
@@ -585,6 +734,12 @@ func TestGetTransactionByID_WithDecimalPrices(t *testing.T) {
 		mockPaymentGateway,
 		nil, // interface{} - using nil for now
 		mockOrderService,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -626,7 +781,8 @@ func TestGetTransactionByID_WithDecimalPrices(t *testing.T) {
 
 	// Set up expectations
 	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transactionQuery, nil)
-	mockTransactionDomainService.On("CalculateMaxCookingTime", transactionQuery.Orders).Return(5 * time.Minute)
+	mockTransactionDomainService.On("GetKitchenSnapshot", ctx).Return(transaction.KitchenSnapshot{}, nil)
+	mockTransactionDomainService.On("EstimateWaitTime", transaction.KitchenSnapshot{}, transactionQuery).Return(transaction.WaitEstimate{CookingTime: 5 * time.Minute})
 	mockTransactionDomainService.On("GetOrderDelayStatus", 5*time.Minute, (*time.Time)(nil), (*time.Time)(nil)).Return(false)
 
 	// Act
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/transaction"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestNewGuestToken(t *testing.T) {
+	token, err := transaction.NewGuestToken()
+	assert.NoError(t, err)
+
+	other, err := transaction.NewGuestToken()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(token, transaction.GuestTokenPrefix))
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, token, transaction.HashGuestToken(token))
+	assert.Equal(t, transaction.HashGuestToken(token), transaction.HashGuestToken(token))
+}
+
+func TestGetGuestTransaction_ByToken(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	transactionService := lookupService(mockTransactionRepo)
+
+	ctx := context.Background()
+	token, err := transaction.NewGuestToken()
+	assert.NoError(t, err)
+	transactionQuery := transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.New()),
+			Guest:       transaction.Guest{Name: "Budi", TokenHash: transaction.HashGuestToken(token)},
+			QueueCode:   transaction.QueueCode{Code: "Q0003", BusinessDay: "2024-05-15"},
+			OrderStatus: transaction.OrderStatus{Status: transaction.OrderStatusPreparing},
+		},
+	}
+	mockTransactionRepo.On("GetTransactionByGuestTokenHash", ctx, nil, transaction.HashGuestToken(token)).Return(transactionQuery, nil)
+
+	result, err := transactionService.GetGuestTransaction(ctx, token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Q0003", result.QueueCode)
+	assert.Equal(t, transaction.OrderStatusPreparing, result.OrderStatus)
+
+	body, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "Budi")
+	assert.NotContains(t, string(body), transactionQuery.Transaction.ID.String())
+	mockTransactionRepo.AssertExpectations(t)
+}
+
+func TestGetGuestTransaction_UnknownToken(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	transactionService := lookupService(mockTransactionRepo)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetTransactionByGuestTokenHash", ctx, nil, transaction.HashGuestToken("gt_unknown")).Return(transaction.Query{}, gorm.ErrRecordNotFound)
+
+	_, err := transactionService.GetGuestTransaction(ctx, "gt_unknown")
+	assert.ErrorIs(t, err, transaction.ErrorTransactionNotFound)
+
+	_, err = transactionService.GetGuestTransaction(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, transaction.ErrorTransactionNotFound)
+	mockTransactionRepo.AssertNumberOfCalls(t, "GetTransactionByGuestTokenHash", 1)
+}
+
+func TestCalculatePriceBreakdown_GuestSkipsPerUserPromotions(t *testing.T) {
+	perUser := newPromotion(promotion.TypeFixedAmount, 5000)
+	perUser.PerUserLimit = 1
+	running := newPromotion(promotion.TypeFixedAmount, 2000)
+	voucher := newPromotion(promotion.TypePercentage, 20)
+	voucher.VoucherCode = "HEMAT20"
+	voucher.PerUserLimit = 1
+
+	mockPromotionRepo := new(MockPromotionRepository)
+	mockPromotionRepo.On("GetActivePromotions", mock.Anything, nil).Return([]promotion.Promotion{perUser, running}, nil)
+	mockPromotionRepo.On("GetPromotionByVoucherCode", mock.Anything, nil, "HEMAT20").Return(voucher, nil)
+
+	mockMenuRepo := new(MockMenuRepositoryForCalculatePrice)
+	orderService := service.NewOrderService(new(MockOrderRepositoryForCalculatePrice), mockMenuRepo, order.NewService(order.PricingPolicy{}), mockPromotionRepo, fixedClock{now: lunchTime})
+	menuID := uuid.New()
+	mockMenuRepo.On("GetMenuByID", mock.Anything, nil, menuID.String()).Return(menu.Menu{
+		ID:    identity.NewIDFromSchema(menuID),
+		Name:  "Dimsum",
+		Price: price(25000),
+	}, nil)
+	orders := []request.Order{{MenuID: menuID.String(), Quantity: 2}}
+
+	breakdown, err := orderService.CalculatePriceBreakdown(context.Background(), "", orders, "", "", nil)
+	assert.NoError(t, err)
+	assertAmount(t, "2000", breakdown.Discount)
+
+	_, err = orderService.CalculatePriceBreakdown(context.Background(), "", orders, "HEMAT20", "", nil)
+	assert.ErrorIs(t, err, promotion.ErrorAccountRequired)
+	mockPromotionRepo.AssertNotCalled(t, "GetUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
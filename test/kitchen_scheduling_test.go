@@ -0,0 +1,254 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"fp-kpl/application/service"
+	menu_item "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockTransactionRepositoryForScheduling reuses the GetNextOrder mock and only
+// overrides the candidate query used by the non-FIFO strategies.
+type MockTransactionRepositoryForScheduling struct {
+	MockTransactionRepositoryForNextOrder
+}
+
+func (m *MockTransactionRepositoryForScheduling) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	args := m.Called(ctx, tx, stationID)
+	return args.Get(0).([]transaction.Query), args.Error(1)
+}
+
+func maxCookingTime(orders []transaction.OrderQuery) time.Duration {
+	return transaction.NewService(nil, transaction.DefaultStationCapacity, realClock).CalculateMaxCookingTime(orders)
+}
+
+func schedulingCandidate(queueCode string, createdAt time.Time, cookingTime time.Duration, priority int) transaction.Query {
+	return transaction.Query{
+		Transaction: transaction.Transaction{
+			QueueCode: transaction.QueueCode{Code: queueCode, Valid: true},
+			Priority:  priority,
+			Timestamp: shared.Timestamp{CreatedAt: createdAt},
+		},
+		Orders: []transaction.OrderQuery{{
+			Order: order.Order{Quantity: 1},
+			Menu: menu_item.Menu{
+				Name:        "Menu " + queueCode,
+				CookingTime: cookingTime,
+			},
+		}},
+	}
+}
+
+func TestSchedulingStrategy_Pick(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	candidates := []transaction.Query{
+		schedulingCandidate("Q0001", now.Add(-30*time.Minute), 40*time.Minute, 0),
+		schedulingCandidate("Q0002", now.Add(-5*time.Minute), 5*time.Minute, 0),
+		schedulingCandidate("Q0003", now.Add(-2*time.Minute), 15*time.Minute, 1),
+	}
+
+	tests := []struct {
+		name     string
+		strategy transaction.SchedulingStrategy
+		expected string
+	}{
+		{"fifo picks the oldest", transaction.NewFIFOStrategy(), "Q0001"},
+		{"shortest cook time picks the quickest", transaction.NewShortestCookTimeStrategy(maxCookingTime), "Q0002"},
+		{"aging lets the long wait win", transaction.NewAgingStrategy(maxCookingTime, 2), "Q0001"},
+		{"priority picks the vip", transaction.NewPriorityStrategy(transaction.NewFIFOStrategy()), "Q0003"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picked := tt.strategy.Pick(candidates, now)
+			assert.Equal(t, tt.expected, candidates[picked].Transaction.QueueCode.Code)
+		})
+	}
+
+	assert.Equal(t, -1, transaction.NewFIFOStrategy().Pick(nil, now))
+	assert.Equal(t, -1, transaction.NewPriorityStrategy(transaction.NewFIFOStrategy()).Pick(nil, now))
+}
+
+func TestNewSchedulingStrategy(t *testing.T) {
+	for _, name := range transaction.SchedulingStrategies {
+		strategy, err := transaction.NewSchedulingStrategy(name, maxCookingTime, transaction.DefaultAgingRate)
+		assert.NoError(t, err)
+		assert.Equal(t, name, strategy.Name())
+	}
+
+	strategy, err := transaction.NewSchedulingStrategy("", maxCookingTime, transaction.DefaultAgingRate)
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.SchedulingFIFO, strategy.Name())
+
+	_, err = transaction.NewSchedulingStrategy("random", maxCookingTime, transaction.DefaultAgingRate)
+	assert.Error(t, err)
+}
+
+func TestGetNextOrder_WithSchedulingStrategy(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForScheduling)
+
+	transactionService := service.NewTransactionService(
+		mockTransactionRepo,
+		new(MockUserRepository),
+		new(MockTableRepository),
+		new(MockOrderRepository),
+		new(MockMenuRepository),
+		nil,
+		new(MockPaymentGatewayPort),
+		nil,
+		nil,
+		nil,
+		transaction.NewShortestCookTimeStrategy(maxCookingTime),
+		nil,
+		realClock,
+		nil,
+		nil,
+	)
+
+	ctx := context.Background()
+	now := time.Now()
+	mockTransactionRepo.On("GetPendingTransactions", ctx, nil, "").Return([]transaction.Query{
+		schedulingCandidate("Q0001", now.Add(-10*time.Minute), 30*time.Minute, 0),
+		schedulingCandidate("Q0002", now.Add(-1*time.Minute), 5*time.Minute, 0),
+	}, nil)
+
+	result, err := transactionService.GetNextOrder(ctx, uuid.NewString())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Q0002", result.QueueCode)
+	assert.Len(t, result.Orders, 1)
+	mockTransactionRepo.AssertNotCalled(t, "GetNextOrder", ctx, nil)
+}
+
+func TestGetNextOrder_WithSchedulingStrategyEmptyQueue(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForScheduling)
+
+	transactionService := service.NewTransactionService(
+		mockTransactionRepo,
+		new(MockUserRepository),
+		new(MockTableRepository),
+		new(MockOrderRepository),
+		new(MockMenuRepository),
+		nil,
+		new(MockPaymentGatewayPort),
+		nil,
+		nil,
+		nil,
+		transaction.NewPriorityStrategy(transaction.NewFIFOStrategy()),
+		nil,
+		realClock,
+		nil,
+		nil,
+	)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetPendingTransactions", ctx, nil, "").Return([]transaction.Query{}, nil)
+
+	_, err := transactionService.GetNextOrder(ctx, uuid.NewString())
+
+	assert.ErrorIs(t, err, transaction.ErrorNextOrderNotFound)
+}
+
+// simulationResult summarises how long orders waited between being paid and
+// the kitchen starting on them.
+type simulationResult struct {
+	averageWait    time.Duration
+	maxWait        time.Duration
+	averageVIPWait time.Duration
+}
+
+// simulateKitchen replays a fixed stream of paid orders against a single cook
+// that always asks the strategy for the next order once it is free.
+func simulateKitchen(strategy transaction.SchedulingStrategy, arrivals []transaction.Query) simulationResult {
+	start := arrivals[0].Transaction.CreatedAt
+	clock := start
+	next := 0
+	var queue []transaction.Query
+	var result simulationResult
+	var totalWait, totalVIPWait time.Duration
+	var vipCount int
+
+	for next < len(arrivals) || len(queue) > 0 {
+		for next < len(arrivals) && !arrivals[next].Transaction.CreatedAt.After(clock) {
+			queue = append(queue, arrivals[next])
+			next++
+		}
+
+		if len(queue) == 0 {
+			clock = arrivals[next].Transaction.CreatedAt
+			continue
+		}
+
+		picked := strategy.Pick(queue, clock)
+		candidate := queue[picked]
+		queue = append(queue[:picked], queue[picked+1:]...)
+
+		wait := clock.Sub(candidate.Transaction.CreatedAt)
+		totalWait += wait
+		if wait > result.maxWait {
+			result.maxWait = wait
+		}
+		if candidate.Transaction.Priority > 0 {
+			totalVIPWait += wait
+			vipCount++
+		}
+
+		clock = clock.Add(maxCookingTime(candidate.Orders))
+	}
+
+	result.averageWait = totalWait / time.Duration(len(arrivals))
+	if vipCount > 0 {
+		result.averageVIPWait = totalVIPWait / time.Duration(vipCount)
+	}
+	return result
+}
+
+func generateArrivals(seed int64, count int) []transaction.Query {
+	random := rand.New(rand.NewSource(seed))
+	createdAt := time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)
+
+	var arrivals []transaction.Query
+	for i := 0; i < count; i++ {
+		createdAt = createdAt.Add(time.Duration(5+random.Intn(10)) * time.Minute)
+		cookingTime := time.Duration(2+random.Intn(15)) * time.Minute
+		priority := 0
+		if random.Intn(10) == 0 {
+			priority = 1
+		}
+		arrivals = append(arrivals, schedulingCandidate(fmt.Sprintf("Q%04d", i+1), createdAt, cookingTime, priority))
+	}
+	return arrivals
+}
+
+func TestKitchenSchedulingSimulation(t *testing.T) {
+	arrivals := generateArrivals(42, 200)
+
+	results := make(map[string]simulationResult)
+	for _, name := range transaction.SchedulingStrategies {
+		strategy, err := transaction.NewSchedulingStrategy(name, maxCookingTime, 0.5)
+		assert.NoError(t, err)
+
+		results[name] = simulateKitchen(strategy, arrivals)
+		t.Logf("%-20s average wait %-10s max wait %-10s vip average wait %s",
+			name, results[name].averageWait.Round(time.Second), results[name].maxWait.Round(time.Second), results[name].averageVIPWait.Round(time.Second))
+	}
+
+	fifo := results[transaction.SchedulingFIFO]
+	shortest := results[transaction.SchedulingShortestCookTime]
+	aging := results[transaction.SchedulingAging]
+	priority := results[transaction.SchedulingPriority]
+
+	assert.Less(t, shortest.averageWait, fifo.averageWait)
+	assert.Less(t, aging.averageWait, fifo.averageWait)
+	assert.LessOrEqual(t, aging.maxWait, shortest.maxWait)
+	assert.Less(t, priority.averageVIPWait, fifo.averageVIPWait)
+}
@@ -0,0 +1,274 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/loyalty"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoyaltyRepository struct{ mock.Mock }
+
+func (m *MockLoyaltyRepository) LockAccount(ctx context.Context, tx interface{}, userID string) error {
+	args := m.Called(ctx, tx, userID)
+	return args.Error(0)
+}
+func (m *MockLoyaltyRepository) GetAccount(ctx context.Context, tx interface{}, userID string) (loyalty.Account, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Get(0).(loyalty.Account), args.Error(1)
+}
+func (m *MockLoyaltyRepository) CreateEntries(ctx context.Context, tx interface{}, entries []loyalty.Entry) error {
+	args := m.Called(ctx, tx, entries)
+	return args.Error(0)
+}
+func (m *MockLoyaltyRepository) GetSettlements(ctx context.Context, tx interface{}, since time.Time) ([]loyalty.Settlement, error) {
+	args := m.Called(ctx, tx, since)
+	return args.Get(0).([]loyalty.Settlement), args.Error(1)
+}
+func (m *MockLoyaltyRepository) GetExpiringUserIDs(ctx context.Context, tx interface{}, from time.Time, to time.Time) ([]string, error) {
+	args := m.Called(ctx, tx, from, to)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func loyaltyPolicy(t *testing.T) loyalty.Policy {
+	policy, err := loyalty.NewPolicy(decimal.NewFromInt(10000), decimal.NewFromInt(100), 12)
+	assert.NoError(t, err)
+	return policy
+}
+
+func settlement(userID identity.ID, status string, total int64, refunded int64) loyalty.Settlement {
+	return loyalty.Settlement{
+		TransactionID: identity.NewID(uuid.New()),
+		UserID:        userID,
+		Payment:       transaction.NewPaymentFromSchema("", status),
+		Total:         price(total),
+		Refunded:      decimal.NewFromInt(refunded),
+	}
+}
+
+func TestLoyaltyPolicy(t *testing.T) {
+	_, err := loyalty.NewPolicy(decimal.Zero, decimal.NewFromInt(100), 12)
+	assert.ErrorIs(t, err, loyalty.ErrorInvalidPolicy)
+	_, err = loyalty.NewPolicy(decimal.NewFromInt(10000), decimal.NewFromInt(100), 0)
+	assert.ErrorIs(t, err, loyalty.ErrorInvalidPolicy)
+
+	policy := loyaltyPolicy(t)
+	assert.Equal(t, int64(4), policy.PointsEarned(price(49999)))
+	assert.Equal(t, int64(250), policy.PointsWithin(decimal.NewFromInt(25050)))
+	assert.True(t, policy.Value(250).Equal(decimal.NewFromInt(25000)))
+	assert.Equal(t, lunchTime.AddDate(1, 0, 0), policy.ExpiresAt(lunchTime))
+}
+
+func TestLoyaltyAccount_Settle(t *testing.T) {
+	policy := loyaltyPolicy(t)
+	userID := identity.NewID(uuid.New())
+
+	t.Run("earns once on a paid transaction", func(t *testing.T) {
+		account := loyalty.Account{UserID: userID}
+		paid := settlement(userID, transaction.PaymentStatusSettlement, 125000, 0)
+
+		entries := account.Settle(paid, policy, lunchTime)
+
+		assert.Len(t, entries, 1)
+		assert.Equal(t, loyalty.EntryTypeEarn, entries[0].Type)
+		assert.Equal(t, int64(12), entries[0].Points)
+		assert.Equal(t, userID, entries[0].UserID)
+		assert.Equal(t, int64(12), account.Balance(lunchTime))
+		assert.Empty(t, account.Settle(paid, policy, lunchTime.Add(time.Hour)))
+	})
+
+	t.Run("pending transactions earn nothing", func(t *testing.T) {
+		account := loyalty.Account{UserID: userID}
+		assert.Empty(t, account.Settle(settlement(userID, transaction.PaymentStatusPending, 125000, 0), policy, lunchTime))
+	})
+
+	t.Run("a partial refund takes back its share of the earned points", func(t *testing.T) {
+		account := loyalty.Account{UserID: userID}
+		paid := settlement(userID, transaction.PaymentStatusSettlement, 100000, 0)
+		account.Settle(paid, policy, lunchTime)
+
+		paid.Refunded = decimal.NewFromInt(25000)
+		entries := account.Settle(paid, policy, lunchTime.Add(time.Hour))
+		assert.Len(t, entries, 1)
+		assert.Equal(t, loyalty.EntryTypeReversal, entries[0].Type)
+		assert.Equal(t, int64(-2), entries[0].Points)
+
+		paid.Refunded = decimal.NewFromInt(100000)
+		entries = account.Settle(paid, policy, lunchTime.Add(2*time.Hour))
+		assert.Len(t, entries, 1)
+		assert.Equal(t, int64(-8), entries[0].Points)
+		assert.Equal(t, int64(0), account.Balance(lunchTime.Add(2*time.Hour)))
+	})
+
+	t.Run("a cancelled transaction gives back the redeemed points", func(t *testing.T) {
+		account := loyalty.Account{UserID: userID}
+		account.Settle(settlement(userID, transaction.PaymentStatusSettlement, 500000, 0), policy, lunchTime)
+
+		cancelled := settlement(userID, transaction.PaymentStatusPending, 40000, 0)
+		_, err := account.Redeem(cancelled.TransactionID, 30, lunchTime.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), account.Balance(lunchTime.Add(time.Hour)))
+		assert.Empty(t, account.Settle(cancelled, policy, lunchTime.Add(time.Hour)))
+
+		cancelled.Payment = transaction.NewPaymentFromSchema("", transaction.PaymentStatusExpire)
+		entries := account.Settle(cancelled, policy, lunchTime.Add(2*time.Hour))
+		assert.Len(t, entries, 1)
+		assert.Equal(t, int64(30), entries[0].Points)
+		assert.Equal(t, int64(50), account.Balance(lunchTime.Add(2*time.Hour)))
+		assert.Empty(t, account.Settle(cancelled, policy, lunchTime.Add(3*time.Hour)))
+	})
+
+	t.Run("taking back spent points leaves a deficit later points pay off", func(t *testing.T) {
+		account := loyalty.Account{UserID: userID}
+		paid := settlement(userID, transaction.PaymentStatusSettlement, 100000, 0)
+		account.Settle(paid, policy, lunchTime)
+		_, err := account.Redeem(identity.NewID(uuid.New()), 10, lunchTime.Add(time.Hour))
+		assert.NoError(t, err)
+
+		paid.Payment = transaction.NewPaymentFromSchema("", transaction.PaymentStatusCancel)
+		account.Settle(paid, policy, lunchTime.Add(2*time.Hour))
+		assert.Equal(t, int64(-10), account.Balance(lunchTime.Add(2*time.Hour)))
+
+		account.Settle(settlement(userID, transaction.PaymentStatusSettlement, 150000, 0), policy, lunchTime.Add(3*time.Hour))
+		assert.Equal(t, int64(5), account.Balance(lunchTime.Add(3*time.Hour)))
+	})
+}
+
+func TestLoyaltyAccount_RedeemAndExpire(t *testing.T) {
+	policy := loyaltyPolicy(t)
+	userID := identity.NewID(uuid.New())
+	account := loyalty.Account{UserID: userID}
+
+	account.Settle(settlement(userID, transaction.PaymentStatusSettlement, 100000, 0), policy, lunchTime)
+	account.Settle(settlement(userID, transaction.PaymentStatusSettlement, 50000, 0), policy, lunchTime.AddDate(0, 6, 0))
+
+	_, err := account.Redeem(identity.NewID(uuid.New()), 16, lunchTime.AddDate(0, 7, 0))
+	assert.ErrorIs(t, err, loyalty.ErrorInsufficientPoints)
+	_, err = account.Redeem(identity.NewID(uuid.New()), 0, lunchTime.AddDate(0, 7, 0))
+	assert.ErrorIs(t, err, loyalty.ErrorInvalidPoints)
+
+	// Spending takes from the points that lapse first.
+	_, err = account.Redeem(identity.NewID(uuid.New()), 8, lunchTime.AddDate(0, 7, 0))
+	assert.NoError(t, err)
+
+	points, expiringAt := account.NextExpiry(lunchTime.AddDate(0, 7, 0))
+	assert.Equal(t, int64(2), points)
+	assert.Equal(t, lunchTime.AddDate(1, 0, 0), *expiringAt)
+
+	lapsed := lunchTime.AddDate(1, 0, 0)
+	assert.Equal(t, int64(5), account.Balance(lapsed))
+
+	entries := account.Expire(lapsed)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, loyalty.EntryTypeExpire, entries[0].Type)
+	assert.Equal(t, int64(-2), entries[0].Points)
+	assert.Equal(t, int64(5), account.Balance(lapsed))
+	assert.Empty(t, account.Expire(lapsed))
+}
+
+func TestPricingPolicy_ApplyLoyalty(t *testing.T) {
+	policy := pricingPolicy(t, 5, 10, order.TaxAfterServiceCharge, 0)
+	breakdown := policy.ApplyDiscounts(price(100000), []order.Discount{{Name: "Promo", Amount: price(20000)}})
+
+	repriced := policy.ApplyLoyalty(breakdown, 100, price(10000))
+
+	assert.Equal(t, int64(100), repriced.LoyaltyPoints)
+	assert.True(t, repriced.LoyaltyDiscount.Price.Equal(decimal.NewFromInt(10000)))
+	assert.True(t, repriced.ServiceCharge.Price.Equal(decimal.NewFromInt(3500)))
+	assert.True(t, repriced.Tax.Price.Equal(decimal.NewFromInt(7350)))
+	assert.True(t, repriced.Total.Price.Equal(decimal.NewFromInt(80850)))
+}
+
+func TestLoyaltyService_RedeemPoints(t *testing.T) {
+	ctx := context.Background()
+	policy := loyaltyPolicy(t)
+	userID := identity.NewID(uuid.New())
+
+	earned := loyalty.Account{UserID: userID}
+	earned.Settle(settlement(userID, transaction.PaymentStatusSettlement, 5000000, 0), policy, lunchTime.Add(-time.Hour))
+
+	newService := func(mockLoyaltyRepo *MockLoyaltyRepository) service.LoyaltyService {
+		return service.NewLoyaltyService(mockLoyaltyRepo, order.NewService(order.PricingPolicy{}), policy, fixedClock{now: lunchTime}, nil, 0)
+	}
+
+	t.Run("uses only the points the order can absorb", func(t *testing.T) {
+		mockLoyaltyRepo := new(MockLoyaltyRepository)
+		mockLoyaltyRepo.On("LockAccount", ctx, nil, userID.String()).Return(nil)
+		mockLoyaltyRepo.On("GetAccount", ctx, nil, userID.String()).Return(earned, nil)
+
+		breakdown := order.PricingPolicy{}.Apply(price(35050))
+		result, err := newService(mockLoyaltyRepo).RedeemPoints(ctx, nil, userID.String(), 500, breakdown)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), result.LoyaltyPoints)
+		assert.True(t, result.Total.Price.Equal(decimal.NewFromInt(50)))
+		mockLoyaltyRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects more points than the balance", func(t *testing.T) {
+		mockLoyaltyRepo := new(MockLoyaltyRepository)
+		mockLoyaltyRepo.On("LockAccount", ctx, nil, userID.String()).Return(nil)
+		mockLoyaltyRepo.On("GetAccount", ctx, nil, userID.String()).Return(earned, nil)
+
+		_, err := newService(mockLoyaltyRepo).RedeemPoints(ctx, nil, userID.String(), 501, order.PricingPolicy{}.Apply(price(100000)))
+
+		assert.ErrorIs(t, err, loyalty.ErrorInsufficientPoints)
+	})
+
+	t.Run("guests have no points", func(t *testing.T) {
+		_, err := newService(new(MockLoyaltyRepository)).RedeemPoints(ctx, nil, "", 10, order.PricingPolicy{}.Apply(price(100000)))
+
+		assert.ErrorIs(t, err, loyalty.ErrorAccountRequired)
+	})
+
+	t.Run("records the redemption on the transaction", func(t *testing.T) {
+		transactionID := identity.NewID(uuid.New())
+		mockLoyaltyRepo := new(MockLoyaltyRepository)
+		mockLoyaltyRepo.On("GetAccount", ctx, nil, userID.String()).Return(earned, nil)
+		mockLoyaltyRepo.On("CreateEntries", ctx, nil, mock.MatchedBy(func(entries []loyalty.Entry) bool {
+			return len(entries) == 1 &&
+				entries[0].Type == loyalty.EntryTypeRedeem &&
+				entries[0].Points == -350 &&
+				entries[0].TransactionID == transactionID
+		})).Return(nil)
+
+		err := newService(mockLoyaltyRepo).RecordRedemption(ctx, nil, userID.String(), transactionID, 350)
+
+		assert.NoError(t, err)
+		mockLoyaltyRepo.AssertExpectations(t)
+	})
+}
+
+func TestLoyaltyService_GetAccount(t *testing.T) {
+	ctx := context.Background()
+	policy := loyaltyPolicy(t)
+	userID := identity.NewID(uuid.New())
+
+	account := loyalty.Account{UserID: userID}
+	account.Settle(settlement(userID, transaction.PaymentStatusSettlement, 200000, 0), policy, lunchTime.Add(-2*time.Hour))
+	redeemed, err := account.Redeem(identity.NewID(uuid.New()), 5, lunchTime.Add(-time.Hour))
+	assert.NoError(t, err)
+
+	mockLoyaltyRepo := new(MockLoyaltyRepository)
+	mockLoyaltyRepo.On("GetAccount", ctx, nil, userID.String()).Return(account, nil)
+	loyaltyService := service.NewLoyaltyService(mockLoyaltyRepo, order.NewService(order.PricingPolicy{}), policy, fixedClock{now: lunchTime}, nil, 0)
+
+	result, err := loyaltyService.GetAccount(ctx, userID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), result.Balance)
+	assert.Equal(t, "100", result.PointValue)
+	assert.Equal(t, int64(15), result.ExpiringPoints)
+	assert.Len(t, result.History, 2)
+	assert.Equal(t, redeemed.ID.String(), result.History[0].ID)
+	assert.Equal(t, int64(-5), result.History[0].Points)
+}
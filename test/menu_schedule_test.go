@@ -0,0 +1,224 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/menu/category"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fixedClock is a clock stopped at one moment.
+type fixedClock struct {
+	now    time.Time
+	cutoff time.Duration
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (c fixedClock) Today() shared.BusinessDay {
+	return shared.NewBusinessDay(c.now, c.cutoff)
+}
+
+func timeWindow(t *testing.T, days []time.Weekday, startTime string, endTime string) shared.TimeWindow {
+	window, err := shared.NewTimeWindow(days, startTime, endTime)
+	assert.NoError(t, err)
+	return window
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	lunch := timeWindow(t, []time.Weekday{time.Wednesday}, "11:00", "14:00")
+	assert.True(t, lunch.Contains(lunchTime))
+	assert.False(t, lunch.Contains(lunchTime.Add(2*time.Hour)))
+	assert.False(t, lunch.Contains(lunchTime.AddDate(0, 0, 1)))
+
+	lateNight := timeWindow(t, nil, "22:00", "02:00")
+	assert.True(t, lateNight.Contains(time.Date(2024, 5, 15, 23, 30, 0, 0, time.UTC)))
+	assert.True(t, lateNight.Contains(time.Date(2024, 5, 16, 1, 30, 0, 0, time.UTC)))
+	assert.False(t, lateNight.Contains(lunchTime))
+
+	allDay := timeWindow(t, []time.Weekday{time.Wednesday}, "", "")
+	assert.True(t, allDay.Contains(lunchTime.Add(11*time.Hour)))
+	assert.Equal(t, "00:00", allDay.StartTime())
+
+	_, err := shared.NewTimeWindow(nil, "25:00", "")
+	assert.ErrorIs(t, err, shared.ErrorInvalidTimeWindow)
+}
+
+func TestTimeWindow_ContainsOvernight(t *testing.T) {
+	fridayNight := timeWindow(t, []time.Weekday{time.Friday}, "22:00", "02:00")
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"friday before the window", time.Date(2024, 5, 17, 21, 59, 0, 0, time.UTC), false},
+		{"friday late evening", time.Date(2024, 5, 17, 22, 0, 0, 0, time.UTC), true},
+		{"saturday after midnight", time.Date(2024, 5, 18, 1, 0, 0, 0, time.UTC), true},
+		{"saturday after the window", time.Date(2024, 5, 18, 2, 0, 0, 0, time.UTC), false},
+		{"friday after midnight belongs to thursday", time.Date(2024, 5, 17, 1, 0, 0, 0, time.UTC), false},
+		{"saturday late evening", time.Date(2024, 5, 18, 23, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fridayNight.Contains(tt.at))
+		})
+	}
+
+	saturdayNight := timeWindow(t, []time.Weekday{time.Saturday}, "22:00", "02:00")
+	assert.True(t, saturdayNight.Contains(time.Date(2024, 5, 19, 1, 0, 0, 0, time.UTC)))
+}
+
+func TestMenu_ScheduleOverridesCategory(t *testing.T) {
+	breakfast := menu.Menu{
+		IsAvailable:      true,
+		CategorySchedule: []shared.TimeWindow{timeWindow(t, nil, "06:00", "10:00")},
+	}
+	assert.False(t, breakfast.IsAvailableAt(lunchTime))
+
+	breakfast.Schedule = []shared.TimeWindow{timeWindow(t, nil, "06:00", "14:00")}
+	assert.True(t, breakfast.IsAvailableAt(lunchTime))
+
+	breakfast.IsAvailable = false
+	assert.False(t, breakfast.IsAvailableAt(lunchTime))
+
+	assert.True(t, menu.Menu{IsAvailable: true}.IsAvailableAt(lunchTime))
+}
+
+func TestMenu_PriceAtHappyHour(t *testing.T) {
+	happyHour, err := menu.NewPriceOverride("Happy hour", price(18000), timeWindow(t, nil, "12:00", "13:00"))
+	assert.NoError(t, err)
+	lunchDeal, err := menu.NewPriceOverride("Lunch deal", price(20000), timeWindow(t, nil, "11:00", "14:00"))
+	assert.NoError(t, err)
+
+	menuEntity := menu.Menu{Price: price(25000), PriceOverrides: []menu.PriceOverride{lunchDeal, happyHour}}
+
+	assertAmount(t, "18000", menuEntity.PriceAt(lunchTime))
+	assertAmount(t, "20000", menuEntity.PriceAt(lunchTime.Add(time.Hour)))
+	assertAmount(t, "25000", menuEntity.PriceAt(lunchTime.Add(3*time.Hour)))
+
+	_, err = menu.NewPriceOverride("Free", price(0), shared.TimeWindow{})
+	assert.ErrorIs(t, err, menu.ErrorInvalidPriceOverride)
+}
+
+func TestGetAllMenus_HonoursSchedule(t *testing.T) {
+	mockMenuRepo := new(MockMenuRepositoryForAvailability)
+	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
+
+	categoryID := identity.NewIDFromSchema(uuid.New())
+	happyHour, err := menu.NewPriceOverride("Happy hour", price(18000), timeWindow(t, nil, "12:00", "13:00"))
+	assert.NoError(t, err)
+
+	coffee := menu.Menu{
+		ID:             identity.NewIDFromSchema(uuid.New()),
+		CategoryID:     categoryID,
+		Name:           "Kopi Susu",
+		IsAvailable:    true,
+		Price:          price(25000),
+		PriceOverrides: []menu.PriceOverride{happyHour},
+	}
+	pancake := menu.Menu{
+		ID:               identity.NewIDFromSchema(uuid.New()),
+		CategoryID:       categoryID,
+		Name:             "Pancake",
+		IsAvailable:      true,
+		Price:            price(30000),
+		CategorySchedule: []shared.TimeWindow{timeWindow(t, nil, "06:00", "10:00")},
+	}
+
+	mockMenuRepo.On("GetAllMenus", mock.Anything, nil).Return([]menu.Menu{coffee, pancake}, nil)
+	mockCategoryRepo.On("GetCategoryByID", mock.Anything, nil, categoryID.String()).Return(category.Category{ID: categoryID, Name: "Breakfast"}, nil)
+
+	menus, err := menuService.GetAllMenus(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, menus, 2)
+	assert.True(t, menus[0].IsAvailable)
+	assert.Equal(t, "18000", menus[0].Price.String())
+	assert.Equal(t, "25000", menus[0].RegularPrice.String())
+	assert.Len(t, menus[0].PriceOverrides, 1)
+	assert.False(t, menus[1].IsAvailable)
+}
+
+func TestUpdateMenuSchedule_InvalidWindow(t *testing.T) {
+	menuService := service.NewMenuService(new(MockMenuRepositoryForAvailability), new(MockCategoryRepositoryForStateMenu), nil, noRatings(), fixedClock{now: lunchTime}, nil)
+
+	_, err := menuService.UpdateMenuSchedule(context.Background(), uuid.New().String(), request.UpdateSchedule{
+		Windows: []request.TimeWindow{{StartTime: "9am", EndTime: "11:00"}},
+	})
+
+	assert.ErrorIs(t, err, shared.ErrorInvalidTimeWindow)
+}
+
+func scheduledOrder(t *testing.T, menuEntity menu.Menu) (order.PriceBreakdown, error) {
+	mockMenuRepo := new(MockMenuRepositoryForCalculatePrice)
+	orderService := service.NewOrderService(new(MockOrderRepositoryForCalculatePrice), mockMenuRepo, order.NewService(order.PricingPolicy{}), noPromotions(), fixedClock{now: lunchTime})
+
+	mockMenuRepo.On("GetMenuByID", mock.Anything, nil, menuEntity.ID.String()).Return(menuEntity, nil)
+
+	return orderService.CalculatePriceBreakdown(context.Background(), "user-1", []request.Order{{MenuID: menuEntity.ID.String(), Quantity: 2}}, "", "", nil)
+}
+
+func TestCalculatePriceBreakdown_RejectsOutOfSchedule(t *testing.T) {
+	_, err := scheduledOrder(t, menu.Menu{
+		ID:       identity.NewIDFromSchema(uuid.New()),
+		Name:     "Pancake",
+		Price:    price(30000),
+		Schedule: []shared.TimeWindow{timeWindow(t, nil, "06:00", "10:00")},
+	})
+
+	assert.ErrorIs(t, err, menu.ErrorMenuOutOfSchedule)
+}
+
+func TestCalculatePriceBreakdown_ChecksScheduleAtPickup(t *testing.T) {
+	menuEntity := menu.Menu{
+		ID:       identity.NewIDFromSchema(uuid.New()),
+		Name:     "Pancake",
+		Price:    price(30000),
+		Schedule: []shared.TimeWindow{timeWindow(t, nil, "06:00", "10:00")},
+	}
+	mockMenuRepo := new(MockMenuRepositoryForCalculatePrice)
+	orderService := service.NewOrderService(new(MockOrderRepositoryForCalculatePrice), mockMenuRepo, order.NewService(order.PricingPolicy{}), noPromotions(), fixedClock{now: lunchTime})
+	mockMenuRepo.On("GetMenuByID", mock.Anything, nil, menuEntity.ID.String()).Return(menuEntity, nil)
+	orders := []request.Order{{MenuID: menuEntity.ID.String(), Quantity: 1}}
+
+	breakfast := time.Date(2024, 5, 16, 8, 0, 0, 0, time.UTC)
+	_, err := orderService.CalculatePriceBreakdown(context.Background(), "user-1", orders, "", transaction.OrderTypePickup, &breakfast)
+	assert.NoError(t, err)
+
+	dinner := time.Date(2024, 5, 16, 19, 0, 0, 0, time.UTC)
+	_, err = orderService.CalculatePriceBreakdown(context.Background(), "user-1", orders, "", transaction.OrderTypePickup, &dinner)
+	assert.ErrorIs(t, err, menu.ErrorMenuOutOfSchedule)
+}
+
+func TestCalculatePriceBreakdown_UsesHappyHourPrice(t *testing.T) {
+	happyHour, err := menu.NewPriceOverride("Happy hour", price(18000), timeWindow(t, nil, "12:00", "13:00"))
+	assert.NoError(t, err)
+
+	breakdown, err := scheduledOrder(t, menu.Menu{
+		ID:             identity.NewIDFromSchema(uuid.New()),
+		Name:           "Kopi Susu",
+		Price:          price(25000),
+		PriceOverrides: []menu.PriceOverride{happyHour},
+	})
+
+	assert.NoError(t, err)
+	assertAmount(t, "36000", breakdown.Subtotal)
+	assert.Len(t, breakdown.Lines, 1)
+	assertAmount(t, "18000", breakdown.Lines[0].UnitPrice)
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/domain/identity"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrderType(t *testing.T) {
+	orderType, err := transaction.NewOrderType("")
+	assert.NoError(t, err)
+	assert.True(t, orderType.IsDineIn())
+	assert.False(t, orderType.IsPackaged())
+
+	orderType, err = transaction.NewOrderType(transaction.OrderTypeTakeaway)
+	assert.NoError(t, err)
+	assert.True(t, orderType.IsPackaged())
+
+	_, err = transaction.NewOrderType("delivery")
+	assert.ErrorIs(t, err, transaction.ErrorInvalidOrderType)
+}
+
+func TestOrderType_ValidatePickupAt(t *testing.T) {
+	today := shared.NewBusinessDay(lunchTime, shared.DefaultBusinessDayCutoff)
+	later := lunchTime.Add(time.Hour)
+	earlier := lunchTime.Add(-time.Minute)
+	lastDay := lunchTime.AddDate(0, 0, transaction.MaxPickupDaysAhead)
+	tooFar := lunchTime.AddDate(0, 0, transaction.MaxPickupDaysAhead+1)
+
+	pickup := transaction.OrderType{Type: transaction.OrderTypePickup}
+	assert.NoError(t, pickup.ValidatePickupAt(&later, lunchTime, today))
+	assert.NoError(t, pickup.ValidatePickupAt(&lastDay, lunchTime, today))
+	assert.ErrorIs(t, pickup.ValidatePickupAt(nil, lunchTime, today), transaction.ErrorPickupTimeRequired)
+	assert.ErrorIs(t, pickup.ValidatePickupAt(&earlier, lunchTime, today), transaction.ErrorInvalidPickupTime)
+	assert.ErrorIs(t, pickup.ValidatePickupAt(&tooFar, lunchTime, today), transaction.ErrorPickupTooFarAhead)
+
+	takeaway := transaction.OrderType{Type: transaction.OrderTypeTakeaway}
+	assert.NoError(t, takeaway.ValidatePickupAt(nil, lunchTime, today))
+	assert.ErrorIs(t, takeaway.ValidatePickupAt(&later, lunchTime, today), transaction.ErrorInvalidPickupTime)
+}
+
+func TestPricingPolicy_PackagingFeeIsTaxedButNotDiscounted(t *testing.T) {
+	policy, err := order.NewPricingPolicy(decimal.Zero, decimal.NewFromInt(10), "", 0, decimal.NewFromInt(2000))
+	assert.NoError(t, err)
+
+	discounts := []order.Discount{{Name: "Promo", Amount: price(50000)}}
+	breakdown := policy.ApplyPackaged(price(40000), discounts)
+
+	assertAmount(t, "40000", breakdown.Discount)
+	assertAmount(t, "2000", breakdown.PackagingFee)
+	assertAmount(t, "200", breakdown.Tax)
+	assertAmount(t, "2200", breakdown.Total)
+
+	dineIn := policy.ApplyDiscounts(price(40000), nil)
+	assertAmount(t, "0", dineIn.PackagingFee)
+	assertAmount(t, "44000", dineIn.Total)
+
+	_, err = order.NewPricingPolicy(decimal.Zero, decimal.Zero, "", 0, decimal.NewFromInt(-1))
+	assert.ErrorIs(t, err, order.ErrorInvalidPricingPolicy)
+}
+
+func TestScheduledPickup_IsHeldUntilDue(t *testing.T) {
+	pickupAt := lunchTime.Add(time.Hour)
+	cookAfter := transaction.CookAfter(pickupAt, 15*time.Minute)
+	assert.Equal(t, pickupAt.Add(-15*time.Minute-transaction.PickupLeadTime), cookAfter)
+
+	held := transaction.Transaction{
+		ID:          identity.NewID(uuid.New()),
+		OrderType:   transaction.OrderType{Type: transaction.OrderTypePickup},
+		PickupAt:    &pickupAt,
+		CookAfter:   &cookAfter,
+		QueueCode:   transaction.QueueCode{Code: "Q0009"},
+		OrderStatus: transaction.OrderStatus{Status: transaction.OrderStatusPending},
+	}
+	assert.True(t, held.IsHeld(lunchTime))
+	assert.False(t, held.IsHeld(cookAfter))
+
+	estimate := transaction.KitchenSnapshot{Now: lunchTime}.Estimate(transaction.Query{
+		Transaction: held,
+		Orders:      []transaction.OrderQuery{{Menu: menu.Menu{CookingTime: 15 * time.Minute}}},
+	})
+	assert.Equal(t, pickupAt, estimate.ETA)
+	assert.Equal(t, 0, estimate.QueuePosition)
+
+	board := transaction.NewDisplayBoard([]transaction.Transaction{held}, lunchTime, time.Minute)
+	assert.Empty(t, board.Preparing)
+
+	board = transaction.NewDisplayBoard([]transaction.Transaction{held}, cookAfter, time.Minute)
+	assert.Len(t, board.Preparing, 1)
+}
+
+func TestStartDelivering_RejectsTakeaway(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	transactionService := lookupService(mockTransactionRepo)
+
+	ctx := context.Background()
+	transactionID := uuid.New()
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID.String()).Return(transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(transactionID),
+			OrderType:   transaction.OrderType{Type: transaction.OrderTypeTakeaway},
+			OrderStatus: transaction.OrderStatus{Status: transaction.OrderStatusReadyToServe},
+		},
+	}, nil)
+
+	_, err := transactionService.StartDelivering(ctx, request.StartDelivering{TransactionID: transactionID.String()})
+
+	assert.ErrorIs(t, err, transaction.ErrorNotDineIn)
+}
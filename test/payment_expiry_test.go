@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/adapter/event"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionRepositoryForPaymentExpiry struct {
+	MockTransactionRepositoryForNextOrder
+}
+
+func (m *MockTransactionRepositoryForPaymentExpiry) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, tx, createdBefore)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
+func TestPayment_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{transaction.PaymentStatusPending, transaction.PaymentStatusSettlement, true},
+		{transaction.PaymentStatusPending, transaction.PaymentStatusExpire, true},
+		{transaction.PaymentStatusCapture, transaction.PaymentStatusSettlement, true},
+		{transaction.PaymentStatusCapture, transaction.PaymentStatusDeny, true},
+		{transaction.PaymentStatusCapture, transaction.PaymentStatusPending, false},
+		{transaction.PaymentStatusExpire, transaction.PaymentStatusSettlement, false},
+		{transaction.PaymentStatusSettlement, transaction.PaymentStatusExpire, false},
+		{transaction.PaymentStatusCancel, transaction.PaymentStatusCapture, false},
+		{transaction.PaymentStatusSettlement, transaction.PaymentStatusSettlement, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			payment := transaction.NewPaymentFromSchema("", tt.from)
+			assert.Equal(t, tt.expected, payment.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestExpireStaleTransactions_Success(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForPaymentExpiry)
+	eventPublisher := event.NewLogPublisher(log.New(io.Discard, "", 0), event.DefaultEventHistorySize)
+	paymentExpiryService := service.NewPaymentExpiryService(mockTransactionRepo, eventPublisher, fixedClock{now: lunchTime}, 15*time.Minute)
+
+	ctx := context.Background()
+	expiredID := identity.NewID(uuid.New())
+	createdAt := time.Now().Add(-time.Hour)
+
+	mockTransactionRepo.On("ExpireUnpaidTransactions", ctx, nil, lunchTime.Add(-15*time.Minute)).Return([]transaction.Transaction{{
+		ID:         expiredID,
+		Payment:    transaction.Payment{Status: transaction.PaymentStatusExpire},
+		TotalPrice: shared.Price{Price: decimal.NewFromInt(45000)},
+		Timestamp:  shared.Timestamp{CreatedAt: createdAt},
+	}}, nil)
+
+	expired, err := paymentExpiryService.ExpireStaleTransactions(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, expiredID.String(), expired[0].TransactionID)
+	assert.Equal(t, "45000", expired[0].TotalPrice)
+	assert.Equal(t, createdAt, expired[0].CreatedAt)
+
+	events := eventPublisher.Events()
+	assert.Len(t, events, 1)
+	assert.Equal(t, transaction.EventTransactionExpired, events[0].Type)
+	assert.Equal(t, expiredID, events[0].TransactionID)
+	assert.Equal(t, lunchTime, events[0].OccurredAt)
+	mockTransactionRepo.AssertExpectations(t)
+}
+
+func TestExpireStaleTransactions_NothingToExpire(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForPaymentExpiry)
+	eventPublisher := event.NewLogPublisher(log.New(io.Discard, "", 0), 0)
+	paymentExpiryService := service.NewPaymentExpiryService(mockTransactionRepo, eventPublisher, fixedClock{now: lunchTime}, 0)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("ExpireUnpaidTransactions", ctx, nil, mock.AnythingOfType("time.Time")).Return([]transaction.Transaction(nil), nil)
+
+	expired, err := paymentExpiryService.ExpireStaleTransactions(ctx)
+
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+	assert.Empty(t, eventPublisher.Events())
+}
+
+func TestExpireStaleTransactions_RepoError(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForPaymentExpiry)
+	eventPublisher := event.NewLogPublisher(log.New(io.Discard, "", 0), 0)
+	paymentExpiryService := service.NewPaymentExpiryService(mockTransactionRepo, eventPublisher, fixedClock{now: lunchTime}, time.Minute)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("ExpireUnpaidTransactions", ctx, nil, mock.AnythingOfType("time.Time")).Return([]transaction.Transaction(nil), assert.AnError)
+
+	expired, err := paymentExpiryService.ExpireStaleTransactions(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, expired)
+	assert.Empty(t, eventPublisher.Events())
+}
+
+func TestTransaction_IsPaymentOverdue(t *testing.T) {
+	timeout := 15 * time.Minute
+	createdBefore := lunchTime.Add(-timeout)
+	unpaid := func(provider string, age time.Duration) transaction.Transaction {
+		return transaction.Transaction{
+			Payment:         transaction.Payment{Status: transaction.PaymentStatusPending},
+			PaymentProvider: provider,
+			Timestamp:       shared.Timestamp{CreatedAt: lunchTime.Add(-age)},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		transaction transaction.Transaction
+		expected    bool
+	}{
+		{"gateway order older than the timeout", unpaid(transaction.PaymentProviderMidtrans, time.Hour), true},
+		{"gateway order within the timeout", unpaid(transaction.PaymentProviderMidtrans, 5*time.Minute), false},
+		{"counter order older than the timeout waits for the cashier", unpaid(transaction.PaymentProviderCounter, time.Hour), false},
+		{"paid order", transaction.Transaction{
+			Payment:         transaction.Payment{Status: transaction.PaymentStatusSettlement},
+			PaymentProvider: transaction.PaymentProviderXendit,
+			Timestamp:       shared.Timestamp{CreatedAt: lunchTime.Add(-time.Hour)},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.transaction.IsPaymentOverdue(createdBefore))
+		})
+	}
+
+	assert.Equal(t, []string{transaction.PaymentProviderCounter}, transaction.UntimedPaymentProviders())
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/adapter/payment_gateway"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeXendit serves the two invoice endpoints the adapter uses and keeps
+// the invoices it created.
+func newFakeXendit(t *testing.T, secretKey string) (*httptest.Server, map[string]map[string]interface{}) {
+	invoices := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, ok := r.BasicAuth()
+		if !ok || username != secretKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			var payload map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			externalID := payload["external_id"].(string)
+			invoices[externalID] = map[string]interface{}{
+				"id":               "inv-" + externalID,
+				"external_id":      externalID,
+				"status":           "PENDING",
+				"amount":           payload["amount"],
+				"invoice_duration": payload["invoice_duration"],
+				"invoice_url":      "https://checkout.xendit.test/" + externalID,
+			}
+			_ = json.NewEncoder(w).Encode(invoices[externalID])
+		case http.MethodGet:
+			result := []map[string]interface{}{}
+			if invoice, ok := invoices[r.URL.Query().Get("external_id")]; ok {
+				result = append(result, invoice)
+			}
+			_ = json.NewEncoder(w).Encode(result)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, invoices
+}
+
+func TestXenditAdapter_ProcessPaymentAndCheckStatus(t *testing.T) {
+	server, invoices := newFakeXendit(t, "xnd_secret")
+	xenditAdapter := payment_gateway.NewXenditAdapter(nil, nil, fixedClock{now: lunchTime}, 15*time.Minute, payment_gateway.XenditConfig{
+		SecretKey: "xnd_secret",
+		BaseURL:   server.URL,
+	})
+	transactionEntity := fakeGatewayTransaction(45000)
+
+	payment, err := xenditAdapter.ProcessPayment(context.Background(), nil, transactionEntity)
+	assert.NoError(t, err)
+	assert.Equal(t, "inv-"+transactionEntity.ID.String(), payment.Token)
+	assert.Equal(t, "https://checkout.xendit.test/"+transactionEntity.ID.String(), payment.PaymentLink)
+	assert.EqualValues(t, 900, invoices[transactionEntity.ID.String()]["invoice_duration"])
+
+	status, err := xenditAdapter.CheckPaymentStatus(context.Background(), transactionEntity.ID.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.PaymentStatusPending, status.Status)
+	assert.True(t, decimal.NewFromInt(45000).Equal(status.GrossAmount))
+
+	invoices[transactionEntity.ID.String()]["status"] = "PAID"
+	status, err = xenditAdapter.CheckPaymentStatus(context.Background(), transactionEntity.ID.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.PaymentStatusSettlement, status.Status)
+
+	_, err = xenditAdapter.CheckPaymentStatus(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, port.ErrorPaymentNotFound)
+
+	wrongKey := payment_gateway.NewXenditAdapter(nil, nil, fixedClock{now: lunchTime}, 0, payment_gateway.XenditConfig{SecretKey: "other", BaseURL: server.URL})
+	_, err = wrongKey.ProcessPayment(context.Background(), nil, transactionEntity)
+	assert.Error(t, err)
+}
+
+func TestXenditAdapter_ParseNotification(t *testing.T) {
+	xenditAdapter := payment_gateway.NewXenditAdapter(nil, nil, fixedClock{now: lunchTime}, 0, payment_gateway.XenditConfig{CallbackToken: "callback-token"})
+	orderID := uuid.NewString()
+	callback := map[string]interface{}{
+		"id":          "inv-123",
+		"external_id": orderID,
+		"status":      "PAID",
+		"amount":      45000,
+		"paid_amount": 45000,
+	}
+
+	datas, err := xenditAdapter.ParseNotification(map[string]string{payment_gateway.XenditCallbackTokenHeader: "callback-token"}, callback)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"order_id":           orderID,
+		"transaction_id":     "inv-123",
+		"transaction_status": transaction.PaymentStatusSettlement,
+		"gross_amount":       "45000.00",
+	}, datas)
+
+	_, err = xenditAdapter.ParseNotification(map[string]string{payment_gateway.XenditCallbackTokenHeader: "forged"}, callback)
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+
+	_, err = xenditAdapter.ParseNotification(nil, callback)
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+
+	callback["status"] = "REFUNDED"
+	_, err = xenditAdapter.ParseNotification(map[string]string{payment_gateway.XenditCallbackTokenHeader: "callback-token"}, callback)
+	assert.Error(t, err)
+}
+
+func TestMidtransAdapter_ParseNotificationUsesProviderKey(t *testing.T) {
+	midtransAdapter := payment_gateway.NewMidtransAdapter(nil, nil, fixedClock{now: lunchTime}, 0, payment_gateway.MidtransConfig{ServerKey: "midtrans-key"})
+	_, err := midtransAdapter.ParseNotification(nil, map[string]interface{}{
+		"order_id":      uuid.NewString(),
+		"status_code":   "200",
+		"gross_amount":  "45000.00",
+		"signature_key": "not-a-signature",
+	})
+	assert.ErrorIs(t, err, port.ErrorInvalidSignature)
+	assert.Equal(t, transaction.PaymentProviderMidtrans, midtransAdapter.Provider())
+}
+
+func TestPaymentGatewayRegistry(t *testing.T) {
+	midtransAdapter := payment_gateway.NewMidtransAdapter(nil, nil, fixedClock{now: lunchTime}, 0, payment_gateway.MidtransConfig{})
+	xenditAdapter := payment_gateway.NewXenditAdapter(nil, nil, fixedClock{now: lunchTime}, 0, payment_gateway.XenditConfig{})
+
+	registry, err := payment_gateway.NewRegistry(transaction.PaymentProviderMidtrans, midtransAdapter, xenditAdapter)
+	assert.NoError(t, err)
+
+	gateway, err := registry.Gateway("")
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.PaymentProviderMidtrans, gateway.Provider())
+
+	gateway, err = registry.Gateway(transaction.PaymentProviderXendit)
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.PaymentProviderXendit, gateway.Provider())
+
+	_, err = registry.Gateway(transaction.PaymentProviderFake)
+	assert.ErrorIs(t, err, port.ErrorPaymentProviderNotFound)
+
+	_, err = payment_gateway.NewRegistry(transaction.PaymentProviderFake, midtransAdapter)
+	assert.ErrorIs(t, err, port.ErrorPaymentProviderNotFound)
+}
@@ -0,0 +1,217 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionRepositoryForReconciliation struct {
+	MockTransactionRepositoryForNextOrder
+}
+
+func (m *MockTransactionRepositoryForReconciliation) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, tx, createdAfter)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
+// fakeReconciliationGateway keeps the state a real gateway would report and
+// records every notification applied through HookPayment.
+type fakeReconciliationGateway struct {
+	statuses map[uuid.UUID]port.PaymentStatusResponse
+	failing  map[uuid.UUID]error
+	hooks    []map[string]interface{}
+}
+
+func newFakeReconciliationGateway() *fakeReconciliationGateway {
+	return &fakeReconciliationGateway{
+		statuses: make(map[uuid.UUID]port.PaymentStatusResponse),
+		failing:  make(map[uuid.UUID]error),
+	}
+}
+
+func (f *fakeReconciliationGateway) ProcessPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
+
+func (f *fakeReconciliationGateway) HookPayment(ctx context.Context, tx interface{}, transactionId uuid.UUID, datas map[string]interface{}) error {
+	f.hooks = append(f.hooks, datas)
+	return nil
+}
+
+func (f *fakeReconciliationGateway) Provider() string {
+	return transaction.PaymentProviderFake
+}
+
+func (f *fakeReconciliationGateway) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+// Gateway lets the fake act as a registry that only knows its own provider.
+func (f *fakeReconciliationGateway) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	if provider != "" && provider != f.Provider() {
+		return nil, port.ErrorPaymentProviderNotFound
+	}
+	return f, nil
+}
+
+func (f *fakeReconciliationGateway) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	if err, ok := f.failing[transactionId]; ok {
+		return port.PaymentStatusResponse{}, err
+	}
+	status, ok := f.statuses[transactionId]
+	if !ok {
+		return port.PaymentStatusResponse{}, port.ErrorPaymentNotFound
+	}
+	return status, nil
+}
+
+func (f *fakeReconciliationGateway) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+
+// gatewayHookHandler stands in for TransactionService.ApplyPaymentStatus, which
+// needs a database transaction, and forwards to the gateway like it does.
+type gatewayHookHandler struct {
+	paymentGatewayPort port.PaymentGatewayPort
+}
+
+func (h gatewayHookHandler) ApplyPaymentStatus(ctx context.Context, provider string, datas map[string]interface{}) error {
+	return h.paymentGatewayPort.HookPayment(ctx, nil, uuid.MustParse(datas["order_id"].(string)), datas)
+}
+
+type failingHookHandler struct{}
+
+func (failingHookHandler) ApplyPaymentStatus(ctx context.Context, provider string, datas map[string]interface{}) error {
+	return assert.AnError
+}
+
+func unsettledTransaction(status string, totalPrice int64) transaction.Transaction {
+	return transaction.Transaction{
+		ID:         identity.NewID(uuid.New()),
+		Payment:    transaction.Payment{Status: status},
+		TotalPrice: shared.Price{Price: decimal.NewFromInt(totalPrice)},
+		Timestamp:  shared.Timestamp{CreatedAt: time.Now().Add(-time.Hour)},
+	}
+}
+
+func (f *fakeReconciliationGateway) report(transactionEntity transaction.Transaction, status string, grossAmount int64) {
+	f.statuses[transactionEntity.ID.ID] = port.PaymentStatusResponse{
+		OrderID:       transactionEntity.ID.String(),
+		TransactionID: "gateway-" + transactionEntity.ID.String(),
+		Status:        status,
+		GrossAmount:   decimal.NewFromInt(grossAmount),
+	}
+}
+func (m *fakeReconciliationGateway) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
+
+func TestReconcile(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForReconciliation)
+	gateway := newFakeReconciliationGateway()
+	reconciliationService := service.NewReconciliationService(mockTransactionRepo, gateway, gatewayHookHandler{gateway}, fixedClock{now: lunchTime}, 24*time.Hour)
+
+	lostWebhook := unsettledTransaction(transaction.PaymentStatusPending, 30000)
+	gateway.report(lostWebhook, transaction.PaymentStatusSettlement, 30000)
+
+	expiredAtGateway := unsettledTransaction(transaction.PaymentStatusPending, 15000)
+	gateway.report(expiredAtGateway, transaction.PaymentStatusExpire, 15000)
+
+	neverOpened := unsettledTransaction(transaction.PaymentStatusPending, 20000)
+
+	inSync := unsettledTransaction(transaction.PaymentStatusCapture, 25000)
+	gateway.report(inSync, transaction.PaymentStatusCapture, 25000)
+
+	paidAfterExpiry := unsettledTransaction(transaction.PaymentStatusExpire, 40000)
+	gateway.report(paidAfterExpiry, transaction.PaymentStatusSettlement, 40000)
+
+	underpaid := unsettledTransaction(transaction.PaymentStatusPending, 50000)
+	gateway.report(underpaid, transaction.PaymentStatusSettlement, 5000)
+
+	unreachable := unsettledTransaction(transaction.PaymentStatusPending, 10000)
+	gateway.failing[unreachable.ID.ID] = assert.AnError
+
+	otherProvider := unsettledTransaction(transaction.PaymentStatusPending, 10000)
+	otherProvider.PaymentProvider = transaction.PaymentProviderXendit
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetUnsettledTransactions", ctx, nil, lunchTime.Add(-24*time.Hour)).Return([]transaction.Transaction{
+		lostWebhook, expiredAtGateway, neverOpened, inSync, paidAfterExpiry, underpaid, unreachable, otherProvider,
+	}, nil)
+
+	report, err := reconciliationService.Reconcile(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, report.Checked)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, 2, report.Failed)
+
+	assert.Len(t, gateway.hooks, 2)
+	assert.Equal(t, lostWebhook.ID.String(), gateway.hooks[0]["order_id"])
+	assert.Equal(t, transaction.PaymentStatusSettlement, gateway.hooks[0]["transaction_status"])
+	assert.Equal(t, "gateway-"+lostWebhook.ID.String(), gateway.hooks[0]["transaction_id"])
+	assert.Equal(t, expiredAtGateway.ID.String(), gateway.hooks[1]["order_id"])
+	assert.Equal(t, transaction.PaymentStatusExpire, gateway.hooks[1]["transaction_status"])
+
+	discrepancies := make(map[string]string)
+	for _, discrepancy := range report.Discrepancies {
+		discrepancies[discrepancy.TransactionID] = discrepancy.Type
+		if discrepancy.TransactionID == lostWebhook.ID.String() {
+			assert.True(t, discrepancy.Resolved)
+		} else {
+			assert.False(t, discrepancy.Resolved)
+		}
+	}
+	assert.Len(t, discrepancies, 3)
+	assert.Equal(t, transaction.DiscrepancyPaidNotRecorded, discrepancies[lostWebhook.ID.String()])
+	assert.Equal(t, transaction.DiscrepancyStatusMismatch, discrepancies[paidAfterExpiry.ID.String()])
+	assert.Equal(t, transaction.DiscrepancyAmountMismatch, discrepancies[underpaid.ID.String()])
+	mockTransactionRepo.AssertExpectations(t)
+}
+
+func TestReconcile_HookFailureLeavesDiscrepancyUnresolved(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForReconciliation)
+	gateway := newFakeReconciliationGateway()
+	reconciliationService := service.NewReconciliationService(mockTransactionRepo, gateway, failingHookHandler{}, fixedClock{now: lunchTime}, 0)
+
+	lostWebhook := unsettledTransaction(transaction.PaymentStatusPending, 30000)
+	gateway.report(lostWebhook, transaction.PaymentStatusCapture, 30000)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetUnsettledTransactions", ctx, nil, mock.AnythingOfType("time.Time")).Return([]transaction.Transaction{lostWebhook}, nil)
+
+	report, err := reconciliationService.Reconcile(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Updated)
+	assert.Equal(t, 1, report.Failed)
+	assert.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, transaction.DiscrepancyPaidNotRecorded, report.Discrepancies[0].Type)
+	assert.False(t, report.Discrepancies[0].Resolved)
+}
+
+func TestReconcile_RepoError(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForReconciliation)
+	gateway := newFakeReconciliationGateway()
+	reconciliationService := service.NewReconciliationService(mockTransactionRepo, gateway, gatewayHookHandler{gateway}, fixedClock{now: lunchTime}, time.Hour)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetUnsettledTransactions", ctx, nil, mock.AnythingOfType("time.Time")).Return([]transaction.Transaction(nil), assert.AnError)
+
+	report, err := reconciliationService.Reconcile(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 0, report.Checked)
+	assert.Empty(t, gateway.hooks)
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func pricingPolicy(t *testing.T, serviceChargeRate int64, taxRate int64, taxOrder string, roundingUnit int64) order.PricingPolicy {
+	policy, err := order.NewPricingPolicy(decimal.NewFromInt(serviceChargeRate), decimal.NewFromInt(taxRate), taxOrder, roundingUnit, decimal.Zero)
+	assert.NoError(t, err)
+	return policy
+}
+
+func assertAmount(t *testing.T, expected string, actual shared.Price) {
+	assert.True(t, decimal.RequireFromString(expected).Equal(actual.Price), "expected %s, got %s", expected, actual.Price.String())
+}
+
+func TestNewPricingPolicy_Validation(t *testing.T) {
+	_, err := order.NewPricingPolicy(decimal.NewFromInt(-1), decimal.Zero, "", 0, decimal.Zero)
+	assert.ErrorIs(t, err, order.ErrorInvalidPricingPolicy)
+
+	_, err = order.NewPricingPolicy(decimal.Zero, decimal.NewFromInt(101), "", 0, decimal.Zero)
+	assert.ErrorIs(t, err, order.ErrorInvalidPricingPolicy)
+
+	_, err = order.NewPricingPolicy(decimal.Zero, decimal.Zero, "sometimes", 0, decimal.Zero)
+	assert.ErrorIs(t, err, order.ErrorInvalidPricingPolicy)
+
+	_, err = order.NewPricingPolicy(decimal.Zero, decimal.Zero, "", 250, decimal.Zero)
+	assert.ErrorIs(t, err, order.ErrorInvalidPricingPolicy)
+
+	policy, err := order.NewPricingPolicy(decimal.Zero, decimal.Zero, "", 0, decimal.Zero)
+	assert.NoError(t, err)
+	assert.Equal(t, order.TaxAfterServiceCharge, policy.TaxOrder)
+}
+
+func TestPricingPolicy_TaxAfterServiceCharge(t *testing.T) {
+	breakdown := pricingPolicy(t, 5, 10, order.TaxAfterServiceCharge, 100).Apply(price(43000))
+
+	assertAmount(t, "43000", breakdown.Subtotal)
+	assertAmount(t, "2150", breakdown.ServiceCharge)
+	assertAmount(t, "4515", breakdown.Tax)
+	assertAmount(t, "35", breakdown.Rounding)
+	assertAmount(t, "49700", breakdown.Total)
+}
+
+func TestPricingPolicy_TaxBeforeServiceCharge(t *testing.T) {
+	breakdown := pricingPolicy(t, 5, 10, order.TaxBeforeServiceCharge, 0).Apply(price(100000))
+
+	assertAmount(t, "10000", breakdown.Tax)
+	assertAmount(t, "5500", breakdown.ServiceCharge)
+	assertAmount(t, "0", breakdown.Rounding)
+	assertAmount(t, "115500", breakdown.Total)
+}
+
+func TestPricingPolicy_RoundsDownToUnit(t *testing.T) {
+	breakdown := pricingPolicy(t, 5, 10, order.TaxAfterServiceCharge, 500).Apply(price(43000))
+
+	assertAmount(t, "-165", breakdown.Rounding)
+	assertAmount(t, "49500", breakdown.Total)
+	assert.True(t, breakdown.Subtotal.Price.Add(breakdown.ServiceCharge.Price).Add(breakdown.Tax.Price).Add(breakdown.Rounding.Price).Equal(breakdown.Total.Price))
+}
+
+func TestPricingPolicy_EmptyPolicyKeepsSubtotal(t *testing.T) {
+	breakdown := order.PricingPolicy{}.Apply(price(65000))
+
+	assertAmount(t, "0", breakdown.ServiceCharge)
+	assertAmount(t, "0", breakdown.Tax)
+	assertAmount(t, "65000", breakdown.Total)
+}
+
+func TestCalculatePriceBreakdown_AppliesPolicy(t *testing.T) {
+	mockMenuRepo := new(MockMenuRepositoryForCalculatePrice)
+	orderDomainService := order.NewService(pricingPolicy(t, 5, 10, order.TaxAfterServiceCharge, 100))
+	orderService := service.NewOrderService(new(MockOrderRepositoryForCalculatePrice), mockMenuRepo, orderDomainService, noPromotions(), fixedClock{now: lunchTime})
+
+	ctx := context.Background()
+	menuID := uuid.New()
+	mockMenuRepo.On("GetMenuByID", ctx, nil, menuID.String()).Return(menu.Menu{
+		ID:    identity.NewIDFromSchema(menuID),
+		Name:  "Nasi Goreng",
+		Price: price(21500),
+	}, nil)
+
+	orders := []request.Order{{MenuID: menuID.String(), Quantity: 2}}
+	breakdown, err := orderService.CalculatePriceBreakdown(ctx, "", orders, "", "", nil)
+
+	assert.NoError(t, err)
+	assertAmount(t, "43000", breakdown.Subtotal)
+	assertAmount(t, "49700", breakdown.Total)
+
+	total, err := orderService.CalculateTotalPrice(ctx, orders)
+	assert.NoError(t, err)
+	assertAmount(t, "49700", total)
+}
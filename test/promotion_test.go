@@ -0,0 +1,371 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/promotion"
+	"fp-kpl/domain/shared"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) CreatePromotion(ctx context.Context, tx interface{}, promotionEntity promotion.Promotion) (promotion.Promotion, error) {
+	args := m.Called(ctx, tx, promotionEntity)
+	return args.Get(0).(promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetAllPromotions(ctx context.Context, tx interface{}) ([]promotion.Promotion, error) {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetPromotionByID(ctx context.Context, tx interface{}, id string) (promotion.Promotion, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetActivePromotions(ctx context.Context, tx interface{}) ([]promotion.Promotion, error) {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetPromotionByVoucherCode(ctx context.Context, tx interface{}, code string) (promotion.Promotion, error) {
+	args := m.Called(ctx, tx, code)
+	return args.Get(0).(promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) UpdatePromotionStatus(ctx context.Context, tx interface{}, id string, isActive bool) (promotion.Promotion, error) {
+	args := m.Called(ctx, tx, id, isActive)
+	return args.Get(0).(promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) LockPromotion(ctx context.Context, tx interface{}, id string) (promotion.Promotion, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(promotion.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetUsage(ctx context.Context, tx interface{}, promotionID string, userID string) (promotion.Usage, error) {
+	args := m.Called(ctx, tx, promotionID, userID)
+	return args.Get(0).(promotion.Usage), args.Error(1)
+}
+
+// noPromotions is a promotion repository without any running promotion.
+func noPromotions() *MockPromotionRepository {
+	mockPromotionRepo := new(MockPromotionRepository)
+	mockPromotionRepo.On("GetActivePromotions", mock.Anything, nil).Return([]promotion.Promotion{}, nil)
+	return mockPromotionRepo
+}
+
+func newPromotion(promotionType string, value int64) promotion.Promotion {
+	return promotion.Promotion{
+		ID:       identity.NewID(uuid.New()),
+		Name:     promotionType,
+		Type:     promotion.NewTypeFromSchema(promotionType),
+		Value:    decimal.NewFromInt(value),
+		IsActive: true,
+	}
+}
+
+// Wednesday 12:30
+var lunchTime = time.Date(2024, 5, 15, 12, 30, 0, 0, time.UTC)
+
+func TestSchedule_WeekdaysAndWindow(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	window, err := shared.NewTimeWindow(weekdays, "11:00", "14:00")
+	assert.NoError(t, err)
+	schedule, err := promotion.NewSchedule(nil, nil, window)
+	assert.NoError(t, err)
+
+	assert.True(t, schedule.Contains(lunchTime))
+	assert.False(t, schedule.Contains(lunchTime.Add(2*time.Hour)))
+	assert.False(t, schedule.Contains(lunchTime.AddDate(0, 0, 3)), "saturday")
+
+	overnight, err := shared.NewTimeWindow(nil, "22:00", "02:00")
+	assert.NoError(t, err)
+	assert.True(t, overnight.Contains(time.Date(2024, 5, 15, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, overnight.Contains(time.Date(2024, 5, 15, 1, 0, 0, 0, time.UTC)))
+	assert.False(t, overnight.Contains(lunchTime))
+
+	_, err = shared.NewTimeWindow(nil, "25:00", "")
+	assert.ErrorIs(t, err, shared.ErrorInvalidTimeWindow)
+
+	campaignStart := lunchTime.Add(time.Hour)
+	_, err = promotion.NewSchedule(&campaignStart, &lunchTime, shared.TimeWindow{})
+	assert.ErrorIs(t, err, promotion.ErrorInvalidPromotion)
+}
+
+func TestPromotion_PercentageScopedToCategory(t *testing.T) {
+	noodles := identity.NewID(uuid.New())
+	p := newPromotion(promotion.TypePercentage, 10)
+	p.CategoryID = noodles
+
+	cart := promotion.Cart{At: lunchTime, Lines: []promotion.Line{
+		{MenuID: identity.NewID(uuid.New()), CategoryID: noodles, UnitPrice: decimal.NewFromInt(18500), Quantity: 1},
+		{MenuID: identity.NewID(uuid.New()), CategoryID: identity.NewID(uuid.New()), UnitPrice: decimal.NewFromInt(8000), Quantity: 2},
+	}}
+
+	assert.Equal(t, "1850", p.Discount(cart).String())
+}
+
+func TestPromotion_BuyTwoGetOne(t *testing.T) {
+	p := newPromotion(promotion.TypeBOGO, 0)
+	p.BuyQuantity = 2
+	p.FreeQuantity = 1
+
+	line := promotion.Line{MenuID: identity.NewID(uuid.New()), UnitPrice: decimal.NewFromInt(12000), Quantity: 2}
+	assert.True(t, p.Discount(promotion.Cart{At: lunchTime, Lines: []promotion.Line{line}}).IsZero())
+
+	line.Quantity = 5
+	assert.Equal(t, "12000", p.Discount(promotion.Cart{At: lunchTime, Lines: []promotion.Line{line}}).String())
+
+	line.Quantity = 6
+	assert.Equal(t, "24000", p.Discount(promotion.Cart{At: lunchTime, Lines: []promotion.Line{line}}).String())
+}
+
+func TestPromotion_MinimumSpendAndSchedule(t *testing.T) {
+	p := newPromotion(promotion.TypeFixedAmount, 10000)
+	p.MinimumSpend = price(50000)
+
+	cart := promotion.Cart{At: lunchTime, Lines: []promotion.Line{
+		{MenuID: identity.NewID(uuid.New()), UnitPrice: decimal.NewFromInt(20000), Quantity: 2},
+	}}
+	assert.True(t, p.Discount(cart).IsZero())
+
+	cart.Lines[0].Quantity = 3
+	assert.Equal(t, "10000", p.Discount(cart).String())
+
+	ended := lunchTime.Add(-time.Hour)
+	p.Schedule.EndsAt = &ended
+	assert.True(t, p.Discount(cart).IsZero())
+}
+
+func TestEvaluate_CapsAtSubtotal(t *testing.T) {
+	cart := promotion.Cart{At: lunchTime, Lines: []promotion.Line{
+		{MenuID: identity.NewID(uuid.New()), UnitPrice: decimal.NewFromInt(15000), Quantity: 1},
+	}}
+
+	applied := promotion.Evaluate([]promotion.Promotion{
+		newPromotion(promotion.TypeFixedAmount, 10000),
+		newPromotion(promotion.TypeFixedAmount, 10000),
+		newPromotion(promotion.TypePercentage, 50),
+	}, cart)
+
+	assert.Len(t, applied, 2)
+	assert.Equal(t, "10000", applied[0].Amount.String())
+	assert.Equal(t, "5000", applied[1].Amount.String())
+}
+
+func TestPromotion_CheckUsage(t *testing.T) {
+	p := newPromotion(promotion.TypeFixedAmount, 5000)
+	p.UsageLimit = 100
+	p.PerUserLimit = 1
+
+	assert.NoError(t, p.CheckUsage(promotion.Usage{Total: 99}))
+	assert.ErrorIs(t, p.CheckUsage(promotion.Usage{Total: 100}), promotion.ErrorUsageLimitReached)
+	assert.ErrorIs(t, p.CheckUsage(promotion.Usage{Total: 3, ByUser: 1}), promotion.ErrorUserLimitReached)
+}
+
+func TestPricingPolicy_DiscountBeforeServiceAndTax(t *testing.T) {
+	breakdown := pricingPolicy(t, 5, 10, order.TaxAfterServiceCharge, 0).ApplyDiscounts(price(100000), []order.Discount{
+		{Name: "Promo", Amount: price(20000)},
+	})
+
+	assertAmount(t, "20000", breakdown.Discount)
+	assertAmount(t, "4000", breakdown.ServiceCharge)
+	assertAmount(t, "8400", breakdown.Tax)
+	assertAmount(t, "92400", breakdown.Total)
+}
+
+// voucherOrder prices one 25000 menu line twice for the voucher tests.
+func voucherOrder(t *testing.T, mockPromotionRepo *MockPromotionRepository, voucherCode string) (order.PriceBreakdown, error) {
+	mockMenuRepo := new(MockMenuRepositoryForCalculatePrice)
+	orderService := service.NewOrderService(new(MockOrderRepositoryForCalculatePrice), mockMenuRepo, order.NewService(order.PricingPolicy{}), mockPromotionRepo, fixedClock{now: lunchTime})
+
+	menuID := uuid.New()
+	mockMenuRepo.On("GetMenuByID", mock.Anything, nil, menuID.String()).Return(menu.Menu{
+		ID:    identity.NewIDFromSchema(menuID),
+		Name:  "Dimsum",
+		Price: price(25000),
+	}, nil)
+
+	return orderService.CalculatePriceBreakdown(context.Background(), "user-1", []request.Order{{MenuID: menuID.String(), Quantity: 2}}, voucherCode, "", nil)
+}
+
+func TestCalculatePriceBreakdown_AppliesVoucher(t *testing.T) {
+	voucher := newPromotion(promotion.TypePercentage, 20)
+	voucher.VoucherCode = "HEMAT20"
+	voucher.PerUserLimit = 1
+
+	mockPromotionRepo := noPromotions()
+	mockPromotionRepo.On("GetPromotionByVoucherCode", mock.Anything, nil, "HEMAT20").Return(voucher, nil)
+	mockPromotionRepo.On("GetUsage", mock.Anything, nil, voucher.ID.String(), "user-1").Return(promotion.Usage{}, nil)
+
+	breakdown, err := voucherOrder(t, mockPromotionRepo, " hemat20 ")
+
+	assert.NoError(t, err)
+	assertAmount(t, "10000", breakdown.Discount)
+	assertAmount(t, "40000", breakdown.Total)
+	assert.Len(t, breakdown.Discounts, 1)
+	assert.Equal(t, "HEMAT20", breakdown.Discounts[0].VoucherCode)
+}
+
+func TestCalculatePriceBreakdown_VoucherUsedUp(t *testing.T) {
+	voucher := newPromotion(promotion.TypePercentage, 20)
+	voucher.VoucherCode = "HEMAT20"
+	voucher.PerUserLimit = 1
+
+	mockPromotionRepo := noPromotions()
+	mockPromotionRepo.On("GetPromotionByVoucherCode", mock.Anything, nil, "HEMAT20").Return(voucher, nil)
+	mockPromotionRepo.On("GetUsage", mock.Anything, nil, voucher.ID.String(), "user-1").Return(promotion.Usage{Total: 4, ByUser: 1}, nil)
+
+	_, err := voucherOrder(t, mockPromotionRepo, "HEMAT20")
+
+	assert.ErrorIs(t, err, promotion.ErrorUserLimitReached)
+}
+
+func TestCalculatePriceBreakdown_VoucherNotApplicable(t *testing.T) {
+	voucher := newPromotion(promotion.TypeFixedAmount, 15000)
+	voucher.VoucherCode = "BIGSPENDER"
+	voucher.MinimumSpend = price(100000)
+
+	mockPromotionRepo := noPromotions()
+	mockPromotionRepo.On("GetPromotionByVoucherCode", mock.Anything, nil, "BIGSPENDER").Return(voucher, nil)
+
+	_, err := voucherOrder(t, mockPromotionRepo, "BIGSPENDER")
+
+	assert.ErrorIs(t, err, promotion.ErrorVoucherNotApplicable)
+}
+
+func TestCalculatePriceBreakdown_SkipsUsedUpPromotion(t *testing.T) {
+	usedUp := newPromotion(promotion.TypeFixedAmount, 5000)
+	usedUp.UsageLimit = 10
+	running := newPromotion(promotion.TypeFixedAmount, 2000)
+
+	mockPromotionRepo := new(MockPromotionRepository)
+	mockPromotionRepo.On("GetActivePromotions", mock.Anything, nil).Return([]promotion.Promotion{usedUp, running}, nil)
+	mockPromotionRepo.On("GetUsage", mock.Anything, nil, usedUp.ID.String(), "user-1").Return(promotion.Usage{Total: 10}, nil)
+
+	breakdown, err := voucherOrder(t, mockPromotionRepo, "")
+
+	assert.NoError(t, err)
+	assertAmount(t, "2000", breakdown.Discount)
+	assertAmount(t, "48000", breakdown.Total)
+}
+
+func TestClaimPromotions_RechecksUsageUnderLock(t *testing.T) {
+	ctx := context.Background()
+	tx := &struct{}{}
+	voucher := newPromotion(promotion.TypePercentage, 20)
+	voucher.VoucherCode = "HEMAT20"
+	voucher.UsageLimit = 5
+	unlimited := newPromotion(promotion.TypeFixedAmount, 2000)
+	breakdown := order.PriceBreakdown{Discounts: []order.Discount{
+		{PromotionID: voucher.ID, Name: voucher.Name, VoucherCode: voucher.VoucherCode},
+		{PromotionID: unlimited.ID, Name: unlimited.Name},
+	}}
+	orderService := func(mockPromotionRepo *MockPromotionRepository) service.OrderService {
+		return service.NewOrderService(nil, nil, nil, mockPromotionRepo, fixedClock{now: lunchTime})
+	}
+
+	t.Run("the last use went to another checkout", func(t *testing.T) {
+		mockPromotionRepo := new(MockPromotionRepository)
+		mockPromotionRepo.On("LockPromotion", ctx, tx, voucher.ID.String()).Return(voucher, nil)
+		mockPromotionRepo.On("LockPromotion", ctx, tx, unlimited.ID.String()).Return(unlimited, nil)
+		mockPromotionRepo.On("GetUsage", ctx, tx, voucher.ID.String(), "user-1").Return(promotion.Usage{Total: 5}, nil)
+
+		err := orderService(mockPromotionRepo).ClaimPromotions(ctx, tx, "user-1", breakdown)
+
+		assert.ErrorIs(t, err, promotion.ErrorUsageLimitReached)
+	})
+
+	t.Run("locks every promotion and counts within the transaction", func(t *testing.T) {
+		mockPromotionRepo := new(MockPromotionRepository)
+		mockPromotionRepo.On("LockPromotion", ctx, tx, voucher.ID.String()).Return(voucher, nil)
+		mockPromotionRepo.On("LockPromotion", ctx, tx, unlimited.ID.String()).Return(unlimited, nil)
+		mockPromotionRepo.On("GetUsage", ctx, tx, voucher.ID.String(), "user-1").Return(promotion.Usage{Total: 4}, nil)
+
+		err := orderService(mockPromotionRepo).ClaimPromotions(ctx, tx, "user-1", breakdown)
+
+		assert.NoError(t, err)
+		mockPromotionRepo.AssertExpectations(t)
+		mockPromotionRepo.AssertNotCalled(t, "GetUsage", ctx, tx, unlimited.ID.String(), mock.Anything)
+	})
+}
+
+func TestCreatePromotion_Validation(t *testing.T) {
+	promotionService := service.NewPromotionService(new(MockPromotionRepository))
+	ctx := context.Background()
+
+	_, err := promotionService.CreatePromotion(ctx, request.CreatePromotion{Name: "Promo", Type: "cashback"})
+	assert.ErrorIs(t, err, promotion.ErrorInvalidPromotionType)
+
+	_, err = promotionService.CreatePromotion(ctx, request.CreatePromotion{Name: "Promo", Type: promotion.TypePercentage, Value: "150"})
+	assert.ErrorIs(t, err, promotion.ErrorInvalidPromotion)
+
+	_, err = promotionService.CreatePromotion(ctx, request.CreatePromotion{Name: "Promo", Type: promotion.TypeBOGO, BuyQuantity: 2})
+	assert.ErrorIs(t, err, promotion.ErrorInvalidPromotion)
+}
+
+func TestCreatePromotion_DuplicateVoucher(t *testing.T) {
+	mockPromotionRepo := new(MockPromotionRepository)
+	promotionService := service.NewPromotionService(mockPromotionRepo)
+	ctx := context.Background()
+
+	mockPromotionRepo.On("GetPromotionByVoucherCode", ctx, nil, "HEMAT20").Return(newPromotion(promotion.TypePercentage, 20), nil)
+
+	_, err := promotionService.CreatePromotion(ctx, request.CreatePromotion{
+		Name:        "Hemat",
+		Type:        promotion.TypePercentage,
+		Value:       "20",
+		VoucherCode: "hemat20",
+	})
+
+	assert.ErrorIs(t, err, promotion.ErrorVoucherAlreadyExists)
+	mockPromotionRepo.AssertNotCalled(t, "CreatePromotion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreatePromotion_Success(t *testing.T) {
+	mockPromotionRepo := new(MockPromotionRepository)
+	promotionService := service.NewPromotionService(mockPromotionRepo)
+	ctx := context.Background()
+
+	created := newPromotion(promotion.TypeBOGO, 0)
+	created.BuyQuantity = 2
+	created.FreeQuantity = 1
+	created.Schedule = promotion.Schedule{Window: shared.TimeWindow{
+		Days:        []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		StartMinute: 11 * 60,
+		EndMinute:   14 * 60,
+	}}
+	mockPromotionRepo.On("CreatePromotion", ctx, nil, mock.MatchedBy(func(p promotion.Promotion) bool {
+		return p.Type.Type == promotion.TypeBOGO && p.IsActive && p.Schedule.Window.StartMinute == 11*60 && len(p.Schedule.Window.Days) == 5
+	})).Return(created, nil)
+
+	result, err := promotionService.CreatePromotion(ctx, request.CreatePromotion{
+		Name:         "Dimsum beli 2 gratis 1",
+		Type:         promotion.TypeBOGO,
+		BuyQuantity:  2,
+		FreeQuantity: 1,
+		Days:         []int{1, 2, 3, 4, 5},
+		StartTime:    "11:00",
+		EndTime:      "14:00",
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+	assert.Equal(t, "11:00", result.StartTime)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, result.Days)
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func lookupService(transactionRepo transaction.Repository) service.TransactionService {
+	return service.NewTransactionService(
+		transactionRepo,
+		new(MockUserRepositoryForTransaction),
+		new(MockTableRepositoryForTransaction),
+		new(MockOrderRepositoryForTransaction),
+		new(MockMenuRepositoryForTransaction),
+		transaction.NewService(transactionRepo, transaction.DefaultStationCapacity, fixedClock{now: lunchTime}),
+		new(MockPaymentGatewayPortForGetByID),
+		nil,
+		new(MockOrderServiceForGetByID),
+		nil,
+		nil,
+		nil,
+		fixedClock{now: lunchTime},
+		nil,
+		nil,
+	)
+}
+
+func TestGenerateQueueCode_UsesBusinessDay(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	afterMidnight := time.Date(2024, 5, 16, 0, 30, 0, 0, time.UTC)
+	domainService := transaction.NewService(mockTransactionRepo, transaction.DefaultStationCapacity, fixedClock{now: afterMidnight, cutoff: 4 * time.Hour})
+
+	ctx := context.Background()
+	transactionID := uuid.New().String()
+	mockTransactionRepo.On("GetLatestQueueCode", ctx, nil, transactionID, "2024-05-15").Return("Q0007", nil)
+
+	queueCode, err := domainService.GenerateQueueCode(ctx, nil, transactionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Q0007", queueCode.Code)
+	assert.Equal(t, "2024-05-15", queueCode.BusinessDay)
+	assert.True(t, queueCode.Valid)
+	mockTransactionRepo.AssertExpectations(t)
+}
+
+func TestLookupTransaction_ByTransactionID(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	transactionService := lookupService(mockTransactionRepo)
+
+	ctx := context.Background()
+	transactionID := uuid.New().String()
+	transactionQuery := transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.MustParse(transactionID)),
+			QueueCode:   transaction.QueueCode{Code: "Q0001", BusinessDay: "2024-05-14"},
+			OrderStatus: transaction.OrderStatus{Status: "preparing"},
+		},
+	}
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transactionQuery, nil)
+
+	result, err := transactionService.LookupTransaction(ctx, transactionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, transactionID, result.ID)
+	assert.Equal(t, "Q0001", result.QueueCode)
+	assert.Equal(t, "2024-05-14", result.BusinessDay)
+	mockTransactionRepo.AssertExpectations(t)
+}
+
+func TestLookupTransaction_NotFound(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	transactionService := lookupService(mockTransactionRepo)
+
+	ctx := context.Background()
+	transactionID := uuid.New().String()
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transaction.Query{}, gorm.ErrRecordNotFound)
+
+	_, err := transactionService.LookupTransaction(ctx, transactionID)
+
+	assert.ErrorIs(t, err, transaction.ErrorTransactionNotFound)
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/menu/category"
+	"fp-kpl/domain/menu/favourite"
+	menu "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/domain/user"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockFavouriteRepository struct{ mock.Mock }
+
+func (m *MockFavouriteRepository) GetFavouritesByUserID(ctx context.Context, tx interface{}, userID string) ([]favourite.Favourite, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Get(0).([]favourite.Favourite), args.Error(1)
+}
+func (m *MockFavouriteRepository) AddFavourite(ctx context.Context, tx interface{}, favouriteEntity favourite.Favourite) (favourite.Favourite, error) {
+	args := m.Called(ctx, tx, favouriteEntity)
+	return favouriteEntity, args.Error(0)
+}
+func (m *MockFavouriteRepository) RemoveFavourite(ctx context.Context, tx interface{}, userID string, menuID string) error {
+	args := m.Called(ctx, tx, userID, menuID)
+	return args.Error(0)
+}
+
+func reorderService(t *testing.T, transactionRepo transaction.Repository, userRepo user.Repository, menuRepo menu.Repository) service.TransactionService {
+	policy, err := order.NewPricingPolicy(decimal.Zero, decimal.Zero, "", 0, decimal.Zero)
+	assert.NoError(t, err)
+	orderService := service.NewOrderService(nil, menuRepo, order.NewService(policy), noPromotions(), fixedClock{now: lunchTime})
+
+	return service.NewTransactionService(
+		transactionRepo,
+		userRepo,
+		nil,
+		nil,
+		menuRepo,
+		nil,
+		nil,
+		nil,
+		orderService,
+		nil,
+		nil,
+		nil,
+		fixedClock{now: lunchTime},
+		nil,
+		nil,
+	)
+}
+
+func pastOrder(menuEntity menu.Menu, unitPrice int64, quantity int) transaction.OrderQuery {
+	return transaction.OrderQuery{
+		Order: order.Order{MenuID: menuEntity.ID, Price: price(unitPrice), Quantity: quantity},
+		Menu:  menuEntity,
+	}
+}
+
+func TestMenu_CheckAvailableAt(t *testing.T) {
+	breakfastOnly, err := shared.NewTimeWindow(nil, "07:00", "10:00")
+	assert.NoError(t, err)
+
+	assert.NoError(t, menu.Menu{IsAvailable: true}.CheckAvailableAt(lunchTime))
+	assert.ErrorIs(t, menu.Menu{}.CheckAvailableAt(lunchTime), menu.ErrorMenuUnavailable)
+	assert.ErrorIs(t, menu.Menu{IsAvailable: true, Schedule: []shared.TimeWindow{breakfastOnly}}.CheckAvailableAt(lunchTime), menu.ErrorMenuOutOfSchedule)
+}
+
+func TestReorder_RepricesAndReportsUnavailable(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	mockUserRepo := new(MockUserRepositoryForTransaction)
+	mockMenuRepo := new(MockMenuRepositoryForTransaction)
+	transactionService := reorderService(t, mockTransactionRepo, mockUserRepo, mockMenuRepo)
+
+	ctx := context.Background()
+	customer := user.User{ID: identity.NewID(uuid.New()), Role: user.Role{Name: user.RoleCustomer}}
+	transactionID := uuid.NewString()
+
+	nasiGoreng := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Nasi Goreng", Price: price(30000), IsAvailable: true}
+	esTeh := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Es Teh", Price: price(5000)}
+	sateAyam := menu.Menu{ID: identity.NewID(uuid.New()), Name: "Sate Ayam", Price: price(25000), IsAvailable: true}
+
+	mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:        identity.NewID(uuid.MustParse(transactionID)),
+			UserID:    customer.ID,
+			OrderType: transaction.OrderType{Type: transaction.OrderTypeDineIn},
+		},
+		Orders: []transaction.OrderQuery{
+			pastOrder(nasiGoreng, 25000, 2),
+			pastOrder(esTeh, 5000, 2),
+			pastOrder(sateAyam, 25000, 1),
+		},
+	}, nil)
+	mockMenuRepo.On("GetMenuByID", ctx, nil, nasiGoreng.ID.String()).Return(nasiGoreng, nil)
+	mockMenuRepo.On("GetMenuByID", ctx, nil, esTeh.ID.String()).Return(esTeh, nil)
+	mockMenuRepo.On("GetMenuByID", ctx, nil, sateAyam.ID.String()).Return(menu.Menu{}, gorm.ErrRecordNotFound)
+
+	result, err := transactionService.Reorder(ctx, customer.ID.String(), transactionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, transactionID, result.SourceTransactionID)
+	assert.Len(t, result.Orders, 1)
+	assert.Equal(t, nasiGoreng.ID.String(), result.Orders[0].Menu.ID)
+	assert.Equal(t, "30000", result.Orders[0].Menu.Price)
+	assert.Equal(t, 2, result.Orders[0].Quantity)
+	assert.Len(t, result.Unavailable, 2)
+	assert.Equal(t, menu.ErrorMenuUnavailable.Error(), result.Unavailable[0].Reason)
+	assert.Equal(t, menu.ErrorMenuNotFound.Error(), result.Unavailable[1].Reason)
+	assert.Equal(t, "Sate Ayam", result.Unavailable[1].Menu.Name)
+	assert.NotNil(t, result.Pricing)
+	assert.Equal(t, "60000", result.Pricing.Total)
+}
+
+func TestReorder_OtherCustomersTransaction(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	mockUserRepo := new(MockUserRepositoryForTransaction)
+	transactionService := reorderService(t, mockTransactionRepo, mockUserRepo, new(MockMenuRepositoryForTransaction))
+
+	ctx := context.Background()
+	customer := user.User{ID: identity.NewID(uuid.New()), Role: user.Role{Name: user.RoleCustomer}}
+	transactionID := uuid.NewString()
+
+	mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transaction.Query{
+		Transaction: transaction.Transaction{UserID: identity.NewID(uuid.New())},
+	}, nil)
+
+	_, err := transactionService.Reorder(ctx, customer.ID.String(), transactionID)
+
+	assert.ErrorIs(t, err, transaction.ErrorNotTransactionOwner)
+}
+
+func TestFavouriteMenus(t *testing.T) {
+	mockMenuRepo := new(MockMenuRepositoryForAvailability)
+	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
+	mockFavouriteRepo := new(MockFavouriteRepository)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, mockFavouriteRepo, noRatings(), fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+	categoryEntity := category.Category{ID: identity.NewID(uuid.New()), Name: "Makanan"}
+	menuEntity := menu.Menu{ID: identity.NewID(uuid.New()), CategoryID: categoryEntity.ID, Name: "Nasi Goreng", Price: price(30000)}
+
+	mockMenuRepo.On("GetMenuByID", ctx, nil, menuEntity.ID.String()).Return(menuEntity, nil)
+	mockCategoryRepo.On("GetCategoryByID", ctx, nil, categoryEntity.ID.String()).Return(categoryEntity, nil)
+	mockFavouriteRepo.On("AddFavourite", ctx, nil, mock.MatchedBy(func(favouriteEntity favourite.Favourite) bool {
+		return favouriteEntity.UserID.String() == userID && favouriteEntity.MenuID == menuEntity.ID
+	})).Return(nil)
+	mockFavouriteRepo.On("GetFavouritesByUserID", ctx, nil, userID).Return([]favourite.Favourite{
+		{UserID: identity.NewID(uuid.MustParse(userID)), MenuID: menuEntity.ID, CreatedAt: lunchTime.Add(-time.Hour)},
+	}, nil)
+
+	added, err := menuService.AddFavouriteMenu(ctx, userID, menuEntity.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, menuEntity.ID.String(), added.ID)
+
+	favourites, err := menuService.GetFavouriteMenus(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, favourites, 1)
+	assert.Equal(t, "Nasi Goreng", favourites[0].Name)
+	assert.False(t, favourites[0].IsAvailable)
+	mockFavouriteRepo.AssertExpectations(t)
+}
+
+func TestFavouriteMenus_UnknownMenuAndMissingFavourite(t *testing.T) {
+	mockMenuRepo := new(MockMenuRepositoryForAvailability)
+	mockFavouriteRepo := new(MockFavouriteRepository)
+	menuService := service.NewMenuService(mockMenuRepo, new(MockCategoryRepositoryForStateMenu), mockFavouriteRepo, noRatings(), fixedClock{now: lunchTime}, nil)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+	menuID := uuid.NewString()
+	mockMenuRepo.On("GetMenuByID", ctx, nil, menuID).Return(menu.Menu{}, gorm.ErrRecordNotFound)
+	mockFavouriteRepo.On("RemoveFavourite", ctx, nil, userID, menuID).Return(gorm.ErrRecordNotFound)
+
+	_, err := menuService.AddFavouriteMenu(ctx, userID, menuID)
+	assert.ErrorIs(t, err, menu.ErrorMenuNotFound)
+
+	err = menuService.RemoveFavouriteMenu(ctx, userID, menuID)
+	assert.ErrorIs(t, err, favourite.ErrorFavouriteNotFound)
+}
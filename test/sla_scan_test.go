@@ -0,0 +1,199 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	menu_item "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/sla"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/infrastructure/adapter/notifier"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionRepositoryForSLA struct {
+	MockTransactionRepositoryForNextOrder
+}
+
+func (m *MockTransactionRepositoryForSLA) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]transaction.Query), args.Error(1)
+}
+
+func (m *MockTransactionRepositoryForSLA) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, tx, limit)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
+type MockSLARepository struct {
+	mock.Mock
+}
+
+func (m *MockSLARepository) RecordAlert(ctx context.Context, tx interface{}, breach sla.Breach) (bool, error) {
+	args := m.Called(ctx, tx, breach)
+	return args.Bool(0), args.Error(1)
+}
+
+func slaQuery(queueCode string, paymentStatus string, orderStatus string, cookingTime time.Duration) transaction.Query {
+	return transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.New()),
+			QueueCode:   transaction.QueueCode{Code: queueCode, Valid: true},
+			Payment:     transaction.Payment{Status: paymentStatus},
+			OrderStatus: transaction.OrderStatus{Status: orderStatus},
+			Timestamp:   shared.Timestamp{CreatedAt: time.Now().Add(-time.Hour)},
+		},
+		Orders: []transaction.OrderQuery{{
+			Order: order.Order{Quantity: 1, CookingStatus: order.CookingStatus{Status: order.CookingStatusPreparing}},
+			Menu:  menu_item.Menu{CookingTime: cookingTime},
+		}},
+	}
+}
+
+func timeAgo(duration time.Duration) *time.Time {
+	at := time.Now().Add(-duration)
+	return &at
+}
+
+func TestSLAEvaluate(t *testing.T) {
+	slaDomainService := sla.NewService(sla.NewPolicy(10*time.Minute, 15*time.Minute), transaction.NewService(nil, transaction.DefaultStationCapacity, realClock))
+
+	pendingLate := slaQuery("Q0001", transaction.PaymentStatusSettlement, transaction.OrderStatusPending, 10*time.Minute)
+	pendingLate.Transaction.PaidAt = timeAgo(20 * time.Minute)
+
+	pendingOnTime := slaQuery("Q0002", transaction.PaymentStatusCapture, transaction.OrderStatusPending, 10*time.Minute)
+	pendingOnTime.Transaction.PaidAt = timeAgo(5 * time.Minute)
+
+	unpaid := slaQuery("Q0003", transaction.PaymentStatusPending, transaction.OrderStatusPending, 10*time.Minute)
+
+	cookingLate := slaQuery("Q0004", transaction.PaymentStatusSettlement, transaction.OrderStatusPreparing, 10*time.Minute)
+	cookingLate.Transaction.CookedAt = timeAgo(25 * time.Minute)
+
+	cookingOnTime := slaQuery("Q0005", transaction.PaymentStatusSettlement, transaction.OrderStatusPreparing, 30*time.Minute)
+	cookingOnTime.Transaction.CookedAt = timeAgo(25 * time.Minute)
+
+	pickupLate := slaQuery("Q0006", transaction.PaymentStatusSettlement, transaction.OrderStatusReadyToServe, 10*time.Minute)
+	pickupLate.Transaction.ReadyAt = timeAgo(12 * time.Minute)
+
+	pickupOnTime := slaQuery("Q0007", transaction.PaymentStatusSettlement, transaction.OrderStatusReadyToServe, 10*time.Minute)
+	pickupOnTime.Transaction.ReadyAt = timeAgo(2 * time.Minute)
+
+	breaches := slaDomainService.Evaluate([]transaction.Query{
+		pendingLate, pendingOnTime, unpaid, cookingLate, cookingOnTime, pickupLate, pickupOnTime,
+	}, transaction.KitchenSnapshot{}, time.Now())
+
+	assert.Len(t, breaches, 3)
+
+	breachTypes := make(map[string]string)
+	for _, breach := range breaches {
+		breachTypes[breach.QueueCode] = breach.Type.Type
+		assert.True(t, breach.Overdue() > 0)
+	}
+	assert.Equal(t, sla.BreachTypePendingOverdue, breachTypes["Q0001"])
+	assert.Equal(t, sla.BreachTypeCookingOverdue, breachTypes["Q0004"])
+	assert.Equal(t, sla.BreachTypePickupOverdue, breachTypes["Q0006"])
+}
+
+func TestSLAScan_EscalatesOnceAndClearsResolvedBreaches(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForSLA)
+	transactionDomainService := transaction.NewService(mockTransactionRepo, transaction.DefaultStationCapacity, realClock)
+	slaDomainService := sla.NewService(sla.NewPolicy(10*time.Minute, 15*time.Minute), transactionDomainService)
+
+	var logs bytes.Buffer
+	logNotifier := notifier.NewLogNotifier(log.New(&logs, "", 0), notifier.DefaultAlertHistorySize)
+
+	mockSLARepo := new(MockSLARepository)
+	slaService := service.NewSLAService(mockTransactionRepo, mockSLARepo, transactionDomainService, slaDomainService, logNotifier)
+
+	pickupLate := slaQuery("Q0001", transaction.PaymentStatusSettlement, transaction.OrderStatusReadyToServe, 10*time.Minute)
+	pickupLate.Transaction.ReadyAt = timeAgo(30 * time.Minute)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetInFlightTransactions", ctx, nil).Return([]transaction.Query{pickupLate}, nil).Twice()
+	mockSLARepo.On("RecordAlert", ctx, nil, mock.AnythingOfType("sla.Breach")).Return(true, nil).Once()
+	mockSLARepo.On("RecordAlert", ctx, nil, mock.AnythingOfType("sla.Breach")).Return(false, nil).Once()
+
+	breaches, err := slaService.Scan(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 1)
+	assert.Equal(t, sla.BreachTypePickupOverdue, breaches[0].Type)
+	assert.Equal(t, "Q0001", breaches[0].QueueCode)
+
+	_, err = slaService.Scan(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, logNotifier.Alerts(), 1)
+	assert.Contains(t, logs.String(), "pickup_overdue")
+	assert.Len(t, slaService.GetCurrentBreaches(ctx), 1)
+
+	mockTransactionRepo.On("GetInFlightTransactions", ctx, nil).Return([]transaction.Query{}, nil).Once()
+
+	breaches, err = slaService.Scan(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, breaches)
+	assert.Empty(t, slaService.GetCurrentBreaches(ctx))
+	assert.Len(t, logNotifier.Alerts(), 1)
+	mockSLARepo.AssertExpectations(t)
+}
+
+func TestSLAScan_SkipsAlertedAndUnrecordedBreaches(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForSLA)
+	transactionDomainService := transaction.NewService(mockTransactionRepo, transaction.DefaultStationCapacity, realClock)
+	logNotifier := notifier.NewLogNotifier(log.New(&bytes.Buffer{}, "", 0), notifier.DefaultAlertHistorySize)
+	mockSLARepo := new(MockSLARepository)
+	slaService := service.NewSLAService(
+		mockTransactionRepo,
+		mockSLARepo,
+		transactionDomainService,
+		sla.NewService(sla.NewPolicy(10*time.Minute, 15*time.Minute), transactionDomainService),
+		logNotifier,
+	)
+
+	pickupLate := slaQuery("Q0001", transaction.PaymentStatusSettlement, transaction.OrderStatusReadyToServe, 10*time.Minute)
+	pickupLate.Transaction.ReadyAt = timeAgo(30 * time.Minute)
+	cookingLate := slaQuery("Q0002", transaction.PaymentStatusSettlement, transaction.OrderStatusPreparing, 10*time.Minute)
+	cookingLate.Transaction.CookedAt = timeAgo(25 * time.Minute)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetInFlightTransactions", ctx, nil).Return([]transaction.Query{pickupLate, cookingLate}, nil)
+	mockSLARepo.On("RecordAlert", ctx, nil, mock.MatchedBy(func(breach sla.Breach) bool {
+		return breach.QueueCode == "Q0001"
+	})).Return(false, nil)
+	mockSLARepo.On("RecordAlert", ctx, nil, mock.MatchedBy(func(breach sla.Breach) bool {
+		return breach.QueueCode == "Q0002"
+	})).Return(false, assert.AnError)
+
+	breaches, err := slaService.Scan(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 2)
+	assert.Empty(t, logNotifier.Alerts())
+}
+
+func TestSLAScan_RepoError(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForSLA)
+	transactionDomainService := transaction.NewService(mockTransactionRepo, transaction.DefaultStationCapacity, realClock)
+	slaService := service.NewSLAService(
+		mockTransactionRepo,
+		new(MockSLARepository),
+		transactionDomainService,
+		sla.NewService(sla.NewPolicy(0, 0), transactionDomainService),
+		notifier.NewLogNotifier(nil, 0),
+	)
+
+	ctx := context.Background()
+	mockTransactionRepo.On("GetInFlightTransactions", ctx, nil).Return([]transaction.Query(nil), assert.AnError)
+
+	breaches, err := slaService.Scan(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, breaches)
+}
@@ -0,0 +1,252 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/domain/user"
+	"fp-kpl/infrastructure/adapter/payment_gateway"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSplitPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockSplitPaymentRepository) CreateSplitPayment(ctx context.Context, tx interface{}, splitPayment transaction.SplitPayment) (transaction.SplitPayment, error) {
+	args := m.Called(ctx, tx, splitPayment)
+	return args.Get(0).(transaction.SplitPayment), args.Error(1)
+}
+
+func (m *MockSplitPaymentRepository) GetSplitPaymentByID(ctx context.Context, tx interface{}, id string) (transaction.SplitPayment, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(transaction.SplitPayment), args.Error(1)
+}
+
+func (m *MockSplitPaymentRepository) LockSplitPayment(ctx context.Context, tx interface{}, id string) (transaction.SplitPayment, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(transaction.SplitPayment), args.Error(1)
+}
+
+func (m *MockSplitPaymentRepository) GetSplitPaymentsByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]transaction.SplitPayment, error) {
+	args := m.Called(ctx, tx, transactionID)
+	return args.Get(0).([]transaction.SplitPayment), args.Error(1)
+}
+
+func (m *MockSplitPaymentRepository) CreateRefund(ctx context.Context, tx interface{}, refund transaction.Refund) (transaction.Refund, error) {
+	args := m.Called(ctx, tx, refund)
+	return args.Get(0).(transaction.Refund), args.Error(1)
+}
+
+func splitPayment(amount int64, status string) transaction.SplitPayment {
+	return transaction.SplitPayment{
+		ID:      identity.NewID(uuid.New()),
+		Amount:  price(amount),
+		Payment: transaction.NewPaymentFromSchema("", status),
+	}
+}
+
+func TestSplitByAmount(t *testing.T) {
+	remaining := decimal.NewFromInt(100000)
+
+	assert.NoError(t, transaction.SplitByAmount(remaining, []decimal.Decimal{decimal.NewFromInt(60000), decimal.NewFromInt(40000)}))
+	assert.NoError(t, transaction.SplitByAmount(remaining, []decimal.Decimal{decimal.NewFromInt(30000)}))
+
+	err := transaction.SplitByAmount(remaining, []decimal.Decimal{decimal.NewFromInt(60000), decimal.NewFromInt(50000)})
+	assert.ErrorIs(t, err, transaction.ErrorSplitExceedsTotal)
+
+	err = transaction.SplitByAmount(remaining, []decimal.Decimal{decimal.Zero})
+	assert.ErrorIs(t, err, transaction.ErrorInvalidSplit)
+}
+
+func TestSplitByLines_SpreadsTotalOverLines(t *testing.T) {
+	first, second, third := identity.NewID(uuid.New()), identity.NewID(uuid.New()), identity.NewID(uuid.New())
+	lines := []transaction.SplitLine{
+		{OrderID: first, Subtotal: decimal.NewFromInt(10000)},
+		{OrderID: second, Subtotal: decimal.NewFromInt(10000)},
+		{OrderID: third, Subtotal: decimal.NewFromInt(10000)},
+	}
+	// The total differs from the lines, e.g. after a service charge.
+	total := price(31000)
+
+	amounts, err := transaction.SplitByLines(total, lines, nil, [][]identity.ID{{first}, {second, third}}, total.Price)
+
+	assert.NoError(t, err)
+	assert.Len(t, amounts, 2)
+	assert.True(t, decimal.RequireFromString("10333.33").Equal(amounts[0]), amounts[0].String())
+	assert.True(t, decimal.RequireFromString("20666.67").Equal(amounts[1]), amounts[1].String())
+	assert.True(t, total.Price.Equal(amounts[0].Add(amounts[1])))
+}
+
+func TestSplitByLines_RejectsClaimedAndForeignLines(t *testing.T) {
+	first, second := identity.NewID(uuid.New()), identity.NewID(uuid.New())
+	lines := []transaction.SplitLine{
+		{OrderID: first, Subtotal: decimal.NewFromInt(10000)},
+		{OrderID: second, Subtotal: decimal.NewFromInt(20000)},
+	}
+	total := price(30000)
+
+	_, err := transaction.SplitByLines(total, lines, []identity.ID{first}, [][]identity.ID{{first}}, decimal.NewFromInt(20000))
+	assert.ErrorIs(t, err, transaction.ErrorInvalidSplit)
+
+	_, err = transaction.SplitByLines(total, lines, nil, [][]identity.ID{{identity.NewID(uuid.New())}}, total.Price)
+	assert.ErrorIs(t, err, transaction.ErrorInvalidSplit)
+
+	_, err = transaction.SplitByLines(total, lines, nil, [][]identity.ID{{first}, {first, second}}, total.Price)
+	assert.ErrorIs(t, err, transaction.ErrorInvalidSplit)
+}
+
+func TestSplitPayments_CoverTotalOnlyWhenPaid(t *testing.T) {
+	total := price(100000)
+	payments := []transaction.SplitPayment{
+		splitPayment(60000, transaction.PaymentStatusSettlement),
+		splitPayment(40000, transaction.PaymentStatusDeny),
+	}
+
+	assert.False(t, transaction.IsCovered(total, payments))
+	assert.True(t, decimal.NewFromInt(40000).Equal(transaction.RemainingAmount(total, payments)))
+
+	payments = append(payments, splitPayment(40000, transaction.PaymentStatusPending))
+	assert.False(t, transaction.IsCovered(total, payments))
+	assert.True(t, transaction.RemainingAmount(total, payments).IsZero())
+
+	payments[2].Payment = transaction.NewPaymentFromSchema("", transaction.PaymentStatusSettlement)
+	assert.True(t, transaction.IsCovered(total, payments))
+	assert.True(t, decimal.NewFromInt(100000).Equal(transaction.PaidAmount(payments)))
+}
+
+func TestCheckFullChargeAfterSplit(t *testing.T) {
+	tests := []struct {
+		status  string
+		wantErr bool
+	}{
+		{transaction.PaymentStatusSettlement, true},
+		{transaction.PaymentStatusCapture, true},
+		{transaction.PaymentStatusExpire, false},
+		{transaction.PaymentStatusCancel, false},
+		{transaction.PaymentStatusDeny, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			err := transaction.CheckFullChargeAfterSplit(tt.status)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, transaction.ErrorPaidAfterSplit)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSplitPayment_Refund(t *testing.T) {
+	paid := splitPayment(50000, transaction.PaymentStatusSettlement)
+	paid.RefundedAmount = price(20000)
+
+	refund, err := paid.Refund(decimal.NewFromInt(30000), "missing item")
+	assert.NoError(t, err)
+	assert.Equal(t, paid.ID, refund.SplitPaymentID)
+	assert.True(t, decimal.NewFromInt(30000).Equal(refund.Amount.Price))
+
+	_, err = paid.Refund(decimal.NewFromInt(30001), "")
+	assert.ErrorIs(t, err, transaction.ErrorInvalidRefund)
+
+	_, err = splitPayment(50000, transaction.PaymentStatusPending).Refund(decimal.NewFromInt(1000), "")
+	assert.ErrorIs(t, err, transaction.ErrorPaymentNotPaid)
+}
+
+func TestSplitBill_RejectsMixedShares(t *testing.T) {
+	splitPaymentService := service.NewSplitPaymentService(new(MockSplitPaymentRepository), nil, nil, nil, nil)
+
+	_, err := splitPaymentService.SplitBill(context.Background(), uuid.NewString(), uuid.NewString(), request.SplitBill{
+		Splits: []request.SplitShare{
+			{Amount: "50000"},
+			{OrderIDs: []string{uuid.NewString()}},
+		},
+	})
+
+	assert.ErrorIs(t, err, transaction.ErrorInvalidSplit)
+}
+
+func TestGetPayments_OtherCustomer(t *testing.T) {
+	mockSplitPaymentRepo := new(MockSplitPaymentRepository)
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	mockUserRepo := new(MockUserRepositoryForTransaction)
+	splitPaymentService := service.NewSplitPaymentService(mockSplitPaymentRepo, mockTransactionRepo, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	transactionID := uuid.NewString()
+	mockUserRepo.On("GetUserByID", ctx, nil, userID.String()).Return(user.User{
+		ID:   identity.NewID(userID),
+		Role: user.Role{Name: user.RoleCustomer},
+	}, nil)
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID).Return(transaction.Query{
+		Transaction: transaction.Transaction{UserID: identity.NewID(uuid.New())},
+	}, nil)
+
+	_, err := splitPaymentService.GetPayments(ctx, userID.String(), transactionID)
+
+	assert.ErrorIs(t, err, transaction.ErrorNotTransactionOwner)
+	mockSplitPaymentRepo.AssertNotCalled(t, "GetSplitPaymentsByTransactionID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPayments_Success(t *testing.T) {
+	mockSplitPaymentRepo := new(MockSplitPaymentRepository)
+	mockTransactionRepo := new(MockTransactionRepositoryForGetByID)
+	mockUserRepo := new(MockUserRepositoryForTransaction)
+	splitPaymentService := service.NewSplitPaymentService(mockSplitPaymentRepo, mockTransactionRepo, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+	userID := identity.NewID(uuid.New())
+	transactionID := identity.NewID(uuid.New())
+	mockUserRepo.On("GetUserByID", ctx, nil, userID.String()).Return(user.User{
+		ID:   userID,
+		Role: user.Role{Name: user.RoleCustomer},
+	}, nil)
+	mockTransactionRepo.On("GetDetailedTransactionByID", ctx, nil, transactionID.String()).Return(transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:         transactionID,
+			UserID:     userID,
+			Payment:    transaction.NewPaymentFromSchema("", transaction.PaymentStatusPending),
+			TotalPrice: shared.NewPriceFromSchema(decimal.NewFromInt(90000)),
+		},
+	}, nil)
+	mockSplitPaymentRepo.On("GetSplitPaymentsByTransactionID", ctx, nil, transactionID.String()).Return([]transaction.SplitPayment{
+		splitPayment(30000, transaction.PaymentStatusSettlement),
+		splitPayment(30000, transaction.PaymentStatusPending),
+		splitPayment(30000, transaction.PaymentStatusExpire),
+	}, nil)
+
+	result, err := splitPaymentService.GetPayments(ctx, userID.String(), transactionID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "30000", result.PaidAmount)
+	assert.Equal(t, "30000", result.RemainingAmount)
+	assert.Equal(t, transaction.PaymentStatusPending, result.PaymentStatus)
+	assert.Len(t, result.Payments, 3)
+	assert.Equal(t, transaction.PaymentStatusExpire, result.Payments[2].Status)
+}
+
+func TestFakeAdapter_ChargesSplitPaymentSeparately(t *testing.T) {
+	fakeAdapter := payment_gateway.NewFakeAdapter(nil, nil, fixedClock{now: lunchTime}, "http://localhost", "http://localhost/api/transaction/hook/fake", "")
+	transactionEntity := fakeGatewayTransaction(90000)
+	share := splitPayment(30000, transaction.PaymentStatusPending)
+	share.TransactionID = transactionEntity.ID
+
+	charge, err := fakeAdapter.ProcessSplitPayment(context.Background(), nil, transactionEntity, share)
+	assert.NoError(t, err)
+
+	payment, err := fakeAdapter.GetPayment(charge.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, share.ID.String(), payment.OrderID)
+	assert.True(t, decimal.NewFromInt(30000).Equal(payment.GrossAmount))
+}
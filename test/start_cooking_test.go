@@ -8,11 +8,13 @@ import (
 	menu_item "fp-kpl/domain/menu/menu_item"
 	"fp-kpl/domain/order"
 	"fp-kpl/domain/port"
+	"fp-kpl/domain/shared"
 	"fp-kpl/domain/table"
 	"fp-kpl/domain/transaction"
 	"fp-kpl/domain/user"
 	"fp-kpl/platform/pagination"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -51,7 +53,7 @@ func (m *MockTransactionRepositoryForStartCooking) GetAllReadyToServeTransaction
 func (m *MockTransactionRepositoryForStartCooking) GetTransactionByID(ctx context.Context, tx interface{}, userID string, id string) (interface{}, error) {
 	return nil, nil
 }
-func (m *MockTransactionRepositoryForStartCooking) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+func (m *MockTransactionRepositoryForStartCooking) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	return "", nil
 }
 func (m *MockTransactionRepositoryForStartCooking) GetNextOrder(ctx context.Context, tx interface{}) (response.NextOrder, error) {
@@ -73,6 +75,50 @@ func (m *MockTransactionRepositoryForStartCooking) UpdateServedAt(ctx context.Co
 	return transaction.Transaction{}, nil
 }
 
+func (m *MockTransactionRepositoryForStartCooking) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForStartCooking) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 // Mocks for other repositories (minimal, not used in these tests)
 type MockUserRepositoryForStartCooking struct{ mock.Mock }
 
@@ -88,6 +134,12 @@ func (m *MockUserRepositoryForStartCooking) GetUserByEmail(ctx context.Context,
 func (m *MockUserRepositoryForStartCooking) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
 	return user.User{}, false, nil
 }
+func (m *MockUserRepositoryForStartCooking) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForStartCooking) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForStartCooking struct{ mock.Mock }
 
@@ -98,6 +150,10 @@ func (m *MockTableRepositoryForStartCooking) GetTableByID(ctx context.Context, t
 	return table.Table{}, nil
 }
 
+func (m *MockTableRepositoryForStartCooking) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	return table.Table{}, nil
+}
+
 type MockOrderRepositoryForStartCooking struct{ mock.Mock }
 
 func (m *MockOrderRepositoryForStartCooking) CreateOrder(ctx context.Context, tx interface{}, orderEntity order.Order) (order.Order, error) {
@@ -106,6 +162,9 @@ func (m *MockOrderRepositoryForStartCooking) CreateOrder(ctx context.Context, tx
 func (m *MockOrderRepositoryForStartCooking) GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]order.Order, error) {
 	return nil, nil
 }
+func (m *MockOrderRepositoryForStartCooking) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
 
 type MockMenuRepositoryForStartCooking struct{ mock.Mock }
 
@@ -121,6 +180,14 @@ func (m *MockMenuRepositoryForStartCooking) GetMenusByCategoryID(ctx context.Con
 func (m *MockMenuRepositoryForStartCooking) UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (menu_item.Menu, error) {
 	return menu_item.Menu{}, nil
 }
+func (m *MockMenuRepositoryForStartCooking) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+func (m *MockMenuRepositoryForStartCooking) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
 
 type MockPaymentGatewayPortForStartCooking struct{ mock.Mock }
 
@@ -130,6 +197,28 @@ func (m *MockPaymentGatewayPortForStartCooking) ProcessPayment(ctx context.Conte
 func (m *MockPaymentGatewayPortForStartCooking) HookPayment(ctx context.Context, tx interface{}, transactionID uuid.UUID, datas map[string]interface{}) error {
 	return nil
 }
+func (m *MockPaymentGatewayPortForStartCooking) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForStartCooking) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+func (m *MockPaymentGatewayPortForStartCooking) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForStartCooking) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForStartCooking) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForStartCooking) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
 
 type MockTransactionInterfaceForStartCooking struct {
 	mock.Mock
@@ -168,13 +257,19 @@ func TestStartCooking_Success(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
@@ -201,13 +296,19 @@ func TestStartCooking_TransactionNotFound(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
@@ -234,13 +335,19 @@ func TestStartCooking_InvalidTransactionType(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
@@ -267,13 +374,19 @@ func TestStartCooking_InvalidOrderStatus(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
@@ -300,13 +413,19 @@ func TestStartCooking_GetTransactionError(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
@@ -333,13 +452,19 @@ func TestStartCooking_UpdateStatusError(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
@@ -366,13 +491,19 @@ func TestStartCooking_WithMultipleOrders(t *testing.T) {
 		mockPaymentGateway,
 		mockTransactionInterface,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
 	queueCode := "Q0001"
 
 	req := request.StartCooking{QueueCode: queueCode}
-	result, err := transactionService.StartCooking(ctx, req)
+	result, err := transactionService.StartCooking(ctx, uuid.NewString(), req)
 
 	// The validation will fail because our mock is not the correct type
 	assert.Error(t, err)
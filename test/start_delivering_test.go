@@ -55,7 +55,7 @@ func (m *MockTransactionRepositoryForStartDelivering) GetAllReadyToServeTransact
 func (m *MockTransactionRepositoryForStartDelivering) GetTransactionByID(ctx context.Context, tx interface{}, userID string, id string) (interface{}, error) {
 	return nil, nil
 }
-func (m *MockTransactionRepositoryForStartDelivering) GetLatestQueueCode(ctx context.Context, tx interface{}, id string) (string, error) {
+func (m *MockTransactionRepositoryForStartDelivering) GetLatestQueueCode(ctx context.Context, tx interface{}, id string, businessDay string) (string, error) {
 	return "", nil
 }
 func (m *MockTransactionRepositoryForStartDelivering) GetNextOrder(ctx context.Context, tx interface{}) (response.NextOrder, error) {
@@ -77,6 +77,50 @@ func (m *MockTransactionRepositoryForStartDelivering) UpdateServedAt(ctx context
 	return transaction.Transaction{}, nil
 }
 
+func (m *MockTransactionRepositoryForStartDelivering) GetNextOrderByStation(ctx context.Context, tx interface{}, stationID string) (response.NextOrder, error) {
+	return response.NextOrder{}, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetPendingTransactions(ctx context.Context, tx interface{}, stationID string) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) UpdatePriority(ctx context.Context, tx interface{}, transactionID string, priority int) (transaction.Transaction, error) {
+	return transaction.Transaction{}, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetKitchenQueue(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetCookingHistory(ctx context.Context, tx interface{}, limit int) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetInFlightTransactions(ctx context.Context, tx interface{}) ([]transaction.Query, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetDisplayBoardTransactions(ctx context.Context, tx interface{}, limit int) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) ExpireUnpaidTransactions(ctx context.Context, tx interface{}, createdBefore time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetUnsettledTransactions(ctx context.Context, tx interface{}, createdAfter time.Time) ([]transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) GetTransactionByGuestTokenHash(ctx context.Context, tx interface{}, tokenHash string) (transaction.Query, error) {
+	return transaction.Query{}, nil
+}
+
+func (m *MockTransactionRepositoryForStartDelivering) LockTransaction(ctx context.Context, tx interface{}, id string) error {
+	return nil
+}
+
 // Mocks for other repositories (minimal, not used in these tests)
 type MockUserRepositoryForStartDelivering struct{ mock.Mock }
 
@@ -92,6 +136,12 @@ func (m *MockUserRepositoryForStartDelivering) GetUserByEmail(ctx context.Contex
 func (m *MockUserRepositoryForStartDelivering) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
 	return user.User{}, false, nil
 }
+func (m *MockUserRepositoryForStartDelivering) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForStartDelivering) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
 
 type MockTableRepositoryForStartDelivering struct{ mock.Mock }
 
@@ -102,6 +152,10 @@ func (m *MockTableRepositoryForStartDelivering) GetTableByID(ctx context.Context
 	return table.Table{}, nil
 }
 
+func (m *MockTableRepositoryForStartDelivering) GetTableByToken(ctx context.Context, tx interface{}, token string) (table.Table, error) {
+	return table.Table{}, nil
+}
+
 type MockOrderRepositoryForStartDelivering struct{ mock.Mock }
 
 func (m *MockOrderRepositoryForStartDelivering) CreateOrder(ctx context.Context, tx interface{}, orderEntity order.Order) (order.Order, error) {
@@ -110,6 +164,9 @@ func (m *MockOrderRepositoryForStartDelivering) CreateOrder(ctx context.Context,
 func (m *MockOrderRepositoryForStartDelivering) GetOrdersByTransactionID(ctx context.Context, tx interface{}, transactionID string) ([]order.Order, error) {
 	return nil, nil
 }
+func (m *MockOrderRepositoryForStartDelivering) UpdateOrdersCookingStatus(ctx context.Context, tx interface{}, transactionID string, stationID string, status string) ([]order.Order, error) {
+	return nil, nil
+}
 
 type MockMenuRepositoryForStartDelivering struct{ mock.Mock }
 
@@ -125,6 +182,14 @@ func (m *MockMenuRepositoryForStartDelivering) GetMenusByCategoryID(ctx context.
 func (m *MockMenuRepositoryForStartDelivering) UpdateMenuAvailability(ctx context.Context, tx interface{}, id string, isAvailable bool) (menu_item.Menu, error) {
 	return menu_item.Menu{}, nil
 }
+func (m *MockMenuRepositoryForStartDelivering) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
+func (m *MockMenuRepositoryForStartDelivering) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu_item.PriceOverride) (menu_item.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu_item.Menu), args.Error(1)
+}
 
 type MockPaymentGatewayPortForStartDelivering struct{ mock.Mock }
 
@@ -134,6 +199,28 @@ func (m *MockPaymentGatewayPortForStartDelivering) ProcessPayment(ctx context.Co
 func (m *MockPaymentGatewayPortForStartDelivering) HookPayment(ctx context.Context, tx interface{}, transactionID uuid.UUID, datas map[string]interface{}) error {
 	return nil
 }
+func (m *MockPaymentGatewayPortForStartDelivering) CheckPaymentStatus(ctx context.Context, transactionId uuid.UUID) (port.PaymentStatusResponse, error) {
+	return port.PaymentStatusResponse{}, nil
+}
+
+func (m *MockPaymentGatewayPortForStartDelivering) CancelPayment(ctx context.Context, transactionId uuid.UUID) error {
+	return nil
+}
+func (m *MockPaymentGatewayPortForStartDelivering) Provider() string {
+	return transaction.PaymentProviderMidtrans
+}
+
+func (m *MockPaymentGatewayPortForStartDelivering) ParseNotification(headers map[string]string, body map[string]interface{}) (map[string]interface{}, error) {
+	return body, nil
+}
+
+func (m *MockPaymentGatewayPortForStartDelivering) Gateway(provider string) (port.PaymentGatewayPort, error) {
+	return m, nil
+}
+
+func (m *MockPaymentGatewayPortForStartDelivering) ProcessSplitPayment(ctx context.Context, tx interface{}, transactionEntity transaction.Transaction, splitPayment transaction.SplitPayment) (port.ProcessPaymentResponse, error) {
+	return port.ProcessPaymentResponse{}, nil
+}
 
 // Mock for transaction.Service
 type MockTransactionServiceForStartDelivering struct{ mock.Mock }
@@ -146,8 +233,20 @@ func (m *MockTransactionServiceForStartDelivering) GetOrderDelayStatus(maxCookin
 	return false
 }
 
-func (m *MockTransactionServiceForStartDelivering) GenerateQueueCode(ctx context.Context, transactionID string) (string, error) {
-	return "", nil
+func (m *MockTransactionServiceForStartDelivering) GenerateQueueCode(ctx context.Context, tx interface{}, transactionID string) (transaction.QueueCode, error) {
+	return transaction.QueueCode{}, nil
+}
+
+func (m *MockTransactionServiceForStartDelivering) GetKitchenSnapshot(ctx context.Context) (transaction.KitchenSnapshot, error) {
+	return transaction.KitchenSnapshot{}, nil
+}
+
+func (m *MockTransactionServiceForStartDelivering) GetThroughput(ctx context.Context) (transaction.Throughput, error) {
+	return transaction.Throughput{}, nil
+}
+
+func (m *MockTransactionServiceForStartDelivering) EstimateWaitTime(snapshot transaction.KitchenSnapshot, target transaction.Query) transaction.WaitEstimate {
+	return transaction.WaitEstimate{}
 }
 
 func TestStartDelivering_Success(t *testing.T) {
@@ -170,6 +269,12 @@ func TestStartDelivering_Success(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -244,6 +349,12 @@ func TestStartDelivering_TransactionNotFound(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -283,6 +394,12 @@ func TestStartDelivering_InvalidTransactionType(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -322,6 +439,12 @@ func TestStartDelivering_InvalidOrderStatus(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -376,6 +499,12 @@ func TestStartDelivering_GetTransactionError(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -415,6 +544,12 @@ func TestStartDelivering_UpdateStatusError(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -485,6 +620,12 @@ func TestStartDelivering_WithMultipleOrders(t *testing.T) {
 		mockPaymentGateway,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
 	)
 
 	ctx := context.Background()
@@ -32,6 +32,11 @@ func (m *MockCategoryRepositoryForStateMenu) GetCategoryByID(ctx context.Context
 	return args.Get(0).(category.Category), args.Error(1)
 }
 
+func (m *MockCategoryRepositoryForStateMenu) UpdateCategorySchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (category.Category, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(category.Category), args.Error(1)
+}
+
 type MockMenuRepositoryForAvailability struct {
 	mock.Mock
 }
@@ -56,12 +61,22 @@ func (m *MockMenuRepositoryForAvailability) UpdateMenuAvailability(ctx context.C
 	return args.Get(0).(menu.Menu), args.Error(1)
 }
 
+func (m *MockMenuRepositoryForAvailability) UpdateMenuSchedule(ctx context.Context, tx interface{}, id string, windows []shared.TimeWindow) (menu.Menu, error) {
+	args := m.Called(ctx, tx, id, windows)
+	return args.Get(0).(menu.Menu), args.Error(1)
+}
+
+func (m *MockMenuRepositoryForAvailability) UpdateMenuPriceOverrides(ctx context.Context, tx interface{}, id string, overrides []menu.PriceOverride) (menu.Menu, error) {
+	args := m.Called(ctx, tx, id, overrides)
+	return args.Get(0).(menu.Menu), args.Error(1)
+}
+
 func TestUpdateMenuAvailability_Success_AvailableToUnavailable(t *testing.T) {
 	// Arrange
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -117,7 +132,7 @@ func TestUpdateMenuAvailability_Success_UnavailableToAvailable(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -173,7 +188,7 @@ func TestUpdateMenuAvailability_Success_SameState(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -229,7 +244,7 @@ func TestUpdateMenuAvailability_MenuNotFound(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -254,7 +269,7 @@ func TestUpdateMenuAvailability_CategoryNotFound(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -297,7 +312,7 @@ func TestUpdateMenuAvailability_RepositoryError(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -322,7 +337,7 @@ func TestUpdateMenuAvailability_WithDecimalPrice(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -378,7 +393,7 @@ func TestUpdateMenuAvailability_StateTransitionValidation(t *testing.T) {
 	mockMenuRepo := new(MockMenuRepositoryForAvailability)
 	mockCategoryRepo := new(MockCategoryRepositoryForStateMenu)
 
-	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo)
+	menuService := service.NewMenuService(mockMenuRepo, mockCategoryRepo, nil, noRatings(), fixedClock{now: lunchTime}, nil)
 
 	ctx := context.Background()
 	menuID := uuid.New().String()
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/station"
+	"fp-kpl/domain/transaction"
+	"fp-kpl/domain/user"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock repositories for station routing tests
+type MockUserRepositoryForStation struct{ mock.Mock }
+
+func (m *MockUserRepositoryForStation) Register(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return user.User{}, nil
+}
+func (m *MockUserRepositoryForStation) GetUserByID(ctx context.Context, tx interface{}, id string) (user.User, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(user.User), args.Error(1)
+}
+func (m *MockUserRepositoryForStation) GetUserByEmail(ctx context.Context, tx interface{}, email string) (user.User, error) {
+	return user.User{}, nil
+}
+func (m *MockUserRepositoryForStation) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
+	return user.User{}, false, nil
+}
+func (m *MockUserRepositoryForStation) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForStation) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	return nil
+}
+
+func newStationCookingFixture(grillID, fryerID uuid.UUID, grillStatus, fryerStatus string) transaction.Query {
+	return transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.New()),
+			OrderStatus: transaction.OrderStatus{Status: transaction.OrderStatusPreparing},
+			QueueCode:   transaction.QueueCode{Code: "Q0001"},
+		},
+		Orders: []transaction.OrderQuery{
+			{Order: order.Order{StationID: identity.NewID(grillID), Quantity: 1, CookingStatus: order.CookingStatus{Status: grillStatus}}},
+			{Order: order.Order{StationID: identity.NewID(fryerID), Quantity: 2, CookingStatus: order.CookingStatus{Status: fryerStatus}}},
+		},
+	}
+}
+
+func TestCheckFinishCooking_StationLeavesOtherStationsPending(t *testing.T) {
+	grillID := uuid.New()
+	fryerID := uuid.New()
+	transactionQuery := newStationCookingFixture(grillID, fryerID, order.CookingStatusPreparing, order.CookingStatusPreparing)
+
+	stationOrders, err := transactionQuery.CheckFinishCooking(grillID.String())
+
+	assert.NoError(t, err)
+	assert.Len(t, stationOrders, 1)
+	assert.Equal(t, 1, stationOrders[0].Order.Quantity)
+	assert.False(t, order.IsCookingFinished([]order.Order{
+		{StationID: identity.NewID(grillID), CookingStatus: order.CookingStatus{Status: order.CookingStatusDone}},
+		{StationID: identity.NewID(fryerID), CookingStatus: order.CookingStatus{Status: order.CookingStatusPreparing}},
+	}))
+}
+
+func TestCheckFinishCooking_RejectsStationWithNothingCooking(t *testing.T) {
+	grillID := uuid.New()
+	fryerID := uuid.New()
+	transactionQuery := newStationCookingFixture(grillID, fryerID, order.CookingStatusDone, order.CookingStatusPreparing)
+
+	_, err := transactionQuery.CheckFinishCooking(grillID.String())
+	assert.ErrorIs(t, err, transaction.ErrorInvalidOrderStatus)
+
+	transactionQuery.Transaction.OrderStatus.Status = transaction.OrderStatusPending
+	_, err = transactionQuery.CheckFinishCooking("")
+	assert.ErrorIs(t, err, transaction.ErrorInvalidOrderStatus)
+}
+
+func TestCheckStartCooking_StationJoinsPreparingTransaction(t *testing.T) {
+	grillID := uuid.New()
+	fryerID := uuid.New()
+	transactionQuery := newStationCookingFixture(grillID, fryerID, order.CookingStatusPreparing, order.CookingStatusPending)
+
+	stationOrders, err := transactionQuery.CheckStartCooking(fryerID.String())
+	assert.NoError(t, err)
+	assert.Len(t, stationOrders, 1)
+
+	_, err = transactionQuery.CheckStartCooking(grillID.String())
+	assert.ErrorIs(t, err, transaction.ErrorInvalidOrderStatus)
+
+	_, err = transactionQuery.CheckStartCooking("")
+	assert.ErrorIs(t, err, transaction.ErrorInvalidOrderStatus)
+}
+
+// Lines whose menu has no station must not be stranded once a station moves
+// the transaction to preparing.
+func TestCheckStartCooking_OffersLinesWithoutStation(t *testing.T) {
+	grillID := uuid.New()
+	fryerID := uuid.New()
+	transactionQuery := newStationCookingFixture(grillID, fryerID, order.CookingStatusPreparing, order.CookingStatusDone)
+	transactionQuery.Orders = append(transactionQuery.Orders, transaction.OrderQuery{
+		Order: order.Order{Quantity: 3, CookingStatus: order.CookingStatus{Status: order.CookingStatusPending}},
+	})
+
+	stationOrders, err := transactionQuery.CheckStartCooking(fryerID.String())
+
+	assert.NoError(t, err)
+	assert.Len(t, stationOrders, 2)
+	assert.Equal(t, 3, stationOrders[1].Order.Quantity)
+}
+
+func TestGetStationNextOrder_StationNotAllowed(t *testing.T) {
+	mockTransactionRepo := new(MockTransactionRepositoryForFinishCooking)
+	mockUserRepo := new(MockUserRepositoryForStation)
+
+	transactionService := service.NewTransactionService(
+		mockTransactionRepo,
+		mockUserRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		realClock,
+		nil,
+		nil,
+	)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+	grillID := uuid.New()
+
+	mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(user.User{StationID: identity.NewID(grillID)}, nil)
+
+	_, err := transactionService.GetStationNextOrder(ctx, userID, uuid.NewString())
+
+	assert.ErrorIs(t, err, station.ErrorStationNotAllowed)
+}
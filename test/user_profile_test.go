@@ -0,0 +1,173 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/user"
+	"fp-kpl/domain/verification"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockUserRepositoryForProfile struct{ mock.Mock }
+
+func (m *MockUserRepositoryForProfile) Register(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	return userEntity, nil
+}
+func (m *MockUserRepositoryForProfile) GetUserByID(ctx context.Context, tx interface{}, id string) (user.User, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(user.User), args.Error(1)
+}
+func (m *MockUserRepositoryForProfile) GetUserByEmail(ctx context.Context, tx interface{}, email string) (user.User, error) {
+	args := m.Called(ctx, tx, email)
+	return args.Get(0).(user.User), args.Error(1)
+}
+func (m *MockUserRepositoryForProfile) CheckEmail(ctx context.Context, tx interface{}, email string) (user.User, bool, error) {
+	args := m.Called(ctx, tx, email)
+	return args.Get(0).(user.User), args.Bool(1), args.Error(2)
+}
+func (m *MockUserRepositoryForProfile) UpdateUser(ctx context.Context, tx interface{}, userEntity user.User) (user.User, error) {
+	args := m.Called(ctx, tx, userEntity)
+	return userEntity, args.Error(0)
+}
+func (m *MockUserRepositoryForProfile) DeleteUser(ctx context.Context, tx interface{}, anonymised user.User) error {
+	args := m.Called(ctx, tx, anonymised)
+	return args.Error(0)
+}
+
+func customerWithPassword(t *testing.T, plainPassword string) user.User {
+	password, err := user.NewPassword(plainPassword)
+	assert.NoError(t, err)
+
+	verifiedAt := lunchTime.Add(-24 * time.Hour)
+	return user.User{
+		ID:              identity.NewID(uuid.New()),
+		Email:           "budi@example.com",
+		Password:        password,
+		Name:            "Budi",
+		PhoneNumber:     "081234567890",
+		Role:            user.Role{Name: user.RoleCustomer},
+		EmailVerifiedAt: &verifiedAt,
+	}
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	customer := customerWithPassword(t, "rahasia123")
+
+	t.Run("changes the name and phone number and keeps the verified email", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+		mockUserRepo.On("UpdateUser", ctx, nil, mock.MatchedBy(func(userEntity user.User) bool {
+			return userEntity.Name == "Budi Santoso" && userEntity.PhoneNumber == "089876543210" && userEntity.IsEmailVerified()
+		})).Return(nil)
+		mockVerificationService := new(MockVerificationService)
+		mockVerificationService.On("SendCode", ctx, customer.ID.String(), verification.ChannelPhone).Return(nil)
+		userService := service.NewUserService(mockUserRepo, nil, mockVerificationService, nil, fixedClock{now: lunchTime}, nil)
+
+		result, err := userService.UpdateProfile(ctx, customer.ID.String(), request.UserUpdateProfile{
+			Name:        "Budi Santoso",
+			PhoneNumber: "089876543210",
+			Email:       customer.Email,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Budi Santoso", result.Name)
+		assert.True(t, result.EmailVerified)
+		assert.False(t, result.PhoneVerified)
+		mockUserRepo.AssertExpectations(t)
+		mockVerificationService.AssertExpectations(t)
+		mockVerificationService.AssertNotCalled(t, "SendCode", ctx, customer.ID.String(), verification.ChannelEmail)
+	})
+
+	t.Run("a new email needs the current password and verifying again", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+		mockUserRepo.On("CheckEmail", ctx, nil, "budi.baru@example.com").Return(user.User{}, false, gorm.ErrRecordNotFound)
+		mockUserRepo.On("UpdateUser", ctx, nil, mock.Anything).Return(nil)
+		mockVerificationService := new(MockVerificationService)
+		mockVerificationService.On("SendCode", ctx, customer.ID.String(), verification.ChannelEmail).Return(nil)
+		userService := service.NewUserService(mockUserRepo, nil, mockVerificationService, nil, fixedClock{now: lunchTime}, nil)
+
+		_, err := userService.UpdateProfile(ctx, customer.ID.String(), request.UserUpdateProfile{Email: "budi.baru@example.com"})
+		assert.ErrorIs(t, err, user.ErrorPasswordRequired)
+
+		_, err = userService.UpdateProfile(ctx, customer.ID.String(), request.UserUpdateProfile{Email: "budi.baru@example.com", CurrentPassword: "salah12345"})
+		assert.ErrorIs(t, err, user.ErrorInvalidPassword)
+
+		result, err := userService.UpdateProfile(ctx, customer.ID.String(), request.UserUpdateProfile{Email: "budi.baru@example.com", CurrentPassword: "rahasia123"})
+		assert.NoError(t, err)
+		assert.Equal(t, "budi.baru@example.com", result.Email)
+		assert.False(t, result.EmailVerified)
+		mockVerificationService.AssertNumberOfCalls(t, "SendCode", 1)
+	})
+
+	t.Run("rejects an email of another account", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+		mockUserRepo.On("CheckEmail", ctx, nil, "ani@example.com").Return(user.User{Email: "ani@example.com"}, true, nil)
+		userService := service.NewUserService(mockUserRepo, nil, nil, nil, fixedClock{now: lunchTime}, nil)
+
+		_, err := userService.UpdateProfile(ctx, customer.ID.String(), request.UserUpdateProfile{Email: "ani@example.com", CurrentPassword: "rahasia123"})
+
+		assert.ErrorIs(t, err, user.ErrorEmailAlreadyExists)
+		mockUserRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	customer := customerWithPassword(t, "rahasia123")
+
+	t.Run("anonymises the customer", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+		mockUserRepo.On("DeleteUser", ctx, nil, mock.MatchedBy(func(anonymised user.User) bool {
+			return anonymised.ID == customer.ID &&
+				anonymised.Name == user.DeletedUserName &&
+				anonymised.PhoneNumber == "" &&
+				anonymised.Password.Password == "" &&
+				!anonymised.IsEmailVerified() &&
+				strings.HasSuffix(anonymised.Email, "@deleted.invalid") &&
+				anonymised.DeletedAt != nil &&
+				anonymised.DeletedAt.Equal(lunchTime)
+		})).Return(nil)
+		userService := service.NewUserService(mockUserRepo, nil, nil, nil, fixedClock{now: lunchTime}, nil)
+
+		err := userService.DeleteAccount(ctx, customer.ID.String(), request.UserDelete{Password: "rahasia123"})
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("needs the password", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+		userService := service.NewUserService(mockUserRepo, nil, nil, nil, fixedClock{now: lunchTime}, nil)
+
+		err := userService.DeleteAccount(ctx, customer.ID.String(), request.UserDelete{Password: "salah12345"})
+
+		assert.ErrorIs(t, err, user.ErrorInvalidPassword)
+		mockUserRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("staff accounts cannot delete themselves", func(t *testing.T) {
+		cashier := customerWithPassword(t, "rahasia123")
+		cashier.Role = user.Role{Name: user.RoleCashier}
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, cashier.ID.String()).Return(cashier, nil)
+		userService := service.NewUserService(mockUserRepo, nil, nil, nil, fixedClock{now: lunchTime}, nil)
+
+		err := userService.DeleteAccount(ctx, cashier.ID.String(), request.UserDelete{Password: "rahasia123"})
+
+		assert.ErrorIs(t, err, user.ErrorDeletionNotAllowed)
+	})
+}
@@ -0,0 +1,382 @@
+package test
+
+import (
+	"context"
+	"fp-kpl/application/request"
+	"fp-kpl/application/response"
+	"fp-kpl/application/service"
+	"fp-kpl/domain/identity"
+	"fp-kpl/domain/user"
+	"fp-kpl/domain/verification"
+	"fp-kpl/infrastructure/adapter/notifier"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVerificationRepository struct{ mock.Mock }
+
+func (m *MockVerificationRepository) CreateChallenge(ctx context.Context, tx interface{}, challenge verification.Challenge) (verification.Challenge, error) {
+	args := m.Called(ctx, tx, challenge)
+	return challenge, args.Error(0)
+}
+func (m *MockVerificationRepository) GetChallengeByID(ctx context.Context, tx interface{}, id string) (verification.Challenge, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(verification.Challenge), args.Error(1)
+}
+func (m *MockVerificationRepository) GetLatestChallenge(ctx context.Context, tx interface{}, userID string, channel string) (verification.Challenge, error) {
+	args := m.Called(ctx, tx, userID, channel)
+	return args.Get(0).(verification.Challenge), args.Error(1)
+}
+func (m *MockVerificationRepository) CountChallengesSince(ctx context.Context, tx interface{}, userID string, channel string, since time.Time) (int64, error) {
+	args := m.Called(ctx, tx, userID, channel, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockVerificationRepository) RecordAttempt(ctx context.Context, tx interface{}, id string, maxAttempts int) error {
+	args := m.Called(ctx, tx, id, maxAttempts)
+	return args.Error(0)
+}
+func (m *MockVerificationRepository) MarkVerified(ctx context.Context, tx interface{}, challenge verification.Challenge, at time.Time) error {
+	args := m.Called(ctx, tx, challenge, at)
+	return args.Error(0)
+}
+
+type MockVerificationService struct{ mock.Mock }
+
+func (m *MockVerificationService) GetStatus(ctx context.Context, userID string) (response.Verification, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(response.Verification), args.Error(1)
+}
+func (m *MockVerificationService) SendCode(ctx context.Context, userID string, channel string) (response.VerificationSent, error) {
+	args := m.Called(ctx, userID, channel)
+	return response.VerificationSent{Channel: channel}, args.Error(0)
+}
+func (m *MockVerificationService) Verify(ctx context.Context, userID string, req request.VerificationVerify) (response.Verification, error) {
+	args := m.Called(ctx, userID, req)
+	return args.Get(0).(response.Verification), args.Error(1)
+}
+func (m *MockVerificationService) VerifyLink(ctx context.Context, req request.VerificationLink) (response.Verification, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(response.Verification), args.Error(1)
+}
+func (m *MockVerificationService) CheckCanOrder(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func verificationPolicy(t *testing.T) verification.Policy {
+	policy, err := verification.NewPolicy(15*time.Minute, time.Minute, 3, 5)
+	assert.NoError(t, err)
+	return policy
+}
+
+func unverifiedCustomer() user.User {
+	return user.User{
+		ID:          identity.NewID(uuid.New()),
+		Email:       "budi@example.com",
+		Name:        "Budi",
+		PhoneNumber: "081234567890",
+		Role:        user.Role{Name: user.RoleCustomer},
+	}
+}
+
+func sentChallenge(userEntity user.User, channel string, code string, sentAt time.Time) verification.Challenge {
+	policy, _ := verification.NewPolicy(15*time.Minute, time.Minute, 3, 5)
+	challenge := policy.NewChallenge(userEntity.ID, channel, verification.Destination(userEntity, channel), code, sentAt)
+	challenge.ID = identity.NewID(uuid.New())
+	return challenge
+}
+
+func TestVerificationPolicy(t *testing.T) {
+	_, err := verification.NewPolicy(0, time.Minute, 3, 5)
+	assert.ErrorIs(t, err, verification.ErrorInvalidPolicy)
+
+	_, err = verification.NewPolicy(time.Minute, time.Minute, 0, 5)
+	assert.ErrorIs(t, err, verification.ErrorInvalidPolicy)
+
+	policy := verificationPolicy(t)
+	latest := sentChallenge(unverifiedCustomer(), verification.ChannelEmail, "123456", lunchTime)
+
+	assert.NoError(t, policy.CheckResend(nil, 0, lunchTime))
+	assert.ErrorIs(t, policy.CheckResend(&latest, 1, lunchTime.Add(30*time.Second)), verification.ErrorResendTooSoon)
+	assert.NoError(t, policy.CheckResend(&latest, 1, lunchTime.Add(time.Minute)))
+	assert.ErrorIs(t, policy.CheckResend(&latest, 5, lunchTime.Add(10*time.Minute)), verification.ErrorTooManyCodes)
+}
+
+func TestVerificationChallenge_CheckUsable(t *testing.T) {
+	customer := unverifiedCustomer()
+	challenge := sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime)
+
+	assert.NoError(t, challenge.CheckUsable(customer.Email, lunchTime.Add(time.Minute), 3))
+	assert.True(t, challenge.Matches("123456"))
+	assert.True(t, challenge.Matches(" 123456 "))
+	assert.False(t, challenge.Matches("654321"))
+	assert.NotContains(t, challenge.CodeHash, "123456")
+
+	assert.ErrorIs(t, challenge.CheckUsable(customer.Email, lunchTime.Add(15*time.Minute), 3), verification.ErrorCodeExpired)
+	assert.ErrorIs(t, challenge.CheckUsable("budi.baru@example.com", lunchTime.Add(time.Minute), 3), verification.ErrorCodeExpired)
+
+	challenge.Attempts = 3
+	assert.ErrorIs(t, challenge.CheckUsable(customer.Email, lunchTime.Add(time.Minute), 3), verification.ErrorTooManyAttempts)
+
+	verifiedAt := lunchTime.Add(time.Minute)
+	challenge.VerifiedAt = &verifiedAt
+	assert.ErrorIs(t, challenge.CheckUsable(customer.Email, lunchTime.Add(2*time.Minute), 3), verification.ErrorAlreadyVerified)
+}
+
+func TestVerification_NewCodeAndMask(t *testing.T) {
+	code, err := verification.NewCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, verification.CodeLength)
+	assert.Empty(t, strings.Trim(code, "0123456789"))
+
+	assert.Equal(t, "b***@example.com", verification.MaskDestination("budi@example.com"))
+	assert.Equal(t, "********7890", verification.MaskDestination("081234567890"))
+}
+
+func TestUser_CheckCanOrder(t *testing.T) {
+	customer := unverifiedCustomer()
+	assert.NoError(t, customer.CheckCanOrder(false))
+	assert.ErrorIs(t, customer.CheckCanOrder(true), user.ErrorUnverified)
+
+	verifiedAt := lunchTime
+	customer.EmailVerifiedAt = &verifiedAt
+	assert.ErrorIs(t, customer.CheckCanOrder(true), user.ErrorUnverified, "the phone number given is not verified yet")
+
+	customer.PhoneVerifiedAt = &verifiedAt
+	assert.NoError(t, customer.CheckCanOrder(true))
+
+	customer.ChangePhone("089876543210")
+	assert.False(t, customer.IsPhoneVerified())
+	assert.ErrorIs(t, customer.CheckCanOrder(true), user.ErrorUnverified)
+
+	noPhone := unverifiedCustomer()
+	noPhone.PhoneNumber = ""
+	noPhone.EmailVerifiedAt = &verifiedAt
+	assert.NoError(t, noPhone.CheckCanOrder(true))
+
+	cashier := unverifiedCustomer()
+	cashier.Role = user.Role{Name: user.RoleCashier}
+	assert.NoError(t, cashier.CheckCanOrder(true))
+}
+
+func TestVerificationService_SendCode(t *testing.T) {
+	ctx := context.Background()
+	customer := unverifiedCustomer()
+	userID := customer.ID.String()
+
+	t.Run("sends an email code with a link and stores only its hash", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(customer, nil)
+		mockVerificationRepo := new(MockVerificationRepository)
+		mockVerificationRepo.On("GetLatestChallenge", ctx, nil, userID, verification.ChannelEmail).Return(verification.Challenge{}, verification.ErrorChallengeNotFound)
+		mockVerificationRepo.On("CountChallengesSince", ctx, nil, userID, verification.ChannelEmail, lunchTime.Add(-time.Hour)).Return(int64(0), nil)
+		mockVerificationRepo.On("CreateChallenge", ctx, nil, mock.Anything).Return(nil)
+		outbox := notifier.NewLogVerificationNotifier(nil, 0)
+		verificationService := service.NewVerificationService(mockVerificationRepo, mockUserRepo, outbox, verificationPolicy(t), fixedClock{now: lunchTime}, "https://kantin.example.com", false)
+
+		result, err := verificationService.SendCode(ctx, userID, verification.ChannelEmail)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "b***@example.com", result.Destination)
+		assert.Equal(t, lunchTime.Add(15*time.Minute), result.ExpiresAt)
+		assert.Equal(t, lunchTime.Add(time.Minute), result.ResendAfter)
+
+		messages := outbox.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, customer.Email, messages[0].Destination)
+		assert.True(t, strings.HasPrefix(messages[0].Link, "https://kantin.example.com"+service.VerificationLinkPath+"?"))
+		assert.Contains(t, messages[0].Link, "code="+messages[0].Code)
+
+		stored := mockVerificationRepo.Calls[2].Arguments.Get(2).(verification.Challenge)
+		assert.Equal(t, verification.HashCode(messages[0].Code), stored.CodeHash)
+		assert.Contains(t, messages[0].Link, "challenge="+stored.ID.String())
+	})
+
+	t.Run("phone codes have no link", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(customer, nil)
+		mockVerificationRepo := new(MockVerificationRepository)
+		mockVerificationRepo.On("GetLatestChallenge", ctx, nil, userID, verification.ChannelPhone).Return(verification.Challenge{}, verification.ErrorChallengeNotFound)
+		mockVerificationRepo.On("CountChallengesSince", ctx, nil, userID, verification.ChannelPhone, mock.Anything).Return(int64(0), nil)
+		mockVerificationRepo.On("CreateChallenge", ctx, nil, mock.Anything).Return(nil)
+		outbox := notifier.NewLogVerificationNotifier(nil, 0)
+		verificationService := service.NewVerificationService(mockVerificationRepo, mockUserRepo, outbox, verificationPolicy(t), fixedClock{now: lunchTime}, "https://kantin.example.com", false)
+
+		_, err := verificationService.SendCode(ctx, userID, verification.ChannelPhone)
+
+		assert.NoError(t, err)
+		assert.Equal(t, customer.PhoneNumber, outbox.Messages()[0].Destination)
+		assert.Empty(t, outbox.Messages()[0].Link)
+	})
+
+	t.Run("throttles resending", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(customer, nil)
+		mockVerificationRepo := new(MockVerificationRepository)
+		mockVerificationRepo.On("GetLatestChallenge", ctx, nil, userID, verification.ChannelEmail).Return(sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime.Add(-30*time.Second)), nil)
+		mockVerificationRepo.On("CountChallengesSince", ctx, nil, userID, verification.ChannelEmail, mock.Anything).Return(int64(1), nil)
+		outbox := notifier.NewLogVerificationNotifier(nil, 0)
+		verificationService := service.NewVerificationService(mockVerificationRepo, mockUserRepo, outbox, verificationPolicy(t), fixedClock{now: lunchTime}, "", false)
+
+		_, err := verificationService.SendCode(ctx, userID, verification.ChannelEmail)
+
+		assert.ErrorIs(t, err, verification.ErrorResendTooSoon)
+		assert.Empty(t, outbox.Messages())
+		mockVerificationRepo.AssertNotCalled(t, "CreateChallenge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("loses to a concurrent send of the same channel", func(t *testing.T) {
+		latest := sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime.Add(-2*time.Minute))
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(customer, nil)
+		mockVerificationRepo := new(MockVerificationRepository)
+		mockVerificationRepo.On("GetLatestChallenge", ctx, nil, userID, verification.ChannelEmail).Return(latest, nil)
+		mockVerificationRepo.On("CountChallengesSince", ctx, nil, userID, verification.ChannelEmail, mock.Anything).Return(int64(1), nil)
+		mockVerificationRepo.On("CreateChallenge", ctx, nil, mock.MatchedBy(func(challenge verification.Challenge) bool {
+			return challenge.ReplacesID == latest.ID
+		})).Return(verification.ErrorResendTooSoon)
+		outbox := notifier.NewLogVerificationNotifier(nil, 0)
+		verificationService := service.NewVerificationService(mockVerificationRepo, mockUserRepo, outbox, verificationPolicy(t), fixedClock{now: lunchTime}, "", false)
+
+		_, err := verificationService.SendCode(ctx, userID, verification.ChannelEmail)
+
+		assert.ErrorIs(t, err, verification.ErrorResendTooSoon)
+		assert.Empty(t, outbox.Messages())
+		mockVerificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a verified or missing address", func(t *testing.T) {
+		verifiedAt := lunchTime
+		verified := customer
+		verified.EmailVerifiedAt = &verifiedAt
+		verified.PhoneNumber = ""
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(verified, nil)
+		verificationService := service.NewVerificationService(new(MockVerificationRepository), mockUserRepo, notifier.NewLogVerificationNotifier(nil, 0), verificationPolicy(t), fixedClock{now: lunchTime}, "", false)
+
+		_, err := verificationService.SendCode(ctx, userID, verification.ChannelEmail)
+		assert.ErrorIs(t, err, verification.ErrorAlreadyVerified)
+
+		_, err = verificationService.SendCode(ctx, userID, verification.ChannelPhone)
+		assert.ErrorIs(t, err, verification.ErrorNoDestination)
+
+		_, err = verificationService.SendCode(ctx, userID, "fax")
+		assert.ErrorIs(t, err, verification.ErrorInvalidChannel)
+	})
+}
+
+func TestVerificationService_Verify(t *testing.T) {
+	ctx := context.Background()
+	customer := unverifiedCustomer()
+	userID := customer.ID.String()
+	now := lunchTime.Add(2 * time.Minute)
+
+	setUp := func(t *testing.T, challenge verification.Challenge) (*MockVerificationRepository, service.VerificationService) {
+		mockUserRepo := new(MockUserRepositoryForProfile)
+		mockUserRepo.On("GetUserByID", ctx, nil, userID).Return(customer, nil)
+		mockVerificationRepo := new(MockVerificationRepository)
+		mockVerificationRepo.On("GetLatestChallenge", ctx, nil, userID, challenge.Channel).Return(challenge, nil)
+		return mockVerificationRepo, service.NewVerificationService(mockVerificationRepo, mockUserRepo, notifier.NewLogVerificationNotifier(nil, 0), verificationPolicy(t), fixedClock{now: now}, "", true)
+	}
+
+	t.Run("the right code verifies the phone number", func(t *testing.T) {
+		challenge := sentChallenge(customer, verification.ChannelPhone, "123456", lunchTime)
+		mockVerificationRepo, verificationService := setUp(t, challenge)
+		mockVerificationRepo.On("RecordAttempt", ctx, nil, challenge.ID.String(), 3).Return(nil)
+		mockVerificationRepo.On("MarkVerified", ctx, nil, challenge, now).Return(nil)
+
+		result, err := verificationService.Verify(ctx, userID, request.VerificationVerify{Channel: verification.ChannelPhone, Code: "123456"})
+
+		assert.NoError(t, err)
+		assert.True(t, result.PhoneVerified)
+		assert.False(t, result.EmailVerified)
+		assert.False(t, result.CanOrder)
+		mockVerificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("a wrong code uses up an attempt", func(t *testing.T) {
+		challenge := sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime)
+		mockVerificationRepo, verificationService := setUp(t, challenge)
+		mockVerificationRepo.On("RecordAttempt", ctx, nil, challenge.ID.String(), 3).Return(nil)
+
+		_, err := verificationService.Verify(ctx, userID, request.VerificationVerify{Channel: verification.ChannelEmail, Code: "654321"})
+
+		assert.ErrorIs(t, err, verification.ErrorInvalidCode)
+		assert.Contains(t, err.Error(), "2 attempts left")
+		mockVerificationRepo.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stops once the attempts run out", func(t *testing.T) {
+		challenge := sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime)
+		challenge.Attempts = 2
+		mockVerificationRepo, verificationService := setUp(t, challenge)
+		mockVerificationRepo.On("RecordAttempt", ctx, nil, challenge.ID.String(), 3).Return(verification.ErrorTooManyAttempts)
+
+		_, err := verificationService.Verify(ctx, userID, request.VerificationVerify{Channel: verification.ChannelEmail, Code: "123456"})
+
+		assert.ErrorIs(t, err, verification.ErrorTooManyAttempts)
+		mockVerificationRepo.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("an expired code is not checked", func(t *testing.T) {
+		challenge := sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime.Add(-time.Hour))
+		mockVerificationRepo, verificationService := setUp(t, challenge)
+
+		_, err := verificationService.Verify(ctx, userID, request.VerificationVerify{Channel: verification.ChannelEmail, Code: "123456"})
+
+		assert.ErrorIs(t, err, verification.ErrorCodeExpired)
+		mockVerificationRepo.AssertNotCalled(t, "RecordAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("an email link stops working once a newer code is sent", func(t *testing.T) {
+		older := sentChallenge(customer, verification.ChannelEmail, "123456", lunchTime)
+		newer := sentChallenge(customer, verification.ChannelEmail, "654321", lunchTime.Add(time.Minute))
+		mockVerificationRepo, verificationService := setUp(t, newer)
+		mockVerificationRepo.On("GetChallengeByID", ctx, nil, older.ID.String()).Return(older, nil)
+
+		_, err := verificationService.VerifyLink(ctx, request.VerificationLink{Challenge: older.ID.String(), Code: "123456"})
+
+		assert.ErrorIs(t, err, verification.ErrorCodeExpired)
+		mockVerificationRepo.AssertNotCalled(t, "RecordAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestVerificationService_CheckCanOrder(t *testing.T) {
+	ctx := context.Background()
+	customer := unverifiedCustomer()
+	mockUserRepo := new(MockUserRepositoryForProfile)
+	mockUserRepo.On("GetUserByID", ctx, nil, customer.ID.String()).Return(customer, nil)
+
+	allowed := service.NewVerificationService(new(MockVerificationRepository), mockUserRepo, nil, verificationPolicy(t), fixedClock{now: lunchTime}, "", false)
+	assert.NoError(t, allowed.CheckCanOrder(ctx, customer.ID.String()))
+	mockUserRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything, mock.Anything)
+
+	required := service.NewVerificationService(new(MockVerificationRepository), mockUserRepo, nil, verificationPolicy(t), fixedClock{now: lunchTime}, "", true)
+	assert.ErrorIs(t, required.CheckCanOrder(ctx, customer.ID.String()), user.ErrorUnverified)
+}
+
+func TestUserService_Register_SendsVerificationCodes(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepositoryForProfile)
+	mockUserRepo.On("CheckEmail", ctx, nil, "budi@example.com").Return(user.User{}, false, nil)
+	mockVerificationService := new(MockVerificationService)
+	mockVerificationService.On("SendCode", ctx, mock.Anything, verification.ChannelEmail).Return(nil)
+	mockVerificationService.On("SendCode", ctx, mock.Anything, verification.ChannelPhone).Return(verification.ErrorSendCode)
+	userService := service.NewUserService(mockUserRepo, nil, mockVerificationService, nil, fixedClock{now: lunchTime}, nil)
+
+	result, err := userService.Register(ctx, request.UserRegister{
+		Email:       "budi@example.com",
+		Password:    "rahasia123",
+		Name:        "Budi",
+		PhoneNumber: "081234567890",
+	})
+
+	assert.NoError(t, err, "a code that cannot be sent does not stop the registration")
+	assert.Equal(t, "budi@example.com", result.Email)
+	mockVerificationService.AssertExpectations(t)
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"fp-kpl/domain/identity"
+	menu_item "fp-kpl/domain/menu/menu_item"
+	"fp-kpl/domain/order"
+	"fp-kpl/domain/shared"
+	"fp-kpl/domain/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	grillStationID = identity.NewID(uuid.New())
+	drinkStationID = identity.NewID(uuid.New())
+)
+
+type estimateLine struct {
+	stationID     identity.ID
+	cookingTime   time.Duration
+	cookingStatus string
+}
+
+func estimateQuery(createdAt time.Time, orderStatus string, cookedAt *time.Time, lines ...estimateLine) transaction.Query {
+	query := transaction.Query{
+		Transaction: transaction.Transaction{
+			ID:          identity.NewID(uuid.New()),
+			OrderStatus: transaction.OrderStatus{Status: orderStatus},
+			CookedAt:    cookedAt,
+			Timestamp:   shared.Timestamp{CreatedAt: createdAt},
+		},
+	}
+	for _, line := range lines {
+		query.Orders = append(query.Orders, transaction.OrderQuery{
+			Order: order.Order{
+				Quantity:      1,
+				StationID:     line.stationID,
+				CookingStatus: order.CookingStatus{Status: line.cookingStatus},
+			},
+			Menu: menu_item.Menu{CookingTime: line.cookingTime},
+		})
+	}
+	return query
+}
+
+func servedQuery(cookingTime time.Duration, took time.Duration) transaction.Query {
+	cookedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	servedAt := cookedAt.Add(took)
+	query := estimateQuery(cookedAt, transaction.OrderStatusServed, &cookedAt, estimateLine{grillStationID, cookingTime, order.CookingStatusDone})
+	query.Transaction.ServedAt = &servedAt
+	return query
+}
+
+func TestLearnThroughput_NotEnoughSamples(t *testing.T) {
+	throughput := transaction.LearnThroughput([]transaction.Query{
+		servedQuery(10*time.Minute, 30*time.Minute),
+	})
+
+	assert.Equal(t, 1.0, throughput.Ratio)
+	assert.Equal(t, transaction.DefaultEstimateSpread, throughput.Spread)
+	assert.Equal(t, 1, throughput.Samples)
+}
+
+func TestLearnThroughput_FromHistory(t *testing.T) {
+	var history []transaction.Query
+	for i := 0; i < 3; i++ {
+		history = append(history, servedQuery(10*time.Minute, 10*time.Minute))
+		history = append(history, servedQuery(10*time.Minute, 20*time.Minute))
+	}
+	history = append(history, estimateQuery(time.Now(), transaction.OrderStatusPending, nil))
+
+	throughput := transaction.LearnThroughput(history)
+
+	assert.Equal(t, 6, throughput.Samples)
+	assert.InDelta(t, 1.5, throughput.Ratio, 0.0001)
+	assert.InDelta(t, 0.5/1.5, throughput.Spread, 0.0001)
+}
+
+func TestEstimate_EmptyQueue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	target := estimateQuery(now, transaction.OrderStatusPending, nil, estimateLine{grillStationID, 20 * time.Minute, order.CookingStatusPending})
+
+	estimate := transaction.KitchenSnapshot{Now: now}.Estimate(target)
+
+	assert.Equal(t, 1, estimate.QueuePosition)
+	assert.Equal(t, 20*time.Minute, estimate.CookingTime)
+	assert.Equal(t, now.Add(20*time.Minute), estimate.ETA)
+	assert.Equal(t, now.Add(15*time.Minute), estimate.EarliestETA)
+	assert.Equal(t, now.Add(25*time.Minute), estimate.LatestETA)
+	assert.Equal(t, 20*time.Minute, estimate.Remaining(now))
+}
+
+func TestEstimate_QueueAndCapacity(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cookedAt := now.Add(-5 * time.Minute)
+
+	inProgress := estimateQuery(now.Add(-20*time.Minute), transaction.OrderStatusPreparing, &cookedAt, estimateLine{grillStationID, 10 * time.Minute, order.CookingStatusPreparing})
+	queued := estimateQuery(now.Add(-10*time.Minute), transaction.OrderStatusPending, nil, estimateLine{grillStationID, 8 * time.Minute, order.CookingStatusPending})
+	otherStation := estimateQuery(now.Add(-8*time.Minute), transaction.OrderStatusPending, nil, estimateLine{drinkStationID, 30 * time.Minute, order.CookingStatusPending})
+	target := estimateQuery(now.Add(-1*time.Minute), transaction.OrderStatusPending, nil, estimateLine{grillStationID, 6 * time.Minute, order.CookingStatusPending})
+	behind := estimateQuery(now, transaction.OrderStatusPending, nil, estimateLine{grillStationID, 40 * time.Minute, order.CookingStatusPending})
+
+	snapshot := transaction.KitchenSnapshot{
+		Queue:           []transaction.Query{inProgress, queued, otherStation, target, behind},
+		StationCapacity: 1,
+		Now:             now,
+	}
+
+	estimate := snapshot.Estimate(target)
+	assert.Equal(t, 3, estimate.QueuePosition)
+	assert.Equal(t, now.Add(19*time.Minute), estimate.ETA)
+
+	snapshot.StationCapacity = 2
+	estimate = snapshot.Estimate(target)
+	assert.Equal(t, now.Add(11*time.Minute), estimate.ETA)
+
+	snapshot.Throughput = transaction.Throughput{Ratio: 2, Spread: 0.1}
+	estimate = snapshot.Estimate(target)
+	assert.Equal(t, 12*time.Minute, estimate.CookingTime)
+	assert.Equal(t, now.Add(27*time.Minute), estimate.ETA)
+}
+
+func TestEstimate_SlowestStationWins(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	queued := estimateQuery(now.Add(-10*time.Minute), transaction.OrderStatusPending, nil, estimateLine{drinkStationID, 15 * time.Minute, order.CookingStatusPending})
+	target := estimateQuery(now, transaction.OrderStatusPending, nil,
+		estimateLine{grillStationID, 12 * time.Minute, order.CookingStatusPending},
+		estimateLine{drinkStationID, 2 * time.Minute, order.CookingStatusPending},
+	)
+
+	estimate := transaction.KitchenSnapshot{Queue: []transaction.Query{queued, target}, Now: now}.Estimate(target)
+
+	assert.Equal(t, now.Add(17*time.Minute), estimate.ETA)
+}
+
+func TestEstimate_FollowsSchedulingStrategy(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	older := estimateQuery(now.Add(-10*time.Minute), transaction.OrderStatusPending, nil, estimateLine{grillStationID, 15 * time.Minute, order.CookingStatusPending})
+	rush := estimateQuery(now.Add(-1*time.Minute), transaction.OrderStatusPending, nil, estimateLine{grillStationID, 10 * time.Minute, order.CookingStatusPending})
+	rush.Transaction.Priority = 1
+	target := estimateQuery(now.Add(-5*time.Minute), transaction.OrderStatusPending, nil, estimateLine{grillStationID, 5 * time.Minute, order.CookingStatusPending})
+
+	snapshot := transaction.KitchenSnapshot{Queue: []transaction.Query{older, target, rush}, Now: now}
+
+	estimate := snapshot.Estimate(target)
+	assert.Equal(t, 2, estimate.QueuePosition)
+	assert.Equal(t, now.Add(20*time.Minute), estimate.ETA)
+
+	snapshot.Strategy = transaction.NewPriorityStrategy(transaction.NewFIFOStrategy())
+	estimate = snapshot.Estimate(target)
+	assert.Equal(t, 3, estimate.QueuePosition)
+	assert.Equal(t, now.Add(30*time.Minute), estimate.ETA)
+
+	snapshot.Strategy = transaction.NewShortestCookTimeStrategy(transaction.NewService(nil, transaction.DefaultStationCapacity, realClock).CalculateMaxCookingTime)
+	estimate = snapshot.Estimate(target)
+	assert.Equal(t, 1, estimate.QueuePosition)
+	assert.Equal(t, now.Add(5*time.Minute), estimate.ETA)
+}
+
+func TestAwaitsKitchen(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	pending := estimateQuery(now, transaction.OrderStatusPending, nil, estimateLine{grillStationID, 5 * time.Minute, order.CookingStatusPending})
+	assert.True(t, pending.AwaitsKitchen(now))
+
+	cooked := estimateQuery(now, transaction.OrderStatusReadyToServe, nil, estimateLine{grillStationID, 5 * time.Minute, order.CookingStatusDone})
+	assert.False(t, cooked.AwaitsKitchen(now))
+
+	assert.False(t, servedQuery(10*time.Minute, 12*time.Minute).AwaitsKitchen(now))
+}
+
+func TestEstimate_InProgressAndServed(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cookedAt := now.Add(-4 * time.Minute)
+
+	cooking := estimateQuery(now.Add(-10*time.Minute), transaction.OrderStatusPreparing, &cookedAt, estimateLine{grillStationID, 10 * time.Minute, order.CookingStatusPreparing})
+	estimate := transaction.KitchenSnapshot{Queue: []transaction.Query{cooking}, Now: now}.Estimate(cooking)
+	assert.Equal(t, 0, estimate.QueuePosition)
+	assert.Equal(t, now.Add(6*time.Minute), estimate.ETA)
+
+	served := servedQuery(10*time.Minute, 12*time.Minute)
+	estimate = transaction.KitchenSnapshot{Now: now}.Estimate(served)
+	assert.Equal(t, *served.Transaction.ServedAt, estimate.ETA)
+	assert.Equal(t, *served.Transaction.ServedAt, estimate.LatestETA)
+	assert.Equal(t, time.Duration(0), estimate.Remaining(now))
+}
+
+func TestGetOrderDelayStatus_UsesLearnedCookingTime(t *testing.T) {
+	transactionDomainService := transaction.NewService(nil, transaction.DefaultStationCapacity, realClock)
+	now := time.Now()
+	cookedAt := now.Add(-15 * time.Minute)
+	target := estimateQuery(now.Add(-20*time.Minute), transaction.OrderStatusPreparing, &cookedAt, estimateLine{grillStationID, 10 * time.Minute, order.CookingStatusPreparing})
+
+	nominal := transaction.KitchenSnapshot{Now: now}
+	learned := transaction.KitchenSnapshot{Throughput: transaction.Throughput{Ratio: 2, Spread: 0.2}, Now: now}
+
+	nominalEstimate := transactionDomainService.EstimateWaitTime(nominal, target)
+	learnedEstimate := transactionDomainService.EstimateWaitTime(learned, target)
+
+	assert.True(t, transactionDomainService.GetOrderDelayStatus(nominalEstimate.CookingTime, &cookedAt, nil))
+	assert.False(t, transactionDomainService.GetOrderDelayStatus(learnedEstimate.CookingTime, &cookedAt, nil))
+}